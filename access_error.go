@@ -1,21 +1,13 @@
 package fosite
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 )
 
-func (c *Fosite) WriteAccessError(rw http.ResponseWriter, _ AccessRequester, err error) {
-	rw.Header().Set("Content-Type", "application/json;charset=UTF-8")
-
-	rfcerr := ErrorToRFC6749Error(err)
-	js, err := json.Marshal(rfcerr)
-	if err != nil {
-		http.Error(rw, fmt.Sprintf(`{"error": "%s"}`, err.Error()), http.StatusInternalServerError)
-		return
+func (c *Fosite) WriteAccessError(rw http.ResponseWriter, requester AccessRequester, err error) {
+	rfcerr := c.toRFC6749Error(requester, err)
+	if rfcerr.Name == errUseDPoPNonce {
+		c.setDPoPNonceHeader(rw.Header())
 	}
-
-	rw.WriteHeader(rfcerr.StatusCode)
-	rw.Write(js)
+	writeJSON(rw, rfcerr.StatusCode, rfcerr)
 }
@@ -1,12 +1,17 @@
 package fosite_test
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/go-errors/errors"
 	"github.com/golang/mock/gomock"
 	. "github.com/ory-am/fosite"
 	. "github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWriteAccessError(t *testing.T) {
@@ -21,4 +26,116 @@ func TestWriteAccessError(t *testing.T) {
 	rw.EXPECT().Write(gomock.Any())
 
 	f.WriteAccessError(rw, nil, ErrInvalidRequest)
+	assert.Equal(t, "application/json;charset=UTF-8", header.Get("Content-Type"))
+	assert.Equal(t, "no-store", header.Get("Cache-Control"))
+	assert.Equal(t, "no-cache", header.Get("Pragma"))
+}
+
+func TestTokenEndpointResponsesAreNotCacheable(t *testing.T) {
+	f := &Fosite{}
+	ctrl := gomock.NewController(t)
+	resp := NewMockAccessResponder(ctrl)
+	defer ctrl.Finish()
+
+	resp.EXPECT().ToMap().Return(map[string]interface{}{"access_token": "foo"})
+
+	for k, c := range []struct {
+		write      func(rw http.ResponseWriter)
+		expectCode int
+	}{
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteAccessResponse(rw, nil, resp) },
+			expectCode: http.StatusOK,
+		},
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteAccessError(rw, nil, errors.New(ErrInvalidGrant)) },
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteAccessError(rw, nil, errors.New(ErrUnauthorizedClient)) },
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteAccessError(rw, nil, errors.New("some unknown error")) },
+			expectCode: http.StatusInternalServerError,
+		},
+	} {
+		rw := httptest.NewRecorder()
+		c.write(rw)
+		assert.Equal(t, c.expectCode, rw.Code, "%d", k)
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"), "%d", k)
+		assert.Equal(t, "no-cache", rw.Header().Get("Pragma"), "%d", k)
+	}
+}
+
+func TestWriteErrorsWithOverriddenStatusCodes(t *testing.T) {
+	f := &Fosite{ErrorStatusCodes: map[string]int{"invalid_client": http.StatusBadRequest}}
+
+	for k, c := range []struct {
+		write           func(rw http.ResponseWriter)
+		expectCode      int
+		expectChallenge string
+	}{
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteAccessError(rw, nil, errors.New(ErrInvalidClient)) },
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteAccessError(rw, nil, errors.New(ErrUnauthorizedClient)) },
+			expectCode: http.StatusUnauthorized,
+		},
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteRevocationResponse(rw, errors.New(ErrInvalidClient)) },
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			write:      func(rw http.ResponseWriter) { f.WriteIntrospectionError(rw, errors.New(ErrInvalidClient)) },
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			write:           func(rw http.ResponseWriter) { (&Fosite{}).WriteIntrospectionError(rw, errors.New(ErrInvalidClient)) },
+			expectCode:      http.StatusUnauthorized,
+			expectChallenge: `Basic realm="introspection"`,
+		},
+	} {
+		rw := httptest.NewRecorder()
+		c.write(rw)
+		assert.Equal(t, c.expectCode, rw.Code, "%d", k)
+		assert.Equal(t, c.expectChallenge, rw.Header().Get("WWW-Authenticate"), "%d", k)
+	}
+}
+
+func TestWriteAccessErrorUsesErrorMapper(t *testing.T) {
+	f := &Fosite{ErrorMapper: ErrorNamesByClient(map[string]map[string]string{
+		"legacy": {"unsupported_grant_type": "invalid_request"},
+	})}
+
+	for k, c := range []struct {
+		description string
+		requester   AccessRequester
+		expect      string
+	}{
+		{
+			description: "should translate the error for the legacy client",
+			requester:   &AccessRequest{Request: Request{Client: &DefaultClient{ID: "legacy"}}},
+			expect:      "invalid_request",
+		},
+		{
+			description: "should not translate the error for other clients",
+			requester:   &AccessRequest{Request: Request{Client: &DefaultClient{ID: "modern"}}},
+			expect:      "unsupported_grant_type",
+		},
+		{
+			description: "should not translate the error if the client is unknown",
+			expect:      "unsupported_grant_type",
+		},
+	} {
+		rw := httptest.NewRecorder()
+		f.WriteAccessError(rw, c.requester, errors.New(ErrUnsupportedGrantType))
+		assert.Equal(t, http.StatusBadRequest, rw.Code, "(%d) %s", k, c.description)
+
+		var body map[string]interface{}
+		require.Nil(t, json.Unmarshal(rw.Body.Bytes(), &body), "(%d) %s", k, c.description)
+		assert.Equal(t, c.expect, body["name"], "(%d) %s", k, c.description)
+	}
 }
@@ -7,6 +7,9 @@ type AccessRequest struct {
 	HandledGrantType Arguments `json:"handledGrantType" gorethink:"handledGrantType"`
 	RequestedAt      time.Time `json:"requestedAt" gorethink:"requestedAt"`
 
+	// OriginalRequest is not stored because it is loaded from storage whenever a grant is exchanged.
+	OriginalRequest Requester `json:"-" gorethink:"-"`
+
 	Request
 }
 
@@ -23,3 +26,11 @@ func NewAccessRequest(session interface{}) *AccessRequest {
 func (a *AccessRequest) GetGrantTypes() Arguments {
 	return a.GrantTypes
 }
+
+func (a *AccessRequest) GetOriginalRequest() Requester {
+	return a.OriginalRequest
+}
+
+func (a *AccessRequest) SetOriginalRequest(original Requester) {
+	a.OriginalRequest = original
+}
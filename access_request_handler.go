@@ -33,47 +33,67 @@ import (
 //   credentials (or assigned other authentication requirements), the
 //   client MUST authenticate with the authorization server as described
 //   in Section 3.2.1.
+//
+// Requests of authenticated clients which are rejected are emitted as failed AuditTokenIssued events.
 func (f *Fosite) NewAccessRequest(ctx context.Context, r *http.Request, session interface{}) (AccessRequester, error) {
+	accessRequest, err := f.newAccessRequest(ctx, r, session)
+	if err != nil && accessRequest != nil && accessRequest.GetClient() != nil {
+		f.emitAuditEvent(ctx, AuditTokenIssued, accessRequest, err)
+	}
+	return accessRequest, err
+}
+
+func (f *Fosite) newAccessRequest(ctx context.Context, r *http.Request, session interface{}) (AccessRequester, error) {
 	accessRequest := NewAccessRequest(session)
 
 	if r.Method != "POST" {
 		return accessRequest, errors.New(ErrInvalidRequest)
 	}
 
-	if err := r.ParseForm(); err != nil {
-		return accessRequest, errors.New(ErrInvalidRequest)
+	if err := f.parseForm(r); err != nil {
+		return accessRequest, err
 	}
 
-	accessRequest.Form = r.PostForm
+	// https://tools.ietf.org/html/rfc6749#section-3.2
+	// The client MUST use the HTTP "POST" method when making access token requests. Parameters are only accepted
+	// in the request body, the URL query is ignored.
+	accessRequest.Form = copyForm(r.PostForm)
 
 	if session == nil {
 		return accessRequest, errors.New("Session must not be nil")
 	}
 
-	accessRequest.Scopes = removeEmpty(strings.Split(r.Form.Get("scope"), " "))
-	accessRequest.GrantTypes = removeEmpty(strings.Split(r.Form.Get("grant_type"), " "))
+	accessRequest.GrantTypes = removeEmpty(strings.Split(accessRequest.Form.Get("grant_type"), " "))
 	if len(accessRequest.GrantTypes) < 1 {
 		return accessRequest, errors.New(ErrInvalidRequest)
 	}
 
-	clientID, clientSecret, ok := r.BasicAuth()
-	if !ok {
-		return accessRequest, errors.New(ErrInvalidRequest)
+	client, err := f.authenticateClient(ctx, r)
+	if err != nil {
+		return accessRequest, err
+	}
+	accessRequest.Client = client
+	accessRequest.SetScopes(parseScopes(accessRequest.Form, client))
+
+	if err := f.validateRequest(ctx, TokenRequestEndpoint, r, client); err != nil {
+		return accessRequest, err
 	}
 
-	client, err := f.Store.GetClient(clientID)
-	if err != nil {
-		return accessRequest, errors.New(ErrInvalidClient)
+	if err := f.validateClientScopes(client, accessRequest.GetScopes()); err != nil {
+		return accessRequest, err
 	}
 
-	// Enforce client authentication
-	if err := f.Hasher.Compare(client.GetHashedSecret(), []byte(clientSecret)); err != nil {
-		return accessRequest, errors.New(ErrInvalidClient)
+	if err := f.validateDPoPProofNonce(r); err != nil {
+		return accessRequest, err
+	}
+
+	handlers, err := f.TokenEndpointHandlers.route(strings.Join(accessRequest.GrantTypes, " "))
+	if err != nil {
+		return accessRequest, err
 	}
-	accessRequest.Client = client
 
 	var found bool = false
-	for _, loader := range f.TokenEndpointHandlers {
+	for _, loader := range handlers {
 		if err := loader.HandleTokenEndpointRequest(ctx, r, accessRequest); err == nil {
 			found = true
 		} else if errors.Is(err, ErrUnknownRequest) {
@@ -84,7 +104,7 @@ func (f *Fosite) NewAccessRequest(ctx context.Context, r *http.Request, session
 	}
 
 	if !found {
-		return nil, ErrUnsupportedGrantType
+		return accessRequest, errors.New(ErrUnsupportedGrantType)
 	}
 
 	if !accessRequest.GetScopes().Has(f.GetMandatoryScope()) {
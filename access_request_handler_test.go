@@ -13,6 +13,7 @@ import (
 	. "github.com/ory-am/fosite"
 	"github.com/ory-am/fosite/internal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 )
 
@@ -25,6 +26,7 @@ func TestNewAccessRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	fosite := &Fosite{Store: store, Hasher: hasher}
+	client.EXPECT().IsPublic().Return(false).AnyTimes()
 	for k, c := range []struct {
 		header    http.Header
 		form      url.Values
@@ -56,8 +58,10 @@ func TestNewAccessRequest(t *testing.T) {
 				"grant_type": {"foo"},
 				"client_id":  {"foo"},
 			},
-			expectErr: ErrInvalidRequest,
-			mock:      func() {},
+			expectErr: ErrInvalidClient,
+			mock: func() {
+				store.EXPECT().GetClient(gomock.Eq("foo")).Return(client, nil)
+			},
 		},
 		{
 			header: http.Header{
@@ -111,6 +115,23 @@ func TestNewAccessRequest(t *testing.T) {
 				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(errors.New(""))
 			},
 		},
+		{
+			header: http.Header{
+				"Authorization": {basicAuth("foo", "bar")},
+			},
+			method: "POST",
+			form: url.Values{
+				"grant_type": {"foo"},
+				"scope":      {"foo"},
+			},
+			expectErr: ErrInvalidScope,
+			mock: func() {
+				store.EXPECT().GetClient(gomock.Eq("foo")).Return(client, nil)
+				client.EXPECT().GetHashedSecret().Return([]byte("foo"))
+				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(nil)
+				client.EXPECT().GetScopes().Return(Arguments{DefaultMandatoryScope})
+			},
+		},
 		{
 			header: http.Header{
 				"Authorization": {basicAuth("foo", "bar")},
@@ -171,6 +192,43 @@ func TestNewAccessRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			header: http.Header{
+				"Authorization": {basicAuth("foo", "bar")},
+			},
+			method: "POST",
+			form: url.Values{
+				"grant_type":    {"foo"},
+				"client_id":     {"foo"},
+				"client_secret": {"bar"},
+			},
+			expectErr: ErrInvalidRequest,
+			mock:      func() {},
+		},
+		{
+			header: http.Header{},
+			method: "POST",
+			form: url.Values{
+				"grant_type":    {"foo"},
+				"client_id":     {"foo"},
+				"client_secret": {"bar"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient(gomock.Eq("foo")).Return(client, nil)
+				client.EXPECT().GetHashedSecret().Return([]byte("foo"))
+				hasher.EXPECT().Compare(gomock.Eq([]byte("foo")), gomock.Eq([]byte("bar"))).Return(nil)
+				handler.EXPECT().HandleTokenEndpointRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ *http.Request, a AccessRequester) {
+					a.SetScopes([]string{DefaultMandatoryScope})
+				}).Return(nil)
+			},
+			handlers: TokenEndpointHandlers{handler},
+			expect: &AccessRequest{
+				GrantTypes: Arguments{"foo"},
+				Request: Request{
+					Client: client,
+				},
+			},
+		},
 	} {
 		r := &http.Request{
 			Header:   c.header,
@@ -198,3 +256,224 @@ func TestNewAccessRequest(t *testing.T) {
 func basicAuth(username, password string) string {
 	return "Basic " + base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%s", username, password)))
 }
+
+// grantTypeHandler is a token endpoint handler declaring the grant types it handles.
+type grantTypeHandler struct {
+	TokenEndpointHandler
+	grantTypes Arguments
+}
+
+func (h *grantTypeHandler) HandledGrantTypes() Arguments {
+	return h.grantTypes
+}
+
+func TestNewAccessRequestRoutesGrantTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	password := internal.NewMockTokenEndpointHandler(ctrl)
+	refresh := internal.NewMockTokenEndpointHandler(ctrl)
+	extension := internal.NewMockTokenEndpointHandler(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{ID: "foo", Secret: []byte("foo"), GrantedScopes: []string{DefaultMandatoryScope}}
+	handlers := TokenEndpointHandlers{
+		&grantTypeHandler{TokenEndpointHandler: password, grantTypes: Arguments{"password"}},
+		&grantTypeHandler{TokenEndpointHandler: refresh, grantTypes: Arguments{"refresh_token"}},
+		extension,
+	}
+	f := &Fosite{Store: store, Hasher: hasher, TokenEndpointHandlers: handlers}
+	grant := func(_ context.Context, _ *http.Request, a AccessRequester) {
+		a.GrantScope(DefaultMandatoryScope)
+	}
+
+	for k, c := range []struct {
+		description string
+		grantType   string
+		handlers    TokenEndpointHandlers
+		mock        func()
+		expectErr   error
+	}{
+		{
+			description: "should route to the declaring handler and extensions only",
+			grantType:   "refresh_token",
+			mock: func() {
+				refresh.EXPECT().HandleTokenEndpointRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant).Return(nil)
+				extension.EXPECT().HandleTokenEndpointRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(ErrUnknownRequest)
+			},
+		},
+		{
+			description: "should fail because no handler handles the grant type",
+			grantType:   "bogus",
+			mock: func() {
+				extension.EXPECT().HandleTokenEndpointRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(ErrUnknownRequest)
+			},
+			expectErr: ErrUnsupportedGrantType,
+		},
+		{
+			description: "should fail because two handlers declare the grant type",
+			grantType:   "password",
+			handlers:    append(handlers, &grantTypeHandler{TokenEndpointHandler: refresh, grantTypes: Arguments{"password"}}),
+			mock:        func() {},
+			expectErr:   ErrMisconfiguration,
+		},
+	} {
+		f.TokenEndpointHandlers = handlers
+		if c.handlers != nil {
+			f.TokenEndpointHandlers = c.handlers
+		}
+
+		store.EXPECT().GetClient("foo").Return(client, nil)
+		hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+		c.mock()
+
+		r := &http.Request{
+			Method:   "POST",
+			Header:   http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			PostForm: url.Values{"grant_type": {c.grantType}, "scope": {DefaultMandatoryScope}},
+		}
+		_, err := f.NewAccessRequest(context.Background(), r, &struct{}{})
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestNewAccessRequestAuthenticatesPublicClients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	handler := internal.NewMockTokenEndpointHandler(ctrl)
+	defer ctrl.Finish()
+
+	public := &DefaultClient{ID: "public", Public: true, GrantedScopes: []string{DefaultMandatoryScope}}
+	confidential := &DefaultClient{ID: "confidential", Secret: []byte("foo"), GrantedScopes: []string{DefaultMandatoryScope}}
+	store.EXPECT().GetClient("public").Return(public, nil).AnyTimes()
+	store.EXPECT().GetClient("confidential").Return(confidential, nil).AnyTimes()
+	f := &Fosite{Store: store, Hasher: hasher, TokenEndpointHandlers: TokenEndpointHandlers{handler}}
+
+	for k, c := range []struct {
+		description string
+		header      http.Header
+		form        url.Values
+		mock        func()
+		expectErr   error
+	}{
+		{
+			description: "should pass because the public client did not authenticate",
+			header:      http.Header{},
+			form:        url.Values{"client_id": {"public"}},
+			mock: func() {
+				handler.EXPECT().HandleTokenEndpointRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ *http.Request, a AccessRequester) {
+					a.GrantScope(DefaultMandatoryScope)
+				}).Return(nil)
+			},
+		},
+		{
+			description: "should fail because the public client sent a secret in the request body",
+			header:      http.Header{},
+			form:        url.Values{"client_id": {"public"}, "client_secret": {"foo"}},
+			mock:        func() {},
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail because the public client used HTTP Basic authentication",
+			header:      http.Header{"Authorization": {basicAuth("public", "foo")}},
+			form:        url.Values{},
+			mock:        func() {},
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail because the confidential client did not authenticate",
+			header:      http.Header{},
+			form:        url.Values{"client_id": {"confidential"}},
+			mock:        func() {},
+			expectErr:   ErrInvalidClient,
+		},
+	} {
+		c.mock()
+		c.form.Set("grant_type", "foo")
+		c.form.Set("scope", DefaultMandatoryScope)
+		r := &http.Request{Method: "POST", Header: c.header, PostForm: c.form}
+
+		ar, err := f.NewAccessRequest(context.Background(), r, &struct{}{})
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.Equal(t, public, ar.GetClient(), "(%d) %s", k, c.description)
+		}
+	}
+}
+
+func TestNewAccessRequestRequiresDPoPNonces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	handler := internal.NewMockTokenEndpointHandler(ctrl)
+	defer ctrl.Finish()
+
+	public := &DefaultClient{ID: "public", Public: true, GrantedScopes: []string{DefaultMandatoryScope}}
+	store.EXPECT().GetClient("public").Return(public, nil).AnyTimes()
+	nonces := &HMACDPoPNonceStrategy{Secret: []byte("foobarfoobarfoobarfoobarfoobarfoobar")}
+	f := &Fosite{Store: store, TokenEndpointHandlers: TokenEndpointHandlers{handler}, DPoPNonceStrategy: nonces}
+
+	nonce, err := nonces.GenerateDPoPNonce()
+	require.Nil(t, err, "%s", err)
+	proof := func(claims string) string {
+		return "eyJ0eXAiOiJkcG9wK2p3dCJ9." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2lnbmF0dXJl"
+	}
+	dpop := func(proof string) http.Header {
+		header := http.Header{}
+		header.Set(DPoPHeader, proof)
+		return header
+	}
+	handle := func() {
+		handler.EXPECT().HandleTokenEndpointRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ *http.Request, a AccessRequester) {
+			a.GrantScope(DefaultMandatoryScope)
+		}).Return(nil)
+	}
+
+	for k, c := range []struct {
+		description string
+		header      http.Header
+		mock        func()
+		expectErr   error
+	}{
+		{
+			description: "should pass because requests without a DPoP proof do not need a nonce",
+			header:      http.Header{},
+			mock:        handle,
+		},
+		{
+			description: "should pass because the DPoP proof contains a fresh nonce",
+			header:      dpop(proof(`{"nonce":"` + nonce + `"}`)),
+			mock:        handle,
+		},
+		{
+			description: "should fail because the DPoP proof contains no nonce",
+			header:      dpop(proof(`{"jti":"foo"}`)),
+			mock:        func() {},
+			expectErr:   ErrUseDPoPNonce,
+		},
+		{
+			description: "should fail because the nonce of the DPoP proof was not issued by the server",
+			header:      dpop(proof(`{"nonce":"forged"}`)),
+			mock:        func() {},
+			expectErr:   ErrUseDPoPNonce,
+		},
+		{
+			description: "should fail because the DPoP proof is malformed",
+			header:      dpop("not-a-proof"),
+			mock:        func() {},
+			expectErr:   ErrInvalidDPoPProof,
+		},
+	} {
+		c.mock()
+		r := &http.Request{Method: "POST", Header: c.header, PostForm: url.Values{
+			"grant_type": {"foo"},
+			"client_id":  {"public"},
+			"scope":      {DefaultMandatoryScope},
+		}}
+
+		_, err := f.NewAccessRequest(context.Background(), r, &struct{}{})
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		t.Logf("Passed test case %d", k)
+	}
+}
@@ -1,11 +1,20 @@
 package fosite
 
 import (
-	"strconv"
 	"strings"
 	"time"
 )
 
+// ExpiresIn returns the expires_in value of tokens valid for lifespan. It is a whole number of seconds as required by
+// https://tools.ietf.org/html/rfc6749#section-5.1 where fractions of a second are truncated, so that the lifetime
+// of a token is never overstated.
+func ExpiresIn(lifespan time.Duration) int64 {
+	if lifespan < 0 {
+		return 0
+	}
+	return int64(lifespan / time.Second)
+}
+
 func NewAccessResponse() AccessResponder {
 	return &AccessResponse{
 		Extra: map[string]interface{}{},
@@ -16,14 +25,32 @@ type AccessResponse struct {
 	Extra       map[string]interface{}
 	AccessToken string
 	TokenType   string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
 }
 
 func (a *AccessResponse) SetScopes(scopes Arguments) {
 	a.SetExtra("scope", strings.Join(scopes, " "))
 }
 
-func (a *AccessResponse) SetExpiresIn(expiresIn time.Duration) {
-	a.SetExtra("expires_in", strconv.Itoa(int(expiresIn)))
+func (a *AccessResponse) SetExpiresIn(lifespan time.Duration) {
+	a.SetExtra("expires_in", ExpiresIn(lifespan))
+}
+
+func (a *AccessResponse) SetIssuedAt(issuedAt time.Time) {
+	a.IssuedAt = issuedAt
+}
+
+func (a *AccessResponse) GetIssuedAt() time.Time {
+	return a.IssuedAt
+}
+
+func (a *AccessResponse) SetExpiresAt(expiresAt time.Time) {
+	a.ExpiresAt = expiresAt
+}
+
+func (a *AccessResponse) GetExpiresAt() time.Time {
+	return a.ExpiresAt
 }
 
 func (a *AccessResponse) SetExtra(key string, value interface{}) {
@@ -2,18 +2,54 @@ package fosite
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-errors/errors"
 	"golang.org/x/net/context"
 )
 
+// NewAccessResponse issues the tokens of a request returned by NewAccessRequest, see TokenEndpointHandler. If the
+// storage implements Transactional, all tokens are stored in a single transaction which is rolled back if one of
+// the handlers fails, so that no tokens are left behind which were never handed out.
+//
+// The outcome is emitted as AuditTokenIssued event.
 func (f *Fosite) NewAccessResponse(ctx context.Context, req *http.Request, requester AccessRequester) (AccessResponder, error) {
-	var err error
-	var tk TokenEndpointHandler
+	response, err := f.newAccessResponse(ctx, req, requester)
+	f.emitAuditEvent(ctx, AuditTokenIssued, requester, err)
+	return response, err
+}
+
+func (f *Fosite) newAccessResponse(ctx context.Context, req *http.Request, requester AccessRequester) (AccessResponder, error) {
+	handlers, err := f.TokenEndpointHandlers.route(strings.Join(requester.GetGrantTypes(), " "))
+	if err != nil {
+		return nil, err
+	}
+	grantClientAudience(requester)
+
+	tx, transactional := f.Store.(Transactional)
+	if !transactional {
+		return populateTokenEndpointResponse(ctx, req, requester, handlers)
+	}
+
+	if ctx, err = tx.BeginTX(ctx); err != nil {
+		return nil, errors.New(ErrServerError)
+	}
+
+	response, err := populateTokenEndpointResponse(ctx, req, requester, handlers)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	} else if err := tx.Commit(ctx); err != nil {
+		tx.Rollback(ctx)
+		return nil, errors.New(ErrServerError)
+	}
+	return response, nil
+}
 
+func populateTokenEndpointResponse(ctx context.Context, req *http.Request, requester AccessRequester, handlers TokenEndpointHandlers) (AccessResponder, error) {
 	response := NewAccessResponse()
-	for _, tk = range f.TokenEndpointHandlers {
-		if err = tk.PopulateTokenEndpointResponse(ctx, req, requester, response); errors.Is(err, ErrUnknownRequest) {
+	for _, tk := range handlers {
+		if err := tk.PopulateTokenEndpointResponse(ctx, req, requester, response); errors.Is(err, ErrUnknownRequest) {
 		} else if err != nil {
 			return nil, errors.Wrap(err, 1)
 		}
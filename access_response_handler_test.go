@@ -69,9 +69,73 @@ func TestNewAccessResponse(t *testing.T) {
 	} {
 		f.TokenEndpointHandlers = c.handlers
 		c.mock()
-		ar, err := f.NewAccessResponse(nil, nil, nil)
+		ar, err := f.NewAccessResponse(nil, nil, NewAccessRequest(nil))
 		assert.True(t, errors.Is(c.expectErr, err), "%d", k)
 		assert.Equal(t, ar, c.expect)
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestNewAccessResponseIsTransactional(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	handler := internal.NewMockTokenEndpointHandler(ctrl)
+	defer ctrl.Finish()
+
+	populate := func(ctx context.Context, _ *http.Request, _ AccessRequester, resp AccessResponder) {
+		assert.Equal(t, true, ctx.Value(txKey{}), "tokens are stored within the transaction")
+		resp.SetAccessToken("foo")
+		resp.SetTokenType("bar")
+	}
+
+	for k, c := range []struct {
+		description      string
+		store            *transactionalStorage
+		mock             func()
+		expectErr        error
+		expectCommitted  int
+		expectRolledBack int
+	}{
+		{
+			description: "should fail because the transaction can not be started",
+			store:       &transactionalStorage{beginErr: errors.New("foo")},
+			mock:        func() {},
+			expectErr:   ErrServerError,
+		},
+		{
+			description: "should roll back because a handler failed",
+			store:       &transactionalStorage{},
+			mock: func() {
+				handler.EXPECT().PopulateTokenEndpointResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(populate).Return(nil)
+				handler.EXPECT().PopulateTokenEndpointResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(ErrInvalidGrant)
+			},
+			expectErr:        ErrInvalidGrant,
+			expectRolledBack: 1,
+		},
+		{
+			description: "should roll back because the transaction can not be committed",
+			store:       &transactionalStorage{commitErr: errors.New("foo")},
+			mock: func() {
+				handler.EXPECT().PopulateTokenEndpointResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(populate).Return(nil).Times(2)
+			},
+			expectErr:        ErrServerError,
+			expectCommitted:  1,
+			expectRolledBack: 1,
+		},
+		{
+			description: "should pass",
+			store:       &transactionalStorage{},
+			mock: func() {
+				handler.EXPECT().PopulateTokenEndpointResponse(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(populate).Return(nil).Times(2)
+			},
+			expectCommitted: 1,
+		},
+	} {
+		c.mock()
+		f := &Fosite{Store: c.store, TokenEndpointHandlers: TokenEndpointHandlers{handler, handler}}
+		_, err := f.NewAccessResponse(context.Background(), nil, NewAccessRequest(nil))
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s", k, c.description, err)
+		assert.Equal(t, 1, c.store.begun, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectCommitted, c.store.committed, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectRolledBack, c.store.rolledBack, "(%d) %s", k, c.description)
+	}
+}
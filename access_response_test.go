@@ -2,6 +2,7 @@ package fosite_test
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/ory-am/fosite"
 	"github.com/stretchr/testify/assert"
@@ -16,9 +17,33 @@ func TestAccessResponse(t *testing.T) {
 	assert.Equal(t, "access", ar.GetAccessToken())
 	assert.Equal(t, "bearer", ar.GetTokenType())
 	assert.Equal(t, "bar", ar.GetExtra("foo"))
+
+	now := time.Now()
+	ar.SetIssuedAt(now)
+	ar.SetExpiresAt(now.Add(time.Hour))
+	assert.Equal(t, now, ar.GetIssuedAt())
+	assert.Equal(t, now.Add(time.Hour), ar.GetExpiresAt())
 	assert.Equal(t, map[string]interface{}{
 		"access_token": "access",
 		"token_type":   "bearer",
 		"foo":          "bar",
 	}, ar.ToMap())
 }
+
+func TestAccessResponseExpiresIn(t *testing.T) {
+	for k, c := range []struct {
+		lifespan time.Duration
+		expect   int64
+	}{
+		{lifespan: time.Hour, expect: 3600},
+		{lifespan: time.Hour + 999*time.Millisecond, expect: 3600},
+		{lifespan: 90*time.Second + time.Second/3, expect: 90},
+		{lifespan: time.Millisecond, expect: 0},
+		{lifespan: -time.Minute, expect: 0},
+	} {
+		ar := NewAccessResponse()
+		ar.SetExpiresIn(c.lifespan)
+		assert.Equal(t, c.expect, ar.GetExtra("expires_in"), "case %d", k)
+		assert.Equal(t, c.expect, ExpiresIn(c.lifespan), "case %d", k)
+	}
+}
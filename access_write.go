@@ -1,21 +1,13 @@
 package fosite
 
 import (
-	"encoding/json"
 	"net/http"
 )
 
 func (c *Fosite) WriteAccessResponse(rw http.ResponseWriter, requester AccessRequester, responder AccessResponder) {
-	js, err := json.Marshal(responder.ToMap())
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
-		return
+	// Only DPoP clients use nonces, see https://tools.ietf.org/html/rfc9449#section-8.2
+	if c.DPoPNonceStrategy != nil && responder.GetTokenType() == DPoPTokenType {
+		c.setDPoPNonceHeader(rw.Header())
 	}
-
-	rw.Header().Set("Content-Type", "application/json;charset=UTF-8")
-	rw.Header().Set("Cache-Control", "no-store")
-	rw.Header().Set("Pragma", "no-cache")
-
-	rw.WriteHeader(http.StatusOK)
-	rw.Write(js)
+	writeJSON(rw, http.StatusOK, responder.ToMap())
 }
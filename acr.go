@@ -0,0 +1,27 @@
+package fosite
+
+import "github.com/go-errors/errors"
+
+// AuthenticationContextSession can be implemented by sessions passed to NewAuthorizeResponse to tell fosite which
+// authentication context class the end-user authenticated with, see
+// http://openid.net/specs/openid-connect-core-1_0.html#IDToken
+type AuthenticationContextSession interface {
+	// GetAuthenticationContextClassReference returns the authentication context class reference (acr).
+	GetAuthenticationContextClassReference() string
+}
+
+// validateAuthenticationContext rejects authorize requests if the client requires the end-user to authenticate
+// with one of a set of authentication context classes and the session does not satisfy them. The
+// ErrUnmetAuthenticationRequirements error can be checked to ask the end-user to authenticate again, e.g. using
+// a second factor, before writing the error to the client.
+func validateAuthenticationContext(ar AuthorizeRequester, session interface{}) error {
+	required := ar.GetClient().GetRequiredACRValues()
+	if len(required) == 0 {
+		return nil
+	}
+
+	if as, ok := session.(AuthenticationContextSession); ok && required.Has(as.GetAuthenticationContextClassReference()) {
+		return nil
+	}
+	return errors.New(ErrUnmetAuthenticationRequirements)
+}
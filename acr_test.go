@@ -0,0 +1,46 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type acrSession string
+
+func (a acrSession) GetAuthenticationContextClassReference() string {
+	return string(a)
+}
+
+func TestValidateAuthenticationContext(t *testing.T) {
+	for k, c := range []struct {
+		description string
+		required    []string
+		session     interface{}
+		expectErr   bool
+	}{
+		{description: "should pass because the client requires no acr"},
+		{description: "should pass because the acr is required", required: []string{"mfa", "hwk"}, session: acrSession("mfa")},
+		{description: "should fail because the acr is not sufficient", required: []string{"mfa"}, session: acrSession("pwd"), expectErr: true},
+		{description: "should fail because the session has no acr", required: []string{"mfa"}, session: acrSession(""), expectErr: true},
+		{description: "should fail because the session does not know the acr", required: []string{"mfa"}, session: struct{}{}, expectErr: true},
+	} {
+		ar := NewAuthorizeRequest()
+		ar.Client = &DefaultClient{RequiredACRValues: c.required}
+
+		err := validateAuthenticationContext(ar, c.session)
+		assert.Equal(t, c.expectErr, err != nil, "(%d) %s: %s", k, c.description, err)
+		if c.expectErr {
+			assert.True(t, errors.Is(err, ErrUnmetAuthenticationRequirements), "(%d) %s", k, c.description)
+		}
+	}
+}
+
+func TestNewAuthorizeResponseRejectsUnmetACR(t *testing.T) {
+	ar := NewAuthorizeRequest()
+	ar.Client = &DefaultClient{RequiredACRValues: []string{"mfa"}}
+
+	_, err := (&Fosite{}).NewAuthorizeResponse(nil, nil, ar, acrSession("pwd"))
+	assert.True(t, errors.Is(err, ErrUnmetAuthenticationRequirements), "%s", err)
+}
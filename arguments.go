@@ -1,21 +1,33 @@
 package fosite
 
-import "strings"
+import (
+	"encoding/json"
+	"strings"
+)
 
 type Arguments []string
 
+// Matches returns true if r and items contain the same arguments, regardless of their order. Lists containing
+// an argument more than once never match.
+//
+// Arguments lists are small, typically less than five scopes or response types. Matches, Has and Exact therefore
+// use linear scans which, unlike building maps or joining strings, do not allocate.
 func (r Arguments) Matches(items ...string) bool {
-	found := make(map[string]bool)
-	for _, item := range items {
-		if !StringInSlice(item, r) {
+	if len(r) != len(items) {
+		return false
+	}
+
+	for k, item := range items {
+		if !StringInSlice(item, r) || StringInSlice(item, items[:k]) {
 			return false
 		}
-		found[item] = true
 	}
 
-	return len(found) == len(r) && len(r) == len(items)
+	// All items are distinct and contained in r, which has the same length, so r contains no duplicates either.
+	return true
 }
 
+// Has returns true if r contains all items.
 func (r Arguments) Has(items ...string) bool {
 	for _, item := range items {
 		if !StringInSlice(item, r) {
@@ -26,6 +38,91 @@ func (r Arguments) Has(items ...string) bool {
 	return true
 }
 
+// Exact returns true if name equals the space separated arguments, e.g. "code token" for Arguments{"code", "token"}.
 func (r Arguments) Exact(name string) bool {
-	return name == strings.Join(r, " ")
+	for k, arg := range r {
+		if k > 0 {
+			if !strings.HasPrefix(name, " ") {
+				return false
+			}
+			name = name[1:]
+		}
+
+		if !strings.HasPrefix(name, arg) {
+			return false
+		}
+		name = name[len(arg):]
+	}
+	return name == ""
+}
+
+// Add returns a new Arguments list containing all arguments followed by items. The receiver is never modified,
+// which makes Add safe to use on lists shared across requests, e.g. the ones owned by a client.
+func (r Arguments) Add(items ...string) Arguments {
+	ret := make(Arguments, 0, len(r)+len(items))
+	ret = append(ret, r...)
+	return append(ret, items...)
+}
+
+// Remove returns a new Arguments list without any of items. The receiver is never modified.
+func (r Arguments) Remove(items ...string) Arguments {
+	ret := make(Arguments, 0, len(r))
+	for _, arg := range r {
+		if !StringInSlice(arg, items) {
+			ret = append(ret, arg)
+		}
+	}
+	return ret
+}
+
+// Intersection returns a new Arguments list containing the arguments of r which are also contained in other, in the
+// order of r and without duplicates. Arguments are compared exactly, use a ScopeStrategy to compare hierarchic
+// scopes. The receiver is never modified.
+func (r Arguments) Intersection(other Arguments) Arguments {
+	ret := make(Arguments, 0, len(r))
+	for _, arg := range r {
+		if StringInSlice(arg, other) && !StringInSlice(arg, ret) {
+			ret = append(ret, arg)
+		}
+	}
+	return ret
+}
+
+// Difference returns a new Arguments list containing the arguments of r which are not contained in other, in the
+// order of r and without duplicates, e.g. the requested scopes which were not granted. Unlike Remove, duplicates are
+// dropped. The receiver is never modified.
+func (r Arguments) Difference(other Arguments) Arguments {
+	ret := make(Arguments, 0, len(r))
+	for _, arg := range r {
+		if !StringInSlice(arg, other) && !StringInSlice(arg, ret) {
+			ret = append(ret, arg)
+		}
+	}
+	return ret
+}
+
+// ScopeArguments are scopes which are encoded in JSON as a space delimited string, e.g. "openid offline", which is
+// the format used by OAuth2 on the wire. Other Arguments, like response or grant types, are encoded as arrays.
+type ScopeArguments []string
+
+// MarshalJSON encodes the scopes as a space delimited string.
+func (r ScopeArguments) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Join(r, " "))
+}
+
+// UnmarshalJSON decodes scopes encoded by MarshalJSON. JSON arrays of strings are decoded as well, so that
+// requests stored in that format before can still be read.
+func (r *ScopeArguments) UnmarshalJSON(data []byte) error {
+	var joined string
+	if err := json.Unmarshal(data, &joined); err != nil {
+		var list []string
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		*r = ScopeArguments(list)
+		return nil
+	}
+
+	*r = ScopeArguments(Arguments{}.Add(removeEmpty(strings.Split(joined, " "))...))
+	return nil
 }
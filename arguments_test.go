@@ -1,6 +1,7 @@
 package fosite
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -37,6 +38,36 @@ func TestArgumentsExact(t *testing.T) {
 			exact:  "baz",
 			expect: false,
 		},
+		{
+			args:   Arguments{},
+			exact:  "",
+			expect: true,
+		},
+		{
+			args:   Arguments{"code", "token"},
+			exact:  "code token",
+			expect: true,
+		},
+		{
+			args:   Arguments{"code", "token"},
+			exact:  "token code",
+			expect: false,
+		},
+		{
+			args:   Arguments{"code", "token"},
+			exact:  "code  token",
+			expect: false,
+		},
+		{
+			args:   Arguments{"code", "token"},
+			exact:  "code token id_token",
+			expect: false,
+		},
+		{
+			args:   Arguments{"code", "token"},
+			exact:  "codetoken",
+			expect: false,
+		},
 	} {
 		assert.Equal(t, c.expect, c.args.Exact(c.exact), "%d", k)
 		t.Logf("Passed test case %d", k)
@@ -141,8 +172,172 @@ func TestArgumentsMatches(t *testing.T) {
 			is:     []string{"baz"},
 			expect: false,
 		},
+		{
+			args:   Arguments{"foo", "bar"},
+			is:     []string{"bar", "foo"},
+			expect: true,
+		},
+		{
+			args:   Arguments{"foo", "foo"},
+			is:     []string{"foo", "foo"},
+			expect: false,
+		},
+		{
+			args:   Arguments{},
+			is:     []string{},
+			expect: true,
+		},
 	} {
 		assert.Equal(t, c.expect, c.args.Matches(c.is...), "%d", k)
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestScopeArgumentsJSON(t *testing.T) {
+	for k, c := range []struct {
+		args   ScopeArguments
+		json   string
+		decode ScopeArguments
+	}{
+		{args: ScopeArguments{"openid", "offline"}, json: `"openid offline"`, decode: ScopeArguments{"openid", "offline"}},
+		{args: ScopeArguments{"openid"}, json: `"openid"`, decode: ScopeArguments{"openid"}},
+		{args: ScopeArguments{}, json: `""`, decode: ScopeArguments{}},
+		{args: nil, json: `""`, decode: ScopeArguments{}},
+	} {
+		out, err := json.Marshal(c.args)
+		assert.Nil(t, err, "%d: %s", k, err)
+		assert.Equal(t, c.json, string(out), "%d", k)
+
+		var decoded ScopeArguments
+		assert.Nil(t, json.Unmarshal(out, &decoded), "%d", k)
+		assert.Equal(t, c.decode, decoded, "%d", k)
+	}
+
+	var decoded ScopeArguments
+	assert.Nil(t, json.Unmarshal([]byte(`" openid  offline "`), &decoded))
+	assert.Equal(t, ScopeArguments{"openid", "offline"}, decoded)
+
+	// Scopes stored as JSON arrays can still be decoded.
+	assert.Nil(t, json.Unmarshal([]byte(`["openid","offline"]`), &decoded))
+	assert.Equal(t, ScopeArguments{"openid", "offline"}, decoded)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`123`), &decoded))
+}
+
+func TestArgumentsJSONAreArrays(t *testing.T) {
+	out, err := json.Marshal(Arguments{"code", "id_token"})
+	assert.Nil(t, err)
+	assert.Equal(t, `["code","id_token"]`, string(out))
+
+	out, err = json.Marshal(&AuthorizeRequest{ResponseTypes: Arguments{"code"}, Request: Request{Scopes: ScopeArguments{"openid"}}})
+	assert.Nil(t, err)
+
+	var fields map[string]interface{}
+	assert.Nil(t, json.Unmarshal(out, &fields))
+	assert.Equal(t, []interface{}{"code"}, fields["responseTypes"])
+	assert.Equal(t, "openid", fields["scopes"])
+}
+
+func TestRequestScopesAreSpaceDelimitedInJSON(t *testing.T) {
+	out, err := json.Marshal(&Request{Scopes: ScopeArguments{"openid", "offline"}, GrantedScopes: ScopeArguments{"openid"}})
+	assert.Nil(t, err)
+
+	var fields map[string]interface{}
+	assert.Nil(t, json.Unmarshal(out, &fields))
+	assert.Equal(t, "openid offline", fields["scopes"])
+	assert.Equal(t, "openid", fields["grantedScopes"])
+}
+
+func TestArgumentsAddAndRemoveDoNotMutate(t *testing.T) {
+	// A list with spare capacity, as append would write into it.
+	shared := make(Arguments, 2, 4)
+	copy(shared, []string{"foo", "bar"})
+
+	a := shared.Add("baz")
+	b := shared.Add("zab")
+	assert.Equal(t, Arguments{"foo", "bar", "baz"}, a)
+	assert.Equal(t, Arguments{"foo", "bar", "zab"}, b)
+	assert.Equal(t, Arguments{"foo", "bar"}, shared)
+
+	assert.Equal(t, Arguments{"bar"}, shared.Remove("foo"))
+	assert.Equal(t, Arguments{}, shared.Remove("foo", "bar"))
+	assert.Equal(t, Arguments{"foo", "bar"}, shared)
+	assert.Equal(t, Arguments{"foo"}, Arguments(nil).Add("foo"))
+}
+
+func TestArgumentsIntersectionAndDifference(t *testing.T) {
+	for k, c := range []struct {
+		args         Arguments
+		other        Arguments
+		intersection Arguments
+		difference   Arguments
+	}{
+		{
+			args:         Arguments{"foo", "bar", "baz"},
+			other:        Arguments{"baz", "foo"},
+			intersection: Arguments{"foo", "baz"},
+			difference:   Arguments{"bar"},
+		},
+		{
+			args:         Arguments{"foo", "bar"},
+			other:        Arguments{},
+			intersection: Arguments{},
+			difference:   Arguments{"foo", "bar"},
+		},
+		{
+			args:         Arguments{},
+			other:        Arguments{"foo"},
+			intersection: Arguments{},
+			difference:   Arguments{},
+		},
+		{
+			args:         Arguments{"foo", "foo", "bar", "bar"},
+			other:        Arguments{"foo"},
+			intersection: Arguments{"foo"},
+			difference:   Arguments{"bar"},
+		},
+		{
+			args:         Arguments{"foo.read"},
+			other:        Arguments{"foo"},
+			intersection: Arguments{},
+			difference:   Arguments{"foo.read"},
+		},
+	} {
+		shared := append(Arguments{}, c.args...)
+		assert.Equal(t, c.intersection, shared.Intersection(c.other), "%d", k)
+		assert.Equal(t, c.difference, shared.Difference(c.other), "%d", k)
+		assert.Equal(t, c.args, shared, "%d", k)
+	}
+}
+
+func TestArgumentsMatchingDoesNotAllocate(t *testing.T) {
+	args := Arguments{"openid", "offline", "photos.read", "photos.write", "profile"}
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		args.Matches("profile", "photos.write", "photos.read", "offline", "openid")
+		args.Has("photos.read", "openid")
+		args.Exact("openid offline photos.read photos.write profile")
+	}))
+}
+
+var benchmarkArguments = Arguments{"openid", "offline", "photos.read", "photos.write", "profile"}
+
+func BenchmarkArgumentsMatches(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkArguments.Matches("profile", "photos.write", "photos.read", "offline", "openid")
+	}
+}
+
+func BenchmarkArgumentsHas(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkArguments.Has("photos.read", "openid")
+	}
+}
+
+func BenchmarkArgumentsExact(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkArguments.Exact("openid offline photos.read photos.write profile")
+	}
+}
@@ -0,0 +1,100 @@
+package fosite
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+)
+
+// AtomicProvider is an OAuth2Provider which delegates all calls to an OAuth2Provider that can be replaced at
+// any time, for example to reload secrets or lifespans. Swapping the provider is safe while requests are served:
+// each call is handled by exactly one configuration snapshot.
+type AtomicProvider struct {
+	provider atomic.Value
+}
+
+type providerSnapshot struct {
+	OAuth2Provider
+}
+
+// NewAtomicProvider returns an AtomicProvider delegating to provider.
+func NewAtomicProvider(provider OAuth2Provider) *AtomicProvider {
+	a := new(AtomicProvider)
+	a.Store(provider)
+	return a
+}
+
+// Store replaces the provider. Calls which are already running finish using the previous provider.
+func (a *AtomicProvider) Store(provider OAuth2Provider) {
+	a.provider.Store(providerSnapshot{provider})
+}
+
+// Load returns the current provider.
+func (a *AtomicProvider) Load() OAuth2Provider {
+	return a.provider.Load().(providerSnapshot).OAuth2Provider
+}
+
+func (a *AtomicProvider) NewAuthorizeRequest(ctx context.Context, req *http.Request) (AuthorizeRequester, error) {
+	return a.Load().NewAuthorizeRequest(ctx, req)
+}
+
+func (a *AtomicProvider) NewAuthorizeResponse(ctx context.Context, req *http.Request, requester AuthorizeRequester, session interface{}) (AuthorizeResponder, error) {
+	return a.Load().NewAuthorizeResponse(ctx, req, requester, session)
+}
+
+func (a *AtomicProvider) WriteAuthorizeError(rw http.ResponseWriter, requester AuthorizeRequester, err error) {
+	a.Load().WriteAuthorizeError(rw, requester, err)
+}
+
+func (a *AtomicProvider) WriteAuthorizeResponse(rw http.ResponseWriter, requester AuthorizeRequester, responder AuthorizeResponder) {
+	a.Load().WriteAuthorizeResponse(rw, requester, responder)
+}
+
+func (a *AtomicProvider) NewAccessRequest(ctx context.Context, req *http.Request, session interface{}) (AccessRequester, error) {
+	return a.Load().NewAccessRequest(ctx, req, session)
+}
+
+func (a *AtomicProvider) NewAccessResponse(ctx context.Context, req *http.Request, requester AccessRequester) (AccessResponder, error) {
+	return a.Load().NewAccessResponse(ctx, req, requester)
+}
+
+func (a *AtomicProvider) WriteAccessError(rw http.ResponseWriter, requester AccessRequester, err error) {
+	a.Load().WriteAccessError(rw, requester, err)
+}
+
+func (a *AtomicProvider) WriteAccessResponse(rw http.ResponseWriter, requester AccessRequester, responder AccessResponder) {
+	a.Load().WriteAccessResponse(rw, requester, responder)
+}
+
+func (a *AtomicProvider) ValidateRequestAuthorization(ctx context.Context, req *http.Request, session interface{}, scope ...string) (AccessRequester, error) {
+	return a.Load().ValidateRequestAuthorization(ctx, req, session, scope...)
+}
+
+func (a *AtomicProvider) ValidateToken(ctx context.Context, token string, session interface{}, audience string, scope ...string) (AccessRequester, error) {
+	return a.Load().ValidateToken(ctx, token, session, audience, scope...)
+}
+
+func (a *AtomicProvider) NewIntrospectionRequest(ctx context.Context, req *http.Request, session interface{}) (IntrospectionResponder, error) {
+	return a.Load().NewIntrospectionRequest(ctx, req, session)
+}
+
+func (a *AtomicProvider) WriteIntrospectionResponse(rw http.ResponseWriter, responder IntrospectionResponder) {
+	a.Load().WriteIntrospectionResponse(rw, responder)
+}
+
+func (a *AtomicProvider) WriteIntrospectionError(rw http.ResponseWriter, err error) {
+	a.Load().WriteIntrospectionError(rw, err)
+}
+
+func (a *AtomicProvider) NewRevocationRequest(ctx context.Context, req *http.Request) error {
+	return a.Load().NewRevocationRequest(ctx, req)
+}
+
+func (a *AtomicProvider) WriteRevocationResponse(rw http.ResponseWriter, err error) {
+	a.Load().WriteRevocationResponse(rw, err)
+}
+
+func (a *AtomicProvider) GetMandatoryScope() string {
+	return a.Load().GetMandatoryScope()
+}
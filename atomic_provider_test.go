@@ -0,0 +1,92 @@
+package fosite_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/client"
+	"github.com/ory-am/fosite/handler/core/strategy"
+	"github.com/ory-am/fosite/hash"
+	"github.com/ory-am/fosite/token/hmac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This test is meant to be run with -race. The store and strategy are real instances shared by all providers, so
+// that races in them are not hidden by the locks of mocks.
+func TestAtomicProviderReloadsConcurrently(t *testing.T) {
+	hasher := &hash.BCrypt{WorkFactor: 4}
+	secret, err := hasher.Hash([]byte("bar"))
+	require.Nil(t, err)
+
+	s := store.NewStore()
+	s.Clients["foo"] = &DefaultClient{
+		ID:            "foo",
+		Secret:        secret,
+		GrantTypes:    []string{"client_credentials"},
+		GrantedScopes: []string{"fosite"},
+	}
+	accessTokenStrategy := &strategy.HMACSHAStrategy{
+		Enigma: &hmac.HMACStrategy{GlobalSecret: []byte("some-super-cool-secret-that-nobody-knows")},
+	}
+
+	factory := func(lifespan time.Duration) OAuth2Provider {
+		f := NewFosite(s)
+		f.Hasher = hasher
+		f.TokenEndpointHandlers.Append(&client.ClientCredentialsGrantHandler{
+			HandleHelper: &core.HandleHelper{
+				AccessTokenStrategy: accessTokenStrategy,
+				AccessTokenStorage:  s,
+				AccessTokenLifespan: lifespan,
+			},
+		})
+		return f
+	}
+
+	provider := NewAtomicProvider(factory(time.Hour))
+	done := make(chan struct{})
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				provider.Store(factory(time.Hour * time.Duration(1+i%2)))
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				form := url.Values{"grant_type": {"client_credentials"}, "scope": {"fosite"}}
+				req, _ := http.NewRequest("POST", "/token", strings.NewReader(form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				req.SetBasicAuth("foo", "bar")
+
+				ar, err := provider.NewAccessRequest(nil, req, struct{}{})
+				if !assert.Nil(t, err, "%s", err) {
+					return
+				}
+
+				resp, err := provider.NewAccessResponse(nil, req, ar)
+				if !assert.Nil(t, err, "%s", err) {
+					return
+				}
+				assert.Contains(t, []interface{}{int64(3600), int64(7200)}, resp.ToMap()["expires_in"])
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+}
@@ -0,0 +1,87 @@
+package fosite
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// AuditEventType is the type of an AuditEvent.
+type AuditEventType string
+
+const (
+	// AuditTokenIssued is emitted by NewAccessResponse once the token endpoint issued, or failed to issue, tokens.
+	// Refreshed tokens are issued using the refresh_token grant type. NewAuthorizeResponse emits it for tokens
+	// issued at the authorize endpoint using the implicit grant type.
+	AuditTokenIssued AuditEventType = "token_issued"
+
+	// AuditTokenRevoked is emitted by NewRevocationRequest.
+	AuditTokenRevoked AuditEventType = "token_revoked"
+
+	// AuditTokenIntrospected is emitted whenever a token is introspected, the outcome tells whether it is active.
+	AuditTokenIntrospected AuditEventType = "token_introspected"
+
+	// AuditAuthFailed is emitted if a client failed to authenticate at the token, revocation or introspection
+	// endpoint.
+	AuditAuthFailed AuditEventType = "auth_failed"
+)
+
+// AuditEvent describes an event relevant to an audit trail. Fields which are unknown when the event is emitted
+// are empty, e.g. the subject if the session does not implement IntrospectionSession.
+type AuditEvent struct {
+	Type      AuditEventType
+	Time      time.Time
+	ClientID  string
+	Subject   string
+	GrantType string
+	Scopes    Arguments
+
+	// Success is the outcome of the event. Err is the reason if the event failed, if known.
+	Success bool
+	Err     error
+}
+
+// AuditSink receives the audit events emitted by Fosite, e.g. to forward them to a SIEM. Emit is called
+// synchronously while the request is served and must therefore not block.
+type AuditSink interface {
+	Emit(ctx context.Context, event *AuditEvent)
+}
+
+// NoopAuditSink discards all audit events.
+type NoopAuditSink struct{}
+
+func (NoopAuditSink) Emit(_ context.Context, _ *AuditEvent) {}
+
+// GetAuditSink returns the AuditSink or NoopAuditSink if none is set.
+func (f *Fosite) GetAuditSink() AuditSink {
+	if f.AuditSink == nil {
+		return NoopAuditSink{}
+	}
+	return f.AuditSink
+}
+
+// emitAuditEvent emits an event of type t about requester, which may be nil.
+func (f *Fosite) emitAuditEvent(ctx context.Context, t AuditEventType, requester Requester, err error) {
+	if f.AuditSink == nil {
+		// Saves looking up the details of events which are discarded anyway.
+		return
+	}
+
+	event := &AuditEvent{Type: t, Time: time.Now(), Success: err == nil, Err: err}
+	if requester != nil {
+		if client := requester.GetClient(); client != nil {
+			event.ClientID = client.GetID()
+		}
+		if session, ok := requester.GetSession().(IntrospectionSession); ok {
+			event.Subject = session.GetSubject()
+		}
+		event.Scopes = requester.GetGrantedScopes()
+	}
+	if ar, ok := requester.(AccessRequester); ok {
+		event.GrantType = strings.Join(ar.GetGrantTypes(), " ")
+	} else if _, ok := requester.(AuthorizeRequester); ok {
+		event.GrantType = "implicit"
+	}
+	f.GetAuditSink().Emit(ctx, event)
+}
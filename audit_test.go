@@ -0,0 +1,227 @@
+package fosite_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type recordingAuditSink struct {
+	events []*AuditEvent
+}
+
+func (s *recordingAuditSink) Emit(_ context.Context, event *AuditEvent) {
+	s.events = append(s.events, event)
+}
+
+type auditSession struct {
+	subject string
+}
+
+func (s *auditSession) GetSubject() string                             { return s.subject }
+func (s *auditSession) GetAuthTime() time.Time                         { return time.Time{} }
+func (s *auditSession) GetAuthenticationContextClassReference() string { return "" }
+
+func TestNoopAuditSinkIsDefault(t *testing.T) {
+	assert.Equal(t, NoopAuditSink{}, (&Fosite{}).GetAuditSink())
+	sink := &recordingAuditSink{}
+	assert.Equal(t, sink, (&Fosite{AuditSink: sink}).GetAuditSink())
+}
+
+func TestAuditEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	revocation := internal.NewMockRevocationHandler(ctrl)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	tokenHandler := internal.NewMockTokenEndpointHandler(ctrl)
+	authorizeHandler := internal.NewMockAuthorizeEndpointHandler(ctrl)
+	defer ctrl.Finish()
+
+	sink := &recordingAuditSink{}
+	client := &DefaultClient{ID: "foo", Secret: []byte("foo")}
+	f := &Fosite{
+		Store:                 store,
+		Hasher:                hasher,
+		AuditSink:             sink,
+		RevocationHandlers:    RevocationHandlers{revocation},
+		TokenIntrospectors:    TokenIntrospectors{introspector},
+		TokenEndpointHandlers: TokenEndpointHandlers{tokenHandler},
+
+		AuthorizeEndpointHandlers: AuthorizeEndpointHandlers{authorizeHandler},
+	}
+	authorize := func(responseType string) func() error {
+		return func() error {
+			ar := NewAuthorizeRequest()
+			ar.Client = client
+			ar.ResponseTypes = Arguments{responseType}
+			ar.GrantScope("offline")
+			_, err := f.NewAuthorizeResponse(nil, nil, ar, &auditSession{subject: "peter"})
+			return err
+		}
+	}
+	request := func(form url.Values) *http.Request {
+		return &http.Request{Method: "POST", Header: http.Header{"Authorization": {basicAuth("foo", "bar")}}, PostForm: form, Form: form}
+	}
+
+	for k, c := range []struct {
+		description string
+		setup       func()
+		run         func() error
+		expect      AuditEvent
+	}{
+		{
+			description: "should emit failed client authentications",
+			setup: func() {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(errors.New("mismatch"))
+			},
+			run: func() error {
+				return f.NewRevocationRequest(nil, request(url.Values{"token": {"some-token"}}))
+			},
+			expect: AuditEvent{Type: AuditAuthFailed, ClientID: "foo", Err: ErrInvalidClient},
+		},
+		{
+			description: "should emit revoked tokens",
+			setup: func() {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+				revocation.EXPECT().RevokeToken(nil, "some-token", "", client).Return(nil)
+			},
+			run: func() error {
+				return f.NewRevocationRequest(nil, request(url.Values{"token": {"some-token"}}))
+			},
+			expect: AuditEvent{Type: AuditTokenRevoked, ClientID: "foo", Success: true},
+		},
+		{
+			description: "should emit failed revocations",
+			setup: func() {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+				revocation.EXPECT().RevokeToken(nil, "some-token", "", client).Return(ErrUnauthorizedClient)
+			},
+			run: func() error {
+				return f.NewRevocationRequest(nil, request(url.Values{"token": {"some-token"}}))
+			},
+			expect: AuditEvent{Type: AuditTokenRevoked, ClientID: "foo", Err: ErrUnauthorizedClient},
+		},
+		{
+			description: "should emit the details of active tokens",
+			setup: func() {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+				introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Do(func(_ context.Context, _ string, ar AccessRequester) {
+					ar.(*AccessRequest).Client = &DefaultClient{ID: "bar"}
+					ar.SetSession(&auditSession{subject: "peter"})
+					ar.GrantScope("offline")
+				}).Return(nil)
+			},
+			run: func() error {
+				_, err := f.NewIntrospectionRequest(nil, request(url.Values{"token": {"some-token"}}), nil)
+				return err
+			},
+			expect: AuditEvent{Type: AuditTokenIntrospected, ClientID: "bar", Subject: "peter", Scopes: Arguments{"offline"}, Success: true},
+		},
+		{
+			description: "should not disclose inactive tokens",
+			setup: func() {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+				introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(ErrRequestUnauthorized)
+			},
+			run: func() error {
+				_, err := f.NewIntrospectionRequest(nil, request(url.Values{"token": {"some-token"}}), nil)
+				return err
+			},
+			expect: AuditEvent{Type: AuditTokenIntrospected},
+		},
+		{
+			description: "should emit rejected token requests",
+			setup: func() {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+				tokenHandler.EXPECT().HandleTokenEndpointRequest(nil, gomock.Any(), gomock.Any()).Return(ErrInvalidGrant)
+			},
+			run: func() error {
+				_, err := f.NewAccessRequest(nil, request(url.Values{"grant_type": {"authorization_code"}}), &auditSession{})
+				return err
+			},
+			expect: AuditEvent{Type: AuditTokenIssued, ClientID: "foo", GrantType: "authorization_code", Err: ErrInvalidGrant},
+		},
+		{
+			description: "should emit issued tokens",
+			setup: func() {
+				tokenHandler.EXPECT().PopulateTokenEndpointResponse(nil, gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ *http.Request, _ AccessRequester, resp AccessResponder) {
+					resp.SetAccessToken("some-token")
+					resp.SetTokenType("bearer")
+				}).Return(nil)
+			},
+			run: func() error {
+				ar := NewAccessRequest(&auditSession{subject: "peter"})
+				ar.Client = client
+				ar.GrantTypes = Arguments{"refresh_token"}
+				ar.GrantScope("offline")
+				_, err := f.NewAccessResponse(nil, nil, ar)
+				return err
+			},
+			expect: AuditEvent{Type: AuditTokenIssued, ClientID: "foo", Subject: "peter", GrantType: "refresh_token", Scopes: Arguments{"offline"}, Success: true},
+		},
+		{
+			description: "should emit tokens issued by the implicit grant",
+			setup: func() {
+				authorizeHandler.EXPECT().HandleAuthorizeEndpointRequest(nil, nil, gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) {
+					resp.AddFragment("access_token", "some-token")
+					ar.SetResponseTypeHandled("token")
+				}).Return(nil)
+			},
+			run:    authorize("token"),
+			expect: AuditEvent{Type: AuditTokenIssued, ClientID: "foo", Subject: "peter", GrantType: "implicit", Scopes: Arguments{"offline"}, Success: true},
+		},
+		{
+			description: "should emit failed implicit grants",
+			setup: func() {
+				authorizeHandler.EXPECT().HandleAuthorizeEndpointRequest(nil, nil, gomock.Any(), gomock.Any()).Return(ErrInvalidGrant)
+			},
+			run:    authorize("id_token"),
+			expect: AuditEvent{Type: AuditTokenIssued, ClientID: "foo", Subject: "peter", GrantType: "implicit", Scopes: Arguments{"offline"}, Err: ErrInvalidGrant},
+		},
+	} {
+		sink.events = nil
+		c.setup()
+		err := c.run()
+		require.Len(t, sink.events, 1, "(%d) %s", k, c.description)
+
+		event := sink.events[0]
+		assert.Equal(t, c.expect.Type, event.Type, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expect.ClientID, event.ClientID, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expect.Subject, event.Subject, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expect.GrantType, event.GrantType, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expect.Scopes, event.Scopes, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expect.Success, event.Success, "(%d) %s", k, c.description)
+		assert.WithinDuration(t, time.Now(), event.Time, time.Minute, "(%d) %s", k, c.description)
+		if c.expect.Err != nil {
+			assert.True(t, errors.Is(c.expect.Err, event.Err), "(%d) %s: %s", k, c.description, event.Err)
+			assert.True(t, errors.Is(c.expect.Err, err), "(%d) %s: %s", k, c.description, err)
+		} else {
+			assert.Nil(t, event.Err, "(%d) %s", k, c.description)
+		}
+	}
+
+	// Codes are audited once they are exchanged at the token endpoint.
+	sink.events = nil
+	authorizeHandler.EXPECT().HandleAuthorizeEndpointRequest(nil, nil, gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) {
+		resp.AddQuery("code", "some-code")
+		ar.SetResponseTypeHandled("code")
+	}).Return(nil)
+	require.Nil(t, authorize("code")())
+	assert.Empty(t, sink.events)
+}
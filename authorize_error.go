@@ -1,33 +1,42 @@
 package fosite
 
 import (
-	"encoding/json"
 	"net/http"
+	"net/url"
 )
 
+// WriteAuthorizeError only redirects the error to the redirect URI of ar if it was validated against the
+// redirect URIs registered by the client. Errors of requests which failed before, e.g. because of an unknown
+// client or an unregistered redirect_uri, are written to the user-agent as JSON instead, see
+// https://tools.ietf.org/html/rfc6749#section-4.1.2.1
+//
+// Like WriteAuthorizeResponse, the error is delivered using the response mode requested by ar.
 func (c *Fosite) WriteAuthorizeError(rw http.ResponseWriter, ar AuthorizeRequester, err error) {
-	rfcerr := ErrorToRFC6749Error(err)
+	rfcerr := c.toRFC6749Error(ar, err)
 
-	if !ar.IsRedirectURIValid() {
-		js, err := json.MarshalIndent(rfcerr, "", "\t")
-		if err != nil {
-			http.Error(rw, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		rw.Header().Set("Content-Type", "application/json")
-		rw.WriteHeader(rfcerr.StatusCode)
-		rw.Write(js)
+	if !c.isRedirectURIValid(ar) {
+		writeJSON(rw, rfcerr.StatusCode, rfcerr)
 		return
 	}
 
 	redirectURI := ar.GetRedirectURI()
-	query := redirectURI.Query()
-	query.Add("error", rfcerr.Name)
-	query.Add("error_description", rfcerr.Description)
-	query.Add("state", ar.GetState())
-	redirectURI.RawQuery = query.Encode()
+	parameters := url.Values{}
+	parameters.Add("error", rfcerr.Name)
+	parameters.Add("error_description", rfcerr.Description)
+	parameters.Add("state", ar.GetState())
+
+	switch ar.GetResponseMode() {
+	case ResponseModeFormPost:
+		writeFormPost(rw, redirectURI, parameters)
+		return
+	case ResponseModeFragment:
+		redirectURI.Fragment = parameters.Encode()
+	default:
+		redirectURI.RawQuery = mergeValues(redirectURI.Query(), parameters).Encode()
+	}
 
-	rw.Header().Add("Location", redirectURI.String())
+	wh := rw.Header()
+	setNoCacheHeaders(wh)
+	wh.Add("Location", redirectURI.String())
 	rw.WriteHeader(http.StatusFound)
 }
@@ -2,6 +2,7 @@ package fosite_test
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -9,6 +10,7 @@ import (
 	. "github.com/ory-am/fosite"
 	. "github.com/ory-am/fosite/internal"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
 )
 
 // Test for
@@ -30,6 +32,7 @@ func TestWriteAuthorizeError(t *testing.T) {
 	rw := NewMockResponseWriter(ctrl)
 	req := NewMockAuthorizeRequester(ctrl)
 	defer ctrl.Finish()
+	req.EXPECT().GetResponseMode().Return("").AnyTimes()
 
 	var urls = []string{
 		"https://foobar.com/",
@@ -57,7 +60,7 @@ func TestWriteAuthorizeError(t *testing.T) {
 				rw.EXPECT().Write(gomock.Any())
 			},
 			checkHeader: func(k int) {
-				assert.Equal(t, "application/json", header.Get("Content-Type"), "%d", k)
+				assert.Equal(t, "application/json;charset=UTF-8", header.Get("Content-Type"), "%d", k)
 			},
 		},
 		{
@@ -94,7 +97,108 @@ func TestWriteAuthorizeError(t *testing.T) {
 		c.mock()
 		oauth2.WriteAuthorizeError(rw, req, c.err)
 		c.checkHeader(k)
+		assert.Equal(t, "no-store", header.Get("Cache-Control"), "%d", k)
+		assert.Equal(t, "no-cache", header.Get("Pragma"), "%d", k)
 		header = http.Header{}
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestWriteAuthorizeErrorOnlyRedirectsToValidatedRedirectURIs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	defer ctrl.Finish()
+
+	store.EXPECT().GetClient("foo").Return(&DefaultClient{ID: "foo", RedirectURIs: []string{"https://foobar.com/cb"}}, nil).AnyTimes()
+	store.EXPECT().GetClient(gomock.Any()).Return(nil, ErrNotFound).AnyTimes()
+	f := &Fosite{Store: store}
+
+	for k, c := range []struct {
+		description    string
+		query          url.Values
+		expectRedirect string
+	}{
+		{
+			description: "should not redirect because the client is unknown",
+			query:       url.Values{"client_id": {"bar"}, "redirect_uri": {"https://evil.com/cb"}, "response_type": {"code"}, "state": {"strong-state"}},
+		},
+		{
+			description: "should not redirect because the redirect_uri is not registered",
+			query:       url.Values{"client_id": {"foo"}, "redirect_uri": {"https://evil.com/cb"}, "response_type": {"code"}, "state": {"strong-state"}},
+		},
+		{
+			description: "should not redirect because the redirect_uri is malformed",
+			query:       url.Values{"client_id": {"foo"}, "redirect_uri": {"https://foobar.com/cb#fragment"}, "response_type": {"code"}, "state": {"strong-state"}},
+		},
+		{
+			description:    "should redirect because the redirect_uri was validated before the scope failed",
+			query:          url.Values{"client_id": {"foo"}, "redirect_uri": {"https://foobar.com/cb"}, "response_type": {"code"}, "state": {"strong-state"}, "scope": {"baz"}},
+			expectRedirect: "https://foobar.com/cb",
+		},
+		{
+			description:    "should redirect to the only registered redirect_uri if none was given",
+			query:          url.Values{"client_id": {"foo"}, "response_type": {"code"}, "state": {"strong-state"}, "scope": {"baz"}},
+			expectRedirect: "https://foobar.com/cb",
+		},
+	} {
+		ar, err := f.NewAuthorizeRequest(context.Background(), &http.Request{Header: http.Header{}, URL: &url.URL{RawQuery: c.query.Encode()}})
+		assert.NotNil(t, err, "(%d) %s", k, c.description)
+
+		rw := httptest.NewRecorder()
+		f.WriteAuthorizeError(rw, ar, err)
+
+		location := rw.Header().Get("Location")
+		if c.expectRedirect == "" {
+			assert.Empty(t, location, "(%d) %s", k, c.description)
+			assert.NotEqual(t, http.StatusFound, rw.Code, "(%d) %s", k, c.description)
+			continue
+		}
+
+		assert.Equal(t, http.StatusFound, rw.Code, "(%d) %s", k, c.description)
+		redirect, _ := url.Parse(location)
+		assert.Equal(t, c.expectRedirect, redirect.Scheme+"://"+redirect.Host+redirect.Path, "(%d) %s", k, c.description)
+		assert.NotEmpty(t, redirect.Query().Get("error"), "(%d) %s", k, c.description)
+	}
+}
+
+func TestWriteAuthorizeErrorHonorsTheResponseMode(t *testing.T) {
+	for k, c := range []struct {
+		mode           string
+		expectLocation string
+		expectForm     string
+	}{
+		{expectLocation: "https://foobar.com/cb?error=invalid_scope&foo=bar&state=foostate"},
+		{mode: ResponseModeQuery, expectLocation: "https://foobar.com/cb?error=invalid_scope&foo=bar&state=foostate"},
+		{mode: ResponseModeFragment, expectLocation: "https://foobar.com/cb?foo=bar#error=invalid_scope&state=foostate"},
+		{mode: ResponseModeFormPost, expectForm: `<input type="hidden" name="error" value="invalid_scope"/>`},
+	} {
+		ar := NewAuthorizeRequest()
+		ar.RedirectURI, _ = url.Parse("https://foobar.com/cb?foo=bar")
+		ar.State = "foostate"
+		ar.ResponseMode = c.mode
+		ar.Client = &DefaultClient{RedirectURIs: []string{"https://foobar.com/cb?foo=bar"}}
+
+		rw := httptest.NewRecorder()
+		(&Fosite{}).WriteAuthorizeError(rw, ar, ErrInvalidScope)
+		if c.expectForm != "" {
+			assert.Equal(t, http.StatusOK, rw.Code, "%d", k)
+			assert.Contains(t, rw.Body.String(), c.expectForm, "%d", k)
+			assert.Contains(t, rw.Body.String(), `<input type="hidden" name="state" value="foostate"/>`, "%d", k)
+			continue
+		}
+
+		location, err := url.Parse(rw.Header().Get("Location"))
+		assert.Nil(t, err, "%d", k)
+		location.RawQuery = withoutDescription(location.Query()).Encode()
+		if f, err := url.ParseQuery(location.Fragment); err == nil && location.Fragment != "" {
+			location.Fragment = withoutDescription(f).Encode()
+		}
+		assert.Equal(t, http.StatusFound, rw.Code, "%d", k)
+		assert.Equal(t, c.expectLocation, location.String(), "%d", k)
+	}
+}
+
+func withoutDescription(values url.Values) url.Values {
+	values.Del("error_description")
+	return values
+}
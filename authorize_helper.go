@@ -56,17 +56,34 @@ func GetRedirectURIFromRequestValues(values url.Values) (string, error) {
 //     particular end-user authorization and validates this redirect URI
 //     with the redirect URI passed to the token's endpoint, such an
 //     attack is detected (see Section 5.2.4.5).
+//
+// Redirect URIs using private-use URI schemes are only accepted for native clients, see
+// http://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
 func MatchRedirectURIWithClientRedirectURIs(rawurl string, client Client) (*url.URL, error) {
-	if rawurl == "" && len(client.GetRedirectURIs()) == 1 {
-		if redirectURIFromClient, err := url.Parse(client.GetRedirectURIs()[0]); err == nil && IsValidRedirectURI(redirectURIFromClient) {
-			// If no redirect_uri was given and the client has exactly one valid redirect_uri registered, use that instead
-			return redirectURIFromClient, nil
+	return matchRedirectURI(rawurl, client.GetRedirectURIs(), func(redirectURI *url.URL) bool {
+		return IsValidRedirectURI(redirectURI) && isAllowedForApplicationType(redirectURI, client)
+	})
+}
+
+// matchRedirectURI is MatchRedirectURIWithClientRedirectURIs matching against the registered URIs and using isValid
+// to validate the registered redirect_uri picked if rawurl is empty.
+func matchRedirectURI(rawurl string, registered []string, isValid func(*url.URL) bool) (*url.URL, error) {
+	switch {
+	case len(registered) == 0:
+		// If no redirect_uri was registered, there is nothing the given redirect_uri could be compared against.
+		return nil, errors.New(ErrInvalidRequest)
+	case rawurl == "" && len(registered) == 1:
+		// If no redirect_uri was given and the client has exactly one valid redirect_uri registered, use that instead
+		if parsed, err := url.Parse(registered[0]); err == nil && isValid(parsed) {
+			return parsed, nil
 		}
-	} else if rawurl != "" && StringInSlice(rawurl, client.GetRedirectURIs()) {
-		// If a redirect_uri was given and the clients knows it (simple string comparison!)
-		// return it.
-		if parsed, err := url.Parse(rawurl); err == nil && IsValidRedirectURI(parsed) {
-			// If no redirect_uri was given and the client has exactly one valid redirect_uri registered, use that instead
+	case rawurl == "":
+		// If multiple redirect_uris were registered, the client MUST include the redirect_uri parameter.
+		return nil, errors.New(ErrInvalidRequest)
+	case isRegisteredRedirectURI(rawurl, registered):
+		// If a redirect_uri was given and the clients knows it (simple string comparison, apart from the case of
+		// scheme and host!) return it.
+		if parsed, err := url.Parse(rawurl); err == nil && isValid(parsed) {
 			return parsed, nil
 		}
 	}
@@ -74,6 +91,43 @@ func MatchRedirectURIWithClientRedirectURIs(rawurl string, client Client) (*url.
 	return nil, errors.New(ErrInvalidRequest)
 }
 
+// isRegisteredRedirectURI checks if rawurl is one of the registered redirect URIs. Scheme and host are case
+// insensitive, see https://tools.ietf.org/html/rfc3986#section-6.2.2.1, everything else is compared as is. The path
+// and query are neither decoded nor cleaned, otherwise e.g. https://foo.com/cb/..%2Fadmin could be accepted for
+// https://foo.com/admin.
+func isRegisteredRedirectURI(rawurl string, registered []string) bool {
+	normalized := normalizeRedirectURI(rawurl)
+	for _, uri := range registered {
+		if rawurl == uri || normalized == normalizeRedirectURI(uri) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRedirectURI lowercases the scheme and host of rawurl. User information, port, path, query and fragment
+// are left untouched.
+func normalizeRedirectURI(rawurl string) string {
+	colon := strings.Index(rawurl, ":")
+	if colon < 0 {
+		return rawurl
+	}
+	scheme, rest := strings.ToLower(rawurl[:colon]), rawurl[colon+1:]
+	if !strings.HasPrefix(rest, "//") {
+		return scheme + ":" + rest
+	}
+
+	authority, rest := rest[2:], ""
+	if i := strings.IndexAny(authority, "/?#"); i >= 0 {
+		authority, rest = authority[:i], authority[i:]
+	}
+	userinfo := ""
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		userinfo, authority = authority[:i+1], authority[i+1:]
+	}
+	return scheme + "://" + userinfo + strings.ToLower(authority) + rest
+}
+
 // IsValidRedirectURI validates a redirect_uri as specified in:
 //
 // * https://tools.ietf.org/html/rfc6749#section-3.1.2
@@ -82,25 +136,81 @@ func MatchRedirectURIWithClientRedirectURIs(rawurl string, client Client) (*url.
 // * https://tools.ietf.org/html/rfc3986#section-4.3
 //   absolute-URI  = scheme ":" hier-part [ "?" query ]
 // * https://tools.ietf.org/html/rfc6819#section-5.1.1
+// * https://tools.ietf.org/html/rfc8252#section-7.1
 func IsValidRedirectURI(redirectURI *url.URL) bool {
+	if !isWellFormedRedirectURI(redirectURI) {
+		return false
+	}
+
+	// Redirect URIs using http are only accepted for loopback interfaces. Other schemes than http and https are
+	// private-use URI schemes of native apps, e.g. com.example.app:/oauth2redirect, which are only accepted for
+	// native clients by MatchRedirectURIWithClientRedirectURIs.
+	if redirectURI.Scheme == "http" && !isLocalhost(redirectURI) {
+		return false
+	}
+
+	return true
+}
+
+// isWellFormedRedirectURI checks that redirectURI is absolute and has no fragment, regardless of its scheme.
+func isWellFormedRedirectURI(redirectURI *url.URL) bool {
 	// We need to explicitly check for a scheme
 	if !govalidator.IsRequestURL(redirectURI.String()) {
 		return false
 	}
 
-	if redirectURI.Fragment != "" {
-		// "The endpoint URI MUST NOT include a fragment component."
+	// "The endpoint URI MUST NOT include a fragment component."
+	return redirectURI.Fragment == ""
+}
+
+// isAllowedForApplicationType checks that the scheme of redirectURI can be used by the client's kind of
+// application. Web clients are served by web servers and therefore can not receive redirects to private-use URI
+// schemes. Native clients may use private-use URI schemes, loopback interfaces and claimed https URIs.
+func isAllowedForApplicationType(redirectURI *url.URL, client Client) bool {
+	if client.GetApplicationType() == ApplicationTypeNative {
+		return true
+	}
+	return redirectURI.Scheme == "https" || redirectURI.Scheme == "http"
+}
+
+// isValidRedirectURI is IsValidRedirectURI for redirect URIs of client. It also accepts http redirect URIs of any
+// host if AllowInsecureRedirectURIs is set.
+func (c *Fosite) isValidRedirectURI(redirectURI *url.URL, client Client) bool {
+	if !isAllowedForApplicationType(redirectURI, client) {
 		return false
+	} else if c.AllowInsecureRedirectURIs {
+		return isWellFormedRedirectURI(redirectURI)
 	}
+	return IsValidRedirectURI(redirectURI)
+}
 
-	if redirectURI.Scheme != "https" && !isLocalhost(redirectURI) {
+// matchRedirectURI is MatchRedirectURIWithClientRedirectURIs honoring AllowInsecureRedirectURIs.
+func (c *Fosite) matchRedirectURI(rawurl string, client Client) (*url.URL, error) {
+	redirectURI, err := matchRedirectURI(rawurl, client.GetRedirectURIs(), func(redirectURI *url.URL) bool {
+		return c.isValidRedirectURI(redirectURI, client)
+	})
+	if err != nil {
+		return nil, err
+	} else if !c.isValidRedirectURI(redirectURI, client) {
+		return nil, errors.New(ErrInvalidRequest)
+	}
+	return redirectURI, nil
+}
+
+// isRedirectURIValid is AuthorizeRequester.IsRedirectURIValid honoring AllowInsecureRedirectURIs. It decides whether
+// errors may be sent to the redirect URI of ar.
+func (c *Fosite) isRedirectURIValid(ar AuthorizeRequester) bool {
+	if ar.IsRedirectURIValid() {
+		return true
+	} else if !c.AllowInsecureRedirectURIs || ar.GetRedirectURI() == nil || ar.GetClient() == nil {
 		return false
 	}
 
-	return true
+	_, err := c.matchRedirectURI(ar.GetRedirectURI().String(), ar.GetClient())
+	return err == nil
 }
 
 func isLocalhost(redirectURI *url.URL) bool {
-	host := strings.Split(redirectURI.Host, ":")[0]
+	host := strings.ToLower(strings.Split(redirectURI.Host, ":")[0])
 	return host == "localhost" || host == "127.0.0.1"
 }
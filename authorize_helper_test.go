@@ -101,6 +101,69 @@ func TestDoesClientWhiteListRedirect(t *testing.T) {
 			url:     "https://bar.com/cb123",
 			isError: true,
 		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{}},
+			url:     "",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{}},
+			url:     "https://bar.com/cb",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://bar.com/cb", "https://foo.com/cb"}},
+			url:     "",
+			isError: true,
+		},
+		{
+			client:   &DefaultClient{RedirectURIs: []string{"https://bar.com/cb", "https://foo.com/cb"}},
+			url:      "https://foo.com/cb",
+			isError:  false,
+			expected: "https://foo.com/cb",
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://bar.com/cb", "https://foo.com/cb"}},
+			url:     "https://foo.com/cb/",
+			isError: true,
+		},
+		{
+			client:   &DefaultClient{RedirectURIs: []string{"HTTPS://Foo.com/cb"}},
+			url:      "https://foo.com/cb",
+			isError:  false,
+			expected: "https://foo.com/cb",
+		},
+		{
+			client:   &DefaultClient{RedirectURIs: []string{"https://foo.com:8443/cb?foo=bar"}},
+			url:      "Https://FOO.COM:8443/cb?foo=bar",
+			isError:  false,
+			expected: "https://FOO.COM:8443/cb?foo=bar",
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://foo.com/cb"}},
+			url:     "https://foo.com/CB",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://foo.com/cb?foo=bar"}},
+			url:     "https://foo.com/cb?foo=BAR",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://foo.com/cb"}},
+			url:     "https://foo.com/c%62",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://foo.com/cb"}},
+			url:     "https://foo.com/admin/../cb",
+			isError: true,
+		},
+		{
+			client:  &DefaultClient{RedirectURIs: []string{"https://foo.com/cb"}},
+			url:     "https://Foo.com@bar.com/cb",
+			isError: true,
+		},
 	} {
 		redir, err := MatchRedirectURIWithClientRedirectURIs(c.url, c.client)
 		assert.Equal(t, c.isError, err != nil, "%d: %s", k, err)
@@ -111,3 +174,70 @@ func TestDoesClientWhiteListRedirect(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestIsValidRedirectURI(t *testing.T) {
+	for k, c := range []struct {
+		rawurl        string
+		expect        bool
+		expectDevMode bool
+	}{
+		{rawurl: "https://foo.bar/cb", expect: true, expectDevMode: true},
+		{rawurl: "http://localhost:1234/cb", expect: true, expectDevMode: true},
+		{rawurl: "http://127.0.0.1/cb", expect: true, expectDevMode: true},
+		{rawurl: "http://LocalHost/cb", expect: true, expectDevMode: true},
+		{rawurl: "http://foo.bar/cb", expect: false, expectDevMode: true},
+		{rawurl: "com.example.app:/oauth2redirect", expect: true, expectDevMode: true},
+		{rawurl: "https://foo.bar/cb#fragment", expect: false, expectDevMode: false},
+		{rawurl: "/cb", expect: false, expectDevMode: false},
+	} {
+		u, err := url.Parse(c.rawurl)
+		require.Nil(t, err, "case %d", k)
+		assert.Equal(t, c.expect, IsValidRedirectURI(u), "case %d: %s", k, c.rawurl)
+		native := &DefaultClient{ApplicationType: ApplicationTypeNative}
+		assert.Equal(t, c.expect, (&Fosite{}).isValidRedirectURI(u, native), "case %d: %s", k, c.rawurl)
+		assert.Equal(t, c.expectDevMode, (&Fosite{AllowInsecureRedirectURIs: true}).isValidRedirectURI(u, native), "case %d: %s", k, c.rawurl)
+	}
+}
+
+func TestMatchRedirectURIAllowsInsecureRedirectURIs(t *testing.T) {
+	client := &DefaultClient{RedirectURIs: []string{"http://foo.bar/cb"}}
+	ar := NewAuthorizeRequest()
+	ar.Client = client
+	ar.RedirectURI, _ = url.Parse("http://foo.bar/cb")
+
+	_, err := (&Fosite{}).matchRedirectURI("", client)
+	assert.NotNil(t, err)
+	assert.False(t, (&Fosite{}).isRedirectURIValid(ar))
+
+	redir, err := (&Fosite{AllowInsecureRedirectURIs: true}).matchRedirectURI("", client)
+	require.Nil(t, err)
+	assert.Equal(t, "http://foo.bar/cb", redir.String())
+	assert.True(t, (&Fosite{AllowInsecureRedirectURIs: true}).isRedirectURIValid(ar))
+}
+
+func TestRedirectURIDependsOnApplicationType(t *testing.T) {
+	for k, c := range []struct {
+		rawurl       string
+		expectWeb    bool
+		expectNative bool
+	}{
+		{rawurl: "https://foo.bar/cb", expectWeb: true, expectNative: true},
+		{rawurl: "http://localhost:1234/cb", expectWeb: true, expectNative: true},
+		{rawurl: "http://foo.bar/cb", expectWeb: false, expectNative: false},
+		{rawurl: "com.example.app:/oauth2redirect", expectWeb: false, expectNative: true},
+		{rawurl: "myapp://callback", expectWeb: false, expectNative: true},
+	} {
+		web := &DefaultClient{RedirectURIs: []string{c.rawurl}}
+		native := &DefaultClient{RedirectURIs: []string{c.rawurl}, ApplicationType: ApplicationTypeNative}
+
+		_, err := MatchRedirectURIWithClientRedirectURIs(c.rawurl, web)
+		assert.Equal(t, c.expectWeb, err == nil, "case %d: %s", k, c.rawurl)
+		_, err = (&Fosite{}).matchRedirectURI(c.rawurl, web)
+		assert.Equal(t, c.expectWeb, err == nil, "case %d: %s", k, c.rawurl)
+
+		_, err = MatchRedirectURIWithClientRedirectURIs(c.rawurl, native)
+		assert.Equal(t, c.expectNative, err == nil, "case %d: %s", k, c.rawurl)
+		_, err = (&Fosite{}).matchRedirectURI(c.rawurl, native)
+		assert.Equal(t, c.expectNative, err == nil, "case %d: %s", k, c.rawurl)
+	}
+}
@@ -9,6 +9,11 @@ type AuthorizeRequest struct {
 	ResponseTypes        Arguments `json:"responseTypes" gorethink:"responseTypes"`
 	RedirectURI          *url.URL  `json:"redirectUri" gorethink:"redirectUri"`
 	State                string    `json:"state" gorethink:"state"`
+	Display              string    `json:"display" gorethink:"display"`
+	ResponseMode         string    `json:"responseMode" gorethink:"responseMode"`
+	UILocales            Arguments `json:"uiLocales" gorethink:"uiLocales"`
+	ClaimsLocales        Arguments `json:"claimsLocales" gorethink:"claimsLocales"`
+	Prompt               Arguments `json:"prompt" gorethink:"prompt"`
 	HandledResponseTypes Arguments `json:"handledResponseTypes" gorethink:"handledResponseTypes"`
 
 	Request
@@ -18,6 +23,9 @@ func NewAuthorizeRequest() *AuthorizeRequest {
 	return &AuthorizeRequest{
 		ResponseTypes:        Arguments{},
 		RedirectURI:          &url.URL{},
+		UILocales:            Arguments{},
+		ClaimsLocales:        Arguments{},
+		Prompt:               Arguments{},
 		HandledResponseTypes: Arguments{},
 		Request:              *NewRequest(),
 	}
@@ -28,8 +36,11 @@ func (d *AuthorizeRequest) IsRedirectURIValid() bool {
 		return false
 	}
 
+	// An empty redirect URI was never validated. It would match the only redirect URI registered by the client,
+	// which is how a missing redirect_uri parameter is treated, but NewAuthorizeRequest always sets the redirect
+	// URI it picked.
 	raw := d.GetRedirectURI().String()
-	if d.GetClient() == nil {
+	if raw == "" || d.GetClient() == nil {
 		return false
 	}
 
@@ -48,12 +59,32 @@ func (d *AuthorizeRequest) GetState() string {
 	return d.State
 }
 
+func (d *AuthorizeRequest) GetDisplay() string {
+	return d.Display
+}
+
+func (d *AuthorizeRequest) GetResponseMode() string {
+	return d.ResponseMode
+}
+
+func (d *AuthorizeRequest) GetUILocales() Arguments {
+	return d.UILocales
+}
+
+func (d *AuthorizeRequest) GetClaimsLocales() Arguments {
+	return d.ClaimsLocales
+}
+
+func (d *AuthorizeRequest) GetPrompt() Arguments {
+	return d.Prompt
+}
+
 func (d *AuthorizeRequest) GetRedirectURI() *url.URL {
 	return d.RedirectURI
 }
 
 func (d *AuthorizeRequest) SetResponseTypeHandled(name string) {
-	d.HandledResponseTypes = append(d.HandledResponseTypes, name)
+	d.HandledResponseTypes = d.HandledResponseTypes.Add(name)
 }
 
 func (d *AuthorizeRequest) DidHandleAllResponseTypes() bool {
@@ -9,48 +9,82 @@ import (
 	"golang.org/x/net/context"
 )
 
+// authorizeRequestParameters are the authorize endpoint parameters defined by OAuth2, OpenID Connect and their
+// extensions. They are always accepted, even if Fosite.RejectUnknownRequestParameters is set.
+var authorizeRequestParameters = []string{
+	"response_type", "client_id", "redirect_uri", "scope", "state", "response_mode", "nonce", "display",
+	"prompt", "max_age", "ui_locales", "claims_locales", "id_token_hint", "login_hint", "acr_values",
+	"claims", "registration", "request", "request_uri", "code_challenge", "code_challenge_method",
+	NoRefreshTokenParameter,
+}
+
+// displayValues are the values of the display parameter defined by
+// http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+var displayValues = []string{"page", "popup", "touch", "wap"}
+
 func (c *Fosite) NewAuthorizeRequest(ctx context.Context, r *http.Request) (AuthorizeRequester, error) {
 	request := &AuthorizeRequest{
 		ResponseTypes:        Arguments{},
 		HandledResponseTypes: Arguments{},
 		Request: Request{
-			Scopes:      Arguments{},
+			Scopes:      ScopeArguments{},
 			RequestedAt: time.Now(),
 		},
 	}
 
-	if err := r.ParseForm(); err != nil {
-		return request, errors.New(ErrInvalidRequest)
+	if err := c.parseForm(r); err != nil {
+		return request, err
 	}
 
-	request.Form = r.Form
-	client, err := c.Store.GetClient(request.GetRequestForm().Get("client_id"))
+	request.Form = mergeForm(r)
+	client, err := c.getClient(ctx, request.GetRequestForm().Get("client_id"))
 	if err != nil {
 		return request, errors.New(ErrInvalidClient)
 	}
 	request.Client = client
 
-	// Fetch redirect URI from request
-	rawRedirURI, err := GetRedirectURIFromRequestValues(r.Form)
-	if err != nil {
-		return request, errors.New(ErrInvalidRequest)
+	if err := c.validateRequest(ctx, AuthorizeRequestEndpoint, r, client); err != nil {
+		return request, err
 	}
 
-	// Validate redirect uri
-	redirectURI, err := MatchRedirectURIWithClientRedirectURIs(rawRedirURI, client)
-	if err != nil {
-		return request, errors.New(ErrInvalidRequest)
-	} else if !IsValidRedirectURI(redirectURI) {
-		return request, errors.New(ErrInvalidRequest)
+	pushed := isPushedAuthorizationRequestURI(request.Form.Get("request_uri"))
+	if err := c.resolveRequestURI(ctx, request, client); err != nil {
+		return request, err
+	} else if err := c.resolveRequestObject(ctx, request, client, pushed); err != nil {
+		return request, err
+	}
+
+	// Unknown parameters are looked for after the request object was merged, it may contain parameters as well.
+	if c.RejectUnknownRequestParameters {
+		for key := range request.Form {
+			if !StringInSlice(key, authorizeRequestParameters) && !client.GetRequestParameters().Has(key) {
+				return request, errors.New(ErrInvalidRequest)
+			}
+		}
+	}
+
+	problems := &validationProblems{aggregate: c.AggregateValidationErrors}
+
+	// Fetch redirect URI from request
+	if rawRedirURI, err := GetRedirectURIFromRequestValues(request.Form); err != nil {
+		if err := problems.add("redirect_uri", errors.New(ErrInvalidRequest)); err != nil {
+			return request, err
+		}
+	} else if redirectURI, err := c.matchRedirectURI(rawRedirURI, client); err != nil {
+		// Validate redirect uri
+		if err := problems.add("redirect_uri", errors.New(ErrInvalidRequest)); err != nil {
+			return request, err
+		}
+	} else {
+		request.RedirectURI = redirectURI
 	}
-	request.RedirectURI = redirectURI
 
 	// https://tools.ietf.org/html/rfc6749#section-3.1.1
 	// Extension response types MAY contain a space-delimited (%x20) list of
 	// values, where the order of values does not matter (e.g., response
 	// type "a b" is the same as "b a").  The meaning of such composite
 	// response types is defined by their respective specifications.
-	request.ResponseTypes = removeEmpty(strings.Split(r.Form.Get("response_type"), " "))
+	request.ResponseTypes = removeEmpty(strings.Split(request.Form.Get("response_type"), " "))
 
 	// rfc6819 4.4.1.8.  Threat: CSRF Attack against redirect-uri
 	// The "state" parameter should be used to link the authorization
@@ -58,20 +92,108 @@ func (c *Fosite) NewAuthorizeRequest(ctx context.Context, r *http.Request) (Auth
 	//
 	// https://tools.ietf.org/html/rfc6819#section-4.4.1.8
 	// The "state" parameter should not	be guessable
-	state := r.Form.Get("state")
+	state := request.Form.Get("state")
 	if len(state) < MinParameterEntropy {
 		// We're assuming that using less then 8 characters for the state can not be considered "unguessable"
-		return request, errors.New(ErrInvalidState)
+		if err := problems.add("state", errors.New(ErrInvalidState)); err != nil {
+			return request, err
+		}
 	}
 	request.State = state
 
+	// The display parameter is passed on to the consent layer, fosite does not render the user interface itself.
+	display := request.Form.Get("display")
+	if display != "" && !StringInSlice(display, displayValues) {
+		if err := problems.add("display", errors.New(ErrInvalidRequest)); err != nil {
+			return request, err
+		}
+	} else {
+		request.Display = display
+	}
+
+	// The response mode is validated so that a client can not ask for a mode it did not register, it is honored by
+	// WriteAuthorizeResponse and WriteAuthorizeError.
+	responseMode := request.Form.Get("response_mode")
+	if err := validateResponseMode(responseMode, request.ResponseTypes, client); err != nil {
+		if err := problems.add("response_mode", err); err != nil {
+			return request, err
+		}
+	} else {
+		request.ResponseMode = responseMode
+	}
+
+	// Like display, ui_locales and claims_locales are only passed on. Fosite checks their syntax, but not whether
+	// the languages are supported.
+	if request.UILocales, err = parseLocales(request.Form.Get("ui_locales")); err != nil {
+		if err := problems.add("ui_locales", err); err != nil {
+			return request, err
+		}
+	}
+	if request.ClaimsLocales, err = parseLocales(request.Form.Get("claims_locales")); err != nil {
+		if err := problems.add("claims_locales", err); err != nil {
+			return request, err
+		}
+	}
+
+	// The claims parameter is evaluated when issuing ID tokens and by the UserInfo endpoint, malformed requests are
+	// rejected early.
+	if _, err := ParseClaimsRequest(request.Form.Get("claims")); err != nil {
+		if err := problems.add("claims", err); err != nil {
+			return request, err
+		}
+	}
+
+	request.Prompt = getPrompt(request.Form)
+	if err := validatePrompt(request.Prompt, client); err != nil {
+		if err := problems.add("prompt", err); err != nil {
+			return request, err
+		}
+	}
+
 	// Remove empty items from arrays
-	request.Scopes = removeEmpty(strings.Split(r.Form.Get("scope"), " "))
+	request.SetScopes(parseScopes(request.Form, client))
+
+	if err := c.validateClientScopes(client, request.GetScopes()); err != nil {
+		if err := problems.add("scope", err); err != nil {
+			return request, err
+		}
+	} else if !request.GetScopes().Has(c.GetMandatoryScope()) {
+		if err := problems.add("scope", errors.New(ErrInvalidScope)); err != nil {
+			return request, err
+		}
+	}
+
+	// The response types are validated last, the handlers need to know the scopes, e.g. to tell whether the
+	// request is an OpenID Connect request.
+	if err := c.validateResponseTypes(ctx, request); err != nil {
+		if err := problems.add("response_type", err); err != nil {
+			return request, err
+		}
+	}
 
-	if !request.Scopes.Has(c.GetMandatoryScope()) {
-		return request, errors.New(ErrInvalidScope)
+	if err := problems.err(); err != nil {
+		return request, err
 	}
 	request.GrantScope(c.GetMandatoryScope())
 
 	return request, nil
 }
+
+// validateResponseTypes rejects response types which the registered handlers do not support or which the handlers
+// would refuse to handle, see ResponseTypeHandler and AuthorizeEndpointValidator.
+func (c *Fosite) validateResponseTypes(ctx context.Context, request *AuthorizeRequest) error {
+	if len(request.ResponseTypes) == 0 {
+		return errors.New(ErrInvalidRequest)
+	} else if !c.AuthorizeEndpointHandlers.supportsResponseTypes(request.ResponseTypes) {
+		return errors.New(ErrUnsupportedResponseType)
+	}
+
+	for _, h := range c.AuthorizeEndpointHandlers {
+		if v, ok := h.(AuthorizeEndpointValidator); ok {
+			if err := v.ValidateAuthorizeEndpointRequest(ctx, request); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
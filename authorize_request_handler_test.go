@@ -1,6 +1,8 @@
 package fosite_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"net/http"
 	"net/url"
 	"testing"
@@ -10,8 +12,11 @@ import (
 	"github.com/ory-am/common/pkg"
 	. "github.com/ory-am/fosite"
 	. "github.com/ory-am/fosite/internal"
+	"github.com/ory-am/fosite/token/jwk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
+	jwt "gopkg.in/dgrijalva/jwt-go.v2"
 )
 
 // Should pass
@@ -148,6 +153,285 @@ func TestNewAuthorizeRequest(t *testing.T) {
 			},
 			expectedError: ErrInvalidScope,
 		},
+		/* unknown request parameter */
+		{
+			desc: "should fail because of an unknown parameter in strict mode",
+			conf: &Fosite{Store: store, RejectUnknownRequestParameters: true},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"foo":           {"bar"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			desc: "should pass because the client registered the custom parameter",
+			conf: &Fosite{Store: store, RejectUnknownRequestParameters: true},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"nonce":         {"some-nonce"},
+				"foo":           {"bar"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}, RequestParameters: []string{"foo"}}, nil)
+			},
+			expect: &AuthorizeRequest{
+				RedirectURI:   redir,
+				ResponseTypes: []string{"code"},
+				State:         "strong-state",
+				Request: Request{
+					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}, RequestParameters: []string{"foo"}},
+					Scopes: []string{DefaultMandatoryScope},
+				},
+			},
+		},
+		{
+			desc: "should pass because unknown parameters are ignored by default",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"foo":           {"bar"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expect: &AuthorizeRequest{
+				RedirectURI:   redir,
+				ResponseTypes: []string{"code"},
+				State:         "strong-state",
+				Request: Request{
+					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}},
+					Scopes: []string{DefaultMandatoryScope},
+				},
+			},
+		},
+		{
+			desc: "should fail because display is unknown",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"display":       {"fullscreen"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			desc: "should pass and expose display",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"display":       {"popup"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expect: &AuthorizeRequest{
+				RedirectURI:   redir,
+				ResponseTypes: []string{"code"},
+				State:         "strong-state",
+				Display:       "popup",
+				Request: Request{
+					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}},
+					Scopes: []string{DefaultMandatoryScope},
+				},
+			},
+		},
+		{
+			desc: "should fail because the client may not use response_mode=query",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"response_mode": {"query"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}, ResponseModes: []string{"form_post"}}, nil)
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			desc: "should pass and keep the response mode",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"response_mode": {"form_post"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expect: &AuthorizeRequest{
+				RedirectURI:   redir,
+				ResponseTypes: []string{"code"},
+				State:         "strong-state",
+				ResponseMode:  "form_post",
+				Request: Request{
+					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}},
+					Scopes: []string{DefaultMandatoryScope},
+				},
+			},
+		},
+		{
+			desc: "should fail because the client may not use prompt=none",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"prompt":        {"none"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}, AllowedPrompts: []string{"login", "consent"}}, nil)
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			desc: "should pass because the client may use prompt=login",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"prompt":        {"login"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}, AllowedPrompts: []string{"login", "consent"}}, nil)
+			},
+			expect: &AuthorizeRequest{
+				RedirectURI:   redir,
+				ResponseTypes: []string{"code"},
+				State:         "strong-state",
+				Request: Request{
+					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}, AllowedPrompts: []string{"login", "consent"}},
+					Scopes: []string{DefaultMandatoryScope},
+				},
+			},
+		},
+		{
+			desc: "should fail because ui_locales contains a malformed language tag",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"ui_locales":    {"de en_US"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			desc: "should fail because claims_locales contains a malformed language tag",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":   {"https://foo.bar/cb"},
+				"client_id":      {"1234"},
+				"response_type":  {"code"},
+				"state":          {"strong-state"},
+				"scope":          {DefaultMandatoryScope},
+				"claims_locales": {"ja-Kana-JP-"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			desc: "should fail because the claims parameter is not a JSON object",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope},
+				"claims":        {`{"id_token": ["email"]}`},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expectedError: ErrInvalidRequest,
+		},
+		{
+			desc: "should pass and expose ui_locales and claims_locales in order of preference",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":   {"https://foo.bar/cb"},
+				"client_id":      {"1234"},
+				"response_type":  {"code"},
+				"state":          {"strong-state"},
+				"scope":          {DefaultMandatoryScope},
+				"ui_locales":     {"fr-CA fr en"},
+				"claims_locales": {"ja-Kana-JP en"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}, nil)
+			},
+			expect: &AuthorizeRequest{
+				RedirectURI:   redir,
+				ResponseTypes: []string{"code"},
+				State:         "strong-state",
+				UILocales:     Arguments{"fr-CA", "fr", "en"},
+				ClaimsLocales: Arguments{"ja-Kana-JP", "en"},
+				Request: Request{
+					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}},
+					Scopes: []string{DefaultMandatoryScope},
+				},
+			},
+		},
+		/* scope not allowed for client */
+		{
+			desc: "should fail because client is not allowed to request scope baz",
+			conf: &Fosite{Store: store},
+			query: url.Values{
+				"redirect_uri":  {"https://foo.bar/cb"},
+				"client_id":     {"1234"},
+				"response_type": {"code token"},
+				"state":         {"strong-state"},
+				"scope":         {DefaultMandatoryScope + " foo baz"},
+			},
+			mock: func() {
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope, "foo", "bar"}}, nil)
+			},
+			expectedError: ErrInvalidScope,
+		},
 		{
 			desc: "should pass",
 			conf: &Fosite{Store: store},
@@ -159,14 +443,14 @@ func TestNewAuthorizeRequest(t *testing.T) {
 				"scope":         {DefaultMandatoryScope + " foo bar"},
 			},
 			mock: func() {
-				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}}, nil)
+				store.EXPECT().GetClient("1234").Return(&DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope, "foo", "bar"}}, nil)
 			},
 			expect: &AuthorizeRequest{
 				RedirectURI:   redir,
 				ResponseTypes: []string{"code", "token"},
 				State:         "strong-state",
 				Request: Request{
-					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}},
+					Client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope, "foo", "bar"}},
 					Scopes: []string{DefaultMandatoryScope, "foo", "bar"},
 				},
 			},
@@ -186,9 +470,237 @@ func TestNewAuthorizeRequest(t *testing.T) {
 		if c.expectedError != nil {
 			assert.Equal(t, err.Error(), c.expectedError.Error(), "%d: %s\n%s", k, c.desc, err)
 		} else {
-			pkg.AssertObjectKeysEqual(t, c.expect, ar, "ResponseTypes", "Scopes", "Client", "RedirectURI", "State")
+			pkg.AssertObjectKeysEqual(t, c.expect, ar, "ResponseTypes", "Scopes", "Client", "RedirectURI", "State", "Display", "ResponseMode", "UILocales", "ClaimsLocales")
 			assert.NotNil(t, ar.GetRequestedAt())
 		}
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestNewAuthorizeRequestAggregatesValidationErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}
+	store.EXPECT().GetClient("1234").Return(client, nil).AnyTimes()
+
+	query := url.Values{
+		"redirect_uri":  {"https://foo.bar/not-registered"},
+		"client_id":     {"1234"},
+		"response_type": {"code"},
+		"state":         {"strong-state"},
+		"scope":         {DefaultMandatoryScope + " baz"},
+	}
+	newRequest := func() *http.Request {
+		return &http.Request{Header: http.Header{}, URL: &url.URL{RawQuery: query.Encode()}}
+	}
+
+	// Only the first problem is reported by default.
+	_, err := (&Fosite{Store: store}).NewAuthorizeRequest(context.Background(), newRequest())
+	assert.True(t, errors.Is(err, ErrInvalidRequest), "%s", err)
+
+	_, err = (&Fosite{Store: store, AggregateValidationErrors: true}).NewAuthorizeRequest(context.Background(), newRequest())
+	require.NotNil(t, err)
+	ve, ok := err.(ValidationErrors)
+	require.True(t, ok, "%T", err)
+	require.Len(t, ve, 2)
+	assert.Equal(t, "redirect_uri", ve[0].Parameter)
+	assert.True(t, errors.Is(ve[0].Err, ErrInvalidRequest))
+	assert.Equal(t, "scope", ve[1].Parameter)
+	assert.True(t, errors.Is(ve[1].Err, ErrInvalidScope))
+
+	rfcerr := ErrorToRFC6749Error(err)
+	assert.Equal(t, "invalid_request", rfcerr.Name)
+	assert.Contains(t, rfcerr.Hint, "redirect_uri (invalid_request)")
+	assert.Contains(t, rfcerr.Hint, "scope (invalid_scope)")
+
+	// A single problem is returned as is.
+	query.Set("redirect_uri", "https://foo.bar/cb")
+	_, err = (&Fosite{Store: store, AggregateValidationErrors: true}).NewAuthorizeRequest(context.Background(), newRequest())
+	assert.True(t, errors.Is(err, ErrInvalidScope), "%s", err)
+
+	query.Set("scope", DefaultMandatoryScope)
+	_, err = (&Fosite{Store: store, AggregateValidationErrors: true}).NewAuthorizeRequest(context.Background(), newRequest())
+	assert.Nil(t, err, "%s", err)
+}
+
+// responseTypeHandler is an authorize endpoint handler declaring its response types and validating requests.
+type responseTypeHandler struct {
+	responseTypes []Arguments
+	validateErr   error
+	validated     int
+}
+
+func (h *responseTypeHandler) HandleAuthorizeEndpointRequest(_ context.Context, _ *http.Request, _ AuthorizeRequester, _ AuthorizeResponder) error {
+	return nil
+}
+
+func (h *responseTypeHandler) SupportedResponseTypes() []Arguments {
+	return h.responseTypes
+}
+
+func (h *responseTypeHandler) ValidateAuthorizeEndpointRequest(_ context.Context, _ AuthorizeRequester) error {
+	h.validated++
+	return h.validateErr
+}
+
+func TestNewAuthorizeRequestValidatesResponseTypes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	handler := NewMockAuthorizeEndpointHandler(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}
+	store.EXPECT().GetClient("1234").Return(client, nil).AnyTimes()
+
+	for k, c := range []struct {
+		description    string
+		responseType   string
+		supported      []Arguments
+		handlers       func(h *responseTypeHandler) AuthorizeEndpointHandlers
+		validateErr    error
+		expectErr      error
+		expectValidate int
+	}{
+		{
+			description:  "should fail because the response type is missing",
+			responseType: "",
+			handlers:     func(h *responseTypeHandler) AuthorizeEndpointHandlers { return AuthorizeEndpointHandlers{h} },
+			expectErr:    ErrInvalidRequest,
+		},
+		{
+			description:  "should fail because no handler supports the response type",
+			responseType: "code id_token",
+			supported:    []Arguments{{"code"}, {"code", "token"}},
+			handlers:     func(h *responseTypeHandler) AuthorizeEndpointHandlers { return AuthorizeEndpointHandlers{h} },
+			expectErr:    ErrUnsupportedResponseType,
+		},
+		{
+			description:    "should pass because not all handlers declare their response types",
+			responseType:   "code id_token",
+			supported:      []Arguments{{"code"}},
+			handlers:       func(h *responseTypeHandler) AuthorizeEndpointHandlers { return AuthorizeEndpointHandlers{h, handler} },
+			expectValidate: 1,
+		},
+		{
+			description:    "should fail because the handler rejects the request",
+			responseType:   "id_token code",
+			supported:      []Arguments{{"code"}, {"code", "id_token"}},
+			handlers:       func(h *responseTypeHandler) AuthorizeEndpointHandlers { return AuthorizeEndpointHandlers{h} },
+			validateErr:    errors.New(ErrInvalidGrant),
+			expectErr:      ErrInvalidGrant,
+			expectValidate: 1,
+		},
+		{
+			description:    "should pass",
+			responseType:   "id_token code",
+			supported:      []Arguments{{"code"}, {"code", "id_token"}},
+			handlers:       func(h *responseTypeHandler) AuthorizeEndpointHandlers { return AuthorizeEndpointHandlers{h} },
+			expectValidate: 1,
+		},
+	} {
+		h := &responseTypeHandler{responseTypes: c.supported, validateErr: c.validateErr}
+		query := url.Values{
+			"redirect_uri":  {"https://foo.bar/cb"},
+			"client_id":     {"1234"},
+			"response_type": {c.responseType},
+			"state":         {"strong-state"},
+			"scope":         {DefaultMandatoryScope},
+		}
+		f := &Fosite{Store: store, AuthorizeEndpointHandlers: c.handlers(h)}
+		_, err := f.NewAuthorizeRequest(context.Background(), &http.Request{Header: http.Header{}, URL: &url.URL{RawQuery: query.Encode()}})
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s: %s", k, c.description, err)
+		assert.Equal(t, c.expectValidate, h.validated, "(%d) %s", k, c.description)
+	}
+}
+
+func TestNewAuthorizeRequestExposesPrompt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}}
+	store.EXPECT().GetClient("1234").Return(client, nil).AnyTimes()
+
+	for k, c := range []struct {
+		prompt    string
+		expect    Arguments
+		expectErr error
+	}{
+		{prompt: ""},
+		{prompt: "create", expect: Arguments{PromptCreate}},
+		{prompt: "login create", expect: Arguments{"login", PromptCreate}},
+		{prompt: "none create", expectErr: ErrInvalidRequest},
+	} {
+		query := url.Values{
+			"redirect_uri":  {"https://foo.bar/cb"},
+			"client_id":     {"1234"},
+			"response_type": {"code"},
+			"state":         {"strong-state"},
+			"scope":         {DefaultMandatoryScope},
+			"prompt":        {c.prompt},
+		}
+		f := &Fosite{Store: store}
+		ar, err := f.NewAuthorizeRequest(context.Background(), &http.Request{Header: http.Header{}, URL: &url.URL{RawQuery: query.Encode()}})
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s: %s", k, c.prompt, err)
+		if c.expectErr == nil {
+			assert.Equal(t, c.expect, ar.GetPrompt(), "(%d) %s", k, c.prompt)
+		}
+	}
+}
+
+func TestNewAuthorizeRequestRejectsUnknownParametersOfRequestObjects(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	defer ctrl.Finish()
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.Nil(t, err, "%s", err)
+	keys := &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{jwk.NewRSAPublicKey("sig", "sig", &key.PublicKey)}}
+
+	for k, c := range []struct {
+		description string
+		parameters  []string
+		claims      map[string]interface{}
+		expectErr   error
+	}{
+		{
+			description: "should pass because the request object contains known parameters only",
+			claims:      map[string]interface{}{"nonce": "some-nonce"},
+		},
+		{
+			description: "should fail because the request object contains an unknown parameter",
+			claims:      map[string]interface{}{"foo": "bar"},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should pass because the client registered the parameter of the request object",
+			parameters:  []string{"foo"},
+			claims:      map[string]interface{}{"foo": "bar"},
+		},
+	} {
+		client := &DefaultClient{ID: "1234", RedirectURIs: []string{"https://foo.bar/cb"}, GrantedScopes: []string{DefaultMandatoryScope}, JSONWebKeys: keys, RequestParameters: c.parameters}
+		store.EXPECT().GetClient("1234").Return(client, nil)
+
+		claims := map[string]interface{}{
+			"aud":          "https://auth.example.com",
+			"redirect_uri": "https://foo.bar/cb",
+			"state":        "strong-state",
+			"scope":        DefaultMandatoryScope,
+		}
+		for key, claim := range c.claims {
+			claims[key] = claim
+		}
+		token := jwt.New(jwt.SigningMethodRS256)
+		token.Claims = claims
+		object, err := token.SignedString(key)
+		require.Nil(t, err, "%s", err)
+
+		query := url.Values{"client_id": {"1234"}, "response_type": {"code"}, "request": {object}}
+		f := &Fosite{Store: store, Issuer: "https://auth.example.com", RejectUnknownRequestParameters: true}
+		_, err = f.NewAuthorizeRequest(context.Background(), &http.Request{Header: http.Header{}, URL: &url.URL{RawQuery: query.Encode()}})
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s: %s", k, c.description, err)
+		t.Logf("Passed test case %d", k)
+	}
+}
@@ -55,6 +55,16 @@ func TestAuthorizeRequest(t *testing.T) {
 			},
 			isRedirValid: false,
 		},
+		{
+			// An empty redirect URI was never validated, even if the client registered only one.
+			ar: &AuthorizeRequest{
+				Request: Request{
+					Client: &DefaultClient{RedirectURIs: []string{"https://foobar.com/cb"}},
+				},
+				RedirectURI: urlparse(""),
+			},
+			isRedirValid: false,
+		},
 		{
 			ar: &AuthorizeRequest{
 				RedirectURI: urlparse("https://foobar.com#123"),
@@ -83,6 +93,11 @@ func TestAuthorizeRequest(t *testing.T) {
 				RedirectURI:   urlparse("https://foobar.com/cb"),
 				ResponseTypes: []string{"foo", "bar"},
 				State:         "foobar",
+				Display:       "touch",
+				ResponseMode:  "fragment",
+				UILocales:     Arguments{"de-CH", "de"},
+				ClaimsLocales: Arguments{"en"},
+				Prompt:        Arguments{"login", PromptCreate},
 			},
 			isRedirValid: true,
 		},
@@ -91,8 +106,13 @@ func TestAuthorizeRequest(t *testing.T) {
 		assert.Equal(t, c.ar.RedirectURI, c.ar.GetRedirectURI(), "%d", k)
 		assert.Equal(t, c.ar.RequestedAt, c.ar.GetRequestedAt(), "%d", k)
 		assert.Equal(t, c.ar.ResponseTypes, c.ar.GetResponseTypes(), "%d", k)
-		assert.Equal(t, c.ar.Scopes, c.ar.GetScopes(), "%d", k)
+		assert.Equal(t, Arguments(c.ar.Scopes), c.ar.GetScopes(), "%d", k)
 		assert.Equal(t, c.ar.State, c.ar.GetState(), "%d", k)
+		assert.Equal(t, c.ar.Display, c.ar.GetDisplay(), "%d", k)
+		assert.Equal(t, c.ar.ResponseMode, c.ar.GetResponseMode(), "%d", k)
+		assert.Equal(t, c.ar.UILocales, c.ar.GetUILocales(), "%d", k)
+		assert.Equal(t, c.ar.ClaimsLocales, c.ar.GetClaimsLocales(), "%d", k)
+		assert.Equal(t, c.ar.Prompt, c.ar.GetPrompt(), "%d", k)
 		assert.Equal(t, c.isRedirValid, c.ar.IsRedirectURIValid(), "%d", k)
 
 		c.ar.GrantScope("foo")
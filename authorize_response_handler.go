@@ -8,7 +8,17 @@ import (
 	"golang.org/x/net/context"
 )
 
+// NewAuthorizeResponse runs the authorize endpoint handlers. The outcome of requests for tokens, e.g. of the
+// implicit or the hybrid flow, is emitted as AuditTokenIssued event.
 func (o *Fosite) NewAuthorizeResponse(ctx context.Context, r *http.Request, ar AuthorizeRequester, session interface{}) (AuthorizeResponder, error) {
+	resp, err := o.newAuthorizeResponse(ctx, r, ar, session)
+	if ar.GetResponseTypes().Has("token") || ar.GetResponseTypes().Has("id_token") {
+		o.emitAuditEvent(ctx, AuditTokenIssued, ar, err)
+	}
+	return resp, err
+}
+
+func (o *Fosite) newAuthorizeResponse(ctx context.Context, r *http.Request, ar AuthorizeRequester, session interface{}) (AuthorizeResponder, error) {
 	var resp = &AuthorizeResponse{
 		Header:   http.Header{},
 		Query:    url.Values{},
@@ -16,6 +26,15 @@ func (o *Fosite) NewAuthorizeResponse(ctx context.Context, r *http.Request, ar A
 	}
 
 	ar.SetSession(session)
+	if err := validateInteraction(ar, session); err != nil {
+		return nil, err
+	} else if err := validateOfflineConsent(ar, session); err != nil {
+		return nil, err
+	} else if err := validateAuthenticationContext(ar, session); err != nil {
+		return nil, err
+	}
+	grantClientAudience(ar)
+
 	for _, h := range o.AuthorizeEndpointHandlers {
 		if err := h.HandleAuthorizeEndpointRequest(ctx, r, ar, resp); err != nil {
 			return nil, err
@@ -17,6 +17,7 @@ func TestNewAuthorizeResponse(t *testing.T) {
 	handlers := []*MockAuthorizeEndpointHandler{NewMockAuthorizeEndpointHandler(ctrl)}
 	ar := NewMockAuthorizeRequester(ctrl)
 	defer ctrl.Finish()
+	ar.EXPECT().GetResponseTypes().Return(Arguments{"code"}).AnyTimes()
 
 	ctx := context.Background()
 	oauth2 := &Fosite{
@@ -26,6 +27,8 @@ func TestNewAuthorizeResponse(t *testing.T) {
 		AuthorizeEndpointHandlers: AuthorizeEndpointHandlers{handlers[0], handlers[0]},
 	}
 	ar.EXPECT().SetSession(gomock.Eq(struct{}{})).AnyTimes()
+	ar.EXPECT().GetGrantedScopes().Return(Arguments{}).AnyTimes()
+	ar.EXPECT().GetClient().Return(&DefaultClient{}).AnyTimes()
 	fooErr := errors.New("foo")
 	for k, c := range []struct {
 		isErr     bool
@@ -2,18 +2,15 @@ package fosite
 
 import (
 	"net/http"
+	"net/url"
 )
 
+// WriteAuthorizeResponse redirects the user-agent to the redirect URI of ar. Parameters of explicit grants are added
+// to the query and those of implicit grants to the fragment, unless ar requested another response mode, see
+// http://openid.net/specs/oauth-v2-multiple-response-types-1_0.html#ResponseModes
 func (c *Fosite) WriteAuthorizeResponse(rw http.ResponseWriter, ar AuthorizeRequester, resp AuthorizeResponder) {
 	redir := ar.GetRedirectURI()
-
-	// Explicit grants
-	q := redir.Query()
-	rq := resp.GetQuery()
-	for k := range rq {
-		q.Set(k, rq.Get(k))
-	}
-	redir.RawQuery = q.Encode()
+	rq, rf := resp.GetQuery(), resp.GetFragment()
 
 	// Set custom headers, e.g. "X-MySuperCoolCustomHeader" or "X-DONT-CACHE-ME"...
 	wh := rw.Header()
@@ -22,14 +19,32 @@ func (c *Fosite) WriteAuthorizeResponse(rw http.ResponseWriter, ar AuthorizeRequ
 		wh.Set(k, rh.Get(k))
 	}
 
+	switch ar.GetResponseMode() {
+	case ResponseModeFormPost:
+		writeFormPost(rw, redir, mergeValues(rq, rf))
+		return
+	case ResponseModeFragment:
+		rq, rf = url.Values{}, mergeValues(rq, rf)
+	case ResponseModeQuery:
+		rq, rf = mergeValues(rq, rf), url.Values{}
+	}
+
+	// Explicit grants
+	q := redir.Query()
+	for k := range rq {
+		q.Set(k, rq.Get(k))
+	}
+	redir.RawQuery = q.Encode()
+
 	// Implicit grants
-	redir.Fragment = resp.GetFragment().Encode()
+	redir.Fragment = rf.Encode()
 
 	// https://tools.ietf.org/html/rfc6749#section-4.1.1
 	// When a decision is established, the authorization server directs the
 	// user-agent to the provided client redirection URI using an HTTP
 	// redirection response, or by other means available to it via the
 	// user-agent.
+	setNoCacheHeaders(wh)
 	wh.Set("Location", redir.String())
 	rw.WriteHeader(http.StatusFound)
 }
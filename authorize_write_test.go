@@ -2,6 +2,7 @@ package fosite_test
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -19,6 +20,7 @@ func TestWriteAuthorizeResponse(t *testing.T) {
 	ar := NewMockAuthorizeRequester(ctrl)
 	resp := NewMockAuthorizeResponder(ctrl)
 	defer ctrl.Finish()
+	ar.EXPECT().GetResponseMode().Return("").AnyTimes()
 
 	for k, c := range []struct {
 		setup  func()
@@ -37,7 +39,9 @@ func TestWriteAuthorizeResponse(t *testing.T) {
 			},
 			expect: func() {
 				assert.Equal(t, http.Header{
-					"Location": []string{"https://foobar.com/?foo=bar"},
+					"Location":      []string{"https://foobar.com/?foo=bar"},
+					"Cache-Control": []string{"no-store"},
+					"Pragma":        []string{"no-cache"},
 				}, header)
 			},
 		},
@@ -54,7 +58,9 @@ func TestWriteAuthorizeResponse(t *testing.T) {
 			},
 			expect: func() {
 				assert.Equal(t, http.Header{
-					"Location": []string{"https://foobar.com/?foo=bar#bar=baz"},
+					"Location":      []string{"https://foobar.com/?foo=bar#bar=baz"},
+					"Cache-Control": []string{"no-store"},
+					"Pragma":        []string{"no-cache"},
 				}, header)
 			},
 		},
@@ -71,7 +77,9 @@ func TestWriteAuthorizeResponse(t *testing.T) {
 			},
 			expect: func() {
 				assert.Equal(t, http.Header{
-					"Location": []string{"https://foobar.com/?bar=baz&foo=bar#bar=baz"},
+					"Location":      []string{"https://foobar.com/?bar=baz&foo=bar#bar=baz"},
+					"Cache-Control": []string{"no-store"},
+					"Pragma":        []string{"no-cache"},
 				}, header)
 			},
 		},
@@ -88,8 +96,10 @@ func TestWriteAuthorizeResponse(t *testing.T) {
 			},
 			expect: func() {
 				assert.Equal(t, http.Header{
-					"X-Bar":    {"baz"},
-					"Location": {"https://foobar.com/?bar=baz&foo=bar#bar=baz"},
+					"X-Bar":         {"baz"},
+					"Location":      {"https://foobar.com/?bar=baz&foo=bar#bar=baz"},
+					"Cache-Control": {"no-store"},
+					"Pragma":        {"no-cache"},
 				}, header)
 			},
 		},
@@ -102,3 +112,59 @@ func TestWriteAuthorizeResponse(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestWriteAuthorizeResponseHonorsTheResponseMode(t *testing.T) {
+	for k, c := range []struct {
+		description    string
+		mode           string
+		expectLocation string
+		expectForm     []string
+	}{
+		{
+			description:    "should use the query for codes and the fragment for tokens by default",
+			expectLocation: "https://foobar.com/cb?code=foo&foo=bar#access_token=bar",
+		},
+		{
+			description:    "should move all parameters to the fragment",
+			mode:           ResponseModeFragment,
+			expectLocation: "https://foobar.com/cb?foo=bar#access_token=bar&code=foo",
+		},
+		{
+			description:    "should move all parameters to the query",
+			mode:           ResponseModeQuery,
+			expectLocation: "https://foobar.com/cb?access_token=bar&code=foo&foo=bar",
+		},
+		{
+			description: "should post all parameters to the redirect URI",
+			mode:        ResponseModeFormPost,
+			expectForm: []string{
+				`action="https://foobar.com/cb?foo=bar"`,
+				`<input type="hidden" name="code" value="foo"/>`,
+				`<input type="hidden" name="access_token" value="bar"/>`,
+			},
+		},
+	} {
+		ar := NewAuthorizeRequest()
+		ar.RedirectURI, _ = url.Parse("https://foobar.com/cb?foo=bar")
+		ar.ResponseMode = c.mode
+		resp := NewAuthorizeResponse()
+		resp.AddQuery("code", "foo")
+		resp.AddFragment("access_token", "bar")
+
+		rw := httptest.NewRecorder()
+		(&Fosite{}).WriteAuthorizeResponse(rw, ar, resp)
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"), "(%d) %s", k, c.description)
+		if c.expectForm == nil {
+			assert.Equal(t, http.StatusFound, rw.Code, "(%d) %s", k, c.description)
+			assert.Equal(t, c.expectLocation, rw.Header().Get("Location"), "(%d) %s", k, c.description)
+			continue
+		}
+
+		assert.Equal(t, http.StatusOK, rw.Code, "(%d) %s", k, c.description)
+		assert.Empty(t, rw.Header().Get("Location"), "(%d) %s", k, c.description)
+		assert.Equal(t, "text/html;charset=UTF-8", rw.Header().Get("Content-Type"), "(%d) %s", k, c.description)
+		for _, expect := range c.expectForm {
+			assert.Contains(t, rw.Body.String(), expect, "(%d) %s", k, c.description)
+		}
+	}
+}
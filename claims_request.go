@@ -0,0 +1,123 @@
+package fosite
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// ClaimRequest is a request for an individual claim, see
+// http://openid.net/specs/openid-connect-core-1_0.html#IndividualClaimsRequests
+type ClaimRequest struct {
+	// Essential indicates that the claim is required for the authorization the client asked for to work smoothly.
+	Essential bool `json:"essential,omitempty"`
+
+	// Value and Values are the values the client wants the claim to have.
+	Value  interface{}   `json:"value,omitempty"`
+	Values []interface{} `json:"values,omitempty"`
+}
+
+// ClaimRequests maps the names of the requested claims to the request. The request of a claim is nil if the client
+// requested it in the default manner, e.g. {"email": null}.
+type ClaimRequests map[string]*ClaimRequest
+
+// ClaimsRequest is the claims parameter of an authorize request, see
+// http://openid.net/specs/openid-connect-core-1_0.html#ClaimsParameter
+type ClaimsRequest struct {
+	// UserInfo are the claims requested to be returned from the UserInfo endpoint.
+	UserInfo ClaimRequests `json:"userinfo,omitempty"`
+
+	// IDToken are the claims requested to be returned in the ID token.
+	IDToken ClaimRequests `json:"id_token,omitempty"`
+}
+
+// ParseClaimsRequest parses the claims parameter of an authorize request. It returns nil if raw is empty and
+// ErrInvalidRequest if raw is not a JSON object of the form defined by the specification.
+func ParseClaimsRequest(raw string) (*ClaimsRequest, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var request ClaimsRequest
+	if err := json.Unmarshal([]byte(raw), &request); err != nil {
+		return nil, errors.New(ErrInvalidRequest)
+	}
+	return &request, nil
+}
+
+// CopyClaimsRequest passes the claims parameter of original, the request a grant was issued for, on to the token
+// request requester. Tokens issued for requester, and refreshed later on, are thus subject to the claims parameter
+// of the authorize request. A claims parameter sent to the token endpoint is ignored.
+func CopyClaimsRequest(original, requester Requester) {
+	form := requester.GetRequestForm()
+	if claims := original.GetRequestForm().Get("claims"); claims != "" {
+		form.Set("claims", claims)
+	} else {
+		form.Del("claims")
+	}
+}
+
+// MissingEssentialClaims returns the names of the claims requested as essential which are missing or empty in
+// available, in lexical order.
+func (r ClaimRequests) MissingEssentialClaims(available map[string]interface{}) []string {
+	var missing []string
+	for name, request := range r {
+		if request == nil || !request.Essential {
+			continue
+		}
+
+		if v, ok := available[name]; !ok || isEmptyClaim(v) {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func isEmptyClaim(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []byte:
+		return len(v) == 0
+	}
+	return false
+}
+
+// EssentialClaimsError is returned if claims the client requested as essential can not be provided. It is written
+// like ErrAccessDenied, the Hint of the RFC6749Error names the missing claims.
+//
+// The specification does not require failing the request, whether such requests fail is therefore configured by
+// the ID token strategy and the UserInfo endpoint.
+type EssentialClaimsError struct {
+	// Claims are the names of the missing claims.
+	Claims []string
+}
+
+func (e *EssentialClaimsError) Error() string {
+	return fmt.Sprintf("Essential claims are not available: %s", strings.Join(e.Claims, ", "))
+}
+
+func (e *EssentialClaimsError) toRFC6749Error() *RFC6749Error {
+	rfcerr := ErrorToRFC6749Error(errors.New(ErrAccessDenied))
+	rfcerr.Hint = e.Error()
+	return rfcerr
+}
+
+// unwrapEssentialClaimsError returns the EssentialClaimsError err wraps, if any.
+func unwrapEssentialClaimsError(err error) (*EssentialClaimsError, bool) {
+	for {
+		ge, ok := err.(*errors.Error)
+		if !ok {
+			break
+		}
+		err = ge.Err
+	}
+	ee, ok := err.(*EssentialClaimsError)
+	return ee, ok
+}
@@ -0,0 +1,68 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClaimsRequest(t *testing.T) {
+	request, err := ParseClaimsRequest("")
+	assert.Nil(t, err)
+	assert.Nil(t, request)
+
+	for _, raw := range []string{"email", `["email"]`, `{"id_token": ["email"]}`, `{"userinfo": {"email": {"essential": "yes"}}}`} {
+		_, err := ParseClaimsRequest(raw)
+		assert.True(t, errors.Is(ErrInvalidRequest, err), "%s: %s", raw, err)
+	}
+
+	request, err = ParseClaimsRequest(`{"userinfo": {"email": {"essential": true}, "picture": null}, "id_token": {"acr": {"values": ["urn:mace:incommon:iap:silver"]}}}`)
+	require.Nil(t, err)
+	assert.Equal(t, &ClaimsRequest{
+		UserInfo: ClaimRequests{"email": {Essential: true}, "picture": nil},
+		IDToken:  ClaimRequests{"acr": {Values: []interface{}{"urn:mace:incommon:iap:silver"}}},
+	}, request)
+}
+
+func TestMissingEssentialClaims(t *testing.T) {
+	requested := ClaimRequests{
+		"email":        {Essential: true},
+		"phone_number": {Essential: true},
+		"address":      {Essential: true},
+		"picture":      nil,
+		"nickname":     {},
+	}
+	assert.Equal(t, []string{"address", "phone_number"}, requested.MissingEssentialClaims(map[string]interface{}{
+		"email":        "peter@example.com",
+		"phone_number": "",
+	}))
+	assert.Empty(t, requested.MissingEssentialClaims(map[string]interface{}{
+		"email":        "peter@example.com",
+		"phone_number": "+1 555 0100",
+		"address":      map[string]interface{}{"country": "DE"},
+	}))
+	assert.Empty(t, ClaimRequests(nil).MissingEssentialClaims(nil))
+}
+
+func TestCopyClaimsRequest(t *testing.T) {
+	authorize := NewAuthorizeRequest()
+	authorize.Form.Set("claims", `{"userinfo": {"email": {"essential": true}}}`)
+	token := NewAccessRequest(nil)
+	token.Form.Set("claims", `{"userinfo": {"phone_number": {"essential": true}}}`)
+
+	CopyClaimsRequest(authorize, token)
+	assert.Equal(t, `{"userinfo": {"email": {"essential": true}}}`, token.Form.Get("claims"))
+
+	CopyClaimsRequest(NewAuthorizeRequest(), token)
+	_, ok := token.Form["claims"]
+	assert.False(t, ok)
+}
+
+func TestEssentialClaimsErrorToRFC6749Error(t *testing.T) {
+	err := errors.New(errors.New(&EssentialClaimsError{Claims: []string{"address", "email"}}))
+	rfcerr := ErrorToRFC6749Error(err)
+	assert.Equal(t, errAccessDeniedName, rfcerr.Name)
+	assert.Equal(t, "Essential claims are not available: address, email", rfcerr.Hint)
+}
@@ -1,6 +1,19 @@
 package fosite
 
-import "strings"
+import (
+	"time"
+
+	"github.com/ory-am/fosite/token/jwk"
+)
+
+const (
+	// ApplicationTypeWeb is the application type of clients running on a web server.
+	ApplicationTypeWeb = "web"
+
+	// ApplicationTypeNative is the application type of clients running on the end-user's device, e.g. mobile or
+	// desktop apps.
+	ApplicationTypeNative = "native"
+)
 
 // Scopes is a list of scopes.
 type Scopes interface {
@@ -16,9 +29,17 @@ type Client interface {
 	// GetHashedSecret returns the hashed secret as it is stored in the store.
 	GetHashedSecret() []byte
 
+	// IsPublic returns true if the client can not keep a secret, e.g. a native or browser-based app, see
+	// https://tools.ietf.org/html/rfc6749#section-2.1
+	IsPublic() bool
+
 	// Returns the client's allowed redirect URIs.
 	GetRedirectURIs() []string
 
+	// Returns the URIs the end-user may be redirected to after logging out, see
+	// http://openid.net/specs/openid-connect-session-1_0.html#RPLogout
+	GetPostLogoutRedirectURIs() []string
+
 	// Returns the client's allowed grant types.
 	GetGrantTypes() Arguments
 
@@ -30,6 +51,55 @@ type Client interface {
 
 	// Returns the scopes this client was granted.
 	GetGrantedScopes() Scopes
+
+	// Returns the scopes this client is allowed to request.
+	GetScopes() Arguments
+
+	// Returns the audiences (e.g. identifiers of resource servers) the client's access tokens are intended for.
+	GetAudience() Arguments
+
+	// Returns custom request parameters this client is allowed to send in addition to the ones
+	// defined by OAuth2 and OpenID Connect.
+	GetRequestParameters() Arguments
+
+	// Returns the pre-registered request_uri values the client may pass by reference.
+	GetRequestURIs() []string
+
+	// Returns the JWS algorithm required for signing ID tokens issued to this client.
+	GetIDTokenSignedResponseAlg() string
+
+	// Returns the JWE alg algorithm required for encrypting ID tokens issued to this client. ID tokens are
+	// not encrypted if empty.
+	GetIDTokenEncryptedResponseAlg() string
+
+	// Returns the JWE enc algorithm required for encrypting ID tokens issued to this client.
+	GetIDTokenEncryptedResponseEnc() string
+
+	// Returns the client's public keys.
+	GetJSONWebKeys() *jwk.JSONWebKeySet
+
+	// Returns the URI the client's public keys are published at. Only used if GetJSONWebKeys returns no keys.
+	GetJSONWebKeysURI() string
+
+	// Returns the kind of the application, either ApplicationTypeWeb or ApplicationTypeNative. Only native clients
+	// may use redirect URIs with private-use URI schemes.
+	GetApplicationType() string
+
+	// Returns the authentication context class references (acr) of which the end-user must have authenticated with
+	// one. Any authentication is accepted if empty.
+	GetRequiredACRValues() Arguments
+
+	// Returns the values of the prompt parameter the client may send, e.g. to keep it from skipping the login using
+	// prompt=none. All values are allowed if empty.
+	GetAllowedPrompts() Arguments
+
+	// Returns the values of the response_mode parameter the client may send. The response modes defined by OAuth2
+	// and OpenID Connect (query, fragment and form_post) are allowed if empty.
+	GetResponseModes() Arguments
+
+	// Returns true if the client may only initiate authorize requests using pushed authorization requests, see
+	// https://tools.ietf.org/html/rfc9126#section-6
+	GetRequirePushedAuthorizationRequests() bool
 }
 
 // DefaultClient is a simple default implementation of the Client interface.
@@ -37,6 +107,7 @@ type DefaultClient struct {
 	ID                string   `json:"id" gorethink:"id"`
 	Name              string   `json:"client_name" gorethink:"client_name"`
 	Secret            []byte   `json:"client_secret,omitempty" gorethink:"client_secret"`
+	Public            bool     `json:"public" gorethink:"public"`
 	RedirectURIs      []string `json:"redirect_uris" gorethink:"redirect_uris"`
 	GrantTypes        []string `json:"grant_types" gorethink:"grant_types"`
 	ResponseTypes     []string `json:"response_types" gorethink:"response_types"`
@@ -47,6 +118,29 @@ type DefaultClient struct {
 	ClientURI         string   `json:"client_uri" gorethink:"client_uri"`
 	LogoURI           string   `json:"logo_uri" gorethink:"logo_uri"`
 	Contacts          []string `json:"contacts" gorethink:"contacts"`
+	RequestParameters []string `json:"request_parameters" gorethink:"request_parameters"`
+	Audience          []string `json:"audience" gorethink:"audience"`
+	RequestURIs       []string `json:"request_uris" gorethink:"request_uris"`
+
+	PostLogoutRedirectURIs []string `json:"post_logout_redirect_uris,omitempty" gorethink:"post_logout_redirect_uris"`
+
+	IDTokenSignedResponseAlg    string `json:"id_token_signed_response_alg" gorethink:"id_token_signed_response_alg"`
+	IDTokenEncryptedResponseAlg string `json:"id_token_encrypted_response_alg" gorethink:"id_token_encrypted_response_alg"`
+	IDTokenEncryptedResponseEnc string `json:"id_token_encrypted_response_enc" gorethink:"id_token_encrypted_response_enc"`
+
+	JSONWebKeys    *jwk.JSONWebKeySet `json:"jwks,omitempty" gorethink:"jwks"`
+	JSONWebKeysURI string             `json:"jwks_uri,omitempty" gorethink:"jwks_uri"`
+
+	ApplicationType   string   `json:"application_type,omitempty" gorethink:"application_type"`
+	RequiredACRValues []string `json:"required_acr_values,omitempty" gorethink:"required_acr_values"`
+	AllowedPrompts    []string `json:"allowed_prompts,omitempty" gorethink:"allowed_prompts"`
+	ResponseModes     []string `json:"response_modes,omitempty" gorethink:"response_modes"`
+
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests,omitempty" gorethink:"require_pushed_authorization_requests"`
+
+	// Lifespans overrides the lifespans of the handlers for this client, see ClientWithCustomLifespans. The keys are
+	// the grant type and the token type separated by a space, e.g. "authorization_code access_token".
+	Lifespans map[string]time.Duration `json:"lifespans,omitempty" gorethink:"lifespans"`
 }
 
 type DefaultScopes struct {
@@ -54,33 +148,7 @@ type DefaultScopes struct {
 }
 
 func (s *DefaultScopes) Grant(requestScope string) bool {
-	for _, scope := range s.Scopes {
-		// foo == foo -> true
-		if scope == requestScope {
-			return true
-		}
-
-		// picture.read > picture -> false (scope picture includes read, write, ...)
-		if len(scope) > len(requestScope) {
-			continue
-		}
-
-		needles := strings.Split(requestScope, ".")
-		haystack := strings.Split(scope, ".")
-		haystackLen := len(haystack) - 1
-		for k, needle := range needles {
-			if haystackLen < k {
-				return true
-			}
-
-			current := haystack[k]
-			if current != needle {
-				continue
-			}
-		}
-	}
-
-	return false
+	return HierarchicScopeStrategy(s.Scopes, requestScope)
 }
 
 func (c *DefaultClient) GetID() string {
@@ -91,16 +159,40 @@ func (c *DefaultClient) GetRedirectURIs() []string {
 	return c.RedirectURIs
 }
 
+func (c *DefaultClient) GetPostLogoutRedirectURIs() []string {
+	return c.PostLogoutRedirectURIs
+}
+
 func (c *DefaultClient) GetHashedSecret() []byte {
 	return c.Secret
 }
 
+func (c *DefaultClient) IsPublic() bool {
+	return c.Public
+}
+
 func (c *DefaultClient) GetGrantedScopes() Scopes {
 	return &DefaultScopes{
 		Scopes: c.GrantedScopes,
 	}
 }
 
+func (c *DefaultClient) GetScopes() Arguments {
+	return Arguments(c.GrantedScopes)
+}
+
+func (c *DefaultClient) GetAudience() Arguments {
+	return Arguments(c.Audience)
+}
+
+func (c *DefaultClient) GetRequestParameters() Arguments {
+	return Arguments(c.RequestParameters)
+}
+
+func (c *DefaultClient) GetRequestURIs() []string {
+	return c.RequestURIs
+}
+
 func (c *DefaultClient) GetGrantTypes() Arguments {
 	// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
 	//
@@ -128,3 +220,67 @@ func (c *DefaultClient) GetResponseTypes() Arguments {
 func (c *DefaultClient) GetOwner() string {
 	return c.Owner
 }
+
+func (c *DefaultClient) GetIDTokenSignedResponseAlg() string {
+	// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+	//
+	// JWS alg algorithm [JWA] REQUIRED for signing the ID Token issued to this Client.
+	// The default, if omitted, is RS256.
+	if c.IDTokenSignedResponseAlg == "" {
+		return "RS256"
+	}
+	return c.IDTokenSignedResponseAlg
+}
+
+func (c *DefaultClient) GetIDTokenEncryptedResponseAlg() string {
+	return c.IDTokenEncryptedResponseAlg
+}
+
+func (c *DefaultClient) GetIDTokenEncryptedResponseEnc() string {
+	// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+	//
+	// If id_token_encrypted_response_alg is specified, the default for this value is A128CBC-HS256.
+	if c.IDTokenEncryptedResponseEnc == "" && c.IDTokenEncryptedResponseAlg != "" {
+		return "A128CBC-HS256"
+	}
+	return c.IDTokenEncryptedResponseEnc
+}
+
+func (c *DefaultClient) GetJSONWebKeys() *jwk.JSONWebKeySet {
+	return c.JSONWebKeys
+}
+
+func (c *DefaultClient) GetJSONWebKeysURI() string {
+	return c.JSONWebKeysURI
+}
+
+func (c *DefaultClient) GetApplicationType() string {
+	// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+	//
+	// Kind of the application. The default, if omitted, is web.
+	if c.ApplicationType == "" {
+		return ApplicationTypeWeb
+	}
+	return c.ApplicationType
+}
+
+func (c *DefaultClient) GetRequiredACRValues() Arguments {
+	return Arguments(c.RequiredACRValues)
+}
+
+func (c *DefaultClient) GetAllowedPrompts() Arguments {
+	return Arguments(c.AllowedPrompts)
+}
+
+func (c *DefaultClient) GetResponseModes() Arguments {
+	return Arguments(c.ResponseModes)
+}
+
+// GetLifespan implements ClientWithCustomLifespans.
+func (c *DefaultClient) GetLifespan(grantType string, tokenType string) time.Duration {
+	return c.Lifespans[grantType+" "+tokenType]
+}
+
+func (c *DefaultClient) GetRequirePushedAuthorizationRequests() bool {
+	return c.RequirePushedAuthorizationRequests
+}
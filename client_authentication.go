@@ -0,0 +1,121 @@
+package fosite
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+const (
+	// ClientSecretBasic authenticates clients using the HTTP Basic authentication scheme.
+	ClientSecretBasic = "client_secret_basic"
+
+	// ClientSecretPost authenticates clients using the client_id and client_secret parameters of the request body.
+	ClientSecretPost = "client_secret_post"
+
+	// ClientAuthenticationNone identifies public clients using the client_id parameter of the request body only.
+	ClientAuthenticationNone = "none"
+)
+
+// authenticateClient authenticates the client using one of the methods defined in
+// https://tools.ietf.org/html/rfc6749#section-2.3.1
+//
+//	The authorization server MUST support the HTTP Basic authentication scheme for authenticating clients that
+//	were issued a client password. [...] Alternatively, the authorization server MAY support including the
+//	client credentials in the request-body [...] The parameters can only be transmitted in the request-body and
+//	MUST NOT be included in the request URI.
+//
+// Public clients can not keep a secret and are therefore identified by their client_id only. They must not
+// present a secret, see https://tools.ietf.org/html/rfc6749#section-2.3
+//
+//	The authorization server MUST NOT issue client passwords or other client credentials to native application
+//	or user-agent-based application clients for the purpose of client authentication.
+//
+// r.PostForm must be parsed already. Failed authentications are emitted as AuditAuthFailed events.
+func (f *Fosite) authenticateClient(ctx context.Context, r *http.Request) (Client, error) {
+	client, clientID, err := f.authenticateClientCredentials(ctx, r)
+	if err != nil {
+		f.GetAuditSink().Emit(ctx, &AuditEvent{Type: AuditAuthFailed, Time: time.Now(), ClientID: clientID, Err: err})
+		return nil, err
+	}
+	return client, nil
+}
+
+// authenticateClientCredentials authenticates the client, see authenticateClient. The returned client ID is the
+// one claimed by the request and is returned even if authentication fails.
+func (f *Fosite) authenticateClientCredentials(ctx context.Context, r *http.Request) (Client, string, error) {
+	method, clientID, clientSecret, err := clientCredentials(r)
+	if err != nil {
+		return nil, clientID, err
+	}
+
+	client, err := f.getClient(ctx, clientID)
+	if err != nil {
+		return nil, clientID, errors.New(ErrInvalidClient)
+	}
+
+	if client.IsPublic() {
+		if method != ClientAuthenticationNone {
+			return nil, clientID, errors.New(ErrInvalidClient)
+		}
+		return client, clientID, nil
+	} else if method == ClientAuthenticationNone {
+		return nil, clientID, errors.New(ErrInvalidClient)
+	}
+
+	// Enforce client authentication
+	if err := f.Hasher.Compare(client.GetHashedSecret(), []byte(clientSecret)); err != nil {
+		return nil, clientID, errors.New(ErrInvalidClient)
+	}
+
+	return client, clientID, nil
+}
+
+// clientCredentials returns the authentication method and the credentials of the client. As required by
+// https://tools.ietf.org/html/rfc6749#section-2.3
+//
+//	The client MUST NOT use more than one authentication method in each request.
+//
+// requests containing credentials in both the Authorization header and the request body are rejected.
+func clientCredentials(r *http.Request) (method, clientID, clientSecret string, err error) {
+	if r.URL != nil && r.URL.Query().Get("client_secret") != "" {
+		return "", "", "", errors.New(ErrInvalidRequest)
+	}
+
+	basicID, basicSecret, hasBasic := r.BasicAuth()
+	postID, postSecret := r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	switch {
+	case hasBasic && postSecret != "":
+		return "", "", "", errors.New(ErrInvalidRequest)
+	case hasBasic:
+		// The client_id parameter may be sent along, but must identify the same client.
+		method, clientID, clientSecret, err = decodeBasicAuthCredentials(basicID, basicSecret)
+		if err == nil && postID != "" && postID != clientID {
+			return "", "", "", errors.New(ErrInvalidRequest)
+		}
+		return method, clientID, clientSecret, err
+	case postID != "" && postSecret != "":
+		return ClientSecretPost, postID, postSecret, nil
+	case postID != "":
+		return ClientAuthenticationNone, postID, "", nil
+	}
+	return "", "", "", errors.New(ErrInvalidRequest)
+}
+
+// decodeBasicAuthCredentials decodes the credentials of the HTTP Basic authentication scheme, see
+// https://tools.ietf.org/html/rfc6749#section-2.3.1
+//
+//	The client identifier is encoded using the "application/x-www-form-urlencoded" encoding algorithm per
+//	Appendix B, and the encoded value is used as the username; the client password is encoded using the same
+//	algorithm and used as the password.
+func decodeBasicAuthCredentials(encodedID, encodedSecret string) (method, clientID, clientSecret string, err error) {
+	if clientID, err = url.QueryUnescape(encodedID); err != nil {
+		return "", "", "", errors.New(ErrInvalidRequest)
+	} else if clientSecret, err = url.QueryUnescape(encodedSecret); err != nil {
+		return "", "", "", errors.New(ErrInvalidRequest)
+	}
+	return ClientSecretBasic, clientID, clientSecret, nil
+}
@@ -0,0 +1,113 @@
+package fosite
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientCredentials(t *testing.T) {
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("foo:bar"))
+	for k, c := range []struct {
+		description  string
+		header       http.Header
+		query        url.Values
+		form         url.Values
+		expectErr    error
+		expectMethod string
+	}{
+		{
+			description: "should fail because no credentials were sent",
+			header:      http.Header{},
+			form:        url.Values{},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description:  "should pass using client_secret_basic",
+			header:       http.Header{"Authorization": {basic}},
+			form:         url.Values{},
+			expectMethod: ClientSecretBasic,
+		},
+		{
+			description:  "should pass using client_secret_basic with a matching client_id",
+			header:       http.Header{"Authorization": {basic}},
+			form:         url.Values{"client_id": {"foo"}},
+			expectMethod: ClientSecretBasic,
+		},
+		{
+			description: "should fail because client_id does not match the Authorization header",
+			header:      http.Header{"Authorization": {basic}},
+			form:        url.Values{"client_id": {"baz"}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description:  "should pass using client_secret_post",
+			header:       http.Header{},
+			form:         url.Values{"client_id": {"foo"}, "client_secret": {"bar"}},
+			expectMethod: ClientSecretPost,
+		},
+		{
+			description: "should fail because client_secret_post is missing the client_id",
+			header:      http.Header{},
+			form:        url.Values{"client_secret": {"bar"}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description:  "should pass using none because only the client_id was sent",
+			header:       http.Header{},
+			form:         url.Values{"client_id": {"foo"}},
+			expectMethod: ClientAuthenticationNone,
+		},
+		{
+			description: "should fail because more than one method was used",
+			header:      http.Header{"Authorization": {basic}},
+			form:        url.Values{"client_id": {"foo"}, "client_secret": {"bar"}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail because the secret was sent in the request URI",
+			header:      http.Header{},
+			query:       url.Values{"client_id": {"foo"}, "client_secret": {"bar"}},
+			form:        url.Values{},
+			expectErr:   ErrInvalidRequest,
+		},
+	} {
+		r := &http.Request{Header: c.header, PostForm: c.form, URL: &url.URL{RawQuery: c.query.Encode()}}
+		method, id, secret, err := clientCredentials(r)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		assert.Equal(t, c.expectMethod, method, "(%d) %s", k, c.description)
+		if c.expectErr == nil {
+			assert.Equal(t, "foo", id, "(%d) %s", k, c.description)
+		}
+		if c.expectErr == nil && c.expectMethod != ClientAuthenticationNone {
+			assert.Equal(t, "bar", secret, "(%d) %s", k, c.description)
+		}
+	}
+}
+
+func TestClientCredentialsDecodesBasicAuth(t *testing.T) {
+	for k, c := range []struct {
+		id           string
+		secret       string
+		expectErr    error
+		expectID     string
+		expectSecret string
+	}{
+		{id: "foo", secret: url.QueryEscape("b+r%:z/ &"), expectID: "foo", expectSecret: "b+r%:z/ &"},
+		{id: url.QueryEscape("my client:1"), secret: "bar", expectID: "my client:1", expectSecret: "bar"},
+		{id: "foo", secret: "b+r", expectID: "foo", expectSecret: "b r"},
+		{id: "foo", secret: "bar%", expectErr: ErrInvalidRequest},
+		{id: "foo%zz", secret: "bar", expectErr: ErrInvalidRequest},
+	} {
+		r := &http.Request{Header: http.Header{}, PostForm: url.Values{}}
+		r.SetBasicAuth(c.id, c.secret)
+		_, id, secret, err := clientCredentials(r)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d)\n%s\n%s", k, err, c.expectErr)
+		assert.Equal(t, c.expectID, id, "%d", k)
+		assert.Equal(t, c.expectSecret, secret, "%d", k)
+	}
+}
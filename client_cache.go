@@ -0,0 +1,139 @@
+package fosite
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ClientCache caches the clients Fosite looks up for TTL, so that the client store is not asked on every request.
+// Only clients which were found are cached, lookups of unknown clients always reach the store.
+//
+// Clients which are updated or deleted are used until their entry expires unless Invalidate is called, see
+// Fosite.InvalidateClient. A ClientCache may be shared by several Fosite instances, e.g. those swapped using
+// AtomicProvider.
+type ClientCache struct {
+	// TTL is the time clients are cached for.
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedClient
+
+	// generations counts the invalidations per client ID and all counts those of InvalidateAll. A client looked up
+	// in the store is only cached if neither changed during the lookup, so that invalidations are not overwritten.
+	generations map[string]uint64
+	all         uint64
+
+	// now returns the current time. Defaults to time.Now.
+	now func() time.Time
+}
+
+type cachedClient struct {
+	client    Client
+	expiresAt time.Time
+}
+
+// NewClientCache returns a ClientCache caching clients for ttl.
+func NewClientCache(ttl time.Duration) *ClientCache {
+	return &ClientCache{TTL: ttl}
+}
+
+// Invalidate removes the client with the given ID from the cache, e.g. because it was updated.
+func (c *ClientCache) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+
+	if c.generations == nil {
+		c.generations = map[string]uint64{}
+	}
+	c.generations[id]++
+}
+
+// InvalidateAll removes all clients from the cache.
+func (c *ClientCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+	c.all++
+}
+
+// get returns the cached client with the given ID. The generation must be passed to set when caching the client
+// looked up in the store instead.
+func (c *ClientCache) get(id string) (client Client, generation uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	generation = c.generation(id)
+	entry, ok := c.entries[id]
+	if !ok {
+		return nil, generation, false
+	} else if !c.getNow().Before(entry.expiresAt) {
+		delete(c.entries, id)
+		return nil, generation, false
+	}
+	return entry.client, generation, true
+}
+
+// set caches client unless it was invalidated after generation was returned by get.
+func (c *ClientCache) set(id string, client Client, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation(id) != generation {
+		return
+	} else if c.entries == nil {
+		c.entries = map[string]cachedClient{}
+	}
+	c.entries[id] = cachedClient{client: client, expiresAt: c.getNow().Add(c.TTL)}
+}
+
+// generation changes whenever the client with the given ID is invalidated. c.mu must be held.
+func (c *ClientCache) generation(id string) uint64 {
+	return c.all + c.generations[id]
+}
+
+func (c *ClientCache) getNow() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// getClient looks up the client with the given ID, using the ClientCache if there is one.
+func (f *Fosite) getClient(ctx context.Context, id string) (Client, error) {
+	var generation uint64
+	if f.ClientCache != nil {
+		cached, g, ok := f.ClientCache.get(id)
+		if ok {
+			return cached, nil
+		}
+		generation = g
+	}
+
+	var client Client
+	var err error
+	if manager, ok := f.Store.(ContextClientManager); ok {
+		client, err = manager.GetClientWithContext(ctx, id)
+	} else {
+		client, err = f.Store.GetClient(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if f.ClientCache != nil {
+		f.ClientCache.set(id, client, generation)
+	}
+	return client, nil
+}
+
+// InvalidateClient removes the client with the given ID from the ClientCache. It must be called whenever a client
+// is updated or deleted, otherwise the previous version is used until it expires. It does nothing if there is no
+// ClientCache.
+func (f *Fosite) InvalidateClient(id string) {
+	if f.ClientCache != nil {
+		f.ClientCache.Invalidate(id)
+	}
+}
@@ -0,0 +1,140 @@
+package fosite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type countingClientManager struct {
+	clients map[string]Client
+	calls   int
+}
+
+func (m *countingClientManager) GetClient(id string) (Client, error) {
+	m.calls++
+	if client, ok := m.clients[id]; ok {
+		return client, nil
+	}
+	return nil, errors.New(ErrNotFound)
+}
+
+type contextClientManager struct {
+	countingClientManager
+	ctx context.Context
+}
+
+func (m *contextClientManager) GetClientWithContext(ctx context.Context, id string) (Client, error) {
+	m.ctx = ctx
+	return m.GetClient(id)
+}
+
+func TestGetClientUsesClientCache(t *testing.T) {
+	now := time.Now()
+	store := &countingClientManager{clients: map[string]Client{"foo": &DefaultClient{ID: "foo"}}}
+	cache := NewClientCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	f := &Fosite{Store: store, ClientCache: cache}
+
+	for k := 0; k < 3; k++ {
+		client, err := f.getClient(nil, "foo")
+		require.Nil(t, err, "%s", err)
+		assert.Equal(t, "foo", client.GetID())
+	}
+	assert.Equal(t, 1, store.calls, "clients are cached")
+
+	// Updated clients are looked up again once they are invalidated.
+	store.clients["foo"] = &DefaultClient{ID: "foo", Public: true}
+	f.InvalidateClient("foo")
+	client, err := f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.True(t, client.IsPublic())
+	assert.Equal(t, 2, store.calls)
+
+	// Clients expire after the TTL.
+	now = now.Add(time.Minute)
+	_, err = f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, 3, store.calls)
+
+	cache.InvalidateAll()
+	_, err = f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, 4, store.calls)
+
+	// Unknown clients are not cached.
+	for k := 0; k < 2; k++ {
+		_, err = f.getClient(nil, "bar")
+		assert.True(t, errors.Is(ErrNotFound, err), "%s", err)
+	}
+	assert.Equal(t, 6, store.calls)
+}
+
+type invalidatingClientManager struct {
+	countingClientManager
+	onGet func()
+}
+
+func (m *invalidatingClientManager) GetClient(id string) (Client, error) {
+	client, err := m.countingClientManager.GetClient(id)
+	m.onGet()
+	return client, err
+}
+
+func TestGetClientDoesNotOverwriteInvalidations(t *testing.T) {
+	store := &invalidatingClientManager{countingClientManager: countingClientManager{clients: map[string]Client{"foo": &DefaultClient{ID: "foo"}}}}
+	f := &Fosite{Store: store, ClientCache: NewClientCache(time.Minute)}
+
+	// The client is updated and invalidated while the previous version is being looked up.
+	store.onGet = func() {
+		store.clients["foo"] = &DefaultClient{ID: "foo", Public: true}
+		f.InvalidateClient("foo")
+	}
+	client, err := f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.False(t, client.IsPublic())
+
+	store.onGet = func() {}
+	client, err = f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.True(t, client.IsPublic(), "the outdated client must not be cached")
+	assert.Equal(t, 2, store.calls)
+
+	store.onGet = func() { f.ClientCache.InvalidateAll() }
+	f.InvalidateClient("foo")
+	_, err = f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	store.onGet = func() {}
+	_, err = f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, 4, store.calls, "clients looked up during InvalidateAll are not cached either")
+
+	_, err = f.getClient(nil, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, 4, store.calls)
+}
+
+func TestGetClientWithoutClientCache(t *testing.T) {
+	store := &countingClientManager{clients: map[string]Client{"foo": &DefaultClient{ID: "foo"}}}
+	f := &Fosite{Store: store}
+	f.InvalidateClient("foo")
+
+	for k := 0; k < 2; k++ {
+		_, err := f.getClient(nil, "foo")
+		require.Nil(t, err, "%s", err)
+	}
+	assert.Equal(t, 2, store.calls)
+}
+
+func TestGetClientPassesContext(t *testing.T) {
+	store := &contextClientManager{countingClientManager: countingClientManager{clients: map[string]Client{"foo": &DefaultClient{ID: "foo"}}}}
+	ctx := context.WithValue(context.Background(), "key", "value")
+
+	_, err := (&Fosite{Store: store}).getClient(ctx, "foo")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, ctx, store.ctx)
+}
@@ -0,0 +1,47 @@
+package fosite
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/token/jwk"
+	"golang.org/x/net/context"
+)
+
+// findClientKey returns the client's public key identified by kid, or the first key usable for verifying alg
+// signatures if kid is empty. Keys registered with the client take precedence over keys published at its
+// jwks_uri. Returns ErrInvalidClient if no such key exists or fetching the client's keys fails.
+func (f *Fosite) findClientKey(ctx context.Context, client Client, kid, alg string) (*jwk.JSONWebKey, error) {
+	var key *jwk.JSONWebKey
+	if keys := client.GetJSONWebKeys(); keys != nil && len(keys.Keys) > 0 {
+		key = findKey(keys, kid, alg)
+	} else if uri := client.GetJSONWebKeysURI(); uri != "" && f.JSONWebKeysFetcher != nil {
+		fetched, err := f.fetchClientKey(ctx, client.GetID(), uri, kid, alg)
+		if err != nil {
+			return nil, errors.New(ErrInvalidClient)
+		}
+		key = fetched
+	}
+
+	if key == nil {
+		return nil, errors.New(ErrInvalidClient)
+	}
+	return key, nil
+}
+
+func (f *Fosite) fetchClientKey(ctx context.Context, id, uri, kid, alg string) (*jwk.JSONWebKey, error) {
+	if kid != "" {
+		return f.JSONWebKeysFetcher.FindByID(ctx, id, uri, kid)
+	}
+
+	keys, err := f.JSONWebKeysFetcher.KeySet(ctx, id, uri)
+	if err != nil {
+		return nil, err
+	}
+	return findKey(keys, kid, alg), nil
+}
+
+func findKey(keys *jwk.JSONWebKeySet, kid, alg string) *jwk.JSONWebKey {
+	if kid != "" {
+		return keys.FindByID(kid)
+	}
+	return keys.Find("sig", alg)
+}
@@ -0,0 +1,94 @@
+package fosite
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/token/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestFindClientKey(t *testing.T) {
+	published := &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{{KeyType: "RSA", KeyID: "published", Use: "sig"}}}
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jwks.json" {
+			http.NotFound(rw, r)
+			return
+		}
+		json.NewEncoder(rw).Encode(published)
+	}))
+	defer ts.Close()
+
+	registered := &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{{KeyType: "RSA", KeyID: "registered", Use: "sig"}}}
+	f := &Fosite{JSONWebKeysFetcher: &jwk.Fetcher{HTTPClient: ts.Client()}}
+
+	for k, c := range []struct {
+		description string
+		fosite      *Fosite
+		client      *DefaultClient
+		kid         string
+		expectKID   string
+		expectErr   error
+	}{
+		{
+			description: "should use registered keys",
+			fosite:      f,
+			client:      &DefaultClient{ID: "foo", JSONWebKeys: registered, JSONWebKeysURI: ts.URL + "/jwks.json"},
+			kid:         "registered",
+			expectKID:   "registered",
+		},
+		{
+			description: "should use keys published at jwks_uri",
+			fosite:      f,
+			client:      &DefaultClient{ID: "foo", JSONWebKeysURI: ts.URL + "/jwks.json"},
+			kid:         "published",
+			expectKID:   "published",
+		},
+		{
+			description: "should find keys without key ID",
+			fosite:      f,
+			client:      &DefaultClient{ID: "foo", JSONWebKeysURI: ts.URL + "/jwks.json"},
+			expectKID:   "published",
+		},
+		{
+			description: "should fail on unknown key IDs",
+			fosite:      f,
+			client:      &DefaultClient{ID: "foo", JSONWebKeys: registered},
+			kid:         "published",
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail if fetching fails",
+			fosite:      f,
+			client:      &DefaultClient{ID: "bar", JSONWebKeysURI: ts.URL + "/unknown"},
+			kid:         "published",
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail if the client has no keys",
+			fosite:      f,
+			client:      &DefaultClient{ID: "foo"},
+			expectErr:   ErrInvalidClient,
+		},
+		{
+			description: "should fail if fetching keys is disabled",
+			fosite:      &Fosite{},
+			client:      &DefaultClient{ID: "foo", JSONWebKeysURI: ts.URL + "/jwks.json"},
+			kid:         "published",
+			expectErr:   ErrInvalidClient,
+		},
+	} {
+		key, err := c.fosite.findClientKey(context.Background(), c.client, c.kid, "RS256")
+		if c.expectErr != nil {
+			assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+			continue
+		}
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+		assert.Equal(t, c.expectKID, key.KeyID, "(%d) %s", k, c.description)
+	}
+}
@@ -1,8 +1,17 @@
 package fosite
 
+import "golang.org/x/net/context"
+
 // ClientManager defines the (persistent) manager interface for clients.
 type ClientManager interface {
 	// GetClient loads the client by its ID or returns an error
 	// if the client does not exist or another error occurred.
 	GetClient(id string) (Client, error)
 }
+
+// ContextClientManager can be implemented by client managers which need the context of the request, e.g. to honor
+// its deadline when calling a remote backend. Fosite then uses GetClientWithContext instead of GetClient.
+type ContextClientManager interface {
+	// GetClientWithContext is GetClient receiving the context of the request.
+	GetClientWithContext(ctx context.Context, id string) (Client, error)
+}
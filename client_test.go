@@ -8,17 +8,32 @@ import (
 
 func TestDefaultClient(t *testing.T) {
 	sc := &DefaultClient{
-		ID:            "1",
-		Secret:        []byte("foobar-"),
-		RedirectURIs:  []string{"foo", "bar"},
-		ResponseTypes: []string{"foo", "bar"},
-		GrantTypes:    []string{"foo", "bar"},
+		ID:                "1",
+		Secret:            []byte("foobar-"),
+		RedirectURIs:      []string{"foo", "bar"},
+		ResponseTypes:     []string{"foo", "bar"},
+		GrantTypes:        []string{"foo", "bar"},
+		RequestParameters: []string{"foo"},
+		RequestURIs:       []string{"https://foo.com/request.jwt"},
+		JSONWebKeysURI:    "https://foo.com/jwks.json",
 	}
 	assert.Equal(t, sc.ID, sc.GetID())
 	assert.Equal(t, sc.RedirectURIs, sc.GetRedirectURIs())
 	assert.Equal(t, sc.Secret, sc.GetHashedSecret())
 	assert.EqualValues(t, sc.ResponseTypes, sc.GetResponseTypes())
 	assert.EqualValues(t, sc.GrantTypes, sc.GetGrantTypes())
+	assert.EqualValues(t, sc.RequestParameters, sc.GetRequestParameters())
+	assert.Equal(t, sc.RequestURIs, sc.GetRequestURIs())
+	assert.Equal(t, sc.JSONWebKeysURI, sc.GetJSONWebKeysURI())
+	assert.Equal(t, ApplicationTypeWeb, sc.GetApplicationType())
+	sc.ApplicationType = ApplicationTypeNative
+	assert.Equal(t, ApplicationTypeNative, sc.GetApplicationType())
+	assert.Equal(t, Arguments(sc.RequiredACRValues), sc.GetRequiredACRValues())
+	assert.Equal(t, Arguments(sc.AllowedPrompts), sc.GetAllowedPrompts())
+	assert.Equal(t, Arguments(sc.ResponseModes), sc.GetResponseModes())
+	assert.False(t, sc.GetRequirePushedAuthorizationRequests())
+	sc.RequirePushedAuthorizationRequests = true
+	assert.True(t, sc.GetRequirePushedAuthorizationRequests())
 
 	assert.False(t, sc.GetGrantedScopes().Grant("foo.bar.baz"))
 	assert.False(t, sc.GetGrantedScopes().Grant("foo.bar"))
@@ -37,6 +52,7 @@ func TestDefaultClient(t *testing.T) {
 	assert.False(t, sc.GetGrantedScopes().Grant("baz"))
 
 	sc.GrantedScopes = []string{"fosite.keys.create", "fosite.keys.get", "fosite.keys.delete", "fosite.keys.update"}
+	assert.EqualValues(t, sc.GrantedScopes, sc.GetScopes())
 	assert.True(t, sc.GetGrantedScopes().Grant("fosite.keys.delete"))
 	assert.True(t, sc.GetGrantedScopes().Grant("fosite.keys.get"))
 	assert.True(t, sc.GetGrantedScopes().Grant("fosite.keys.get"))
@@ -46,6 +62,17 @@ func TestDefaultClient(t *testing.T) {
 	sc.ResponseTypes = []string{}
 	assert.Equal(t, "code", sc.GetResponseTypes()[0])
 	assert.Equal(t, "authorization_code", sc.GetGrantTypes()[0])
+	assert.Equal(t, "RS256", sc.GetIDTokenSignedResponseAlg())
+
+	sc.IDTokenSignedResponseAlg = "ES256"
+	assert.Equal(t, "ES256", sc.GetIDTokenSignedResponseAlg())
+
+	assert.Empty(t, sc.GetIDTokenEncryptedResponseAlg())
+	assert.Empty(t, sc.GetIDTokenEncryptedResponseEnc())
+	sc.IDTokenEncryptedResponseAlg = "RSA-OAEP"
+	assert.Equal(t, "A128CBC-HS256", sc.GetIDTokenEncryptedResponseEnc())
+	sc.IDTokenEncryptedResponseEnc = "A256GCM"
+	assert.Equal(t, "A256GCM", sc.GetIDTokenEncryptedResponseEnc())
 }
 
 func TestDefaultScope(t *testing.T) {
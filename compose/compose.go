@@ -0,0 +1,82 @@
+// Package compose wires up fosite and its handlers, so that applications do not have to assemble the handlers
+// and their shared dependencies by hand.
+//
+//	strategy := &compose.CommonStrategy{
+//	    CoreStrategy:               compose.NewOAuth2HMACStrategy(secret),
+//	    OpenIDConnectTokenStrategy: compose.NewOpenIDConnectStrategy(key),
+//	}
+//	if err := strategy.ValidateConfiguration(); err != nil {
+//	    log.Fatal(err)
+//	}
+//	oauth2 := compose.ComposeAllEnabled(&compose.Config{}, store, strategy)
+//
+// Use compose.NewOAuth2JWTStrategy(key) instead of compose.NewOAuth2HMACStrategy(secret) to issue JWTs instead of
+// opaque tokens, the handlers work with either.
+package compose
+
+import (
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/hash"
+)
+
+// Factory creates a handler using config, storage and strategy. The handler is registered with every
+// endpoint whose handler interface it implements, unless the factory returns a *Registration.
+type Factory func(config *Config, storage interface{}, strategy interface{}) interface{}
+
+// Compose returns a fosite instance using the handlers created by factories. Handlers are registered in the
+// order of factories and only with the endpoints whose handler interface they implement:
+//
+// * fosite.AuthorizeEndpointHandler for the authorize endpoint.
+// * fosite.TokenEndpointHandler for the token endpoint.
+// * fosite.RevocationHandler for the revocation endpoint.
+// * fosite.TokenIntrospector for the introspection endpoint.
+// * fosite.AuthorizedRequestValidator for validating requests to resource servers.
+//
+// storage must implement fosite.Storage as well as the storage interfaces of the handlers and strategy the
+// strategy interfaces of the handlers. Compose panics on startup if this is not the case, if a factory returns
+// a handler which implements none of the handler interfaces or a *Registration with an endpoint its handler
+// does not implement.
+func Compose(config *Config, storage interface{}, strategy interface{}, factories ...Factory) fosite.OAuth2Provider {
+	f := fosite.NewFosite(storage.(fosite.Storage))
+	f.Hasher = &hash.BCrypt{WorkFactor: config.GetHashCost()}
+	f.ScopeStrategy = config.GetScopeStrategy()
+
+	for _, factory := range factories {
+		res := factory(config, storage, strategy)
+
+		var err error
+		if r, ok := res.(*Registration); ok {
+			err = Register(f, r.Handler, r.Endpoints...)
+		} else {
+			err = Register(f, res, implementedEndpoints(res)...)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return f
+}
+
+// ComposeAllEnabled returns a fosite instance with all OAuth2 and OpenID Connect handlers enabled. strategy must
+// be a *CommonStrategy.
+func ComposeAllEnabled(config *Config, storage interface{}, strategy interface{}) fosite.OAuth2Provider {
+	return Compose(
+		config,
+		storage,
+		strategy,
+		OAuth2AuthorizeExplicitFactory,
+		OAuth2PKCEFactory,
+		OAuth2AuthorizeImplicitFactory,
+		OAuth2ClientCredentialsGrantFactory,
+		OAuth2RefreshTokenGrantFactory,
+		OAuth2ResourceOwnerPasswordCredentialsFactory,
+		OAuth2TokenRevocationFactory,
+		OAuth2TokenIntrospectionFactory,
+
+		OpenIDConnectExplicit,
+		OpenIDConnectImplicit,
+		OpenIDConnectHybrid,
+		OpenIDConnectRefresh,
+	)
+}
@@ -0,0 +1,130 @@
+package compose
+
+import (
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/client"
+	"github.com/ory-am/fosite/handler/core/explicit"
+	"github.com/ory-am/fosite/handler/core/implicit"
+	"github.com/ory-am/fosite/handler/core/owner"
+	"github.com/ory-am/fosite/handler/core/pkce"
+	"github.com/ory-am/fosite/handler/core/refresh"
+	"github.com/ory-am/fosite/handler/core/revocation"
+)
+
+// OAuth2AuthorizeExplicitFactory creates an OAuth2 authorize code grant ("authorize explicit flow") handler and
+// registers it with the authorize and the token endpoint.
+func OAuth2AuthorizeExplicitFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &explicit.AuthorizeExplicitGrantTypeHandler{
+		AccessTokenStrategy:       strategy.(core.AccessTokenStrategy),
+		RefreshTokenStrategy:      strategy.(core.RefreshTokenStrategy),
+		AuthorizeCodeStrategy:     strategy.(core.AuthorizeCodeStrategy),
+		AuthorizeCodeGrantStorage: storage.(explicit.AuthorizeCodeGrantStorage),
+		AuthCodeLifespan:          config.GetAuthorizeCodeLifespan(),
+		AccessTokenLifespan:       config.GetAccessTokenLifespan(),
+		RefreshTokenLifespan:      config.GetRefreshTokenLifespan(),
+		RefreshTokenMaxLifespan:   config.GetRefreshTokenMaxLifespan(),
+
+		IncludeRefreshTokenExpiresIn: config.IncludeRefreshTokenExpiresIn,
+	}
+}
+
+// OAuth2PKCEFactory creates a PKCE handler and registers it with the authorize and the token endpoint. It must
+// come after OAuth2AuthorizeExplicitFactory, whose handler loads the authorize request at the token endpoint.
+func OAuth2PKCEFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &pkce.Handler{
+		Force:                      config.EnforcePKCE,
+		ForceForPublicClients:      config.EnforcePKCEForPublicClients,
+		EnablePlainChallengeMethod: config.EnablePKCEPlainChallengeMethod,
+	}
+}
+
+// OAuth2AuthorizeImplicitFactory creates an OAuth2 implicit grant ("authorize implicit flow") handler and
+// registers it with the authorize endpoint.
+func OAuth2AuthorizeImplicitFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &implicit.AuthorizeImplicitGrantTypeHandler{
+		AccessTokenStrategy: strategy.(core.AccessTokenStrategy),
+		AccessTokenStorage:  storage.(core.AccessTokenStorage),
+		AccessTokenLifespan: config.GetAccessTokenLifespan(),
+	}
+}
+
+// OAuth2ClientCredentialsGrantFactory creates an OAuth2 client credentials grant handler and registers it with
+// the token endpoint.
+func OAuth2ClientCredentialsGrantFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &client.ClientCredentialsGrantHandler{
+		HandleHelper: newHandleHelper(config, storage, strategy),
+	}
+}
+
+// OAuth2RefreshTokenGrantFactory creates an OAuth2 refresh grant handler and registers it with the token
+// endpoint.
+func OAuth2RefreshTokenGrantFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &refresh.RefreshTokenGrantHandler{
+		AccessTokenStrategy:      strategy.(core.AccessTokenStrategy),
+		RefreshTokenStrategy:     strategy.(core.RefreshTokenStrategy),
+		RefreshTokenGrantStorage: storage.(refresh.RefreshTokenGrantStorage),
+		AccessTokenLifespan:      config.GetAccessTokenLifespan(),
+		RefreshTokenLifespan:     config.GetRefreshTokenLifespan(),
+		RefreshTokenMaxLifespan:  config.GetRefreshTokenMaxLifespan(),
+		ScopeStrategy:            config.GetScopeStrategy(),
+		ConfirmationExtractor:    config.ConfirmationExtractor,
+
+		IncludeRefreshTokenExpiresIn: config.IncludeRefreshTokenExpiresIn,
+	}
+}
+
+// OAuth2ResourceOwnerPasswordCredentialsFactory creates an OAuth2 resource owner password credentials grant
+// handler and registers it with the token endpoint.
+func OAuth2ResourceOwnerPasswordCredentialsFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &owner.ResourceOwnerPasswordCredentialsGrantHandler{
+		HandleHelper: newHandleHelper(config, storage, strategy),
+		ResourceOwnerPasswordCredentialsGrantStorage: storage.(owner.ResourceOwnerPasswordCredentialsGrantStorage),
+	}
+}
+
+// OAuth2TokenIntrospectionFactory creates an access token validator and registers it for validating requests to
+// resource servers and with the introspection endpoint.
+//
+// The factories of optional endpoints, introspection and revocation, leave the storage nil instead of panicking if
+// storage does not implement the interface they require. The endpoint then responds with
+// fosite.ErrUnsupportedEndpoint and fosite.Fosite.ValidateConfiguration reports the missing storage.
+func OAuth2TokenIntrospectionFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	accessTokenStrategy, _ := strategy.(core.AccessTokenStrategy)
+	accessTokenStorage, _ := storage.(core.AccessTokenStorage)
+	return &core.CoreValidator{
+		AccessTokenStrategy: accessTokenStrategy,
+		AccessTokenStorage:  accessTokenStorage,
+	}
+}
+
+// OAuth2RefreshTokenIntrospectionFactory creates a refresh token introspector and registers it with the
+// introspection endpoint. It is not part of ComposeAllEnabled because introspecting refresh tokens is optional.
+func OAuth2RefreshTokenIntrospectionFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	refreshTokenStrategy, _ := strategy.(core.RefreshTokenStrategy)
+	refreshTokenStorage, _ := storage.(core.RefreshTokenStorage)
+	return &refresh.RefreshTokenIntrospector{
+		RefreshTokenStrategy: refreshTokenStrategy,
+		RefreshTokenStorage:  refreshTokenStorage,
+	}
+}
+
+// OAuth2TokenRevocationFactory creates an access and refresh token revocation handler and registers it with the
+// revocation endpoint.
+func OAuth2TokenRevocationFactory(config *Config, storage interface{}, strategy interface{}) interface{} {
+	accessTokenStrategy, _ := strategy.(core.AccessTokenStrategy)
+	refreshTokenStrategy, _ := strategy.(core.RefreshTokenStrategy)
+	revocationStorage, _ := storage.(revocation.TokenRevocationStorage)
+	return &revocation.TokenRevocationHandler{
+		AccessTokenStrategy:    accessTokenStrategy,
+		RefreshTokenStrategy:   refreshTokenStrategy,
+		TokenRevocationStorage: revocationStorage,
+	}
+}
+
+func newHandleHelper(config *Config, storage interface{}, strategy interface{}) *core.HandleHelper {
+	return &core.HandleHelper{
+		AccessTokenStrategy: strategy.(core.AccessTokenStrategy),
+		AccessTokenStorage:  storage.(core.AccessTokenStorage),
+		AccessTokenLifespan: config.GetAccessTokenLifespan(),
+	}
+}
@@ -0,0 +1,80 @@
+package compose
+
+import (
+	"github.com/ory-am/fosite/handler/core/explicit"
+	"github.com/ory-am/fosite/handler/core/implicit"
+	"github.com/ory-am/fosite/handler/oidc"
+	oidcexplicit "github.com/ory-am/fosite/handler/oidc/explicit"
+	"github.com/ory-am/fosite/handler/oidc/hybrid"
+	oidcimplicit "github.com/ory-am/fosite/handler/oidc/implicit"
+	oidcrefresh "github.com/ory-am/fosite/handler/oidc/refresh"
+	oidcstrategy "github.com/ory-am/fosite/handler/oidc/strategy"
+	"github.com/ory-am/fosite/token/jwt"
+)
+
+// OpenIDConnectExplicit creates an OpenID Connect explicit ("authorize code flow") handler and registers it with
+// the authorize and the token endpoint. This handler does not issue access tokens, use it together with
+// OAuth2AuthorizeExplicitFactory.
+//
+// strategy must be a *CommonStrategy.
+func OpenIDConnectExplicit(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &oidcexplicit.OpenIDConnectExplicitHandler{
+		OpenIDConnectRequestStorage: storage.(oidc.OpenIDConnectRequestStorage),
+		IDTokenHandleHelper:         newIDTokenHandleHelper(strategy),
+		RS256JWTStrategy:            newJWTStrategy(strategy),
+	}
+}
+
+// OpenIDConnectRefresh creates an OpenID Connect refresh handler and registers it with the token endpoint. It issues
+// a new ID token whenever a refresh token granted the openid scope is used. This handler does not issue access
+// tokens, use it together with OAuth2RefreshTokenGrantFactory.
+//
+// strategy must be a *CommonStrategy.
+func OpenIDConnectRefresh(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &oidcrefresh.OpenIDConnectRefreshHandler{
+		IDTokenHandleHelper: newIDTokenHandleHelper(strategy),
+		RS256JWTStrategy:    newJWTStrategy(strategy),
+	}
+}
+
+// OpenIDConnectImplicit creates an OpenID Connect implicit ("implicit flow") handler and registers it with the
+// authorize endpoint.
+//
+// strategy must be a *CommonStrategy.
+func OpenIDConnectImplicit(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &oidcimplicit.OpenIDConnectImplicitHandler{
+		AuthorizeImplicitGrantTypeHandler: OAuth2AuthorizeImplicitFactory(config, storage, strategy).(*implicit.AuthorizeImplicitGrantTypeHandler),
+		IDTokenHandleHelper:               newIDTokenHandleHelper(strategy),
+		RS256JWTStrategy:                  newJWTStrategy(strategy),
+	}
+}
+
+// OpenIDConnectHybrid creates an OpenID Connect hybrid ("hybrid flow") handler and registers it with the
+// authorize endpoint.
+//
+// strategy must be a *CommonStrategy.
+func OpenIDConnectHybrid(config *Config, storage interface{}, strategy interface{}) interface{} {
+	return &hybrid.OpenIDConnectHybridHandler{
+		AuthorizeExplicitGrantTypeHandler: OAuth2AuthorizeExplicitFactory(config, storage, strategy).(*explicit.AuthorizeExplicitGrantTypeHandler),
+		AuthorizeImplicitGrantTypeHandler: OAuth2AuthorizeImplicitFactory(config, storage, strategy).(*implicit.AuthorizeImplicitGrantTypeHandler),
+		IDTokenHandleHelper:               newIDTokenHandleHelper(strategy),
+		Enigma:                            newJWTStrategy(strategy),
+	}
+}
+
+func newIDTokenHandleHelper(strategy interface{}) *oidc.IDTokenHandleHelper {
+	return &oidc.IDTokenHandleHelper{
+		IDTokenStrategy: strategy.(oidc.OpenIDConnectTokenStrategy),
+	}
+}
+
+// newJWTStrategy returns the JWTStrategy of a CommonStrategy, falling back to the key of the ID token strategy.
+func newJWTStrategy(strategy interface{}) *jwt.RS256JWTStrategy {
+	common := strategy.(*CommonStrategy)
+	if common.JWTStrategy != nil {
+		return common.JWTStrategy
+	} else if ds, ok := common.OpenIDConnectTokenStrategy.(*oidcstrategy.DefaultStrategy); ok {
+		return ds.RS256JWTStrategy
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package compose
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/client"
+	"github.com/ory-am/fosite/handler/core/explicit"
+	"github.com/ory-am/fosite/handler/core/implicit"
+	"github.com/ory-am/fosite/handler/core/owner"
+	"github.com/ory-am/fosite/handler/core/pkce"
+	"github.com/ory-am/fosite/handler/core/refresh"
+	"github.com/ory-am/fosite/handler/core/revocation"
+	corestrategy "github.com/ory-am/fosite/handler/core/strategy"
+	oidcexplicit "github.com/ory-am/fosite/handler/oidc/explicit"
+	"github.com/ory-am/fosite/handler/oidc/hybrid"
+	oidcimplicit "github.com/ory-am/fosite/handler/oidc/implicit"
+	oidcrefresh "github.com/ory-am/fosite/handler/oidc/refresh"
+	"github.com/ory-am/fosite/hash"
+	"github.com/ory-am/fosite/internal"
+	"github.com/ory-am/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestComposeAllEnabled(t *testing.T) {
+	strategy := &CommonStrategy{
+		CoreStrategy:               NewOAuth2HMACStrategy([]byte("some-super-cool-secret-that-nobody-knows")),
+		OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(internal.MustRSAKey()),
+	}
+	f, ok := ComposeAllEnabled(&Config{HashCost: 4}, &store.Store{}, strategy).(*fosite.Fosite)
+	require.True(t, ok)
+
+	assert.IsType(t, &explicit.AuthorizeExplicitGrantTypeHandler{}, f.AuthorizeEndpointHandlers[0])
+	assert.IsType(t, &pkce.Handler{}, f.AuthorizeEndpointHandlers[1])
+	assert.IsType(t, &implicit.AuthorizeImplicitGrantTypeHandler{}, f.AuthorizeEndpointHandlers[2])
+	assert.IsType(t, &oidcexplicit.OpenIDConnectExplicitHandler{}, f.AuthorizeEndpointHandlers[3])
+	assert.IsType(t, &oidcimplicit.OpenIDConnectImplicitHandler{}, f.AuthorizeEndpointHandlers[4])
+	assert.IsType(t, &hybrid.OpenIDConnectHybridHandler{}, f.AuthorizeEndpointHandlers[5])
+	assert.Len(t, f.AuthorizeEndpointHandlers, 6)
+
+	assert.IsType(t, &explicit.AuthorizeExplicitGrantTypeHandler{}, f.TokenEndpointHandlers[0])
+	assert.IsType(t, &pkce.Handler{}, f.TokenEndpointHandlers[1])
+	assert.IsType(t, &client.ClientCredentialsGrantHandler{}, f.TokenEndpointHandlers[2])
+	assert.IsType(t, &refresh.RefreshTokenGrantHandler{}, f.TokenEndpointHandlers[3])
+	assert.IsType(t, &owner.ResourceOwnerPasswordCredentialsGrantHandler{}, f.TokenEndpointHandlers[4])
+	assert.IsType(t, &oidcexplicit.OpenIDConnectExplicitHandler{}, f.TokenEndpointHandlers[5])
+	assert.IsType(t, &oidcrefresh.OpenIDConnectRefreshHandler{}, f.TokenEndpointHandlers[6])
+	assert.Len(t, f.TokenEndpointHandlers, 7)
+
+	assert.IsType(t, &revocation.TokenRevocationHandler{}, f.RevocationHandlers[0])
+	assert.Len(t, f.RevocationHandlers, 1)
+
+	assert.IsType(t, &core.CoreValidator{}, f.AuthorizedRequestValidators[0])
+	assert.Len(t, f.AuthorizedRequestValidators, 1)
+	assert.IsType(t, &core.CoreValidator{}, f.TokenIntrospectors[0])
+	assert.Len(t, f.TokenIntrospectors, 1)
+
+	// The OpenID Connect handlers share the key of the ID token strategy for computing token hashes
+	h := f.AuthorizeEndpointHandlers[5].(*hybrid.OpenIDConnectHybridHandler)
+	assert.NotNil(t, h.Enigma)
+	assert.Equal(t, time.Hour, h.AuthorizeImplicitGrantTypeHandler.AccessTokenLifespan)
+}
+
+func TestComposeUsesTheConfiguredScopeStrategy(t *testing.T) {
+	strategy := &CommonStrategy{
+		CoreStrategy:               NewOAuth2HMACStrategy([]byte("some-super-cool-secret-that-nobody-knows")),
+		OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(internal.MustRSAKey()),
+	}
+	exact := func(haystack []string, needle string) bool {
+		return fosite.StringInSlice(needle, haystack)
+	}
+
+	f := ComposeAllEnabled(&Config{HashCost: 4}, &store.Store{}, strategy).(*fosite.Fosite)
+	assert.True(t, f.GetScopeStrategy()([]string{"photos"}, "photos.read"), "defaults to the hierarchic scope strategy")
+	assert.True(t, f.TokenEndpointHandlers[3].(*refresh.RefreshTokenGrantHandler).ScopeStrategy([]string{"photos"}, "photos.read"))
+
+	f = ComposeAllEnabled(&Config{HashCost: 4, ScopeStrategy: exact}, &store.Store{}, strategy).(*fosite.Fosite)
+	assert.False(t, f.GetScopeStrategy()([]string{"photos"}, "photos.read"))
+	assert.False(t, f.TokenEndpointHandlers[3].(*refresh.RefreshTokenGrantHandler).ScopeStrategy([]string{"photos"}, "photos.read"), "refresh tokens are downscoped using the same strategy")
+}
+
+func TestComposedAuthorizeRequestsAreValidatedBeforeConsent(t *testing.T) {
+	strategy := &CommonStrategy{
+		CoreStrategy:               NewOAuth2HMACStrategy([]byte("some-super-cool-secret-that-nobody-knows")),
+		OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(internal.MustRSAKey()),
+	}
+	s := store.NewStore()
+	s.Clients["code-only"] = &fosite.DefaultClient{
+		ID:            "code-only",
+		RedirectURIs:  []string{"https://foo.bar/cb"},
+		ResponseTypes: []string{"code"},
+		GrantTypes:    []string{"authorization_code"},
+		GrantedScopes: []string{fosite.DefaultMandatoryScope, "openid"},
+	}
+	f := ComposeAllEnabled(&Config{HashCost: 4}, s, strategy)
+
+	for k, c := range []struct {
+		description  string
+		responseType string
+		scope        string
+		expectErr    error
+	}{
+		{description: "should pass", responseType: "code", scope: fosite.DefaultMandatoryScope},
+		{description: "should fail because no handler supports the response type", responseType: "code id_token", scope: fosite.DefaultMandatoryScope, expectErr: fosite.ErrUnsupportedResponseType},
+		{description: "should fail because the client may not use the implicit flow", responseType: "token", scope: fosite.DefaultMandatoryScope, expectErr: fosite.ErrInvalidGrant},
+		{description: "should fail because the client may not receive ID tokens", responseType: "code", scope: fosite.DefaultMandatoryScope + " openid", expectErr: fosite.ErrInvalidRequest},
+		{description: "should fail because the client may not use the hybrid flow", responseType: "code token", scope: fosite.DefaultMandatoryScope, expectErr: fosite.ErrInvalidGrant},
+		{description: "should fail because an ID token was requested without the openid scope", responseType: "id_token", scope: fosite.DefaultMandatoryScope, expectErr: fosite.ErrInvalidRequest},
+		{description: "should fail because an ID token was requested without the openid scope", responseType: "token id_token", scope: fosite.DefaultMandatoryScope, expectErr: fosite.ErrInvalidRequest},
+	} {
+		query := url.Values{
+			"client_id":     {"code-only"},
+			"redirect_uri":  {"https://foo.bar/cb"},
+			"response_type": {c.responseType},
+			"scope":         {c.scope},
+			"state":         {"strong-state"},
+		}
+		_, err := f.NewAuthorizeRequest(context.Background(), &http.Request{Header: http.Header{}, URL: &url.URL{RawQuery: query.Encode()}})
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s: %s", k, c.description, err)
+	}
+}
+
+func TestComposedPKCE(t *testing.T) {
+	strategy := &CommonStrategy{
+		CoreStrategy:               NewOAuth2HMACStrategy([]byte("some-super-cool-secret-that-nobody-knows")),
+		OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(internal.MustRSAKey()),
+	}
+	s := store.NewStore()
+	s.Clients["public"] = &fosite.DefaultClient{
+		ID:            "public",
+		Public:        true,
+		RedirectURIs:  []string{"https://foo.bar/cb"},
+		ResponseTypes: []string{"code"},
+		GrantTypes:    []string{"authorization_code"},
+		GrantedScopes: []string{fosite.DefaultMandatoryScope},
+	}
+	f := ComposeAllEnabled(&Config{HashCost: 4, EnforcePKCEForPublicClients: true}, s, strategy)
+
+	verifier := "some-code-verifier-which-is-long-enough-to-be-valid"
+	hash := sha256.Sum256([]byte(verifier))
+	authorize := func(challenge string) (string, error) {
+		query := url.Values{
+			"client_id":     {"public"},
+			"redirect_uri":  {"https://foo.bar/cb"},
+			"response_type": {"code"},
+			"scope":         {fosite.DefaultMandatoryScope},
+			"state":         {"strong-state"},
+		}
+		if challenge != "" {
+			query.Set("code_challenge", challenge)
+			query.Set("code_challenge_method", "S256")
+		}
+		ar, err := f.NewAuthorizeRequest(context.Background(), &http.Request{Header: http.Header{}, URL: &url.URL{RawQuery: query.Encode()}})
+		if err != nil {
+			return "", err
+		}
+		ar.GrantScope(fosite.DefaultMandatoryScope)
+		resp, err := f.NewAuthorizeResponse(context.Background(), &http.Request{}, ar, &struct{}{})
+		if err != nil {
+			return "", err
+		}
+		return resp.GetQuery().Get("code"), nil
+	}
+	exchange := func(code, verifier string) error {
+		r := &http.Request{Method: "POST", Header: http.Header{}, PostForm: url.Values{
+			"grant_type":    {"authorization_code"},
+			"client_id":     {"public"},
+			"code":          {code},
+			"redirect_uri":  {"https://foo.bar/cb"},
+			"code_verifier": {verifier},
+		}}
+		_, err := f.NewAccessRequest(context.Background(), r, &struct{}{})
+		return err
+	}
+
+	_, err := authorize("")
+	assert.True(t, errors.Is(fosite.ErrInvalidRequest, err), "public clients must use PKCE: %s", err)
+
+	code, err := authorize(base64.RawURLEncoding.EncodeToString(hash[:]))
+	require.Nil(t, err, "%s", err)
+	assert.True(t, errors.Is(fosite.ErrInvalidGrant, exchange(code, "another-code-verifier-which-is-long-enough-to-be-valid")))
+	assert.Nil(t, exchange(code, verifier))
+}
+
+func TestComposedHandlersWorkWithEitherCoreStrategy(t *testing.T) {
+	secret, err := (&hash.BCrypt{WorkFactor: 4}).Hash([]byte("bar"))
+	require.Nil(t, err)
+
+	for k, c := range []struct {
+		description string
+		strategy    core.CoreStrategy
+	}{
+		{description: "opaque tokens", strategy: NewOAuth2HMACStrategy([]byte("some-super-cool-secret-that-nobody-knows"))},
+		{description: "JWTs", strategy: NewOAuth2JWTStrategy(internal.MustRSAKey())},
+	} {
+		s := store.NewStore()
+		s.Clients["foo"] = &fosite.DefaultClient{
+			ID:            "foo",
+			Secret:        secret,
+			GrantTypes:    []string{"client_credentials"},
+			GrantedScopes: []string{fosite.DefaultMandatoryScope},
+		}
+		f := ComposeAllEnabled(&Config{HashCost: 4}, s, &CommonStrategy{
+			CoreStrategy:               c.strategy,
+			OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(internal.MustRSAKey()),
+		}).(*fosite.Fosite)
+
+		r := &http.Request{
+			Method:   "POST",
+			Header:   http.Header{},
+			PostForm: url.Values{"grant_type": {"client_credentials"}, "scope": {fosite.DefaultMandatoryScope}},
+		}
+		r.SetBasicAuth("foo", "bar")
+
+		// JWTs carry their expiry, the HMAC strategy ignores the session.
+		session := &corestrategy.JWTSession{JWTClaims: &jwt.JWTClaims{ExpiresAt: time.Now().Add(time.Hour)}}
+		ar, err := f.NewAccessRequest(context.Background(), r, session)
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+		resp, err := f.NewAccessResponse(context.Background(), r, ar)
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+
+		_, err = f.ValidateToken(context.Background(), resp.GetAccessToken(), &corestrategy.JWTSession{}, "")
+		assert.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+	}
+}
+
+func TestValidateCommonStrategy(t *testing.T) {
+	strong, err := rsa.GenerateKey(rand.Reader, jwt.MinRSAKeySize)
+	require.Nil(t, err, "%s", err)
+	secret := []byte("some-super-cool-secret-that-nobody-knows")
+
+	for k, c := range []struct {
+		description string
+		strategy    *CommonStrategy
+		expectError bool
+	}{
+		{
+			description: "should pass with a long secret and strong keys",
+			strategy:    &CommonStrategy{CoreStrategy: NewOAuth2HMACStrategy(secret), OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(strong)},
+		},
+		{
+			description: "should pass with a strong key for JWTs",
+			strategy:    &CommonStrategy{CoreStrategy: NewOAuth2JWTStrategy(strong), JWTStrategy: &jwt.RS256JWTStrategy{PrivateKey: strong}},
+		},
+		{
+			description: "should fail because the HMAC secret is too short",
+			strategy:    &CommonStrategy{CoreStrategy: NewOAuth2HMACStrategy([]byte("foobarfoobarfoobarfoobar")), OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(strong)},
+			expectError: true,
+		},
+		{
+			description: "should fail because the ID token key is too weak",
+			strategy:    &CommonStrategy{CoreStrategy: NewOAuth2HMACStrategy(secret), OpenIDConnectTokenStrategy: NewOpenIDConnectStrategy(internal.MustRSAKey())},
+			expectError: true,
+		},
+		{
+			description: "should fail because the signing key is missing",
+			strategy:    &CommonStrategy{CoreStrategy: NewOAuth2JWTStrategy(nil)},
+			expectError: true,
+		},
+		{
+			description: "should fail because the issuer is not a valid URL",
+			strategy: &CommonStrategy{CoreStrategy: &corestrategy.RS256JWTStrategy{
+				RS256JWTStrategy: &jwt.RS256JWTStrategy{PrivateKey: strong},
+				Issuer:           "auth.example.com",
+			}},
+			expectError: true,
+		},
+	} {
+		err := c.strategy.ValidateConfiguration()
+		assert.Equal(t, c.expectError, err != nil, "(%d) %s: %s", k, c.description, err)
+	}
+}
+
+func TestOptionalEndpointFactoriesWithoutStorage(t *testing.T) {
+	strategy := NewOAuth2HMACStrategy([]byte("some-super-cool-secret-that-nobody-knows"))
+	storage := struct{}{}
+
+	revocationHandler := OAuth2TokenRevocationFactory(&Config{}, storage, strategy).(*revocation.TokenRevocationHandler)
+	assert.NotNil(t, revocationHandler.ValidateConfiguration())
+	err := revocationHandler.RevokeToken(nil, "token", "", &fosite.DefaultClient{ID: "foo"})
+	assert.True(t, errors.Is(fosite.ErrUnsupportedEndpoint, err), "%s", err)
+
+	validator := OAuth2TokenIntrospectionFactory(&Config{}, storage, strategy).(*core.CoreValidator)
+	assert.NotNil(t, validator.ValidateConfiguration())
+
+	introspector := OAuth2RefreshTokenIntrospectionFactory(&Config{}, storage, strategy).(*refresh.RefreshTokenIntrospector)
+	assert.NotNil(t, introspector.ValidateConfiguration())
+
+	f := &fosite.Fosite{Store: &store.Store{}, Hasher: &hash.BCrypt{}, RevocationHandlers: fosite.RevocationHandlers{revocationHandler}}
+	assert.NotNil(t, f.ValidateConfiguration())
+}
+
+func TestConfigDefaults(t *testing.T) {
+	c := &Config{}
+	assert.Equal(t, time.Hour, c.GetAccessTokenLifespan())
+	assert.Equal(t, time.Minute*15, c.GetAuthorizeCodeLifespan())
+	assert.Equal(t, 12, c.GetHashCost())
+
+	c = &Config{AccessTokenLifespan: time.Minute, AuthorizeCodeLifespan: time.Second, HashCost: 10}
+	assert.Equal(t, time.Minute, c.GetAccessTokenLifespan())
+	assert.Equal(t, time.Second, c.GetAuthorizeCodeLifespan())
+	assert.Equal(t, 10, c.GetHashCost())
+}
@@ -0,0 +1,88 @@
+package compose
+
+import (
+	"time"
+
+	"github.com/ory-am/fosite"
+)
+
+// Config configures the handlers created by Compose. The zero value uses sensible defaults.
+type Config struct {
+	// AccessTokenLifespan sets how long an access token is going to be valid. Defaults to one hour.
+	AccessTokenLifespan time.Duration
+
+	// AuthorizeCodeLifespan sets how long an authorize code is going to be valid. Defaults to fifteen minutes.
+	AuthorizeCodeLifespan time.Duration
+
+	// RefreshTokenLifespan sets how long a refresh token is going to be valid. Refresh tokens do not expire if zero.
+	RefreshTokenLifespan time.Duration
+
+	// RefreshTokenMaxLifespan sets how long refresh tokens can be refreshed in total. There is no limit if zero.
+	RefreshTokenMaxLifespan time.Duration
+
+	// IncludeRefreshTokenExpiresIn adds the non-standard refresh_token_expires_in parameter to token responses
+	// containing a refresh token.
+	IncludeRefreshTokenExpiresIn bool
+
+	// ConfirmationExtractor returns the key a client proved possession of, e.g.
+	// fosite.TLSClientCertificateConfirmation. Refresh tokens bound to a key are rejected if nil.
+	ConfirmationExtractor fosite.ConfirmationExtractor
+
+	// ScopeStrategy is used to match scopes at the authorize, token and introspection endpoints and when refresh
+	// tokens are downscoped. Defaults to fosite.HierarchicScopeStrategy.
+	ScopeStrategy fosite.ScopeStrategy
+
+	// HashCost sets the cost of the password hashing cost. Defaults to 12.
+	HashCost int
+
+	// EnforcePKCE requires every client to use PKCE when requesting authorize codes.
+	EnforcePKCE bool
+
+	// EnforcePKCEForPublicClients requires public clients to use PKCE when requesting authorize codes.
+	EnforcePKCEForPublicClients bool
+
+	// EnablePKCEPlainChallengeMethod allows the plain code_challenge_method. Only S256 is accepted if false.
+	EnablePKCEPlainChallengeMethod bool
+}
+
+// GetAccessTokenLifespan returns how long an access token should be valid. Defaults to one hour.
+func (c *Config) GetAccessTokenLifespan() time.Duration {
+	if c.AccessTokenLifespan == 0 {
+		return time.Hour
+	}
+	return c.AccessTokenLifespan
+}
+
+// GetAuthorizeCodeLifespan returns how long an authorize code should be valid. Defaults to fifteen minutes.
+func (c *Config) GetAuthorizeCodeLifespan() time.Duration {
+	if c.AuthorizeCodeLifespan == 0 {
+		return time.Minute * 15
+	}
+	return c.AuthorizeCodeLifespan
+}
+
+// GetRefreshTokenLifespan returns how long a refresh token should be valid. Refresh tokens do not expire if zero.
+func (c *Config) GetRefreshTokenLifespan() time.Duration {
+	return c.RefreshTokenLifespan
+}
+
+// GetRefreshTokenMaxLifespan returns how long refresh tokens can be refreshed in total. There is no limit if zero.
+func (c *Config) GetRefreshTokenMaxLifespan() time.Duration {
+	return c.RefreshTokenMaxLifespan
+}
+
+// GetScopeStrategy returns the strategy for matching scopes. Defaults to fosite.HierarchicScopeStrategy.
+func (c *Config) GetScopeStrategy() fosite.ScopeStrategy {
+	if c.ScopeStrategy == nil {
+		return fosite.HierarchicScopeStrategy
+	}
+	return c.ScopeStrategy
+}
+
+// GetHashCost returns the bcrypt cost factor. Defaults to 12.
+func (c *Config) GetHashCost() int {
+	if c.HashCost == 0 {
+		return 12
+	}
+	return c.HashCost
+}
@@ -0,0 +1,122 @@
+package compose
+
+import (
+	"fmt"
+
+	"github.com/ory-am/fosite"
+)
+
+// Endpoint identifies a list of handlers of fosite.
+type Endpoint string
+
+const (
+	// AuthorizeEndpoint handlers implement fosite.AuthorizeEndpointHandler.
+	AuthorizeEndpoint Endpoint = "authorize"
+
+	// TokenEndpoint handlers implement fosite.TokenEndpointHandler.
+	TokenEndpoint Endpoint = "token"
+
+	// RevocationEndpoint handlers implement fosite.RevocationHandler.
+	RevocationEndpoint Endpoint = "revocation"
+
+	// IntrospectionEndpoint handlers implement fosite.TokenIntrospector.
+	IntrospectionEndpoint Endpoint = "introspection"
+
+	// ResourceServer handlers implement fosite.AuthorizedRequestValidator and validate requests to resource servers.
+	ResourceServer Endpoint = "resource server"
+)
+
+// AllEndpoints lists all endpoints in the order Compose registers handlers with them.
+var AllEndpoints = []Endpoint{AuthorizeEndpoint, TokenEndpoint, RevocationEndpoint, IntrospectionEndpoint, ResourceServer}
+
+// Registration can be returned by a Factory to register Handler only with Endpoints, instead of every endpoint
+// whose handler interface it implements.
+type Registration struct {
+	Handler   interface{}
+	Endpoints []Endpoint
+}
+
+// Register registers handler with endpoints. It fails without modifying f if handler does not implement the
+// handler interface of one of the endpoints, or if handler declares a grant type (see fosite.GrantTypeHandler)
+// which is already handled by another token endpoint handler.
+func Register(f *fosite.Fosite, handler interface{}, endpoints ...Endpoint) error {
+	if len(endpoints) == 0 {
+		return fmt.Errorf("compose: handler %T does not implement any endpoint handler interface", handler)
+	}
+
+	for _, endpoint := range endpoints {
+		if !Implements(handler, endpoint) {
+			return fmt.Errorf("compose: handler %T can not be registered with the %s endpoint", handler, endpoint)
+		} else if endpoint == TokenEndpoint {
+			if err := checkGrantTypes(f.TokenEndpointHandlers, handler); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, endpoint := range endpoints {
+		switch endpoint {
+		case AuthorizeEndpoint:
+			f.AuthorizeEndpointHandlers.Append(handler.(fosite.AuthorizeEndpointHandler))
+		case TokenEndpoint:
+			f.TokenEndpointHandlers.Append(handler.(fosite.TokenEndpointHandler))
+		case RevocationEndpoint:
+			f.RevocationHandlers.Append(handler.(fosite.RevocationHandler))
+		case IntrospectionEndpoint:
+			f.TokenIntrospectors.Append(handler.(fosite.TokenIntrospector))
+		case ResourceServer:
+			f.AuthorizedRequestValidators.Append(handler.(fosite.AuthorizedRequestValidator))
+		}
+	}
+	return nil
+}
+
+// Implements returns true if handler implements the handler interface of endpoint.
+func Implements(handler interface{}, endpoint Endpoint) bool {
+	var ok bool
+	switch endpoint {
+	case AuthorizeEndpoint:
+		_, ok = handler.(fosite.AuthorizeEndpointHandler)
+	case TokenEndpoint:
+		_, ok = handler.(fosite.TokenEndpointHandler)
+	case RevocationEndpoint:
+		_, ok = handler.(fosite.RevocationHandler)
+	case IntrospectionEndpoint:
+		_, ok = handler.(fosite.TokenIntrospector)
+	case ResourceServer:
+		_, ok = handler.(fosite.AuthorizedRequestValidator)
+	}
+	return ok
+}
+
+// implementedEndpoints returns all endpoints handler can be registered with.
+func implementedEndpoints(handler interface{}) []Endpoint {
+	var endpoints []Endpoint
+	for _, endpoint := range AllEndpoints {
+		if Implements(handler, endpoint) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// checkGrantTypes returns an error if handler declares a grant type which is declared by one of handlers.
+func checkGrantTypes(handlers fosite.TokenEndpointHandlers, handler interface{}) error {
+	gh, ok := handler.(fosite.GrantTypeHandler)
+	if !ok {
+		return nil
+	}
+
+	for _, registered := range handlers {
+		rgh, ok := registered.(fosite.GrantTypeHandler)
+		if !ok {
+			continue
+		}
+		for _, grantType := range gh.HandledGrantTypes() {
+			if rgh.HandledGrantTypes().Has(grantType) {
+				return fmt.Errorf("compose: grant type %s of handler %T is already handled by %T", grantType, handler, registered)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/implicit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	for k, c := range []struct {
+		description string
+		handler     interface{}
+		endpoints   []Endpoint
+		expectErr   bool
+		expect      func(f *fosite.Fosite)
+	}{
+		{
+			description: "should fail because an authorize handler can not handle token requests",
+			handler:     &implicit.AuthorizeImplicitGrantTypeHandler{},
+			endpoints:   []Endpoint{AuthorizeEndpoint, TokenEndpoint},
+			expectErr:   true,
+			expect: func(f *fosite.Fosite) {
+				assert.Empty(t, f.AuthorizeEndpointHandlers)
+				assert.Empty(t, f.TokenEndpointHandlers)
+			},
+		},
+		{
+			description: "should fail because no endpoint was given",
+			handler:     &implicit.AuthorizeImplicitGrantTypeHandler{},
+			expectErr:   true,
+		},
+		{
+			description: "should register with the given endpoints only",
+			handler:     &core.CoreValidator{},
+			endpoints:   []Endpoint{IntrospectionEndpoint},
+			expect: func(f *fosite.Fosite) {
+				assert.Len(t, f.TokenIntrospectors, 1)
+				assert.Empty(t, f.AuthorizedRequestValidators)
+			},
+		},
+	} {
+		f := fosite.NewFosite(nil)
+		err := Register(f, c.handler, c.endpoints...)
+		assert.Equal(t, c.expectErr, err != nil, "(%d) %s: %s", k, c.description, err)
+		if c.expect != nil {
+			c.expect(f)
+		}
+	}
+}
+
+func TestComposeRefusesMisconfiguration(t *testing.T) {
+	for k, factory := range []Factory{
+		func(_ *Config, _ interface{}, _ interface{}) interface{} {
+			return struct{}{}
+		},
+		func(_ *Config, _ interface{}, _ interface{}) interface{} {
+			return &Registration{Handler: &implicit.AuthorizeImplicitGrantTypeHandler{}, Endpoints: []Endpoint{TokenEndpoint}}
+		},
+	} {
+		assert.Panics(t, func() { Compose(&Config{}, &store.Store{}, nil, factory) }, "%d", k)
+	}
+
+	// Two handlers claiming the same grant type would make token requests ambiguous.
+	assert.Panics(t, func() {
+		Compose(&Config{}, &store.Store{}, &CommonStrategy{CoreStrategy: NewOAuth2HMACStrategy([]byte("some-super-cool-secret-that-nobody-knows"))}, OAuth2RefreshTokenGrantFactory, OAuth2RefreshTokenGrantFactory)
+	})
+
+	f := Compose(&Config{}, &store.Store{}, nil, func(_ *Config, _ interface{}, _ interface{}) interface{} {
+		return &Registration{Handler: &core.CoreValidator{}, Endpoints: []Endpoint{ResourceServer}}
+	}).(*fosite.Fosite)
+	assert.Len(t, f.AuthorizedRequestValidators, 1)
+	assert.Empty(t, f.TokenIntrospectors)
+}
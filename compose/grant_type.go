@@ -0,0 +1,64 @@
+package compose
+
+import (
+	"net/http"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"golang.org/x/net/context"
+)
+
+// GrantTypeFactory creates the token endpoint handler of an extension grant type, see ExtensionGrantFactory.
+type GrantTypeFactory func(config *Config, storage interface{}, strategy interface{}) fosite.TokenEndpointHandler
+
+// ExtensionGrantFactory returns a Factory registering the handler created by factory with the token endpoint for
+// grantType, an extension grant type as defined by https://tools.ietf.org/html/rfc6749#section-4.5, e.g.
+//
+//	compose.Compose(config, store, strategy,
+//	    compose.OAuth2AuthorizeExplicitFactory,
+//	    compose.ExtensionGrantFactory("urn:example:params:oauth:grant-type:custom", newCustomGrantHandler),
+//	)
+//
+// The handler does not need to implement fosite.GrantTypeHandler nor check the grant_type parameter: it is only
+// called for token requests using exactly grantType, and only for clients allowed to use grantType. Token requests
+// of other clients are rejected with fosite.ErrInvalidGrant, like the built-in grant types do. Compose panics if
+// grantType is handled by another handler already.
+func ExtensionGrantFactory(grantType string, factory GrantTypeFactory) Factory {
+	return func(config *Config, storage interface{}, strategy interface{}) interface{} {
+		return &Registration{
+			Handler: &extensionGrantHandler{
+				TokenEndpointHandler: factory(config, storage, strategy),
+				grantType:            grantType,
+			},
+			Endpoints: []Endpoint{TokenEndpoint},
+		}
+	}
+}
+
+type extensionGrantHandler struct {
+	fosite.TokenEndpointHandler
+	grantType string
+}
+
+// HandledGrantTypes implements fosite.GrantTypeHandler.
+func (h *extensionGrantHandler) HandledGrantTypes() fosite.Arguments {
+	return fosite.Arguments{h.grantType}
+}
+
+func (h *extensionGrantHandler) HandleTokenEndpointRequest(ctx context.Context, r *http.Request, requester fosite.AccessRequester) error {
+	if !requester.GetGrantTypes().Exact(h.grantType) {
+		return errors.New(fosite.ErrUnknownRequest)
+	} else if !requester.GetClient().GetGrantTypes().Has(h.grantType) {
+		return errors.New(fosite.ErrInvalidGrant)
+	}
+	return h.TokenEndpointHandler.HandleTokenEndpointRequest(ctx, r, requester)
+}
+
+func (h *extensionGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, r *http.Request, requester fosite.AccessRequester, responder fosite.AccessResponder) error {
+	if !requester.GetGrantTypes().Exact(h.grantType) {
+		return errors.New(fosite.ErrUnknownRequest)
+	} else if !requester.GetClient().GetGrantTypes().Has(h.grantType) {
+		return errors.New(fosite.ErrInvalidGrant)
+	}
+	return h.TokenEndpointHandler.PopulateTokenEndpointResponse(ctx, r, requester, responder)
+}
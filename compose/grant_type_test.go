@@ -0,0 +1,92 @@
+package compose
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+const customGrantType = "urn:example:params:oauth:grant-type:custom"
+
+type customGrantHandler struct {
+	handled   int
+	populated int
+}
+
+func (h *customGrantHandler) HandleTokenEndpointRequest(_ context.Context, _ *http.Request, requester fosite.AccessRequester) error {
+	h.handled++
+	requester.GrantScope("custom")
+	return nil
+}
+
+func (h *customGrantHandler) PopulateTokenEndpointResponse(_ context.Context, _ *http.Request, _ fosite.AccessRequester, responder fosite.AccessResponder) error {
+	h.populated++
+	responder.SetAccessToken("custom-token")
+	responder.SetTokenType("bearer")
+	return nil
+}
+
+func TestExtensionGrantFactory(t *testing.T) {
+	handler := &customGrantHandler{}
+	f := Compose(&Config{}, &store.Store{}, nil, ExtensionGrantFactory(customGrantType, func(_ *Config, _ interface{}, _ interface{}) fosite.TokenEndpointHandler {
+		return handler
+	})).(*fosite.Fosite)
+	require.Len(t, f.TokenEndpointHandlers, 1)
+	assert.Empty(t, f.AuthorizeEndpointHandlers)
+
+	h := f.TokenEndpointHandlers[0]
+	assert.Equal(t, fosite.Arguments{customGrantType}, h.(fosite.GrantTypeHandler).HandledGrantTypes())
+
+	for k, c := range []struct {
+		description string
+		grantTypes  fosite.Arguments
+		client      fosite.Arguments
+		expectErr   error
+		expectCalls int
+	}{
+		{
+			description: "should not be responsible for other grant types",
+			grantTypes:  fosite.Arguments{"password"},
+			client:      fosite.Arguments{customGrantType, "password"},
+			expectErr:   fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should fail because the client may not use the grant type",
+			grantTypes:  fosite.Arguments{customGrantType},
+			client:      fosite.Arguments{"password"},
+			expectErr:   fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass",
+			grantTypes:  fosite.Arguments{customGrantType},
+			client:      fosite.Arguments{customGrantType},
+			expectCalls: 1,
+		},
+	} {
+		*handler = customGrantHandler{}
+		ar := fosite.NewAccessRequest(nil)
+		ar.GrantTypes = c.grantTypes
+		ar.Client = &fosite.DefaultClient{GrantTypes: c.client}
+
+		err := h.HandleTokenEndpointRequest(nil, nil, ar)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s: %s", k, c.description, err)
+		err = h.PopulateTokenEndpointResponse(nil, nil, ar, fosite.NewAccessResponse())
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s: %s", k, c.description, err)
+		assert.Equal(t, c.expectCalls, handler.handled, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectCalls, handler.populated, "(%d) %s", k, c.description)
+	}
+
+	assert.Panics(t, func() {
+		Compose(&Config{}, &store.Store{}, nil, ExtensionGrantFactory("refresh_token", func(_ *Config, _ interface{}, _ interface{}) fosite.TokenEndpointHandler {
+			return handler
+		}), ExtensionGrantFactory("refresh_token", func(_ *Config, _ interface{}, _ interface{}) fosite.TokenEndpointHandler {
+			return handler
+		}))
+	}, "grant types can only be handled once")
+}
@@ -0,0 +1,56 @@
+package compose
+
+import (
+	"crypto/rsa"
+
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/core"
+	corestrategy "github.com/ory-am/fosite/handler/core/strategy"
+	"github.com/ory-am/fosite/handler/oidc"
+	oidcstrategy "github.com/ory-am/fosite/handler/oidc/strategy"
+	"github.com/ory-am/fosite/token/hmac"
+	"github.com/ory-am/fosite/token/jwt"
+)
+
+// CommonStrategy bundles the strategies required by the handlers created by Compose. CoreStrategy is usually
+// created by NewOAuth2HMACStrategy or NewOAuth2JWTStrategy.
+type CommonStrategy struct {
+	core.CoreStrategy
+	oidc.OpenIDConnectTokenStrategy
+
+	// JWTStrategy is used by the OpenID Connect handlers for computing the at_hash and c_hash claims. Defaults to
+	// the key of OpenIDConnectTokenStrategy if it is a *strategy.DefaultStrategy.
+	JWTStrategy *jwt.RS256JWTStrategy
+}
+
+// ValidateConfiguration validates all strategies implementing fosite.ConfigurationValidator, e.g. the size of
+// signing keys and the length of HMAC secrets. Call it at startup to refuse running with an insecure configuration.
+func (s *CommonStrategy) ValidateConfiguration() error {
+	strategies := []interface{}{s.CoreStrategy, s.OpenIDConnectTokenStrategy}
+	if s.JWTStrategy != nil {
+		strategies = append(strategies, s.JWTStrategy)
+	}
+
+	return fosite.ValidateConfigurations(strategies...)
+}
+
+// NewOAuth2HMACStrategy returns a strategy issuing HMAC-SHA signed authorize codes, access and refresh tokens.
+func NewOAuth2HMACStrategy(secret []byte) *corestrategy.HMACSHAStrategy {
+	return &corestrategy.HMACSHAStrategy{
+		Enigma: &hmac.HMACStrategy{GlobalSecret: secret},
+	}
+}
+
+// NewOAuth2JWTStrategy returns a strategy issuing RS256 signed authorize codes, access and refresh tokens.
+func NewOAuth2JWTStrategy(key *rsa.PrivateKey) *corestrategy.RS256JWTStrategy {
+	return &corestrategy.RS256JWTStrategy{
+		RS256JWTStrategy: &jwt.RS256JWTStrategy{PrivateKey: key},
+	}
+}
+
+// NewOpenIDConnectStrategy returns a strategy issuing RS256 signed ID tokens.
+func NewOpenIDConnectStrategy(key *rsa.PrivateKey) *oidcstrategy.DefaultStrategy {
+	return &oidcstrategy.DefaultStrategy{
+		RS256JWTStrategy: &jwt.RS256JWTStrategy{PrivateKey: key},
+	}
+}
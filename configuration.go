@@ -0,0 +1,68 @@
+package fosite
+
+import (
+	"crypto/rsa"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// Configuration overrides the static configuration of the handlers and strategies for a single request. It allows
+// serving multiple tenants with one provider, each using its own issuer, signing key and lifespans.
+type Configuration interface {
+	// GetIssuer returns the issuer of the tokens. The issuer of the strategy is used if empty.
+	GetIssuer() string
+
+	// GetSigningKey returns the key RS256 signed tokens are signed and validated with. The key of the strategy is
+	// used if nil.
+	GetSigningKey() *rsa.PrivateKey
+
+	// GetLifespan returns how long tokens of tokenType issued using grantType are valid, see
+	// ClientWithCustomLifespans. The lifespan of the handler is used if zero.
+	GetLifespan(grantType string, tokenType string) time.Duration
+}
+
+type configurationContextKey struct{}
+
+// WithConfiguration returns a copy of ctx carrying config. Passing the returned context to the methods of
+// OAuth2Provider, e.g. from a middleware resolving the tenant of a request, makes the handlers use config instead
+// of their static configuration.
+func WithConfiguration(ctx context.Context, config Configuration) context.Context {
+	return context.WithValue(ctx, configurationContextKey{}, config)
+}
+
+// ConfigurationFromContext returns the configuration injected by WithConfiguration.
+func ConfigurationFromContext(ctx context.Context) (Configuration, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	config, ok := ctx.Value(configurationContextKey{}).(Configuration)
+	return config, ok && config != nil
+}
+
+// IssuerFromContext returns the issuer of the configuration in ctx, or fallback if there is none.
+func IssuerFromContext(ctx context.Context, fallback string) string {
+	if config, ok := ConfigurationFromContext(ctx); ok && config.GetIssuer() != "" {
+		return config.GetIssuer()
+	}
+	return fallback
+}
+
+// SigningKeyFromContext returns the signing key of the configuration in ctx, or fallback if there is none.
+func SigningKeyFromContext(ctx context.Context, fallback *rsa.PrivateKey) *rsa.PrivateKey {
+	if config, ok := ConfigurationFromContext(ctx); ok && config.GetSigningKey() != nil {
+		return config.GetSigningKey()
+	}
+	return fallback
+}
+
+// GetEffectiveLifespanWithContext is GetEffectiveLifespan using the lifespan of the configuration in ctx, if any,
+// instead of fallback. Lifespans set by the client take precedence over both.
+func GetEffectiveLifespanWithContext(ctx context.Context, client Client, grantType string, tokenType string, fallback time.Duration) time.Duration {
+	if config, ok := ConfigurationFromContext(ctx); ok {
+		if lifespan := config.GetLifespan(grantType, tokenType); lifespan > 0 {
+			fallback = lifespan
+		}
+	}
+	return GetEffectiveLifespan(client, grantType, tokenType, fallback)
+}
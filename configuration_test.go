@@ -0,0 +1,78 @@
+package fosite_test
+
+import (
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type tenantConfiguration struct {
+	issuer   string
+	key      *rsa.PrivateKey
+	lifespan time.Duration
+}
+
+func (c *tenantConfiguration) GetIssuer() string {
+	return c.issuer
+}
+
+func (c *tenantConfiguration) GetSigningKey() *rsa.PrivateKey {
+	return c.key
+}
+
+func (c *tenantConfiguration) GetLifespan(grantType string, tokenType string) time.Duration {
+	if tokenType == AccessToken {
+		return c.lifespan
+	}
+	return 0
+}
+
+func TestConfigurationFromContext(t *testing.T) {
+	_, ok := ConfigurationFromContext(nil)
+	assert.False(t, ok)
+	_, ok = ConfigurationFromContext(context.Background())
+	assert.False(t, ok)
+
+	key := internal.MustRSAKey()
+	fallback := internal.MustRSAKey()
+	tenant := &tenantConfiguration{issuer: "https://tenant.example.com", key: key}
+	ctx := WithConfiguration(context.Background(), tenant)
+
+	config, ok := ConfigurationFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, tenant, config)
+
+	assert.Equal(t, "https://tenant.example.com", IssuerFromContext(ctx, "https://example.com"))
+	assert.Equal(t, "https://example.com", IssuerFromContext(context.Background(), "https://example.com"))
+	assert.Equal(t, "https://example.com", IssuerFromContext(WithConfiguration(context.Background(), &tenantConfiguration{}), "https://example.com"))
+
+	assert.Equal(t, key, SigningKeyFromContext(ctx, fallback))
+	assert.Equal(t, fallback, SigningKeyFromContext(nil, fallback))
+	assert.Equal(t, fallback, SigningKeyFromContext(WithConfiguration(context.Background(), &tenantConfiguration{}), fallback))
+}
+
+func TestGetEffectiveLifespanWithContext(t *testing.T) {
+	ctx := WithConfiguration(context.Background(), &tenantConfiguration{lifespan: time.Minute * 30})
+	client := &DefaultClient{Lifespans: map[string]time.Duration{"implicit " + AccessToken: time.Minute}}
+
+	for k, c := range []struct {
+		ctx       context.Context
+		client    Client
+		grantType string
+		tokenType string
+		expect    time.Duration
+	}{
+		{ctx: nil, client: &DefaultClient{}, grantType: "implicit", tokenType: AccessToken, expect: time.Hour},
+		{ctx: ctx, client: &DefaultClient{}, grantType: "implicit", tokenType: AccessToken, expect: time.Minute * 30},
+		{ctx: ctx, client: &DefaultClient{}, grantType: "implicit", tokenType: RefreshToken, expect: time.Hour},
+		{ctx: ctx, client: client, grantType: "implicit", tokenType: AccessToken, expect: time.Minute},
+		{ctx: ctx, client: client, grantType: "authorization_code", tokenType: AccessToken, expect: time.Minute * 30},
+	} {
+		assert.Equal(t, c.expect, GetEffectiveLifespanWithContext(c.ctx, c.client, c.grantType, c.tokenType, time.Hour), "case %d", k)
+	}
+}
@@ -0,0 +1,124 @@
+package fosite
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+const (
+	// ConfirmationJWKThumbprint is the confirmation method of tokens bound to the key of DPoP proofs, see
+	// https://tools.ietf.org/html/rfc9449#section-6.1
+	ConfirmationJWKThumbprint = "jkt"
+
+	// ConfirmationX509Thumbprint is the confirmation method of tokens bound to a TLS client certificate, see
+	// https://tools.ietf.org/html/rfc8705#section-3.1
+	ConfirmationX509Thumbprint = "x5t#S256"
+
+	// BearerTokenType is the token_type of access tokens which are not bound to a DPoP key, including tokens bound
+	// to a TLS client certificate, see https://tools.ietf.org/html/rfc8705#section-3
+	BearerTokenType = "bearer"
+
+	// DPoPTokenType is the token_type of access tokens bound to the key of a DPoP proof, see
+	// https://tools.ietf.org/html/rfc9449#section-5
+	DPoPTokenType = "DPoP"
+)
+
+// ConfirmationSession can be implemented by sessions of sender-constrained tokens to declare the key the token is
+// bound to. The confirmation (cnf) is added to introspection responses and verified if the resource server sends
+// the proof it received to the introspection endpoint, see NewIntrospectionRequest.
+//
+// Access and refresh tokens issued together share the session, so the refresh token is bound to the same key. The
+// refresh grant requires a fresh proof of that key, see ValidateConfirmation.
+type ConfirmationSession interface {
+	// GetConfirmation returns the confirmation methods of the token keyed by their name, e.g.
+	// {"jkt": "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I"}, or nil if the token is not bound.
+	GetConfirmation() map[string]string
+}
+
+// getConfirmation returns the confirmation of the token looked up by ar or nil if it is not bound.
+func getConfirmation(ar Requester) map[string]string {
+	if session, ok := ar.GetSession().(ConfirmationSession); ok {
+		return session.GetConfirmation()
+	}
+	return nil
+}
+
+// GetTokenType returns the token_type of the access tokens issued for requester, DPoPTokenType if its session binds
+// them to a DPoP key and BearerTokenType otherwise.
+func GetTokenType(requester Requester) string {
+	if _, ok := getConfirmation(requester)[ConfirmationJWKThumbprint]; ok {
+		return DPoPTokenType
+	}
+	return BearerTokenType
+}
+
+// parseConfirmation parses the cnf parameter of introspection requests, a JSON object of the same form as the cnf
+// member of the introspection response, e.g. {"jkt": "..."}. It returns nil if raw is empty.
+func parseConfirmation(raw string) (map[string]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var confirmation map[string]string
+	if err := json.Unmarshal([]byte(raw), &confirmation); err != nil || len(confirmation) == 0 {
+		return nil, errors.New(ErrInvalidRequest)
+	}
+	return confirmation, nil
+}
+
+// isConfirmed returns true if presented proves possession of the key the token introspected by ar is bound to.
+// Every confirmation method of the token must be presented and match, tokens which are not bound are confirmed by
+// any proof. If presented is nil, the resource server verifies the binding itself and isConfirmed returns true.
+func isConfirmed(ar AccessRequester, presented map[string]string) bool {
+	if presented == nil {
+		return true
+	}
+
+	for method, expected := range getConfirmation(ar) {
+		actual, ok := presented[method]
+		if !ok || subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// ConfirmationExtractor returns the confirmation of the key the client proved possession of in req, e.g.
+// {"jkt": "..."} for the key of a verified DPoP proof or the result of TLSClientCertificateConfirmation. It returns
+// nil if req contains no proof and an error if the proof is invalid. Extractors of DPoP proofs return
+// ErrInvalidDPoPProof or ErrUseDPoPNonce, other errors are written as invalid_grant.
+type ConfirmationExtractor func(ctx context.Context, req *http.Request) (map[string]string, error)
+
+// TLSClientCertificateConfirmation is a ConfirmationExtractor for tokens bound to the TLS client certificate, see
+// https://tools.ietf.org/html/rfc8705#section-3.1
+func TLSClientCertificateConfirmation(_ context.Context, req *http.Request) (map[string]string, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return nil, nil
+	}
+
+	thumbprint := sha256.Sum256(req.TLS.PeerCertificates[0].Raw)
+	return map[string]string{ConfirmationX509Thumbprint: base64.RawURLEncoding.EncodeToString(thumbprint[:])}, nil
+}
+
+// ValidateConfirmation checks that presented proves possession of the key the tokens of requester are bound to.
+// Unlike at the introspection endpoint, a missing proof does not defer the check to someone else: tokens which are
+// bound require presented to contain every confirmation method. It returns ErrInvalidDPoPProof if the key of the
+// DPoP proof does not match and ErrInvalidGrant for all other mismatches.
+func ValidateConfirmation(requester Requester, presented map[string]string) error {
+	for method, expected := range getConfirmation(requester) {
+		actual, ok := presented[method]
+		if ok && subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1 {
+			continue
+		} else if ok && method == ConfirmationJWKThumbprint {
+			return errors.New(ErrInvalidDPoPProof)
+		}
+		return errors.New(ErrInvalidGrant)
+	}
+	return nil
+}
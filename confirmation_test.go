@@ -0,0 +1,80 @@
+package fosite_test
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/go-errors/errors"
+	. "github.com/ory-am/fosite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfirmation(t *testing.T) {
+	bound := &Request{Session: confirmationSession{ConfirmationJWKThumbprint: "some-thumbprint"}}
+	certificateBound := &Request{Session: confirmationSession{ConfirmationX509Thumbprint: "some-thumbprint"}}
+
+	for k, c := range []struct {
+		description string
+		requester   Requester
+		presented   map[string]string
+		expectErr   error
+	}{
+		{
+			description: "should pass because the token is not bound",
+			requester:   &Request{Session: confirmationSession{}},
+		},
+		{
+			description: "should pass because the key matches",
+			requester:   bound,
+			presented:   map[string]string{ConfirmationJWKThumbprint: "some-thumbprint"},
+		},
+		{
+			description: "should fail because no proof is presented",
+			requester:   bound,
+			expectErr:   ErrInvalidGrant,
+		},
+		{
+			description: "should fail because another confirmation method is presented",
+			requester:   bound,
+			presented:   map[string]string{ConfirmationX509Thumbprint: "some-thumbprint"},
+			expectErr:   ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the DPoP proof is signed with another key",
+			requester:   bound,
+			presented:   map[string]string{ConfirmationJWKThumbprint: "other-thumbprint"},
+			expectErr:   ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because another client certificate is presented",
+			requester:   certificateBound,
+			presented:   map[string]string{ConfirmationX509Thumbprint: "other-thumbprint"},
+			expectErr:   ErrInvalidGrant,
+		},
+	} {
+		err := ValidateConfirmation(c.requester, c.presented)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+}
+
+func TestTLSClientCertificateConfirmation(t *testing.T) {
+	presented, err := TLSClientCertificateConfirmation(nil, &http.Request{})
+	assert.Nil(t, err)
+	assert.Nil(t, presented)
+
+	certificate := &x509.Certificate{Raw: []byte("some-certificate")}
+	thumbprint := sha256.Sum256(certificate.Raw)
+	presented, err = TLSClientCertificateConfirmation(nil, &http.Request{TLS: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{ConfirmationX509Thumbprint: base64.RawURLEncoding.EncodeToString(thumbprint[:])}, presented)
+}
+
+func TestGetTokenType(t *testing.T) {
+	assert.Equal(t, BearerTokenType, GetTokenType(&Request{}))
+	assert.Equal(t, BearerTokenType, GetTokenType(&Request{Session: confirmationSession{ConfirmationX509Thumbprint: "some-thumbprint"}}))
+	assert.Equal(t, DPoPTokenType, GetTokenType(&Request{Session: confirmationSession{ConfirmationJWKThumbprint: "some-thumbprint"}}))
+}
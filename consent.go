@@ -0,0 +1,45 @@
+package fosite
+
+import "github.com/go-errors/errors"
+
+// ConsentSession can be implemented by sessions passed to NewAuthorizeResponse to tell fosite whether the
+// end-user consented to the granted scopes, either in this request or in an earlier, remembered one.
+type ConsentSession interface {
+	// HasConsent returns true if the end-user consented to the granted scopes.
+	HasConsent() bool
+}
+
+// offlineScopes are the scopes which, when granted, allow the client to obtain refresh tokens.
+var offlineScopes = []string{"offline", "offline_access"}
+
+// validateOfflineConsent enforces http://openid.net/specs/openid-connect-core-1_0.html#OfflineAccess
+//
+//	When offline access is requested, [...] the Authorization Server MUST ensure that the prompt parameter
+//	contains consent unless other conditions for processing the request permitting offline access to the
+//	requested resources are in place.
+//
+// If the session implements ConsentSession, it decides whether consent was given. Otherwise, offline access
+// is only refused if the end-user could not have been prompted for consent because of prompt=none.
+func validateOfflineConsent(ar AuthorizeRequester, session interface{}) error {
+	var offline bool
+	for _, scope := range offlineScopes {
+		offline = offline || ar.GetGrantedScopes().Has(scope)
+	}
+
+	if !offline {
+		return nil
+	}
+
+	if cs, ok := session.(ConsentSession); ok {
+		if !cs.HasConsent() {
+			return errors.New(ErrConsentRequired)
+		}
+		return nil
+	}
+
+	prompt := getPrompt(ar.GetRequestForm())
+	if prompt.Has("none") && !prompt.Has("consent") {
+		return errors.New(ErrConsentRequired)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package fosite
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type consentSession bool
+
+func (c consentSession) HasConsent() bool {
+	return bool(c)
+}
+
+func TestValidateOfflineConsent(t *testing.T) {
+	for k, c := range []struct {
+		granted   Arguments
+		prompt    string
+		session   interface{}
+		expectErr bool
+	}{
+		{granted: Arguments{"foo"}, prompt: "none"},
+		{granted: Arguments{"offline"}},
+		{granted: Arguments{"offline"}, prompt: "consent"},
+		{granted: Arguments{"offline"}, prompt: "none", expectErr: true},
+		{granted: Arguments{"offline_access"}, prompt: "none", expectErr: true},
+		{granted: Arguments{"offline_access"}, prompt: "none", session: consentSession(true)},
+		{granted: Arguments{"offline_access"}, prompt: "login", session: consentSession(false), expectErr: true},
+		{granted: Arguments{"foo"}, session: consentSession(false)},
+	} {
+		ar := &AuthorizeRequest{
+			Request: Request{
+				GrantedScopes: ScopeArguments(c.granted),
+				Form:          url.Values{"prompt": {c.prompt}},
+			},
+		}
+
+		err := validateOfflineConsent(ar, c.session)
+		assert.Equal(t, c.expectErr, err != nil, "%d: %s", k, err)
+		if c.expectErr {
+			assert.True(t, errors.Is(err, ErrConsentRequired), "%d", k)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package fosite
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+const (
+	// DPoPHeader is the header clients send DPoP proofs in, see https://tools.ietf.org/html/rfc9449#section-4.1
+	DPoPHeader = "DPoP"
+
+	// DPoPNonceHeader is the header servers use to provide DPoP nonces, see
+	// https://tools.ietf.org/html/rfc9449#section-8
+	DPoPNonceHeader = "DPoP-Nonce"
+
+	// DefaultDPoPNonceLifespan is the default time a DPoP nonce is accepted for.
+	DefaultDPoPNonceLifespan = 5 * time.Minute
+
+	// dpopNonceClockSkew is the time a nonce may appear to be issued in the future, e.g. because it was issued by
+	// another instance with a slightly different clock.
+	dpopNonceClockSkew = 5 * time.Second
+)
+
+// DPoPNonceStrategy issues and validates the nonces servers may require in DPoP proofs to limit the time a proof
+// can be used for, see https://tools.ietf.org/html/rfc9449#section-8
+//
+//	Including a nonce value contributes to this goal by having the server provide a nonce to the client and
+//	requiring that the nonce be included in the DPoP proof.
+type DPoPNonceStrategy interface {
+	// GenerateDPoPNonce returns a fresh nonce.
+	GenerateDPoPNonce() (string, error)
+
+	// ValidateDPoPNonce returns an error if nonce was not issued by GenerateDPoPNonce or is no longer fresh.
+	ValidateDPoPNonce(nonce string) error
+}
+
+// HMACDPoPNonceStrategy issues stateless nonces consisting of the time they were issued at and an HMAC-SHA256
+// signature. All instances sharing the secret accept each other's nonces.
+type HMACDPoPNonceStrategy struct {
+	// Secret signs the nonces. It must be at least 32 bytes long.
+	Secret []byte
+
+	// Lifespan is the time a nonce is accepted for. Defaults to DefaultDPoPNonceLifespan.
+	Lifespan time.Duration
+
+	// now returns the current time. Defaults to time.Now.
+	now func() time.Time
+}
+
+func (s *HMACDPoPNonceStrategy) GenerateDPoPNonce() (string, error) {
+	if len(s.Secret) < 32 {
+		return "", errors.New("Secret for signing DPoP nonces must be at least 32 bytes long")
+	}
+
+	issuedAt := make([]byte, 8)
+	binary.BigEndian.PutUint64(issuedAt, uint64(s.getNow().Unix()))
+	return base64.RawURLEncoding.EncodeToString(append(issuedAt, s.sign(issuedAt)...)), nil
+}
+
+func (s *HMACDPoPNonceStrategy) ValidateDPoPNonce(nonce string) error {
+	if len(s.Secret) < 32 {
+		return errors.New("Secret for signing DPoP nonces must be at least 32 bytes long")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(nonce)
+	if err != nil || len(raw) != 8+sha256.Size {
+		return errors.New("DPoP nonce is malformed")
+	} else if !hmac.Equal(raw[8:], s.sign(raw[:8])) {
+		return errors.New("DPoP nonce signature is invalid")
+	}
+
+	issuedAt := time.Unix(int64(binary.BigEndian.Uint64(raw[:8])), 0)
+	if age := s.getNow().Sub(issuedAt); age < -dpopNonceClockSkew || age > s.getLifespan() {
+		return errors.New("DPoP nonce is not fresh")
+	}
+	return nil
+}
+
+func (s *HMACDPoPNonceStrategy) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func (s *HMACDPoPNonceStrategy) getLifespan() time.Duration {
+	if s.Lifespan == 0 {
+		return DefaultDPoPNonceLifespan
+	}
+	return s.Lifespan
+}
+
+func (s *HMACDPoPNonceStrategy) getNow() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
+
+// ValidateDPoPNonce validates the nonce claim of a DPoP proof presented at the token endpoint. It returns
+// ErrUseDPoPNonce if Fosite.DPoPNonceStrategy is set and the nonce is missing or not fresh, in which case
+// WriteAccessError provides a new nonce. Any nonce is accepted if Fosite.DPoPNonceStrategy is nil.
+//
+// NewAccessRequest validates the nonce of the proof in the DPoP header of token requests.
+func (f *Fosite) ValidateDPoPNonce(nonce string) error {
+	if f.DPoPNonceStrategy == nil {
+		return nil
+	} else if nonce == "" {
+		return errors.New(ErrUseDPoPNonce)
+	} else if err := f.DPoPNonceStrategy.ValidateDPoPNonce(nonce); err != nil {
+		return errors.New(ErrUseDPoPNonce)
+	}
+	return nil
+}
+
+// validateDPoPProofNonce validates the nonce claim of the DPoP proof of a token request, requests without a proof
+// are not DPoP requests and pass. The signature of the proof is verified by the ConfirmationExtractor of the grant
+// handlers, only the nonce is read here. It returns ErrInvalidDPoPProof if the proof is not a JWS.
+func (f *Fosite) validateDPoPProofNonce(r *http.Request) error {
+	proof := r.Header.Get(DPoPHeader)
+	if proof == "" || f.DPoPNonceStrategy == nil {
+		return nil
+	}
+
+	parts := strings.Split(proof, ".")
+	if len(parts) != 3 {
+		return errors.New(ErrInvalidDPoPProof)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errors.New(ErrInvalidDPoPProof)
+	}
+
+	var claims struct {
+		Nonce string `json:"nonce"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return errors.New(ErrInvalidDPoPProof)
+	}
+	return f.ValidateDPoPNonce(claims.Nonce)
+}
+
+// setDPoPNonceHeader provides a fresh nonce to the client, which it uses for its next DPoP proof. Thus clients do
+// not need to be challenged once their nonce expired, see https://tools.ietf.org/html/rfc9449#section-8.2
+func (f *Fosite) setDPoPNonceHeader(header http.Header) {
+	if f.DPoPNonceStrategy == nil {
+		return
+	}
+
+	if nonce, err := f.DPoPNonceStrategy.GenerateDPoPNonce(); err == nil {
+		header.Set(DPoPNonceHeader, nonce)
+	}
+}
@@ -0,0 +1,89 @@
+package fosite
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACDPoPNonceStrategy(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	s := &HMACDPoPNonceStrategy{Secret: []byte("foobarfoobarfoobarfoobarfoobarfoobar"), now: func() time.Time { return now }}
+
+	nonce, err := s.GenerateDPoPNonce()
+	require.Nil(t, err, "%s", err)
+	other, err := (&HMACDPoPNonceStrategy{Secret: []byte("barfoobarfoobarfoobarfoobarfoobarfoo")}).GenerateDPoPNonce()
+	require.Nil(t, err, "%s", err)
+
+	for k, c := range []struct {
+		nonce       string
+		now         time.Time
+		expectError bool
+	}{
+		{nonce: nonce, now: now},
+		{nonce: nonce, now: now.Add(DefaultDPoPNonceLifespan)},
+		{nonce: nonce, now: now.Add(-time.Second)},
+		{nonce: nonce, now: now.Add(DefaultDPoPNonceLifespan + time.Second), expectError: true},
+		{nonce: nonce, now: now.Add(-time.Minute), expectError: true},
+		{nonce: other, now: now, expectError: true},
+		{nonce: nonce[:len(nonce)-1], now: now, expectError: true},
+		{nonce: "", now: now, expectError: true},
+		{nonce: "not-a-nonce", now: now, expectError: true},
+	} {
+		s.now = func() time.Time { return c.now }
+		err := s.ValidateDPoPNonce(c.nonce)
+		assert.Equal(t, c.expectError, err != nil, "case %d: %s", k, err)
+	}
+
+	_, err = (&HMACDPoPNonceStrategy{Secret: []byte("foo")}).GenerateDPoPNonce()
+	assert.NotNil(t, err)
+	assert.NotNil(t, (&HMACDPoPNonceStrategy{Secret: []byte("foo")}).ValidateDPoPNonce(nonce))
+}
+
+func TestValidateDPoPNonce(t *testing.T) {
+	assert.Nil(t, (&Fosite{}).ValidateDPoPNonce(""), "nonces are not required without a strategy")
+
+	s := &HMACDPoPNonceStrategy{Secret: []byte("foobarfoobarfoobarfoobarfoobarfoobar")}
+	f := &Fosite{DPoPNonceStrategy: s}
+	nonce, err := s.GenerateDPoPNonce()
+	require.Nil(t, err, "%s", err)
+
+	assert.Nil(t, f.ValidateDPoPNonce(nonce))
+	assert.True(t, errors.Is(f.ValidateDPoPNonce(""), ErrUseDPoPNonce))
+	assert.True(t, errors.Is(f.ValidateDPoPNonce("not-a-nonce"), ErrUseDPoPNonce))
+}
+
+func TestTokenEndpointProvidesDPoPNonces(t *testing.T) {
+	s := &HMACDPoPNonceStrategy{Secret: []byte("foobarfoobarfoobarfoobarfoobarfoobar")}
+	f := &Fosite{DPoPNonceStrategy: s}
+
+	rw := httptest.NewRecorder()
+	f.WriteAccessError(rw, nil, f.ValidateDPoPNonce(""))
+	assert.Equal(t, 400, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"use_dpop_nonce"`)
+	assert.Nil(t, s.ValidateDPoPNonce(rw.Header().Get(DPoPNonceHeader)))
+
+	rw = httptest.NewRecorder()
+	f.WriteAccessError(rw, nil, errors.New(ErrInvalidGrant))
+	assert.Empty(t, rw.Header().Get(DPoPNonceHeader), "only the nonce challenge provides a nonce")
+
+	rw = httptest.NewRecorder()
+	response := NewAccessResponse()
+	response.SetAccessToken("foo")
+	response.SetTokenType(DPoPTokenType)
+	f.WriteAccessResponse(rw, nil, response)
+	assert.Nil(t, s.ValidateDPoPNonce(rw.Header().Get(DPoPNonceHeader)), "successful DPoP responses provide the next nonce")
+
+	rw = httptest.NewRecorder()
+	(&Fosite{}).WriteAccessResponse(rw, nil, response)
+	assert.Empty(t, rw.Header().Get(DPoPNonceHeader))
+
+	rw = httptest.NewRecorder()
+	response.SetTokenType(BearerTokenType)
+	f.WriteAccessResponse(rw, nil, response)
+	assert.Empty(t, rw.Header().Get(DPoPNonceHeader), "bearer token responses provide no nonce")
+}
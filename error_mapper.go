@@ -0,0 +1,32 @@
+package fosite
+
+// ErrorMapper translates an error before it is written to client, e.g. to the subset of error codes a legacy
+// client understands. client is nil if it is unknown, e.g. because the client failed to authenticate or the error
+// was written by WriteRevocationResponse or WriteIntrospectionError. ErrorMapper must return rfcerr unchanged for
+// clients which understand all error codes.
+type ErrorMapper func(client Client, rfcerr *RFC6749Error) *RFC6749Error
+
+// ErrorNamesByClient returns an ErrorMapper which replaces the names of errors written to the clients in names,
+// keyed by client ID and error name, e.g.
+//
+//	ErrorNamesByClient(map[string]map[string]string{
+//		"legacy-client": {"unsupported_grant_type": "invalid_request"},
+//	})
+//
+// The status code, description and hint of the error are kept.
+func ErrorNamesByClient(names map[string]map[string]string) ErrorMapper {
+	return func(client Client, rfcerr *RFC6749Error) *RFC6749Error {
+		if client == nil {
+			return rfcerr
+		}
+
+		name, ok := names[client.GetID()][rfcerr.Name]
+		if !ok {
+			return rfcerr
+		}
+
+		mapped := *rfcerr
+		mapped.Name = name
+		return &mapped
+	}
+}
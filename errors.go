@@ -7,39 +7,66 @@ import (
 )
 
 var (
-	ErrRequestUnauthorized     = errors.New("The request could not be authorized")
-	ErrRequestForbidden        = errors.New("The request is not allowed")
-	ErrInvalidRequest          = errors.New("The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed")
-	ErrUnauthorizedClient      = errors.New("The client is not authorized to request a token using this method")
-	ErrAccessDenied            = errors.New("The resource owner or authorization server denied the request")
-	ErrUnsupportedResponseType = errors.New("The authorization server does not support obtaining a token using this method")
-	ErrInvalidScope            = errors.New("The requested scope is invalid, unknown, or malformed")
-	ErrServerError             = errors.New("The authorization server encountered an unexpected condition that prevented it from fulfilling the request")
-	ErrTemporarilyUnavailable  = errors.New("The authorization server is currently unable to handle the request due to a temporary overloading or maintenance of the server")
-	ErrUnsupportedGrantType    = errors.New("The authorization grant type is not supported by the authorization server")
-	ErrInvalidGrant            = errors.New("The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client")
-	ErrInvalidClient           = errors.New("Client authentication failed (e.g., unknown client, no client authentication included, or unsupported authentication method)")
-	ErrInvalidState            = errors.Errorf("The state is missing or has less than %d characters and is therefore considered too weak", MinParameterEntropy)
-	ErrInsufficientEntropy     = errors.Errorf("The request used a security parameter (e.g., anti-replay, anti-csrf) with insufficient entropy (minimum of %d characters)", MinParameterEntropy)
-	ErrMisconfiguration        = errors.New("The request failed because of a misconfiguration")
-	ErrNotFound                = errors.New("Could not find the requested resource(s)")
+	ErrRequestUnauthorized             = errors.New("The request could not be authorized")
+	ErrRequestForbidden                = errors.New("The request is not allowed")
+	ErrInvalidRequest                  = errors.New("The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed")
+	ErrUnauthorizedClient              = errors.New("The client is not authorized to request a token using this method")
+	ErrAccessDenied                    = errors.New("The resource owner or authorization server denied the request")
+	ErrUnsupportedResponseType         = errors.New("The authorization server does not support obtaining a token using this method")
+	ErrInvalidScope                    = errors.New("The requested scope is invalid, unknown, or malformed")
+	ErrServerError                     = errors.New("The authorization server encountered an unexpected condition that prevented it from fulfilling the request")
+	ErrTemporarilyUnavailable          = errors.New("The authorization server is currently unable to handle the request due to a temporary overloading or maintenance of the server")
+	ErrUnsupportedGrantType            = errors.New("The authorization grant type is not supported by the authorization server")
+	ErrInvalidGrant                    = errors.New("The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client")
+	ErrInvalidClient                   = errors.New("Client authentication failed (e.g., unknown client, no client authentication included, or unsupported authentication method)")
+	ErrInvalidState                    = errors.Errorf("The state is missing or has less than %d characters and is therefore considered too weak", MinParameterEntropy)
+	ErrInsufficientEntropy             = errors.Errorf("The request used a security parameter (e.g., anti-replay, anti-csrf) with insufficient entropy (minimum of %d characters)", MinParameterEntropy)
+	ErrMisconfiguration                = errors.New("The request failed because of a misconfiguration")
+	ErrNotFound                        = errors.New("Could not find the requested resource(s)")
+	ErrConsentRequired                 = errors.New("The authorization server requires end-user consent")
+	ErrLoginRequired                   = errors.New("The authorization server requires end-user authentication")
+	ErrInteractionRequired             = errors.New("The authorization server requires end-user interaction of some form to proceed")
+	ErrAccountSelectionRequired        = errors.New("The end-user is required to select a session at the authorization server")
+	ErrInvalidRequestURI               = errors.New("The request_uri in the authorization request returns an error or contains invalid data")
+	ErrRequestURINotSupported          = errors.New("The authorization server does not support use of the request_uri parameter")
+	ErrInvalidTokenFormat              = errors.New("The token is malformed")
+	ErrUnsupportedTokenType            = errors.New("The authorization server does not support the type of the presented token")
+	ErrUnmetAuthenticationRequirements = errors.New("The authorization server is unable to meet the requirements of the client for the authentication of the end-user")
+	ErrUseDPoPNonce                    = errors.New("The authorization server requires a nonce in the DPoP proof")
+	ErrInvalidRequestObject            = errors.New("The request parameter contains an invalid request object")
+	ErrUnsupportedEndpoint             = errors.New("The authorization server does not support this endpoint")
+	ErrReplayed                        = errors.New("The token, proof or nonce was already used")
+	ErrInvalidDPoPProof                = errors.New("The DPoP proof is invalid or does not prove possession of the key the grant is bound to")
 )
 
 const (
-	errInvalidRequestName          = "invalid_request"
-	errUnauthorizedClientName      = "unauthorized_client"
-	errAccessDeniedName            = "acccess_denied"
-	errUnsupportedResponseTypeName = "unsupported_response_type"
-	errInvalidScopeName            = "invalid_scope"
-	errServerErrorName             = "server_error"
-	errTemporarilyUnavailableName  = "temporarily_unavailable"
-	errUnsupportedGrantTypeName    = "unsupported_grant_type"
-	errInvalidGrantName            = "invalid_grant"
-	errInvalidClientName           = "invalid_client"
-	errInvalidError                = "invalid_error"
-	errInvalidState                = "invalid_state"
-	errMisconfiguration            = "misconfiguration"
-	errInsufficientEntropy         = "insufficient_entropy"
+	errInvalidRequestName              = "invalid_request"
+	errUnauthorizedClientName          = "unauthorized_client"
+	errAccessDeniedName                = "acccess_denied"
+	errUnsupportedResponseTypeName     = "unsupported_response_type"
+	errInvalidScopeName                = "invalid_scope"
+	errServerErrorName                 = "server_error"
+	errTemporarilyUnavailableName      = "temporarily_unavailable"
+	errUnsupportedGrantTypeName        = "unsupported_grant_type"
+	errInvalidGrantName                = "invalid_grant"
+	errInvalidClientName               = "invalid_client"
+	errInvalidError                    = "invalid_error"
+	errInvalidState                    = "invalid_state"
+	errMisconfiguration                = "misconfiguration"
+	errInsufficientEntropy             = "insufficient_entropy"
+	errConsentRequired                 = "consent_required"
+	errLoginRequired                   = "login_required"
+	errInteractionRequired             = "interaction_required"
+	errAccountSelectionRequired        = "account_selection_required"
+	errInvalidRequestURI               = "invalid_request_uri"
+	errRequestURINotSupported          = "request_uri_not_supported"
+	errInvalidTokenFormat              = "invalid_token"
+	errUnsupportedTokenType            = "unsupported_token_type"
+	errUnmetAuthenticationRequirements = "unmet_authentication_requirements"
+	errUseDPoPNonce                    = "use_dpop_nonce"
+	errInvalidRequestObject            = "invalid_request_object"
+	errUnsupportedEndpoint             = "unsupported_endpoint"
+	errInvalidDPoPProof                = "invalid_dpop_proof"
 )
 
 type RFC6749Error struct {
@@ -50,6 +77,12 @@ type RFC6749Error struct {
 }
 
 func ErrorToRFC6749Error(err error) *RFC6749Error {
+	if ve, ok := unwrapValidationErrors(err); ok {
+		return ve.toRFC6749Error()
+	} else if ee, ok := unwrapEssentialClaimsError(err); ok {
+		return ee.toRFC6749Error()
+	}
+
 	ge, ok := err.(*errors.Error)
 	if !ok {
 		return &RFC6749Error{
@@ -140,6 +173,100 @@ func ErrorToRFC6749Error(err error) *RFC6749Error {
 			Description: ge.Error(),
 			StatusCode:  http.StatusBadRequest,
 		}
+	} else if errors.Is(ge, ErrConsentRequired) {
+		return &RFC6749Error{
+			Name:        errConsentRequired,
+			Description: ge.Error(),
+			Hint:        "Prompt the end-user for consent, offline access and prompt=consent require it.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrLoginRequired) {
+		return &RFC6749Error{
+			Name:        errLoginRequired,
+			Description: ge.Error(),
+			Hint:        "Prompt the end-user to authenticate, prompt=login or max_age require a fresh authentication.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrInteractionRequired) {
+		return &RFC6749Error{
+			Name:        errInteractionRequired,
+			Description: ge.Error(),
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrAccountSelectionRequired) {
+		return &RFC6749Error{
+			Name:        errAccountSelectionRequired,
+			Description: ge.Error(),
+			Hint:        "Prompt the end-user to select one of the accounts they are signed in with.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrInvalidRequestURI) {
+		return &RFC6749Error{
+			Name:        errInvalidRequestURI,
+			Description: ge.Error(),
+			Hint:        "Make sure that the request_uri is registered for the client, uses https and is reachable from the authorization server.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrRequestURINotSupported) {
+		return &RFC6749Error{
+			Name:        errRequestURINotSupported,
+			Description: ge.Error(),
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrInvalidTokenFormat) {
+		return &RFC6749Error{
+			Name:        errInvalidTokenFormat,
+			Description: ge.Error(),
+			StatusCode:  http.StatusUnauthorized,
+		}
+	} else if errors.Is(ge, ErrUnsupportedTokenType) {
+		return &RFC6749Error{
+			Name:        errUnsupportedTokenType,
+			Description: ge.Error(),
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrUnmetAuthenticationRequirements) {
+		return &RFC6749Error{
+			Name:        errUnmetAuthenticationRequirements,
+			Description: ge.Error(),
+			Hint:        "Make sure that the end-user authenticates using one of the authentication context classes required by the client.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrInvalidRequestObject) {
+		return &RFC6749Error{
+			Name:        errInvalidRequestObject,
+			Description: ge.Error(),
+			Hint:        "Make sure that the request object is signed with one of the keys registered by the client.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrUnsupportedEndpoint) {
+		return &RFC6749Error{
+			Name:        errUnsupportedEndpoint,
+			Description: ge.Error(),
+			Hint:        "Make sure that a handler and the storage it requires are registered for this endpoint.",
+			StatusCode:  http.StatusNotImplemented,
+		}
+	} else if errors.Is(ge, ErrReplayed) {
+		return &RFC6749Error{
+			Name:        errInvalidRequestName,
+			Description: ge.Error(),
+			Hint:        "Make sure that every token, proof and nonce is used only once.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrInvalidDPoPProof) {
+		return &RFC6749Error{
+			Name:        errInvalidDPoPProof,
+			Description: ge.Error(),
+			Hint:        "Sign the DPoP proof with the key the refresh token was issued for.",
+			StatusCode:  http.StatusBadRequest,
+		}
+	} else if errors.Is(ge, ErrUseDPoPNonce) {
+		return &RFC6749Error{
+			Name:        errUseDPoPNonce,
+			Description: ge.Error(),
+			Hint:        "Retry the request with a DPoP proof containing the nonce of the DPoP-Nonce header.",
+			StatusCode:  http.StatusBadRequest,
+		}
 	}
 	return &RFC6749Error{
 		Name:        errInvalidError,
@@ -148,3 +275,20 @@ func ErrorToRFC6749Error(err error) *RFC6749Error {
 		StatusCode:  http.StatusInternalServerError,
 	}
 }
+
+// toRFC6749Error is ErrorToRFC6749Error applying the ErrorMapper for the client of requester, which may be nil, and
+// the status codes configured in ErrorStatusCodes.
+func (f *Fosite) toRFC6749Error(requester Requester, err error) *RFC6749Error {
+	rfcerr := ErrorToRFC6749Error(err)
+	if f.ErrorMapper != nil {
+		var client Client
+		if requester != nil {
+			client = requester.GetClient()
+		}
+		rfcerr = f.ErrorMapper(client, rfcerr)
+	}
+	if status, ok := f.ErrorStatusCodes[rfcerr.Name]; ok {
+		rfcerr.StatusCode = status
+	}
+	return rfcerr
+}
@@ -2,6 +2,7 @@ package fosite
 
 import (
 	native "errors"
+	"net/http"
 	"testing"
 
 	"github.com/go-errors/errors"
@@ -23,4 +24,19 @@ func TestErrorToRFC6749(t *testing.T) {
 	assert.Equal(t, errInvalidGrantName, ErrorToRFC6749Error(errors.New(ErrInvalidGrant)).Name)
 	assert.Equal(t, errInvalidClientName, ErrorToRFC6749Error(errors.New(ErrInvalidClient)).Name)
 	assert.Equal(t, errInvalidState, ErrorToRFC6749Error(errors.New(ErrInvalidState)).Name)
+	assert.Equal(t, errConsentRequired, ErrorToRFC6749Error(errors.New(ErrConsentRequired)).Name)
+	assert.Equal(t, errInvalidRequestURI, ErrorToRFC6749Error(errors.New(ErrInvalidRequestURI)).Name)
+	assert.Equal(t, errRequestURINotSupported, ErrorToRFC6749Error(errors.New(ErrRequestURINotSupported)).Name)
+	assert.Equal(t, errInvalidTokenFormat, ErrorToRFC6749Error(errors.New(ErrInvalidTokenFormat)).Name)
+	assert.Equal(t, errUnsupportedTokenType, ErrorToRFC6749Error(errors.New(ErrUnsupportedTokenType)).Name)
+	assert.Equal(t, errUnmetAuthenticationRequirements, ErrorToRFC6749Error(errors.New(ErrUnmetAuthenticationRequirements)).Name)
+	assert.Equal(t, errUseDPoPNonce, ErrorToRFC6749Error(errors.New(ErrUseDPoPNonce)).Name)
+	assert.Equal(t, errInvalidRequestObject, ErrorToRFC6749Error(errors.New(ErrInvalidRequestObject)).Name)
+	assert.Equal(t, errUnsupportedEndpoint, ErrorToRFC6749Error(errors.New(ErrUnsupportedEndpoint)).Name)
+	assert.Equal(t, http.StatusNotImplemented, ErrorToRFC6749Error(errors.New(ErrUnsupportedEndpoint)).StatusCode)
+	assert.Equal(t, errInvalidDPoPProof, ErrorToRFC6749Error(errors.New(ErrInvalidDPoPProof)).Name)
+	assert.Equal(t, errInvalidRequestName, ErrorToRFC6749Error(errors.New(ErrReplayed)).Name)
+	assert.Equal(t, errLoginRequired, ErrorToRFC6749Error(errors.New(ErrLoginRequired)).Name)
+	assert.Equal(t, errInteractionRequired, ErrorToRFC6749Error(errors.New(ErrInteractionRequired)).Name)
+	assert.Equal(t, errAccountSelectionRequired, ErrorToRFC6749Error(errors.New(ErrAccountSelectionRequired)).Name)
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/ory-am/fosite/handler/core/implicit"
 	"github.com/ory-am/fosite/handler/core/owner"
 	"github.com/ory-am/fosite/handler/core/refresh"
+	"github.com/ory-am/fosite/handler/core/revocation"
 	"github.com/ory-am/fosite/handler/core/strategy"
 	"github.com/ory-am/fosite/handler/oidc"
 	oidcexplicit "github.com/ory-am/fosite/handler/oidc/explicit"
@@ -45,7 +46,7 @@ var store = &exampleStore.Store{
 			RedirectURIs:  []string{"http://localhost:3846/callback"},
 			ResponseTypes: []string{"id_token", "code", "token"},
 			GrantTypes:    []string{"implicit", "refresh_token", "authorization_code", "password", "client_credentials"},
-			GrantedScopes: []string{"fosite", "offline"},
+			GrantedScopes: []string{"fosite", "openid", "offline"},
 		},
 	},
 	Users: map[string]exampleStore.UserRelation{
@@ -246,11 +247,21 @@ func fositeFactory() OAuth2Provider {
 	}
 	f.AuthorizeEndpointHandlers.Append(oidcHybrid)
 
-	// Add a request validator for Access Tokens to fosite
-	f.AuthorizedRequestValidators.Append(&core.CoreValidator{
+	// Add a request validator for Access Tokens to fosite. The validator is also able to introspect access tokens.
+	coreValidator := &core.CoreValidator{
 		AccessTokenStrategy: hmacStrategy,
 		AccessTokenStorage:  store,
-	})
+	}
+	f.AuthorizedRequestValidators.Append(coreValidator)
+	f.TokenIntrospectors.Append(coreValidator)
+
+	// This handler revokes access and refresh tokens at the revocation endpoint.
+	revocationHandler := &revocation.TokenRevocationHandler{
+		AccessTokenStrategy:    selectedStrategy,
+		RefreshTokenStrategy:   selectedStrategy,
+		TokenRevocationStorage: store,
+	}
+	f.RevocationHandlers.Append(revocationHandler)
 
 	return f
 }
@@ -263,6 +274,8 @@ func main() {
 
 	http.HandleFunc("/auth", authEndpoint)
 	http.HandleFunc("/token", tokenEndpoint)
+	http.HandleFunc("/introspect", introspectionEndpoint)
+	http.HandleFunc("/revoke", revocationEndpoint)
 
 	http.HandleFunc("/", homeHandler)
 	http.HandleFunc("/callback", callbackHandler)
@@ -274,6 +287,34 @@ func main() {
 	log.Fatal(http.ListenAndServe(":3846", nil))
 }
 
+func introspectionEndpoint(rw http.ResponseWriter, req *http.Request) {
+	ctx := NewContext()
+	mySessionData := newSession("")
+
+	// This will authenticate the client and iterate through the registered TokenIntrospectors to look up the token.
+	response, err := oauth2.NewIntrospectionRequest(ctx, req, mySessionData)
+	if err != nil {
+		log.Printf("Error occurred in NewIntrospectionRequest: %s\nStack: \n%s", err, err.(*errors.Error).ErrorStack())
+		oauth2.WriteIntrospectionError(rw, err)
+		return
+	}
+
+	oauth2.WriteIntrospectionResponse(rw, response)
+}
+
+func revocationEndpoint(rw http.ResponseWriter, req *http.Request) {
+	ctx := NewContext()
+
+	// This will authenticate the client and iterate through the registered RevocationHandlers to revoke the token.
+	err := oauth2.NewRevocationRequest(ctx, req)
+	if err != nil {
+		log.Printf("Error occurred in NewRevocationRequest: %s\nStack: \n%s", err, err.(*errors.Error).ErrorStack())
+	}
+
+	// Writes an error response if err is not nil.
+	oauth2.WriteRevocationResponse(rw, err)
+}
+
 func tokenEndpoint(rw http.ResponseWriter, req *http.Request) {
 	// This context will be passed to all methods.
 	ctx := NewContext()
@@ -1,6 +1,9 @@
 package store
 
 import (
+	"sync"
+	"time"
+
 	"github.com/go-errors/errors"
 	"github.com/ory-am/fosite"
 	"golang.org/x/net/context"
@@ -19,6 +22,15 @@ type Store struct {
 	Implicit       map[string]fosite.Requester
 	RefreshTokens  map[string]fosite.Requester
 	Users          map[string]UserRelation
+
+	// Seen are the identifiers of one-time tokens and when they may be forgotten, see fosite.ReplayStorage.
+	Seen map[string]time.Time
+
+	// seenMutex makes MarkSeen atomic.
+	seenMutex sync.Mutex
+
+	// mutex guards the sessions, so that the store can be shared by concurrent requests.
+	mutex sync.RWMutex
 }
 
 func NewStore() *Store {
@@ -30,16 +42,23 @@ func NewStore() *Store {
 		Implicit:       make(map[string]fosite.Requester),
 		RefreshTokens:  make(map[string]fosite.Requester),
 		Users:          make(map[string]UserRelation),
+		Seen:           make(map[string]time.Time),
 	}
 
 }
 
 func (s *Store) CreateOpenIDConnectSession(_ context.Context, authorizeCode string, requester fosite.Requester) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.IDSessions[authorizeCode] = requester
 	return nil
 }
 
 func (s *Store) GetOpenIDConnectSession(_ context.Context, authorizeCode string, requester fosite.Requester) (fosite.Requester, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	cl, ok := s.IDSessions[authorizeCode]
 	if !ok {
 		return nil, fosite.ErrNotFound
@@ -48,11 +67,17 @@ func (s *Store) GetOpenIDConnectSession(_ context.Context, authorizeCode string,
 }
 
 func (s *Store) DeleteOpenIDConnectSession(_ context.Context, authorizeCode string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	delete(s.IDSessions, authorizeCode)
 	return nil
 }
 
 func (s *Store) GetClient(id string) (fosite.Client, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	cl, ok := s.Clients[id]
 	if !ok {
 		return nil, fosite.ErrNotFound
@@ -61,11 +86,17 @@ func (s *Store) GetClient(id string) (fosite.Client, error) {
 }
 
 func (s *Store) CreateAuthorizeCodeSession(_ context.Context, code string, req fosite.Requester) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.AuthorizeCodes[code] = req
 	return nil
 }
 
 func (s *Store) GetAuthorizeCodeSession(_ context.Context, code string, _ interface{}) (fosite.Requester, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	rel, ok := s.AuthorizeCodes[code]
 	if !ok {
 		return nil, fosite.ErrNotFound
@@ -74,16 +105,25 @@ func (s *Store) GetAuthorizeCodeSession(_ context.Context, code string, _ interf
 }
 
 func (s *Store) DeleteAuthorizeCodeSession(_ context.Context, code string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	delete(s.AuthorizeCodes, code)
 	return nil
 }
 
 func (s *Store) CreateAccessTokenSession(_ context.Context, signature string, req fosite.Requester) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.AccessTokens[signature] = req
 	return nil
 }
 
 func (s *Store) GetAccessTokenSession(_ context.Context, signature string, _ interface{}) (fosite.Requester, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	rel, ok := s.AccessTokens[signature]
 	if !ok {
 		return nil, fosite.ErrNotFound
@@ -92,16 +132,25 @@ func (s *Store) GetAccessTokenSession(_ context.Context, signature string, _ int
 }
 
 func (s *Store) DeleteAccessTokenSession(_ context.Context, signature string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	delete(s.AccessTokens, signature)
 	return nil
 }
 
 func (s *Store) CreateRefreshTokenSession(_ context.Context, signature string, req fosite.Requester) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.RefreshTokens[signature] = req
 	return nil
 }
 
 func (s *Store) GetRefreshTokenSession(_ context.Context, signature string, _ interface{}) (fosite.Requester, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	rel, ok := s.RefreshTokens[signature]
 	if !ok {
 		return nil, fosite.ErrNotFound
@@ -110,16 +159,25 @@ func (s *Store) GetRefreshTokenSession(_ context.Context, signature string, _ in
 }
 
 func (s *Store) DeleteRefreshTokenSession(_ context.Context, signature string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	delete(s.RefreshTokens, signature)
 	return nil
 }
 
 func (s *Store) CreateImplicitAccessTokenSession(_ context.Context, code string, req fosite.Requester) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.Implicit[code] = req
 	return nil
 }
 
 func (s *Store) Authenticate(_ context.Context, name string, secret string) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	rel, ok := s.Users[name]
 	if !ok {
 		return fosite.ErrNotFound
@@ -152,3 +210,51 @@ func (s *Store) PersistRefreshTokenGrantSession(ctx context.Context, originalRef
 
 	return nil
 }
+
+// PurgeExpired removes the sessions whose tokens expired before the given time. Sessions are only removed if their
+// expiry was recorded using fosite.Requester.SetExpiresAt.
+func (s *Store) PurgeExpired(_ context.Context, before time.Time) error {
+	s.mutex.Lock()
+	purge(s.AuthorizeCodes, before, fosite.AuthorizeCode)
+	purge(s.AccessTokens, before, fosite.AccessToken)
+	purge(s.Implicit, before, fosite.AccessToken)
+	purge(s.RefreshTokens, before, fosite.RefreshToken, fosite.RefreshTokenFamily)
+	s.mutex.Unlock()
+
+	s.seenMutex.Lock()
+	defer s.seenMutex.Unlock()
+	for id, expiresAt := range s.Seen {
+		if expiresAt.Before(before) {
+			delete(s.Seen, id)
+		}
+	}
+	return nil
+}
+
+// MarkSeen implements fosite.ReplayStorage. Identifiers which may be forgotten are treated as unseen, even if they
+// were not purged yet.
+func (s *Store) MarkSeen(_ context.Context, id string, expiresAt time.Time) (bool, error) {
+	s.seenMutex.Lock()
+	defer s.seenMutex.Unlock()
+
+	if s.Seen == nil {
+		s.Seen = make(map[string]time.Time)
+	}
+
+	if until, ok := s.Seen[id]; ok && time.Now().Before(until) {
+		return true, nil
+	}
+	s.Seen[id] = expiresAt
+	return false, nil
+}
+
+func purge(sessions map[string]fosite.Requester, before time.Time, tokenTypes ...string) {
+	for key, req := range sessions {
+		for _, tokenType := range tokenTypes {
+			if expiresAt := req.GetExpiresAt(tokenType); !expiresAt.IsZero() && expiresAt.Before(before) {
+				delete(sessions, key)
+				break
+			}
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package fosite
 
-import "github.com/ory-am/fosite/hash"
+import (
+	"time"
+
+	"github.com/ory-am/fosite/hash"
+	"github.com/ory-am/fosite/token/jwk"
+)
 
 // AuthorizeEndpointHandlers is a list of AuthorizeEndpointHandler
 type AuthorizeEndpointHandlers []AuthorizeEndpointHandler
@@ -26,6 +31,22 @@ func (t *AuthorizedRequestValidators) Append(h AuthorizedRequestValidator) {
 	*t = append(*t, h)
 }
 
+// TokenIntrospectors is a list of TokenIntrospector
+type TokenIntrospectors []TokenIntrospector
+
+// Add adds an TokenIntrospector to this list
+func (t *TokenIntrospectors) Append(h TokenIntrospector) {
+	*t = append(*t, h)
+}
+
+// RevocationHandlers is a list of RevocationHandler
+type RevocationHandlers []RevocationHandler
+
+// Add adds an RevocationHandler to this list
+func (t *RevocationHandlers) Append(h RevocationHandler) {
+	*t = append(*t, h)
+}
+
 // NewFosite returns a new OAuth2Provider implementation
 func NewFosite(store Storage) *Fosite {
 	return &Fosite{
@@ -34,16 +55,96 @@ func NewFosite(store Storage) *Fosite {
 		AuthorizeEndpointHandlers:   AuthorizeEndpointHandlers{},
 		TokenEndpointHandlers:       TokenEndpointHandlers{},
 		AuthorizedRequestValidators: AuthorizedRequestValidators{},
+		TokenIntrospectors:          TokenIntrospectors{},
+		RevocationHandlers:          RevocationHandlers{},
+		RequestValidators:           RequestValidators{},
 		Hasher: &hash.BCrypt{WorkFactor: 12},
+		ScopeStrategy:               HierarchicScopeStrategy,
+		JSONWebKeysFetcher:          &jwk.Fetcher{},
 	}
 }
 
 // Fosite implements OAuth2Provider.
+//
+// Fosite and its handlers are read by concurrent requests and must therefore not be modified once they serve
+// requests. To change the configuration at runtime, create a new instance and swap it using AtomicProvider.
 type Fosite struct {
 	MandatoryScope              string
 	Store                       Storage
 	AuthorizeEndpointHandlers   AuthorizeEndpointHandlers
 	TokenEndpointHandlers       TokenEndpointHandlers
 	AuthorizedRequestValidators AuthorizedRequestValidators
+	TokenIntrospectors          TokenIntrospectors
+	RevocationHandlers          RevocationHandlers
 	Hasher                      hash.Hasher
+
+	// RequestValidators enforce policies for all requests to the authorize, token, introspection and revocation
+	// endpoints before they are passed to the handlers.
+	RequestValidators RequestValidators
+
+	// ScopeStrategy is used to check if a client is allowed to request a scope.
+	ScopeStrategy ScopeStrategy
+
+	// IntrospectionScope is the scope clients must be allowed to request, see Client.GetScopes, to use the
+	// introspection endpoint. It is compared using ScopeStrategy. Every confidential client may introspect tokens
+	// if empty.
+	IntrospectionScope string
+
+	// RejectUnknownRequestParameters rejects authorize requests containing parameters which are neither defined
+	// by OAuth2 / OpenID Connect nor registered by the client. If false, unknown parameters are ignored.
+	RejectUnknownRequestParameters bool
+
+	// AggregateValidationErrors makes NewAuthorizeRequest look for all problems of a request instead of returning
+	// the first one, see ValidationErrors. The client still receives a single error code.
+	AggregateValidationErrors bool
+
+	// MaxRequestBodySize, MaxRequestParameters and MaxRequestParameterLength limit the size of requests
+	// to the authorize and token endpoints. Defaults are used if not set, see request_limits.go.
+	MaxRequestBodySize        int64
+	MaxRequestParameters      int
+	MaxRequestParameterLength int
+
+	// ErrorStatusCodes overrides the HTTP status codes used when writing errors, keyed by the error name, e.g.
+	// {"invalid_client": http.StatusBadRequest} for clients unable to handle 401 Unauthorized. Errors not listed
+	// use the status codes required by the specifications.
+	ErrorStatusCodes map[string]int
+
+	// ErrorMapper translates errors before they are written to a client, e.g. for legacy clients which only
+	// understand some error codes. ErrorStatusCodes applies to the translated error. Errors are not translated if nil.
+	ErrorMapper ErrorMapper
+
+	// AllowInsecureRedirectURIs accepts redirect URIs using http for hosts other than localhost. This must only be
+	// enabled in development environments.
+	AllowInsecureRedirectURIs bool
+
+	// RequestURIFetcher fetches request objects passed by reference. Authorize requests containing the request_uri
+	// parameter are rejected if nil.
+	RequestURIFetcher RequestURIFetcher
+
+	// Issuer is the issuer identifier of the authorization server, e.g. "https://auth.example.com". Request objects
+	// must contain it in their aud claim and are rejected if it is empty. The issuer of the Configuration in the
+	// context takes precedence.
+	Issuer string
+
+	// RequireRegisteredRequestURIs only accepts request_uri values which are pre-registered by the client.
+	RequireRegisteredRequestURIs bool
+
+	// JSONWebKeysFetcher fetches and caches the keys of clients which registered a jwks_uri. Keys published at
+	// jwks_uri can not be used if nil.
+	JSONWebKeysFetcher *jwk.Fetcher
+
+	// AuditSink receives audit events about issued, revoked and introspected tokens and failed client
+	// authentications. Events are discarded if nil.
+	AuditSink AuditSink
+
+	// DPoPNonceStrategy issues the nonces DPoP proofs must contain, see ValidateDPoPNonce. Nonces are not required if
+	// nil.
+	DPoPNonceStrategy DPoPNonceStrategy
+
+	// ClientCache caches the clients looked up in Store. Every lookup reaches the Store if nil.
+	ClientCache *ClientCache
+
+	// ReplayWindow is how long identifiers of one-time tokens are remembered by CheckReplay. Longer windows accept
+	// older tokens at the cost of storing more identifiers. Defaults to DefaultReplayWindow.
+	ReplayWindow time.Duration
 }
@@ -22,4 +22,7 @@ mockgen -package internal -destination internal/request.go github.com/ory-am/fos
 mockgen -package internal -destination internal/access_request.go github.com/ory-am/fosite AccessRequester
 mockgen -package internal -destination internal/access_response.go github.com/ory-am/fosite AccessResponder
 mockgen -package internal -destination internal/authorize_request.go github.com/ory-am/fosite AuthorizeRequester
-mockgen -package internal -destination internal/authorize_response.go github.com/ory-am/fosite AuthorizeResponder
\ No newline at end of file
+mockgen -package internal -destination internal/authorize_response.go github.com/ory-am/fosite AuthorizeResponder
+mockgen -package internal -destination internal/introspector.go github.com/ory-am/fosite TokenIntrospector
+mockgen -package internal -destination internal/revocation_handler.go github.com/ory-am/fosite RevocationHandler
+mockgen -package internal -destination internal/core_revocation_storage.go github.com/ory-am/fosite/handler/core/revocation BulkRevocationStorage
\ No newline at end of file
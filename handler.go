@@ -24,6 +24,60 @@ type AuthorizeEndpointHandler interface {
 	HandleAuthorizeEndpointRequest(ctx context.Context, req *http.Request, requester AuthorizeRequester, responder AuthorizeResponder) error
 }
 
+// AuthorizeEndpointValidator can be implemented by authorize endpoint handlers to reject requests they are
+// responsible for in NewAuthorizeRequest, before the end-user is asked for consent, instead of failing in
+// NewAuthorizeResponse. The session is not known yet when NewAuthorizeRequest validates the request.
+type AuthorizeEndpointValidator interface {
+	// ValidateAuthorizeEndpointRequest returns an error if the handler is responsible for the request but will
+	// be unable to handle it, e.g. because the client may not use the response type. It must return nil if
+	// the handler is not responsible and must neither issue anything nor modify the request.
+	ValidateAuthorizeEndpointRequest(ctx context.Context, requester AuthorizeRequester) error
+}
+
+// ResponseTypeHandler is implemented by authorize endpoint handlers which declare the response types they are
+// responsible for. If all authorize endpoint handlers implement ResponseTypeHandler, NewAuthorizeRequest
+// rejects response types none of them supports with ErrUnsupportedResponseType.
+type ResponseTypeHandler interface {
+	// SupportedResponseTypes returns the combinations of response types the handler is responsible for, e.g.
+	// []Arguments{{"token", "id_token"}} for the response_type "id_token token".
+	SupportedResponseTypes() []Arguments
+}
+
+// supportsResponseTypes returns false if all handlers declare their response types and none of them supports
+// responseTypes. It returns true if no handler is registered, or if one of them does not declare its response
+// types because it can not be told whether the handler is responsible.
+func (a AuthorizeEndpointHandlers) supportsResponseTypes(responseTypes Arguments) bool {
+	if len(a) == 0 {
+		return true
+	}
+
+	var supported bool
+	for _, h := range a {
+		rh, ok := h.(ResponseTypeHandler)
+		if !ok {
+			return true
+		}
+		for _, combination := range rh.SupportedResponseTypes() {
+			if responseTypes.Matches(combination...) {
+				supported = true
+			}
+		}
+	}
+	return supported
+}
+
+// TokenEndpointHandler handles token requests in two phases. OAuth2Provider.NewAccessRequest calls
+// HandleTokenEndpointRequest, which validates the request, e.g. the authorization code or the user's credentials,
+// and grants scopes, but must not issue tokens or modify the storage yet: the application may still deny the
+// request after NewAccessRequest returns. OAuth2Provider.NewAccessResponse then calls
+// PopulateTokenEndpointResponse, which issues and stores the tokens and sets them on the responder.
+// NewAccessRequest calls the HandleTokenEndpointRequest methods of all handlers before NewAccessResponse calls
+// any PopulateTokenEndpointResponse, so every check which may reject the request belongs in
+// HandleTokenEndpointRequest: once a handler populated the response, its tokens are stored unless the storage
+// is Transactional.
+//
+// Handlers of extension grant types should implement GrantTypeHandler as well, or be registered using
+// compose.ExtensionGrantFactory.
 type TokenEndpointHandler interface {
 	// PopulateTokenEndpointResponse is responsible for setting return values and should only be executed if
 	// the handler's HandleTokenEndpointRequest did not return ErrUnknownRequest.
@@ -33,3 +87,34 @@ type TokenEndpointHandler interface {
 	// the request, this method should return ErrUnknownRequest and otherwise handle the request.
 	HandleTokenEndpointRequest(ctx context.Context, req *http.Request, requester AccessRequester) error
 }
+
+// GrantTypeHandler is implemented by token endpoint handlers which declare the grant types they are responsible
+// for. A token request is routed to the one GrantTypeHandler declaring its grant_type, other GrantTypeHandlers
+// are not called. Token endpoint handlers which do not declare grant types, e.g. the OpenID Connect handlers
+// extending the authorization code grant, are called for every request and must return ErrUnknownRequest if
+// they are not responsible.
+type GrantTypeHandler interface {
+	// HandledGrantTypes returns the grant types the handler is responsible for.
+	HandledGrantTypes() Arguments
+}
+
+// route returns the handlers which are called for grantType, in the order they were registered. Returns
+// ErrMisconfiguration if more than one handler declares grantType.
+func (t TokenEndpointHandlers) route(grantType string) (TokenEndpointHandlers, error) {
+	var routed TokenEndpointHandlers
+	var claimed bool
+	for _, h := range t {
+		gh, ok := h.(GrantTypeHandler)
+		if !ok {
+			routed = append(routed, h)
+			continue
+		} else if !gh.HandledGrantTypes().Has(grantType) {
+			continue
+		} else if claimed {
+			return nil, errors.New(ErrMisconfiguration)
+		}
+		claimed = true
+		routed = append(routed, h)
+	}
+	return routed, nil
+}
@@ -11,6 +11,18 @@ import (
 
 type ClientCredentialsGrantHandler struct {
 	*core.HandleHelper
+
+	// AudienceDefaultScopes maps the audience parameter of requests which omit the scope parameter to the scopes
+	// requested instead, e.g. {"https://api.example.com": {"fosite", "api.read"}}. The audience must be registered
+	// by the client, see fosite.Client.GetAudience, otherwise the request is rejected with fosite.ErrInvalidRequest.
+	// All default scopes must be granted to the client, otherwise the request is rejected with
+	// fosite.ErrInvalidScope. The defaults must contain the mandatory scope of fosite.
+	AudienceDefaultScopes map[string]fosite.Arguments
+}
+
+// HandledGrantTypes implements fosite.GrantTypeHandler.
+func (c *ClientCredentialsGrantHandler) HandledGrantTypes() fosite.Arguments {
+	return fosite.Arguments{"client_credentials"}
 }
 
 // ValidateTokenEndpointRequest implements https://tools.ietf.org/html/rfc6749#section-4.4.2
@@ -21,21 +33,60 @@ func (c *ClientCredentialsGrantHandler) HandleTokenEndpointRequest(_ context.Con
 		return errors.New(fosite.ErrUnknownRequest)
 	}
 
+	// The client MUST authenticate with the authorization server as described in Section 3.2.1.
+	// Public clients are accepted by the token endpoint without authentication, they must not obtain tokens on
+	// their own behalf.
 	client := request.GetClient()
-	for _, scope := range request.GetScopes() {
+	if client.IsPublic() {
+		return errors.New(fosite.ErrInvalidClient)
+	}
+
+	scopes := request.GetScopes()
+	if len(scopes) == 0 {
+		var err error
+		if scopes, err = c.requestDefaultScopes(request, client); err != nil {
+			return err
+		}
+	}
+
+	for _, scope := range scopes {
 		if client.GetGrantedScopes().Grant(scope) {
 			request.GrantScope(scope)
 		}
 	}
 
-	// The client MUST authenticate with the authorization server as described in Section 3.2.1.
-	// This requirement is already fulfilled because fosite requries all token requests to be authenticated as described
-	// in https://tools.ietf.org/html/rfc6749#section-3.2.1
-
 	// There's nothing else to do. All other security considerations are for the client side.
 	return nil
 }
 
+// requestDefaultScopes requests and returns the default scopes of the audience parameter, see
+// AudienceDefaultScopes. Requests of unknown audiences are left unchanged.
+func (c *ClientCredentialsGrantHandler) requestDefaultScopes(request fosite.AccessRequester, client fosite.Client) (fosite.Arguments, error) {
+	if len(c.AudienceDefaultScopes) == 0 {
+		return nil, nil
+	}
+
+	audience := request.GetRequestForm().Get("audience")
+	defaults, ok := c.AudienceDefaultScopes[audience]
+	if !ok {
+		return nil, nil
+	} else if !client.GetAudience().Has(audience) {
+		return nil, errors.New(fosite.ErrInvalidRequest)
+	}
+
+	// Unlike requested scopes, which are granted as far as the client may obtain them, the defaults are
+	// configured by the server and must be granted completely.
+	for _, scope := range defaults {
+		if !client.GetGrantedScopes().Grant(scope) {
+			return nil, errors.New(fosite.ErrInvalidScope)
+		}
+	}
+
+	scopes := append(fosite.Arguments{}, defaults...)
+	request.SetScopes(scopes)
+	return scopes, nil
+}
+
 // PopulateTokenEndpointResponse implements https://tools.ietf.org/html/rfc6749#section-4.4.3
 func (c *ClientCredentialsGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, r *http.Request, request fosite.AccessRequester, response fosite.AccessResponder) error {
 	if !request.GetGrantTypes().Exact("client_credentials") {
@@ -2,6 +2,7 @@ package client
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/ory-am/fosite"
 	"github.com/ory-am/fosite/internal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ory-am/fosite/handler/core"
 )
@@ -22,7 +24,7 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	h := ClientCredentialsGrantHandler{
-		&core.HandleHelper{
+		HandleHelper: &core.HandleHelper{
 			AccessTokenStorage:  store,
 			AccessTokenStrategy: chgen,
 			AccessTokenLifespan: time.Hour,
@@ -41,6 +43,17 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 				areq.EXPECT().GetGrantTypes().Return(fosite.Arguments{""})
 			},
 		},
+		{
+			description: "should fail because the client is public",
+			expectErr:   fosite.ErrInvalidClient,
+			mock: func() {
+				areq.EXPECT().GetGrantTypes().Return(fosite.Arguments{"client_credentials"})
+				areq.EXPECT().GetClient().Return(&fosite.DefaultClient{
+					GrantTypes: fosite.Arguments{"client_credentials"},
+					Public:     true,
+				})
+			},
+		},
 		{
 			description: "should pass",
 			mock: func() {
@@ -62,6 +75,93 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 	}
 }
 
+func TestTokenRequestOfPublicClientIsRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	defer ctrl.Finish()
+
+	f := fosite.NewFosite(store)
+	f.TokenEndpointHandlers.Append(&ClientCredentialsGrantHandler{HandleHelper: &core.HandleHelper{}})
+	store.EXPECT().GetClient("foo").Return(&fosite.DefaultClient{
+		ID:            "foo",
+		Public:        true,
+		GrantTypes:    fosite.Arguments{"client_credentials"},
+		GrantedScopes: fosite.Arguments{"fosite"},
+	}, nil)
+
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {"foo"}}
+	_, err := f.NewAccessRequest(nil, &http.Request{Method: "POST", Header: http.Header{}, PostForm: form, Form: form}, struct{}{})
+	assert.True(t, errors.Is(fosite.ErrInvalidClient, err), "%s", err)
+}
+
+func TestHandleTokenEndpointRequestWithAudienceDefaultScopes(t *testing.T) {
+	h := ClientCredentialsGrantHandler{
+		HandleHelper: &core.HandleHelper{},
+		AudienceDefaultScopes: map[string]fosite.Arguments{
+			"https://api.example.com":   {"fosite", "api.read"},
+			"https://admin.example.com": {"fosite", "admin"},
+			"https://other.example.com": {"fosite", "photos"},
+		},
+	}
+	client := &fosite.DefaultClient{
+		GrantedScopes: []string{"fosite", "api", "photos"},
+		Audience:      []string{"https://api.example.com", "https://admin.example.com"},
+	}
+
+	for k, c := range []struct {
+		description   string
+		form          url.Values
+		expectErr     error
+		expectScopes  fosite.Arguments
+		expectGranted fosite.Arguments
+	}{
+		{
+			description:   "should request the default scopes of the audience",
+			form:          url.Values{"audience": {"https://api.example.com"}},
+			expectScopes:  fosite.Arguments{"fosite", "api.read"},
+			expectGranted: fosite.Arguments{"fosite", "api.read"},
+		},
+		{
+			description: "should fail because the client may not obtain all default scopes",
+			form:        url.Values{"audience": {"https://admin.example.com"}},
+			expectErr:   fosite.ErrInvalidScope,
+		},
+		{
+			description: "should fail because the client did not register the audience",
+			form:        url.Values{"audience": {"https://other.example.com"}},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description:  "should ignore unknown audiences",
+			form:         url.Values{"audience": {"https://unknown.example.com"}},
+			expectScopes: fosite.Arguments{},
+		},
+		{
+			description:   "should prefer the scope parameter",
+			form:          url.Values{"audience": {"https://api.example.com"}, "scope": {"photos"}},
+			expectScopes:  fosite.Arguments{"photos"},
+			expectGranted: fosite.Arguments{"photos"},
+		},
+	} {
+		areq := fosite.NewAccessRequest(nil)
+		areq.GrantTypes = fosite.Arguments{"client_credentials"}
+		areq.Client = client
+		areq.Form = c.form
+		if scope := c.form.Get("scope"); scope != "" {
+			areq.SetScopes(fosite.Arguments{scope})
+		}
+
+		err := h.HandleTokenEndpointRequest(nil, nil, areq)
+		if c.expectErr != nil {
+			assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s", k, c.description, err)
+			continue
+		}
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+		assert.Equal(t, c.expectScopes, areq.GetScopes(), "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectGranted, areq.GetGrantedScopes(), "(%d) %s", k, c.description)
+	}
+}
+
 func TestPopulateTokenEndpointResponse(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	store := internal.NewMockClientCredentialsGrantStorage(ctrl)
@@ -71,7 +171,7 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 	defer ctrl.Finish()
 
 	h := ClientCredentialsGrantHandler{
-		&core.HandleHelper{
+		HandleHelper: &core.HandleHelper{
 			AccessTokenStorage:  store,
 			AccessTokenStrategy: chgen,
 			AccessTokenLifespan: time.Hour,
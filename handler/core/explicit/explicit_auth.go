@@ -29,6 +29,18 @@ type AuthorizeExplicitGrantTypeHandler struct {
 
 	// AccessTokenLifespan defines the lifetime of an access token.
 	AccessTokenLifespan time.Duration
+
+	// RefreshTokenLifespan defines the lifetime of a refresh token. Because a new refresh token is issued whenever
+	// one is used, it limits how long a grant may stay unused. Refresh tokens do not expire if zero.
+	RefreshTokenLifespan time.Duration
+
+	// RefreshTokenMaxLifespan limits how long refresh tokens can be refreshed, counted from the first refresh token
+	// issued for the authorization. There is no limit if zero.
+	RefreshTokenMaxLifespan time.Duration
+
+	// IncludeRefreshTokenExpiresIn adds refresh_token_expires_in, the remaining lifetime of the refresh token in
+	// seconds, to the token response. The parameter is not standardized but expected by some client libraries.
+	IncludeRefreshTokenExpiresIn bool
 }
 
 func (c *AuthorizeExplicitGrantTypeHandler) HandleAuthorizeEndpointRequest(ctx context.Context, req *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) error {
@@ -37,11 +49,29 @@ func (c *AuthorizeExplicitGrantTypeHandler) HandleAuthorizeEndpointRequest(ctx c
 		return nil
 	}
 
+	if err := c.ValidateAuthorizeEndpointRequest(ctx, ar); err != nil {
+		return err
+	}
+
+	return c.IssueAuthorizeCode(ctx, req, ar, resp)
+}
+
+// SupportedResponseTypes implements fosite.ResponseTypeHandler.
+func (c *AuthorizeExplicitGrantTypeHandler) SupportedResponseTypes() []Arguments {
+	return []Arguments{{"code"}}
+}
+
+// ValidateAuthorizeEndpointRequest implements fosite.AuthorizeEndpointValidator.
+func (c *AuthorizeExplicitGrantTypeHandler) ValidateAuthorizeEndpointRequest(_ context.Context, ar AuthorizeRequester) error {
+	if !ar.GetResponseTypes().Exact("code") {
+		return nil
+	}
+
 	if !ar.GetClient().GetResponseTypes().Has("code") {
 		return errors.New(ErrInvalidGrant)
 	}
 
-	return c.IssueAuthorizeCode(ctx, req, ar, resp)
+	return nil
 }
 
 func (c *AuthorizeExplicitGrantTypeHandler) IssueAuthorizeCode(ctx context.Context, req *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) error {
@@ -50,6 +80,7 @@ func (c *AuthorizeExplicitGrantTypeHandler) IssueAuthorizeCode(ctx context.Conte
 		return errors.New(ErrServerError)
 	}
 
+	c.SetAuthorizeCodeExpiry(ctx, ar)
 	if err := c.AuthorizeCodeGrantStorage.CreateAuthorizeCodeSession(ctx, signature, ar); err != nil {
 		return errors.New(ErrServerError)
 	}
@@ -60,3 +91,19 @@ func (c *AuthorizeExplicitGrantTypeHandler) IssueAuthorizeCode(ctx context.Conte
 	ar.SetResponseTypeHandled("code")
 	return nil
 }
+
+// SetAuthorizeCodeExpiry records when the authorize code issued for ar expires, so that stores can purge it. Call it
+// before the authorize code session is created.
+func (c *AuthorizeExplicitGrantTypeHandler) SetAuthorizeCodeExpiry(ctx context.Context, ar Requester) {
+	ar.SetExpiresAt(AuthorizeCode, ar.GetRequestedAt().Add(c.getAuthCodeLifespan(ctx, ar.GetClient())))
+}
+
+// getAuthCodeLifespan returns the lifespan of authorize codes issued to client. If no lifespan has been set, the
+// default lifespan is used. The handler itself is not modified as it is shared by concurrent requests.
+func (c *AuthorizeExplicitGrantTypeHandler) getAuthCodeLifespan(ctx context.Context, client Client) time.Duration {
+	lifespan := c.AuthCodeLifespan
+	if lifespan <= 0 {
+		lifespan = authCodeDefaultLifespan
+	}
+	return GetEffectiveLifespanWithContext(ctx, client, "authorization_code", AuthorizeCode, lifespan)
+}
@@ -65,7 +65,7 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 		{
 			description: "should pass",
 			setup: func() {
-				areq.GrantedScopes = fosite.Arguments{"a", "b"}
+				areq.GrantedScopes = fosite.ScopeArguments{"a", "b"}
 				areq.State = "superstate"
 				store.EXPECT().CreateAuthorizeCodeSession(nil, "authsig", areq).Return(nil)
 				aresp.EXPECT().AddQuery("code", "someauthcode.authsig")
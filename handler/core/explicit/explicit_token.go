@@ -2,13 +2,20 @@ package explicit
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/core"
 	"golang.org/x/net/context"
 )
 
+// HandledGrantTypes implements fosite.GrantTypeHandler.
+func (c *AuthorizeExplicitGrantTypeHandler) HandledGrantTypes() fosite.Arguments {
+	return fosite.Arguments{"authorization_code"}
+}
+
 // HandleTokenEndpointRequest implements
 // * https://tools.ietf.org/html/rfc6749#section-4.1.3 (everything)
 func (c *AuthorizeExplicitGrantTypeHandler) HandleTokenEndpointRequest(ctx context.Context, r *http.Request, request fosite.AccessRequester) error {
@@ -45,6 +52,12 @@ func (c *AuthorizeExplicitGrantTypeHandler) HandleTokenEndpointRequest(ctx conte
 		return errors.New(fosite.ErrInvalidRequest)
 	}
 
+	// Only codes issued for response_type=code, possibly combined with other response types as in the hybrid flow,
+	// may be exchanged using the authorization_code grant.
+	if !getResponseTypes(authorizeRequest).Has("code") {
+		return errors.New(fosite.ErrInvalidGrant)
+	}
+
 	// ensure that the "redirect_uri" parameter is present if the
 	// "redirect_uri" parameter was included in the initial authorization
 	// request as described in Section 4.1.1, and if included ensure that
@@ -54,15 +67,10 @@ func (c *AuthorizeExplicitGrantTypeHandler) HandleTokenEndpointRequest(ctx conte
 		return errors.New(fosite.ErrInvalidRequest)
 	}
 
-	// If no lifespan has been set, reset to default lifespan
-	if c.AuthCodeLifespan <= 0 {
-		c.AuthCodeLifespan = authCodeDefaultLifespan
-	}
-
 	// https://tools.ietf.org/html/rfc6819#section-5.1.5.3]
 	// A short expiration time for tokens is a means of protection against
 	// the following threats: replay, token leak, online guessing
-	if authorizeRequest.GetRequestedAt().Add(c.AuthCodeLifespan).Before(time.Now()) {
+	if authorizeRequest.GetRequestedAt().Add(c.getAuthCodeLifespan(ctx, request.GetClient())).Before(time.Now()) {
 		return errors.New(fosite.ErrInvalidRequest)
 	}
 
@@ -71,7 +79,15 @@ func (c *AuthorizeExplicitGrantTypeHandler) HandleTokenEndpointRequest(ctx conte
 	// credentials (or assigned other authentication requirements), the
 	// client MUST authenticate with the authorization server as described
 	// in Section 3.2.1.
+	request.SetOriginalRequest(authorizeRequest)
 	request.SetSession(authorizeRequest.GetSession())
+
+	// The tokens are issued for the scopes the resource owner granted to the code.
+	for _, scope := range authorizeRequest.GetGrantedScopes() {
+		request.GrantScope(scope)
+	}
+	fosite.CopyClaimsRequest(authorizeRequest, request)
+	fosite.CopyGrantedAudience(authorizeRequest, request)
 	return nil
 }
 
@@ -97,25 +113,46 @@ func (c *AuthorizeExplicitGrantTypeHandler) PopulateTokenEndpointResponse(ctx co
 		return errors.New(fosite.ErrServerError)
 	}
 
+	issuedAt := time.Now()
 	var refresh, refreshSignature string
-	if authorizeRequest.GetGrantedScopes().Has("offline") {
+	if fosite.IsRefreshTokenRequested(authorizeRequest) {
 		refresh, refreshSignature, err = c.RefreshTokenStrategy.GenerateRefreshToken(ctx, requester)
 		if err != nil {
 			return errors.New(fosite.ErrServerError)
 		}
+
+		core.SetRefreshTokenExpiry(requester, nil, issuedAt,
+			fosite.GetEffectiveLifespanWithContext(ctx, requester.GetClient(), "authorization_code", fosite.RefreshToken, c.RefreshTokenLifespan),
+			fosite.GetEffectiveLifespanWithContext(ctx, requester.GetClient(), "authorization_code", fosite.RefreshTokenFamily, c.RefreshTokenMaxLifespan))
 	}
 
+	lifespan := fosite.GetEffectiveLifespanWithContext(ctx, requester.GetClient(), "authorization_code", fosite.AccessToken, c.AccessTokenLifespan)
+	requester.SetExpiresAt(fosite.AccessToken, issuedAt.Add(lifespan))
 	if err := c.AuthorizeCodeGrantStorage.PersistAuthorizeCodeGrantSession(ctx, signature, accessSignature, refreshSignature, requester); err != nil {
 		return errors.New(fosite.ErrServerError)
 	}
 
 	responder.SetAccessToken(access)
-	responder.SetTokenType("bearer")
-	responder.SetExpiresIn(c.AccessTokenLifespan / time.Second)
+	responder.SetTokenType(fosite.GetTokenType(requester))
+	responder.SetExpiresIn(lifespan)
+	responder.SetIssuedAt(issuedAt)
+	responder.SetExpiresAt(issuedAt.Add(lifespan))
 	responder.SetScopes(requester.GetGrantedScopes())
 	if refresh != "" {
 		responder.SetExtra("refresh_token", refresh)
+		if c.IncludeRefreshTokenExpiresIn {
+			core.SetRefreshTokenExpiresIn(requester, responder, issuedAt)
+		}
 	}
 
 	return nil
 }
+
+// getResponseTypes returns the response types of the authorize request an authorize code was issued for. Storages
+// which do not return a fosite.AuthorizeRequester keep them in the request form.
+func getResponseTypes(authorizeRequest fosite.Requester) fosite.Arguments {
+	if ar, ok := authorizeRequest.(fosite.AuthorizeRequester); ok && len(ar.GetResponseTypes()) > 0 {
+		return ar.GetResponseTypes()
+	}
+	return fosite.Arguments(strings.Fields(authorizeRequest.GetRequestForm().Get("response_type")))
+}
@@ -32,6 +32,7 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 		AuthorizeCodeStrategy:     auch,
 		AccessTokenStrategy:       ach,
 		RefreshTokenStrategy:      rch,
+		RefreshTokenLifespan:      time.Hour,
 	}
 	for k, c := range []struct {
 		description string
@@ -93,14 +94,31 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 		{
 			description: "should pass",
 			setup: func() {
-				areq.GrantedScopes = fosite.Arguments{"foo"}
+				areq.GrantedScopes = fosite.ScopeArguments{"foo"}
 				store.EXPECT().PersistAuthorizeCodeGrantSession(nil, "authsig", "ats", "rts", areq).Return(nil)
 
 				aresp.EXPECT().SetAccessToken("access.ats")
 				aresp.EXPECT().SetTokenType("bearer")
 				aresp.EXPECT().SetExtra("refresh_token", "refresh.rts")
 				aresp.EXPECT().SetExpiresIn(gomock.Any())
-				aresp.EXPECT().SetScopes(areq.GrantedScopes)
+				aresp.EXPECT().SetIssuedAt(gomock.Any())
+				aresp.EXPECT().SetExpiresAt(gomock.Any())
+				aresp.EXPECT().SetScopes(areq.GetGrantedScopes())
+			},
+		},
+		{
+			description: "should pass without a refresh token because the authorize request asked for none",
+			setup: func() {
+				authreq.GrantedScopes = []string{"offline_access"}
+				authreq.Form.Set(fosite.NoRefreshTokenParameter, "true")
+				store.EXPECT().PersistAuthorizeCodeGrantSession(nil, "authsig", "ats", "", areq).Return(nil)
+
+				aresp.EXPECT().SetAccessToken("access.ats")
+				aresp.EXPECT().SetTokenType("bearer")
+				aresp.EXPECT().SetExpiresIn(gomock.Any())
+				aresp.EXPECT().SetIssuedAt(gomock.Any())
+				aresp.EXPECT().SetExpiresAt(gomock.Any())
+				aresp.EXPECT().SetScopes(areq.GetGrantedScopes())
 			},
 		},
 	} {
@@ -109,6 +127,7 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
 		t.Logf("Passed test case %d", k)
 	}
+	assert.WithinDuration(t, time.Now().Add(time.Hour), areq.GetExpiresAt(fosite.RefreshToken), time.Minute)
 }
 
 func TestHandleTokenEndpointRequest(t *testing.T) {
@@ -118,6 +137,7 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 	defer ctrl.Finish()
 
 	authreq := fosite.NewAuthorizeRequest()
+	authreq.ResponseTypes = fosite.Arguments{"code"}
 	areq := fosite.NewAccessRequest(nil)
 	httpreq := &http.Request{PostForm: url.Values{}}
 
@@ -167,7 +187,8 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 				store.EXPECT().GetAuthorizeCodeSession(nil, "bar", nil).AnyTimes().Return(authreq, nil)
 
 				areq.Client = &fosite.DefaultClient{ID: "foo"}
-				authreq.Scopes = fosite.Arguments{"a", "b"}
+				authreq.Scopes = fosite.ScopeArguments{"a", "b"}
+				authreq.GrantedScopes = fosite.ScopeArguments{"a"}
 				authreq.Client = &fosite.DefaultClient{ID: "bar"}
 			},
 			expectErr: fosite.ErrInvalidRequest,
@@ -203,10 +224,43 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 				authreq.RequestedAt = time.Now().Add(time.Hour)
 			},
 		},
+		{
+			description: "should fail because the code was not issued for response_type=code",
+			setup: func() {
+				authreq.ResponseTypes = fosite.Arguments{"id_token"}
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the code was issued for an unknown response type",
+			setup: func() {
+				authreq.ResponseTypes = fosite.Arguments{}
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass because the response types are read from the stored request form",
+			setup: func() {
+				authreq.Form.Set("response_type", "code id_token")
+			},
+		},
 	} {
 		c.setup()
 		err := h.HandleTokenEndpointRequest(nil, httpreq, areq)
 		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
 		t.Logf("Passed test case %d", k)
 	}
+
+	// The scopes the resource owner granted to the code are granted to the token request.
+	assert.True(t, areq.GetGrantedScopes().Has("a"))
+	assert.False(t, areq.GetGrantedScopes().Has("b"), "requested scopes which were not granted")
+
+	// The authorize request is exposed so that handlers can read its parameters when issuing tokens.
+	assert.Equal(t, authreq, areq.GetOriginalRequest())
+
+	// Clients may shorten the lifespan of their authorize codes.
+	authreq.RequestedAt = time.Now().Add(-time.Minute * 10)
+	areq.Client = &fosite.DefaultClient{ID: "foo", Lifespans: map[string]time.Duration{"authorization_code " + fosite.AuthorizeCode: time.Minute * 5}}
+	err := h.HandleTokenEndpointRequest(nil, httpreq, areq)
+	assert.True(t, errors.Is(fosite.ErrInvalidRequest, err), "%s", err)
 }
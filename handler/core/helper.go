@@ -2,6 +2,7 @@ package core
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	. "github.com/ory-am/fosite"
@@ -18,13 +19,67 @@ func (h *HandleHelper) IssueAccessToken(ctx context.Context, req *http.Request,
 	token, signature, err := h.AccessTokenStrategy.GenerateAccessToken(ctx, requester)
 	if err != nil {
 		return err
-	} else if err := h.AccessTokenStorage.CreateAccessTokenSession(ctx, signature, requester); err != nil {
+	}
+
+	issuedAt := time.Now()
+	lifespan := GetEffectiveLifespanWithContext(ctx, requester.GetClient(), strings.Join(requester.GetGrantTypes(), " "), AccessToken, h.AccessTokenLifespan)
+	requester.SetExpiresAt(AccessToken, issuedAt.Add(lifespan))
+	if err := h.AccessTokenStorage.CreateAccessTokenSession(ctx, signature, requester); err != nil {
 		return err
 	}
 
 	responder.SetAccessToken(token)
-	responder.SetTokenType("bearer")
-	responder.SetExpiresIn(h.AccessTokenLifespan / time.Second)
+	responder.SetTokenType(GetTokenType(requester))
+	responder.SetExpiresIn(lifespan)
+	responder.SetIssuedAt(issuedAt)
+	responder.SetExpiresAt(issuedAt.Add(lifespan))
 	responder.SetScopes(requester.GetGrantedScopes())
 	return nil
 }
+
+// SetRefreshTokenExpiry sets when the refresh token issued for requester at issuedAt expires. Each refresh token is
+// valid for lifespan, which therefore acts as an idle timeout, but never beyond maxLifespan counted from the first
+// refresh token issued for the authorization. original is the stored request of the refresh token being exchanged,
+// if any. Zero durations do not limit the lifespan.
+func SetRefreshTokenExpiry(requester Requester, original Requester, issuedAt time.Time, lifespan, maxLifespan time.Duration) {
+	var family time.Time
+	if original != nil {
+		family = original.GetExpiresAt(RefreshTokenFamily)
+	}
+	if family.IsZero() && maxLifespan > 0 {
+		family = issuedAt.Add(maxLifespan)
+	}
+
+	var expiresAt time.Time
+	if lifespan > 0 {
+		expiresAt = issuedAt.Add(lifespan)
+	}
+	if !family.IsZero() && (expiresAt.IsZero() || family.Before(expiresAt)) {
+		expiresAt = family
+	}
+
+	if !family.IsZero() {
+		requester.SetExpiresAt(RefreshTokenFamily, family)
+	}
+	if !expiresAt.IsZero() {
+		requester.SetExpiresAt(RefreshToken, expiresAt)
+	}
+}
+
+// SetRefreshTokenExpiresIn adds refresh_token_expires_in to responder if the refresh token issued for requester at
+// issuedAt expires, see SetRefreshTokenExpiry.
+func SetRefreshTokenExpiresIn(requester Requester, responder AccessResponder, issuedAt time.Time) {
+	if expiresAt := requester.GetExpiresAt(RefreshToken); !expiresAt.IsZero() {
+		responder.SetExtra("refresh_token_expires_in", ExpiresIn(expiresAt.Sub(issuedAt)))
+	}
+}
+
+// IsRefreshTokenExpired returns true if the refresh token issued for requester or its family expired before now.
+func IsRefreshTokenExpired(requester Requester, now time.Time) bool {
+	for _, tokenType := range []string{RefreshToken, RefreshTokenFamily} {
+		if expiresAt := requester.GetExpiresAt(tokenType); !expiresAt.IsZero() && expiresAt.Before(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -59,6 +59,171 @@ func TestIssueAccessToken(t *testing.T) {
 		require.Equal(t, err == nil, c.err == nil)
 		if c.err != nil {
 			assert.EqualError(t, err, c.err.Error(), "Case %d", k)
+		} else {
+			assert.False(t, aresp.GetIssuedAt().IsZero(), "Case %d", k)
+			assert.Equal(t, aresp.GetIssuedAt().Add(time.Hour), aresp.GetExpiresAt(), "Case %d", k)
 		}
 	}
 }
+
+type confirmationSession map[string]string
+
+func (s confirmationSession) GetConfirmation() map[string]string { return s }
+
+func TestIssueAccessTokenTypeDependsOnBinding(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accessStrat := internal.NewMockAccessTokenStrategy(ctrl)
+	accessStore := internal.NewMockAccessTokenStorage(ctrl)
+	defer ctrl.Finish()
+
+	helper := HandleHelper{
+		AccessTokenStorage:  accessStore,
+		AccessTokenStrategy: accessStrat,
+		AccessTokenLifespan: time.Hour,
+	}
+
+	for k, c := range []struct {
+		description string
+		session     interface{}
+		expect      string
+	}{
+		{description: "should issue bearer tokens without a session", expect: "bearer"},
+		{description: "should issue bearer tokens if the token is not bound", session: confirmationSession{}, expect: "bearer"},
+		{description: "should issue bearer tokens if the token is bound to a client certificate", session: confirmationSession{fosite.ConfirmationX509Thumbprint: "certificate"}, expect: "bearer"},
+		{description: "should issue DPoP tokens if the token is bound to a DPoP key", session: confirmationSession{fosite.ConfirmationJWKThumbprint: "key"}, expect: "DPoP"},
+	} {
+		areq := fosite.NewAccessRequest(c.session)
+		aresp := &fosite.AccessResponse{Extra: map[string]interface{}{}}
+		accessStrat.EXPECT().GenerateAccessToken(nil, areq).Return("token", "signature", nil)
+		accessStore.EXPECT().CreateAccessTokenSession(nil, "signature", areq).Return(nil)
+
+		require.Nil(t, helper.IssueAccessToken(nil, &http.Request{}, areq, aresp), "(%d) %s", k, c.description)
+		assert.Equal(t, c.expect, aresp.GetTokenType(), "(%d) %s", k, c.description)
+	}
+}
+
+func TestIssueAccessTokenUsesClientLifespan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accessStrat := internal.NewMockAccessTokenStrategy(ctrl)
+	accessStore := internal.NewMockAccessTokenStorage(ctrl)
+	defer ctrl.Finish()
+
+	helper := HandleHelper{
+		AccessTokenStorage:  accessStore,
+		AccessTokenStrategy: accessStrat,
+		AccessTokenLifespan: time.Hour,
+	}
+
+	for k, c := range []struct {
+		grantType string
+		expect    time.Duration
+	}{
+		{grantType: "client_credentials", expect: time.Minute},
+		{grantType: "password", expect: time.Hour},
+	} {
+		areq := &fosite.AccessRequest{GrantTypes: fosite.Arguments{c.grantType}}
+		areq.Client = &fosite.DefaultClient{Lifespans: map[string]time.Duration{"client_credentials " + fosite.AccessToken: time.Minute}}
+		aresp := &fosite.AccessResponse{Extra: map[string]interface{}{}}
+		accessStrat.EXPECT().GenerateAccessToken(nil, areq).Return("token", "signature", nil)
+		accessStore.EXPECT().CreateAccessTokenSession(nil, "signature", areq).Return(nil)
+
+		require.Nil(t, helper.IssueAccessToken(nil, &http.Request{}, areq, aresp), "Case %d", k)
+		assert.Equal(t, aresp.GetIssuedAt().Add(c.expect), aresp.GetExpiresAt(), "Case %d", k)
+	}
+}
+
+func TestSetRefreshTokenExpiry(t *testing.T) {
+	now := time.Now()
+	for k, c := range []struct {
+		description        string
+		originalFamily     time.Time
+		lifespan           time.Duration
+		maxLifespan        time.Duration
+		expectExpiresAt    time.Time
+		expectFamilyExpiry time.Time
+	}{
+		{
+			description: "should not expire without lifespans",
+		},
+		{
+			description:     "should use the idle lifespan",
+			lifespan:        time.Hour,
+			expectExpiresAt: now.Add(time.Hour),
+		},
+		{
+			description:        "should start the family with the first refresh token",
+			lifespan:           time.Hour,
+			maxLifespan:        time.Hour * 24,
+			expectExpiresAt:    now.Add(time.Hour),
+			expectFamilyExpiry: now.Add(time.Hour * 24),
+		},
+		{
+			description:        "should keep the expiry of the family",
+			originalFamily:     now.Add(time.Hour * 2),
+			lifespan:           time.Hour,
+			maxLifespan:        time.Hour * 24,
+			expectExpiresAt:    now.Add(time.Hour),
+			expectFamilyExpiry: now.Add(time.Hour * 2),
+		},
+		{
+			description:        "should not outlive the family",
+			originalFamily:     now.Add(time.Minute),
+			lifespan:           time.Hour,
+			maxLifespan:        time.Hour * 24,
+			expectExpiresAt:    now.Add(time.Minute),
+			expectFamilyExpiry: now.Add(time.Minute),
+		},
+		{
+			description:        "should use the absolute lifespan without idle lifespan",
+			maxLifespan:        time.Hour * 24,
+			expectExpiresAt:    now.Add(time.Hour * 24),
+			expectFamilyExpiry: now.Add(time.Hour * 24),
+		},
+	} {
+		original := fosite.NewRequest()
+		if !c.originalFamily.IsZero() {
+			original.SetExpiresAt(fosite.RefreshTokenFamily, c.originalFamily)
+		}
+
+		requester := fosite.NewRequest()
+		SetRefreshTokenExpiry(requester, original, now, c.lifespan, c.maxLifespan)
+		assert.Equal(t, c.expectExpiresAt, requester.GetExpiresAt(fosite.RefreshToken), "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectFamilyExpiry, requester.GetExpiresAt(fosite.RefreshTokenFamily), "(%d) %s", k, c.description)
+	}
+}
+
+func TestSetRefreshTokenExpiresIn(t *testing.T) {
+	now := time.Now()
+	for k, c := range []struct {
+		expiresAt time.Time
+		expect    interface{}
+	}{
+		{expect: nil},
+		{expiresAt: now.Add(time.Hour), expect: int64(3600)},
+		{expiresAt: now.Add(time.Hour + time.Second/2), expect: int64(3600)},
+	} {
+		requester := fosite.NewRequest()
+		if !c.expiresAt.IsZero() {
+			requester.SetExpiresAt(fosite.RefreshToken, c.expiresAt)
+		}
+		responder := fosite.NewAccessResponse()
+		SetRefreshTokenExpiresIn(requester, responder, now)
+		assert.Equal(t, c.expect, responder.GetExtra("refresh_token_expires_in"), "Case %d", k)
+	}
+}
+
+func TestIsRefreshTokenExpired(t *testing.T) {
+	now := time.Now()
+	r := fosite.NewRequest()
+	assert.False(t, IsRefreshTokenExpired(r, now))
+
+	r.SetExpiresAt(fosite.RefreshToken, now.Add(time.Hour))
+	assert.False(t, IsRefreshTokenExpired(r, now))
+
+	r.SetExpiresAt(fosite.RefreshTokenFamily, now.Add(-time.Minute))
+	assert.True(t, IsRefreshTokenExpired(r, now))
+
+	r.SetExpiresAt(fosite.RefreshTokenFamily, now.Add(time.Hour))
+	r.SetExpiresAt(fosite.RefreshToken, now.Add(-time.Minute))
+	assert.True(t, IsRefreshTokenExpired(r, now))
+}
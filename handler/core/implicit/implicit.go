@@ -31,6 +31,24 @@ func (c *AuthorizeImplicitGrantTypeHandler) HandleAuthorizeEndpointRequest(ctx c
 		return nil
 	}
 
+	if err := c.ValidateAuthorizeEndpointRequest(ctx, ar); err != nil {
+		return err
+	}
+
+	return c.IssueImplicitAccessToken(ctx, req, ar, resp)
+}
+
+// SupportedResponseTypes implements fosite.ResponseTypeHandler.
+func (c *AuthorizeImplicitGrantTypeHandler) SupportedResponseTypes() []Arguments {
+	return []Arguments{{"token"}}
+}
+
+// ValidateAuthorizeEndpointRequest implements fosite.AuthorizeEndpointValidator.
+func (c *AuthorizeImplicitGrantTypeHandler) ValidateAuthorizeEndpointRequest(_ context.Context, ar AuthorizeRequester) error {
+	if !ar.GetResponseTypes().Exact("token") {
+		return nil
+	}
+
 	if !ar.GetClient().GetResponseTypes().Has("token") {
 		return errors.New(ErrInvalidGrant)
 	}
@@ -39,7 +57,7 @@ func (c *AuthorizeImplicitGrantTypeHandler) HandleAuthorizeEndpointRequest(ctx c
 		return errors.New(ErrInvalidGrant)
 	}
 
-	return c.IssueImplicitAccessToken(ctx, req, ar, resp)
+	return nil
 }
 
 func (c *AuthorizeImplicitGrantTypeHandler) IssueImplicitAccessToken(ctx context.Context, req *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) error {
@@ -47,13 +65,17 @@ func (c *AuthorizeImplicitGrantTypeHandler) IssueImplicitAccessToken(ctx context
 	token, signature, err := c.AccessTokenStrategy.GenerateAccessToken(ctx, ar)
 	if err != nil {
 		return errors.New(ErrServerError)
-	} else if err := c.AccessTokenStorage.CreateAccessTokenSession(ctx, signature, ar); err != nil {
+	}
+
+	lifespan := GetEffectiveLifespanWithContext(ctx, ar.GetClient(), "implicit", AccessToken, c.AccessTokenLifespan)
+	ar.SetExpiresAt(AccessToken, time.Now().Add(lifespan))
+	if err := c.AccessTokenStorage.CreateAccessTokenSession(ctx, signature, ar); err != nil {
 		return errors.New(ErrServerError)
 	}
 
 	resp.AddFragment("access_token", token)
-	resp.AddFragment("expires_in", strconv.Itoa(int(c.AccessTokenLifespan/time.Second)))
-	resp.AddFragment("token_type", "bearer")
+	resp.AddFragment("expires_in", strconv.FormatInt(ExpiresIn(lifespan), 10))
+	resp.AddFragment("token_type", GetTokenType(ar))
 	resp.AddFragment("state", ar.GetState())
 	resp.AddFragment("scope", strings.Join(ar.GetGrantedScopes(), "+"))
 	ar.SetResponseTypeHandled("token")
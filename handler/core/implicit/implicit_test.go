@@ -65,12 +65,12 @@ func TestAuthorizeImplicitEndpointHandler(t *testing.T) {
 			description: "should pass",
 			setup: func() {
 				areq.State = "state"
-				areq.GrantedScopes = fosite.Arguments{"scope"}
+				areq.GrantedScopes = fosite.ScopeArguments{"scope"}
 
 				store.EXPECT().CreateAccessTokenSession(nil, "ats", areq).AnyTimes().Return(nil)
 
 				aresp.EXPECT().AddFragment("access_token", "access.ats")
-				aresp.EXPECT().AddFragment("expires_in", strconv.Itoa(int(h.AccessTokenLifespan/time.Second)))
+				aresp.EXPECT().AddFragment("expires_in", strconv.FormatInt(int64(h.AccessTokenLifespan/time.Second), 10))
 				aresp.EXPECT().AddFragment("token_type", "bearer")
 				aresp.EXPECT().AddFragment("state", "state")
 				aresp.EXPECT().AddFragment("scope", "scope")
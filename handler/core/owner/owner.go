@@ -16,6 +16,11 @@ type ResourceOwnerPasswordCredentialsGrantHandler struct {
 	*core.HandleHelper
 }
 
+// HandledGrantTypes implements fosite.GrantTypeHandler.
+func (c *ResourceOwnerPasswordCredentialsGrantHandler) HandledGrantTypes() fosite.Arguments {
+	return fosite.Arguments{"password"}
+}
+
 // HandleTokenEndpointRequest implements https://tools.ietf.org/html/rfc6749#section-4.3.2
 func (c *ResourceOwnerPasswordCredentialsGrantHandler) HandleTokenEndpointRequest(ctx context.Context, req *http.Request, request fosite.AccessRequester) error {
 	// grant_type REQUIRED.
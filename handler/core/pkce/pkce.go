@@ -0,0 +1,132 @@
+package pkce
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"regexp"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"golang.org/x/net/context"
+)
+
+const (
+	// ChallengeMethodS256 and ChallengeMethodPlain are the code_challenge_method values defined by
+	// https://tools.ietf.org/html/rfc7636#section-4.2
+	ChallengeMethodS256  = "S256"
+	ChallengeMethodPlain = "plain"
+)
+
+// verifierPattern matches code verifiers and challenges, both are 43 to 128 characters long, see
+// https://tools.ietf.org/html/rfc7636#section-4.1
+//
+//	code-verifier = 43*128unreserved
+//	unreserved = ALPHA / DIGIT / "-" / "." / "_" / "~"
+var verifierPattern = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+// Handler implements Proof Key for Code Exchange, see https://tools.ietf.org/html/rfc7636
+//
+// It must be registered after the authorize code grant handler, which loads the authorize request the code_challenge
+// was sent with. Clients not sending a code_challenge are not affected unless PKCE is enforced.
+type Handler struct {
+	// Force requires every client to send a code_challenge with authorize requests issuing a code.
+	Force bool
+
+	// ForceForPublicClients requires public clients to send a code_challenge with authorize requests issuing a
+	// code. Public clients can not authenticate, PKCE prevents stolen codes from being exchanged.
+	ForceForPublicClients bool
+
+	// EnablePlainChallengeMethod allows the plain code_challenge_method. Only S256 is accepted if false.
+	EnablePlainChallengeMethod bool
+}
+
+// HandleAuthorizeEndpointRequest implements fosite.AuthorizeEndpointHandler. The code_challenge is stored with the
+// authorize request by the handler issuing the code.
+func (c *Handler) HandleAuthorizeEndpointRequest(ctx context.Context, _ *http.Request, ar fosite.AuthorizeRequester, _ fosite.AuthorizeResponder) error {
+	return c.ValidateAuthorizeEndpointRequest(ctx, ar)
+}
+
+// SupportedResponseTypes implements fosite.ResponseTypeHandler. The handler only validates requests for codes, it
+// supports no response type on its own.
+func (c *Handler) SupportedResponseTypes() []fosite.Arguments {
+	return nil
+}
+
+// ValidateAuthorizeEndpointRequest implements fosite.AuthorizeEndpointValidator.
+func (c *Handler) ValidateAuthorizeEndpointRequest(_ context.Context, ar fosite.AuthorizeRequester) error {
+	if !ar.GetResponseTypes().Has("code") {
+		return nil
+	}
+
+	challenge := ar.GetRequestForm().Get("code_challenge")
+	method := ar.GetRequestForm().Get("code_challenge_method")
+	if challenge == "" {
+		if c.Force || (c.ForceForPublicClients && ar.GetClient().IsPublic()) || method != "" {
+			return errors.New(fosite.ErrInvalidRequest)
+		}
+		return nil
+	}
+
+	// code_challenge_method OPTIONAL, defaults to "plain" if not present in the request.
+	switch method {
+	case ChallengeMethodS256:
+	case ChallengeMethodPlain, "":
+		if !c.EnablePlainChallengeMethod {
+			return errors.New(fosite.ErrInvalidRequest)
+		}
+	default:
+		return errors.New(fosite.ErrInvalidRequest)
+	}
+
+	if !verifierPattern.MatchString(challenge) {
+		return errors.New(fosite.ErrInvalidRequest)
+	}
+	return nil
+}
+
+// HandleTokenEndpointRequest implements https://tools.ietf.org/html/rfc7636#section-4.6
+func (c *Handler) HandleTokenEndpointRequest(_ context.Context, _ *http.Request, request fosite.AccessRequester) error {
+	if !request.GetGrantTypes().Exact("authorization_code") {
+		return errors.New(fosite.ErrUnknownRequest)
+	}
+
+	original := request.GetOriginalRequest()
+	if original == nil {
+		return errors.New(fosite.ErrMisconfiguration)
+	}
+
+	challenge := original.GetRequestForm().Get("code_challenge")
+	verifier := request.GetRequestForm().Get("code_verifier")
+	if challenge == "" {
+		// A code_verifier without a code_challenge indicates that the code_challenge was stripped from the
+		// authorize request.
+		if verifier != "" {
+			return errors.New(fosite.ErrInvalidGrant)
+		}
+		return nil
+	}
+
+	// If the values are not equal, an error response indicating "invalid_grant" as described in Section 5.2 of
+	// [RFC6749] MUST be returned.
+	if !verifierPattern.MatchString(verifier) {
+		return errors.New(fosite.ErrInvalidGrant)
+	}
+
+	expected := verifier
+	if original.GetRequestForm().Get("code_challenge_method") == ChallengeMethodS256 {
+		hash := sha256.Sum256([]byte(verifier))
+		expected = base64.RawURLEncoding.EncodeToString(hash[:])
+	}
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) != 1 {
+		return errors.New(fosite.ErrInvalidGrant)
+	}
+	return nil
+}
+
+// PopulateTokenEndpointResponse implements fosite.TokenEndpointHandler. The tokens are issued by the authorize
+// code grant handler.
+func (c *Handler) PopulateTokenEndpointResponse(_ context.Context, _ *http.Request, _ fosite.AccessRequester, _ fosite.AccessResponder) error {
+	return errors.New(fosite.ErrUnknownRequest)
+}
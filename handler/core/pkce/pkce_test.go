@@ -0,0 +1,194 @@
+package pkce
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	verifier = strings.Repeat("verifier-", 5)
+	s256     = func() string {
+		hash := sha256.Sum256([]byte(verifier))
+		return base64.RawURLEncoding.EncodeToString(hash[:])
+	}()
+)
+
+func TestValidateAuthorizeEndpointRequest(t *testing.T) {
+	for k, c := range []struct {
+		description   string
+		handler       *Handler
+		responseTypes fosite.Arguments
+		form          url.Values
+		public        bool
+		expectErr     error
+	}{
+		{
+			description:   "should pass because no code is issued",
+			handler:       &Handler{Force: true},
+			responseTypes: fosite.Arguments{"token"},
+			form:          url.Values{},
+		},
+		{
+			description:   "should pass because PKCE is optional",
+			handler:       &Handler{},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{},
+		},
+		{
+			description:   "should fail because PKCE is enforced",
+			handler:       &Handler{Force: true},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{},
+			expectErr:     fosite.ErrInvalidRequest,
+		},
+		{
+			description:   "should fail because PKCE is enforced for public clients",
+			handler:       &Handler{ForceForPublicClients: true},
+			responseTypes: fosite.Arguments{"code", "id_token"},
+			form:          url.Values{},
+			public:        true,
+			expectErr:     fosite.ErrInvalidRequest,
+		},
+		{
+			description:   "should pass because PKCE is only enforced for public clients",
+			handler:       &Handler{ForceForPublicClients: true},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{},
+		},
+		{
+			description:   "should pass with the S256 method",
+			handler:       &Handler{Force: true},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{"code_challenge": {s256}, "code_challenge_method": {"S256"}},
+		},
+		{
+			description:   "should fail because the plain method is disabled",
+			handler:       &Handler{},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{"code_challenge": {verifier}},
+			expectErr:     fosite.ErrInvalidRequest,
+		},
+		{
+			description:   "should pass because the plain method is enabled",
+			handler:       &Handler{EnablePlainChallengeMethod: true},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{"code_challenge": {verifier}, "code_challenge_method": {"plain"}},
+		},
+		{
+			description:   "should fail because the method is unknown",
+			handler:       &Handler{},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{"code_challenge": {s256}, "code_challenge_method": {"S512"}},
+			expectErr:     fosite.ErrInvalidRequest,
+		},
+		{
+			description:   "should fail because the method was sent without a challenge",
+			handler:       &Handler{},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{"code_challenge_method": {"S256"}},
+			expectErr:     fosite.ErrInvalidRequest,
+		},
+		{
+			description:   "should fail because the challenge is too short",
+			handler:       &Handler{},
+			responseTypes: fosite.Arguments{"code"},
+			form:          url.Values{"code_challenge": {"foo"}, "code_challenge_method": {"S256"}},
+			expectErr:     fosite.ErrInvalidRequest,
+		},
+	} {
+		ar := fosite.NewAuthorizeRequest()
+		ar.ResponseTypes = c.responseTypes
+		ar.Form = c.form
+		ar.Client = &fosite.DefaultClient{Public: c.public}
+
+		err := c.handler.ValidateAuthorizeEndpointRequest(nil, ar)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		err = c.handler.HandleAuthorizeEndpointRequest(nil, nil, ar, fosite.NewAuthorizeResponse())
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestHandleTokenEndpointRequest(t *testing.T) {
+	h := &Handler{}
+	for k, c := range []struct {
+		description string
+		grantType   string
+		authorize   url.Values
+		verifier    string
+		expectErr   error
+	}{
+		{
+			description: "should fail because not responsible",
+			grantType:   "refresh_token",
+			authorize:   url.Values{"code_challenge": {s256}, "code_challenge_method": {"S256"}},
+			expectErr:   fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should pass because the code was issued without a challenge",
+			grantType:   "authorization_code",
+			authorize:   url.Values{},
+		},
+		{
+			description: "should fail because the code was issued without a challenge",
+			grantType:   "authorization_code",
+			authorize:   url.Values{},
+			verifier:    verifier,
+			expectErr:   fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass with the S256 method",
+			grantType:   "authorization_code",
+			authorize:   url.Values{"code_challenge": {s256}, "code_challenge_method": {"S256"}},
+			verifier:    verifier,
+		},
+		{
+			description: "should fail because the verifier does not match",
+			grantType:   "authorization_code",
+			authorize:   url.Values{"code_challenge": {s256}, "code_challenge_method": {"S256"}},
+			verifier:    strings.Repeat("another-verifier-", 3),
+			expectErr:   fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the verifier is missing",
+			grantType:   "authorization_code",
+			authorize:   url.Values{"code_challenge": {s256}, "code_challenge_method": {"S256"}},
+			expectErr:   fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the S256 challenge was sent as verifier",
+			grantType:   "authorization_code",
+			authorize:   url.Values{"code_challenge": {s256}, "code_challenge_method": {"S256"}},
+			verifier:    s256,
+			expectErr:   fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass with the plain method",
+			grantType:   "authorization_code",
+			authorize:   url.Values{"code_challenge": {verifier}},
+			verifier:    verifier,
+		},
+	} {
+		original := fosite.NewAuthorizeRequest()
+		original.Form = c.authorize
+		areq := fosite.NewAccessRequest(nil)
+		areq.GrantTypes = fosite.Arguments{c.grantType}
+		areq.Form.Set("code_verifier", c.verifier)
+		areq.SetOriginalRequest(original)
+
+		err := h.HandleTokenEndpointRequest(nil, nil, areq)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		t.Logf("Passed test case %d", k)
+	}
+
+	areq := fosite.NewAccessRequest(nil)
+	areq.GrantTypes = fosite.Arguments{"authorization_code"}
+	assert.True(t, errors.Is(fosite.ErrMisconfiguration, h.HandleTokenEndpointRequest(nil, nil, areq)), "the authorize code grant handler must run first")
+}
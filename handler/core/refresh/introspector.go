@@ -0,0 +1,68 @@
+package refresh
+
+import (
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/core"
+	"golang.org/x/net/context"
+)
+
+// RefreshTokenIntrospector implements fosite.TokenIntrospector for refresh tokens, which allows clients to find
+// out whether their refresh tokens are still active, see https://tools.ietf.org/html/rfc7662#section-2.1
+//
+// It requires a RefreshTokenStrategy and a core.RefreshTokenStorage. If one of them is nil, IntrospectToken returns
+// fosite.ErrUnsupportedEndpoint, leaving the token to the other introspectors, and ValidateConfiguration reports
+// what is missing.
+type RefreshTokenIntrospector struct {
+	RefreshTokenStrategy core.RefreshTokenStrategy
+	RefreshTokenStorage  core.RefreshTokenStorage
+}
+
+// IntrospectToken implements fosite.TokenIntrospector.
+func (r *RefreshTokenIntrospector) IntrospectToken(ctx context.Context, token string, accessRequest fosite.AccessRequester) error {
+	if r.checkRequirements() != nil {
+		return errors.New(fosite.ErrUnsupportedEndpoint)
+	}
+
+	sig, err := r.RefreshTokenStrategy.ValidateRefreshToken(ctx, accessRequest, token)
+	if err != nil {
+		return errors.New(fosite.ErrRequestUnauthorized)
+	}
+
+	or, err := r.RefreshTokenStorage.GetRefreshTokenSession(ctx, sig, accessRequest.GetSession())
+	if err != nil {
+		return errors.New(fosite.ErrRequestUnauthorized)
+	}
+
+	if core.IsRefreshTokenExpired(or, time.Now()) {
+		return errors.New(fosite.ErrRequestUnauthorized)
+	}
+
+	accessRequest.Merge(or)
+	return nil
+}
+
+// IntrospectedTokenType implements fosite.TokenTypeIntrospector.
+func (r *RefreshTokenIntrospector) IntrospectedTokenType() string {
+	return fosite.RefreshToken
+}
+
+// ValidateConfiguration implements fosite.ConfigurationValidator. The RefreshTokenStrategy is validated as well.
+func (r *RefreshTokenIntrospector) ValidateConfiguration() error {
+	if err := r.checkRequirements(); err != nil {
+		return err
+	}
+	return fosite.ValidateConfigurations(r.RefreshTokenStrategy)
+}
+
+// checkRequirements makes sure that the strategy and storage are set. It is cheap enough to run on every request.
+func (r *RefreshTokenIntrospector) checkRequirements() error {
+	if r.RefreshTokenStrategy == nil {
+		return errors.New("RefreshTokenIntrospector requires a RefreshTokenStrategy")
+	} else if r.RefreshTokenStorage == nil {
+		return errors.New("RefreshTokenIntrospector requires a RefreshTokenStorage, the storage does not implement it")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package refresh
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTokenIntrospector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockRefreshTokenGrantStorage(ctrl)
+	chgen := internal.NewMockRefreshTokenStrategy(ctrl)
+	defer ctrl.Finish()
+
+	r := &RefreshTokenIntrospector{RefreshTokenStrategy: chgen, RefreshTokenStorage: store}
+	assert.Equal(t, "refresh_token", r.IntrospectedTokenType())
+
+	for k, c := range []struct {
+		description string
+		setup       func(areq *fosite.AccessRequest)
+		expectErr   error
+	}{
+		{
+			description: "should fail because token does not validate",
+			setup: func(areq *fosite.AccessRequest) {
+				chgen.EXPECT().ValidateRefreshToken(nil, areq, "some.refreshtokensig").Return("", errors.New(""))
+			},
+			expectErr: fosite.ErrRequestUnauthorized,
+		},
+		{
+			description: "should fail because token can't be found",
+			setup: func(areq *fosite.AccessRequest) {
+				chgen.EXPECT().ValidateRefreshToken(nil, areq, "some.refreshtokensig").Return("refreshtokensig", nil)
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(nil, fosite.ErrNotFound)
+			},
+			expectErr: fosite.ErrRequestUnauthorized,
+		},
+		{
+			description: "should fail because the token expired",
+			setup: func(areq *fosite.AccessRequest) {
+				expired := &fosite.Request{Client: &fosite.DefaultClient{ID: "foo"}}
+				expired.SetExpiresAt(fosite.RefreshToken, time.Now().Add(-time.Minute))
+				chgen.EXPECT().ValidateRefreshToken(nil, areq, "some.refreshtokensig").Return("refreshtokensig", nil)
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(expired, nil)
+			},
+			expectErr: fosite.ErrRequestUnauthorized,
+		},
+		{
+			description: "should pass and merge the stored request",
+			setup: func(areq *fosite.AccessRequest) {
+				chgen.EXPECT().ValidateRefreshToken(nil, areq, "some.refreshtokensig").Return("refreshtokensig", nil)
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.ScopeArguments{"offline"},
+				}, nil)
+			},
+		},
+	} {
+		areq := fosite.NewAccessRequest(nil)
+		c.setup(areq)
+		err := r.IntrospectToken(nil, "some.refreshtokensig", areq)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.Equal(t, "foo", areq.GetClient().GetID(), "(%d) %s", k, c.description)
+			assert.Equal(t, fosite.Arguments{"offline"}, areq.GetGrantedScopes(), "(%d) %s", k, c.description)
+		}
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestRefreshTokenIntrospectorWithoutStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r := &RefreshTokenIntrospector{RefreshTokenStrategy: internal.NewMockRefreshTokenStrategy(ctrl)}
+	assert.NotNil(t, r.ValidateConfiguration())
+
+	err := r.IntrospectToken(nil, "some.refreshtokensig", fosite.NewAccessRequest(nil))
+	assert.True(t, errors.Is(fosite.ErrUnsupportedEndpoint, err), "%s", err)
+}
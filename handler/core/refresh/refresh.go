@@ -20,6 +20,34 @@ type RefreshTokenGrantHandler struct {
 
 	// AccessTokenLifespan defines the lifetime of an access token.
 	AccessTokenLifespan time.Duration
+
+	// RefreshTokenLifespan defines the lifetime of a refresh token. Because a new refresh token is issued whenever
+	// one is used, it limits how long a grant may stay unused. Refresh tokens do not expire if zero.
+	RefreshTokenLifespan time.Duration
+
+	// RefreshTokenMaxLifespan limits how long refresh tokens can be refreshed, counted from the first refresh token
+	// issued for the authorization. There is no limit if zero.
+	RefreshTokenMaxLifespan time.Duration
+
+	// ScopeStrategy is used to check if a requested scope was granted to the refresh token. Defaults to
+	// fosite.HierarchicScopeStrategy.
+	ScopeStrategy fosite.ScopeStrategy
+
+	// IncludeAudience adds the audience of the client to the token response.
+	IncludeAudience bool
+
+	// ConfirmationExtractor returns the key the client proved possession of. Refresh tokens bound to a key, see
+	// fosite.ConfirmationSession, are only accepted with a proof of that key, and are rejected if nil.
+	ConfirmationExtractor fosite.ConfirmationExtractor
+
+	// IncludeRefreshTokenExpiresIn adds refresh_token_expires_in, the remaining lifetime of the refresh token in
+	// seconds, to the token response. The parameter is not standardized but expected by some client libraries.
+	IncludeRefreshTokenExpiresIn bool
+}
+
+// HandledGrantTypes implements fosite.GrantTypeHandler.
+func (c *RefreshTokenGrantHandler) HandledGrantTypes() fosite.Arguments {
+	return fosite.Arguments{"refresh_token"}
 }
 
 // HandleTokenEndpointRequest implements https://tools.ietf.org/html/rfc6749#section-6
@@ -40,22 +68,57 @@ func (c *RefreshTokenGrantHandler) HandleTokenEndpointRequest(ctx context.Contex
 		return errors.New(fosite.ErrInvalidRequest)
 	}
 
-	accessRequest, err := c.RefreshTokenGrantStorage.GetRefreshTokenSession(ctx, signature, nil)
+	accessRequest, err := c.RefreshTokenGrantStorage.GetRefreshTokenSession(ctx, signature, request.GetSession())
 	if errors.Is(err, fosite.ErrNotFound) {
 		return errors.New(fosite.ErrInvalidRequest)
 	} else if err != nil {
 		return errors.New(fosite.ErrServerError)
 	}
 
-	request.SetScopes(accessRequest.GetScopes())
-	for _, scope := range accessRequest.GetGrantedScopes() {
-		request.GrantScope(scope)
-	}
-
 	// The authorization server MUST ... and ensure that the refresh token was issued to the authenticated client
 	if accessRequest.GetClient().GetID() != request.GetClient().GetID() {
 		return errors.New(fosite.ErrInvalidRequest)
 	}
+
+	// https://tools.ietf.org/html/rfc6749#section-5.2
+	// The provided ... refresh token is invalid, expired, revoked ...
+	if core.IsRefreshTokenExpired(accessRequest, time.Now()) {
+		return errors.New(fosite.ErrInvalidGrant)
+	}
+
+	// A stolen sender-constrained refresh token must not be usable without the key it is bound to.
+	if err := c.validateConfirmation(ctx, req, accessRequest); err != nil {
+		return err
+	}
+
+	// The refreshed tokens are issued for the original session, so that claims set at authorize time survive.
+	request.SetOriginalRequest(accessRequest)
+	request.SetSession(accessRequest.GetSession())
+	fosite.CopyClaimsRequest(accessRequest, request)
+	fosite.CopyGrantedAudience(accessRequest, request)
+
+	// scope OPTIONAL.
+	// The requested scope MUST NOT include any scope not originally granted by the resource owner, and if omitted
+	// is treated as equal to the scope originally granted by the resource owner.
+	requested := request.GetScopes()
+	if len(requested) == 0 {
+		request.SetScopes(accessRequest.GetScopes())
+		for _, scope := range accessRequest.GetGrantedScopes() {
+			request.GrantScope(scope)
+		}
+		return nil
+	}
+
+	strategy := c.getScopeStrategy()
+	for _, scope := range requested {
+		if !strategy(accessRequest.GetGrantedScopes(), scope) {
+			return errors.New(fosite.ErrInvalidScope)
+		}
+	}
+
+	for _, scope := range requested {
+		request.GrantScope(scope)
+	}
 	return nil
 }
 
@@ -80,14 +143,51 @@ func (c *RefreshTokenGrantHandler) PopulateTokenEndpointResponse(ctx context.Con
 		return errors.New(fosite.ErrServerError)
 	}
 
+	issuedAt := time.Now()
+	core.SetRefreshTokenExpiry(requester, requester.GetOriginalRequest(), issuedAt,
+		fosite.GetEffectiveLifespanWithContext(ctx, requester.GetClient(), "refresh_token", fosite.RefreshToken, c.RefreshTokenLifespan),
+		fosite.GetEffectiveLifespanWithContext(ctx, requester.GetClient(), "refresh_token", fosite.RefreshTokenFamily, c.RefreshTokenMaxLifespan))
+
+	lifespan := fosite.GetEffectiveLifespanWithContext(ctx, requester.GetClient(), "refresh_token", fosite.AccessToken, c.AccessTokenLifespan)
+	requester.SetExpiresAt(fosite.AccessToken, issuedAt.Add(lifespan))
 	if err := c.RefreshTokenGrantStorage.PersistRefreshTokenGrantSession(ctx, signature, accessSignature, refreshSignature, requester); err != nil {
 		return errors.New(fosite.ErrServerError)
 	}
 
 	responder.SetAccessToken(accessToken)
-	responder.SetTokenType("bearer")
-	responder.SetExpiresIn(c.AccessTokenLifespan / time.Second)
+	responder.SetTokenType(fosite.GetTokenType(requester))
+	responder.SetExpiresIn(lifespan)
+	responder.SetIssuedAt(issuedAt)
+	responder.SetExpiresAt(issuedAt.Add(lifespan))
+	// The granted scopes are always included so that clients know the outcome of downscoping.
 	responder.SetScopes(requester.GetGrantedScopes())
 	responder.SetExtra("refresh_token", refreshToken)
+	if c.IncludeRefreshTokenExpiresIn {
+		core.SetRefreshTokenExpiresIn(requester, responder, issuedAt)
+	}
+
+	if audience := requester.GetClient().GetAudience(); c.IncludeAudience && len(audience) > 0 {
+		responder.SetExtra("audience", audience)
+	}
 	return nil
 }
+
+func (c *RefreshTokenGrantHandler) validateConfirmation(ctx context.Context, req *http.Request, accessRequest fosite.Requester) error {
+	var presented map[string]string
+	if c.ConfirmationExtractor != nil {
+		var err error
+		if presented, err = c.ConfirmationExtractor(ctx, req); errors.Is(err, fosite.ErrInvalidDPoPProof) || errors.Is(err, fosite.ErrUseDPoPNonce) {
+			return errors.New(err)
+		} else if err != nil {
+			return errors.New(fosite.ErrInvalidGrant)
+		}
+	}
+	return fosite.ValidateConfirmation(accessRequest, presented)
+}
+
+func (c *RefreshTokenGrantHandler) getScopeStrategy() fosite.ScopeStrategy {
+	if c.ScopeStrategy == nil {
+		return fosite.HierarchicScopeStrategy
+	}
+	return c.ScopeStrategy
+}
@@ -11,8 +11,13 @@ import (
 	"github.com/ory-am/fosite"
 	"github.com/ory-am/fosite/internal"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
 )
 
+type boundSession map[string]string
+
+func (s boundSession) GetConfirmation() map[string]string { return s }
+
 func TestHandleTokenEndpointRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	store := internal.NewMockRefreshTokenGrantStorage(ctrl)
@@ -28,9 +33,11 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 		AccessTokenLifespan:      time.Hour,
 	}
 	for k, c := range []struct {
-		description string
-		setup       func()
-		expectErr   error
+		description   string
+		setup         func()
+		expectErr     error
+		expectScopes  fosite.Arguments
+		expectSession interface{}
 	}{
 		{
 			description: "should fail because not responsible",
@@ -81,10 +88,154 @@ func TestHandleTokenEndpointRequest(t *testing.T) {
 				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{Client: &fosite.DefaultClient{ID: "foo"}}, nil)
 			},
 		},
+		{
+			description: "should fail because a scope was not originally granted",
+			setup: func() {
+				areq.SetScopes(fosite.Arguments{"foo", "baz"})
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.ScopeArguments{"foo", "bar"},
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidScope,
+		},
+		{
+			description: "should pass and downscope",
+			setup: func() {
+				areq.SetScopes(fosite.Arguments{"foo.read"})
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:        &fosite.DefaultClient{ID: "foo"},
+					GrantedScopes: fosite.ScopeArguments{"foo", "bar"},
+				}, nil)
+			},
+			expectScopes: fosite.Arguments{"foo.read"},
+		},
+		{
+			description: "should fail because the refresh token expired",
+			setup: func() {
+				expired := &fosite.Request{Client: &fosite.DefaultClient{ID: "foo"}}
+				expired.SetExpiresAt(fosite.RefreshToken, time.Now().Add(-time.Minute))
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(expired, nil)
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the absolute lifespan of the refresh token family passed",
+			setup: func() {
+				expired := &fosite.Request{Client: &fosite.DefaultClient{ID: "foo"}}
+				expired.SetExpiresAt(fosite.RefreshToken, time.Now().Add(time.Hour))
+				expired.SetExpiresAt(fosite.RefreshTokenFamily, time.Now().Add(-time.Minute))
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(expired, nil)
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass and expose the original request and session",
+			setup: func() {
+				areq.SetScopes(fosite.Arguments{})
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", nil).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Form:    url.Values{"custom": {"claim"}},
+					Session: "session",
+				}, nil)
+			},
+			expectSession: "session",
+		},
+		{
+			description: "should fail because the refresh token is bound to a key and no proof can be extracted",
+			setup: func() {
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", gomock.Any()).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Session: boundSession{fosite.ConfirmationJWKThumbprint: "key"},
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the DPoP proof is invalid",
+			setup: func() {
+				h.ConfirmationExtractor = func(_ context.Context, _ *http.Request) (map[string]string, error) {
+					return nil, errors.New(fosite.ErrInvalidDPoPProof)
+				}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", gomock.Any()).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Session: boundSession{fosite.ConfirmationJWKThumbprint: "key"},
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because the DPoP proof lacks the nonce",
+			setup: func() {
+				h.ConfirmationExtractor = func(_ context.Context, _ *http.Request) (map[string]string, error) {
+					return nil, errors.New(fosite.ErrUseDPoPNonce)
+				}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", gomock.Any()).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Session: boundSession{fosite.ConfirmationJWKThumbprint: "key"},
+				}, nil)
+			},
+			expectErr: fosite.ErrUseDPoPNonce,
+		},
+		{
+			description: "should fail with invalid_grant because a proof other than DPoP is invalid",
+			setup: func() {
+				h.ConfirmationExtractor = func(_ context.Context, _ *http.Request) (map[string]string, error) {
+					return nil, errors.New("certificate expired")
+				}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", gomock.Any()).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Session: boundSession{fosite.ConfirmationX509Thumbprint: "certificate"},
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the DPoP proof was signed with another key",
+			setup: func() {
+				h.ConfirmationExtractor = func(_ context.Context, _ *http.Request) (map[string]string, error) {
+					return map[string]string{fosite.ConfirmationJWKThumbprint: "other-key"}, nil
+				}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", gomock.Any()).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Session: boundSession{fosite.ConfirmationJWKThumbprint: "key"},
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidDPoPProof,
+		},
+		{
+			description: "should fail because the refresh token is bound to a client certificate which was not presented",
+			setup: func() {
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", gomock.Any()).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Session: boundSession{fosite.ConfirmationX509Thumbprint: "certificate"},
+				}, nil)
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass because the proof matches the key the refresh token is bound to",
+			setup: func() {
+				h.ConfirmationExtractor = func(_ context.Context, _ *http.Request) (map[string]string, error) {
+					return map[string]string{fosite.ConfirmationJWKThumbprint: "key"}, nil
+				}
+				store.EXPECT().GetRefreshTokenSession(nil, "refreshtokensig", gomock.Any()).Return(&fosite.Request{
+					Client:  &fosite.DefaultClient{ID: "foo"},
+					Session: boundSession{fosite.ConfirmationJWKThumbprint: "key"},
+				}, nil)
+			},
+		},
 	} {
 		c.setup()
 		err := h.HandleTokenEndpointRequest(nil, httpreq, areq)
 		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectScopes != nil {
+			assert.Equal(t, c.expectScopes, areq.GetGrantedScopes(), "(%d) %s", k, c.description)
+		}
+		if c.expectSession != nil {
+			assert.Equal(t, c.expectSession, areq.GetSession(), "(%d) %s", k, c.description)
+			assert.Equal(t, "claim", areq.GetOriginalRequest().GetRequestForm().Get("custom"), "(%d) %s", k, c.description)
+		}
 		t.Logf("Passed test case %d", k)
 	}
 }
@@ -105,6 +256,7 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 		RefreshTokenStrategy:     rcts,
 		AccessTokenStrategy:      acts,
 		AccessTokenLifespan:      time.Hour,
+		RefreshTokenLifespan:     time.Hour * 24,
 	}
 	for k, c := range []struct {
 		description string
@@ -159,8 +311,43 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 				aresp.EXPECT().SetAccessToken("access.atsig")
 				aresp.EXPECT().SetTokenType("bearer")
 				aresp.EXPECT().SetExpiresIn(gomock.Any())
+				aresp.EXPECT().SetIssuedAt(gomock.Any())
+				aresp.EXPECT().SetExpiresAt(gomock.Any())
+				aresp.EXPECT().SetScopes(gomock.Any())
+				aresp.EXPECT().SetExtra("refresh_token", "refresh.resig")
+			},
+		},
+		{
+			description: "should pass and include the audience",
+			setup: func() {
+				h.IncludeAudience = true
+				areq.Client = &fosite.DefaultClient{Audience: []string{"https://api.example.com"}}
+				areq.GrantedScopes = fosite.ScopeArguments{"foo.read"}
+
+				aresp.EXPECT().SetAccessToken("access.atsig")
+				aresp.EXPECT().SetTokenType("bearer")
+				aresp.EXPECT().SetExpiresIn(gomock.Any())
+				aresp.EXPECT().SetIssuedAt(gomock.Any())
+				aresp.EXPECT().SetExpiresAt(gomock.Any())
+				aresp.EXPECT().SetScopes(fosite.Arguments{"foo.read"})
+				aresp.EXPECT().SetExtra("refresh_token", "refresh.resig")
+				aresp.EXPECT().SetExtra("audience", fosite.Arguments{"https://api.example.com"})
+			},
+		},
+		{
+			description: "should pass and include refresh_token_expires_in",
+			setup: func() {
+				h.IncludeAudience = false
+				h.IncludeRefreshTokenExpiresIn = true
+
+				aresp.EXPECT().SetAccessToken("access.atsig")
+				aresp.EXPECT().SetTokenType("bearer")
+				aresp.EXPECT().SetExpiresIn(gomock.Any())
+				aresp.EXPECT().SetIssuedAt(gomock.Any())
+				aresp.EXPECT().SetExpiresAt(gomock.Any())
 				aresp.EXPECT().SetScopes(gomock.Any())
 				aresp.EXPECT().SetExtra("refresh_token", "refresh.resig")
+				aresp.EXPECT().SetExtra("refresh_token_expires_in", int64(86400))
 			},
 		},
 	} {
@@ -169,4 +356,6 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
 		t.Logf("Passed test case %d", k)
 	}
+	// Every refresh token is valid for the full lifespan from the time it was issued at.
+	assert.WithinDuration(t, time.Now().Add(time.Hour*24), areq.GetExpiresAt(fosite.RefreshToken), time.Minute)
 }
@@ -0,0 +1,48 @@
+package revocation
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"golang.org/x/net/context"
+)
+
+// BulkRevocationService revokes all access and refresh tokens of a subject or a client in one call, for example
+// when an account is deleted. Use a token revocation endpoint as defined in https://tools.ietf.org/html/rfc7009
+// to revoke single tokens.
+type BulkRevocationService struct {
+	BulkRevocationStorage BulkRevocationStorage
+}
+
+// RevokeTokensBySubject revokes all tokens issued on behalf of subject and returns how many tokens were revoked.
+func (r *BulkRevocationService) RevokeTokensBySubject(ctx context.Context, subject string) (int, error) {
+	if subject == "" {
+		return 0, errors.New(fosite.ErrInvalidRequest)
+	}
+
+	return revoke(ctx, subject, r.BulkRevocationStorage.RevokeRefreshTokensBySubject, r.BulkRevocationStorage.RevokeAccessTokensBySubject)
+}
+
+// RevokeTokensByClient revokes all tokens issued to the client and returns how many tokens were revoked.
+func (r *BulkRevocationService) RevokeTokensByClient(ctx context.Context, clientID string) (int, error) {
+	if clientID == "" {
+		return 0, errors.New(fosite.ErrInvalidRequest)
+	}
+
+	return revoke(ctx, clientID, r.BulkRevocationStorage.RevokeRefreshTokensByClient, r.BulkRevocationStorage.RevokeAccessTokensByClient)
+}
+
+// revoke runs the refresh token revocation before the access token revocation, so no new access tokens can be
+// obtained while the access tokens are being revoked.
+func revoke(ctx context.Context, id string, refresh, access func(context.Context, string) (int, error)) (int, error) {
+	refreshed, err := refresh(ctx, id)
+	if err != nil {
+		return refreshed, errors.New(fosite.ErrServerError)
+	}
+
+	accessed, err := access(ctx, id)
+	if err != nil {
+		return refreshed + accessed, errors.New(fosite.ErrServerError)
+	}
+
+	return refreshed + accessed, nil
+}
@@ -0,0 +1,109 @@
+package revocation
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokeTokensBySubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockBulkRevocationStorage(ctrl)
+	defer ctrl.Finish()
+
+	r := &BulkRevocationService{BulkRevocationStorage: store}
+	for k, c := range []struct {
+		description string
+		subject     string
+		setup       func()
+		expectErr   error
+		expect      int
+	}{
+		{
+			description: "should fail because subject is empty",
+			setup:       func() {},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because refresh tokens could not be revoked",
+			subject:     "peter",
+			setup: func() {
+				store.EXPECT().RevokeRefreshTokensBySubject(nil, "peter").Return(0, errors.New(""))
+			},
+			expectErr: fosite.ErrServerError,
+		},
+		{
+			description: "should fail because access tokens could not be revoked",
+			subject:     "peter",
+			setup: func() {
+				store.EXPECT().RevokeRefreshTokensBySubject(nil, "peter").Return(2, nil)
+				store.EXPECT().RevokeAccessTokensBySubject(nil, "peter").Return(0, errors.New(""))
+			},
+			expectErr: fosite.ErrServerError,
+			expect:    2,
+		},
+		{
+			description: "should pass",
+			subject:     "peter",
+			setup: func() {
+				store.EXPECT().RevokeRefreshTokensBySubject(nil, "peter").Return(2, nil)
+				store.EXPECT().RevokeAccessTokensBySubject(nil, "peter").Return(3, nil)
+			},
+			expect: 5,
+		},
+	} {
+		c.setup()
+		count, err := r.RevokeTokensBySubject(nil, c.subject)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		assert.Equal(t, c.expect, count, "(%d) %s", k, c.description)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestRevokeTokensByClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockBulkRevocationStorage(ctrl)
+	defer ctrl.Finish()
+
+	r := &BulkRevocationService{BulkRevocationStorage: store}
+	for k, c := range []struct {
+		description string
+		client      string
+		setup       func()
+		expectErr   error
+		expect      int
+	}{
+		{
+			description: "should fail because client id is empty",
+			setup:       func() {},
+			expectErr:   fosite.ErrInvalidRequest,
+		},
+		{
+			description: "should fail because refresh tokens could not be revoked",
+			client:      "foo",
+			setup: func() {
+				store.EXPECT().RevokeRefreshTokensByClient(nil, "foo").Return(0, errors.New(""))
+			},
+			expectErr: fosite.ErrServerError,
+		},
+		{
+			description: "should pass",
+			client:      "foo",
+			setup: func() {
+				store.EXPECT().RevokeRefreshTokensByClient(nil, "foo").Return(1, nil)
+				store.EXPECT().RevokeAccessTokensByClient(nil, "foo").Return(0, nil)
+			},
+			expect: 1,
+		},
+	} {
+		c.setup()
+		count, err := r.RevokeTokensByClient(nil, c.client)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		assert.Equal(t, c.expect, count, "(%d) %s", k, c.description)
+		t.Logf("Passed test case %d", k)
+	}
+}
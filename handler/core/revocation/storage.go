@@ -0,0 +1,24 @@
+package revocation
+
+import (
+	"github.com/ory-am/fosite/handler/core"
+	"golang.org/x/net/context"
+)
+
+// BulkRevocationStorage removes all tokens belonging to a subject or a client. Each method returns the number
+// of tokens it revoked.
+type BulkRevocationStorage interface {
+	RevokeAccessTokensBySubject(ctx context.Context, subject string) (count int, err error)
+
+	RevokeRefreshTokensBySubject(ctx context.Context, subject string) (count int, err error)
+
+	RevokeAccessTokensByClient(ctx context.Context, clientID string) (count int, err error)
+
+	RevokeRefreshTokensByClient(ctx context.Context, clientID string) (count int, err error)
+}
+
+// TokenRevocationStorage is used by TokenRevocationHandler to look up and remove single tokens.
+type TokenRevocationStorage interface {
+	core.AccessTokenStorage
+	core.RefreshTokenStorage
+}
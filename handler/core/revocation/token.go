@@ -0,0 +1,109 @@
+package revocation
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/core"
+	"golang.org/x/net/context"
+)
+
+// TokenRevocationHandler implements fosite.RevocationHandler for the access and refresh tokens issued by the core
+// handlers, see https://tools.ietf.org/html/rfc7009
+//
+// It requires both token strategies and a TokenRevocationStorage, which is optional for servers without a
+// revocation endpoint. If one of them is nil, RevokeToken returns fosite.ErrUnsupportedEndpoint instead of
+// panicking and ValidateConfiguration reports what is missing.
+type TokenRevocationHandler struct {
+	AccessTokenStrategy    core.AccessTokenStrategy
+	RefreshTokenStrategy   core.RefreshTokenStrategy
+	TokenRevocationStorage TokenRevocationStorage
+}
+
+// RevokeToken looks up token as refresh token first unless tokenTypeHint is "access_token", as suggested by
+// https://tools.ietf.org/html/rfc7009#section-2.1
+//
+//	If the server is unable to locate the token using the given hint, it MUST extend its search across all of
+//	its supported token types.
+func (r *TokenRevocationHandler) RevokeToken(ctx context.Context, token string, tokenTypeHint string, client fosite.Client) error {
+	if r.checkRequirements() != nil {
+		return errors.New(fosite.ErrUnsupportedEndpoint)
+	}
+
+	lookups := []func(context.Context, string, fosite.Client) error{r.revokeRefreshToken, r.revokeAccessToken}
+	if tokenTypeHint == "access_token" {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for _, lookup := range lookups {
+		if err := lookup(ctx, token, client); !errors.Is(err, fosite.ErrUnknownRequest) {
+			return err
+		}
+	}
+	return errors.New(fosite.ErrUnknownRequest)
+}
+
+func (r *TokenRevocationHandler) revokeRefreshToken(ctx context.Context, token string, client fosite.Client) error {
+	sig, err := r.RefreshTokenStrategy.ValidateRefreshToken(ctx, nil, token)
+	if err != nil {
+		return errors.New(fosite.ErrUnknownRequest)
+	}
+
+	or, err := r.TokenRevocationStorage.GetRefreshTokenSession(ctx, sig, nil)
+	if err != nil {
+		return errors.New(fosite.ErrUnknownRequest)
+	} else if err := checkClient(or, client); err != nil {
+		return err
+	}
+
+	if err := r.TokenRevocationStorage.DeleteRefreshTokenSession(ctx, sig); err != nil {
+		return errors.New(fosite.ErrServerError)
+	}
+	return nil
+}
+
+func (r *TokenRevocationHandler) revokeAccessToken(ctx context.Context, token string, client fosite.Client) error {
+	sig, err := r.AccessTokenStrategy.ValidateAccessToken(ctx, nil, token)
+	if err != nil {
+		return errors.New(fosite.ErrUnknownRequest)
+	}
+
+	or, err := r.TokenRevocationStorage.GetAccessTokenSession(ctx, sig, nil)
+	if err != nil {
+		return errors.New(fosite.ErrUnknownRequest)
+	} else if err := checkClient(or, client); err != nil {
+		return err
+	}
+
+	if err := r.TokenRevocationStorage.DeleteAccessTokenSession(ctx, sig); err != nil {
+		return errors.New(fosite.ErrServerError)
+	}
+	return nil
+}
+
+// ValidateConfiguration implements fosite.ConfigurationValidator. The strategies are validated as well.
+func (r *TokenRevocationHandler) ValidateConfiguration() error {
+	if err := r.checkRequirements(); err != nil {
+		return err
+	}
+	return fosite.ValidateConfigurations(r.AccessTokenStrategy, r.RefreshTokenStrategy)
+}
+
+// checkRequirements makes sure that the strategies and storage are set. It is cheap enough to run on every request.
+func (r *TokenRevocationHandler) checkRequirements() error {
+	if r.AccessTokenStrategy == nil {
+		return errors.New("TokenRevocationHandler requires an AccessTokenStrategy")
+	} else if r.RefreshTokenStrategy == nil {
+		return errors.New("TokenRevocationHandler requires a RefreshTokenStrategy")
+	} else if r.TokenRevocationStorage == nil {
+		return errors.New("TokenRevocationHandler requires a TokenRevocationStorage, the storage does not implement it")
+	}
+	return nil
+}
+
+// checkClient makes sure that clients can only revoke their own tokens.
+func checkClient(requester fosite.Requester, client fosite.Client) error {
+	if requester.GetClient() == nil || requester.GetClient().GetID() != client.GetID() {
+		return errors.New(fosite.ErrUnauthorizedClient)
+	}
+	return nil
+}
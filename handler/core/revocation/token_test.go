@@ -0,0 +1,108 @@
+package revocation
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+type tokenRevocationStorage struct {
+	*internal.MockAccessTokenStorage
+	*internal.MockRefreshTokenGrantStorage
+}
+
+func TestRevokeToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	accessStrategy := internal.NewMockAccessTokenStrategy(ctrl)
+	refreshStrategy := internal.NewMockRefreshTokenStrategy(ctrl)
+	accessStore := internal.NewMockAccessTokenStorage(ctrl)
+	refreshStore := internal.NewMockRefreshTokenGrantStorage(ctrl)
+	defer ctrl.Finish()
+
+	h := &TokenRevocationHandler{
+		AccessTokenStrategy:    accessStrategy,
+		RefreshTokenStrategy:   refreshStrategy,
+		TokenRevocationStorage: tokenRevocationStorage{accessStore, refreshStore},
+	}
+	client := &fosite.DefaultClient{ID: "foo"}
+	own := &fosite.Request{Client: client}
+	foreign := &fosite.Request{Client: &fosite.DefaultClient{ID: "bar"}}
+
+	for k, c := range []struct {
+		description string
+		hint        string
+		setup       func()
+		expectErr   error
+	}{
+		{
+			description: "should fail because the token is unknown",
+			setup: func() {
+				refreshStrategy.EXPECT().ValidateRefreshToken(nil, nil, "token").Return("", errors.New(""))
+				accessStrategy.EXPECT().ValidateAccessToken(nil, nil, "token").Return("sig", nil)
+				accessStore.EXPECT().GetAccessTokenSession(nil, "sig", nil).Return(nil, errors.New(""))
+			},
+			expectErr: fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should revoke a refresh token",
+			setup: func() {
+				refreshStrategy.EXPECT().ValidateRefreshToken(nil, nil, "token").Return("sig", nil)
+				refreshStore.EXPECT().GetRefreshTokenSession(nil, "sig", nil).Return(own, nil)
+				refreshStore.EXPECT().DeleteRefreshTokenSession(nil, "sig").Return(nil)
+			},
+		},
+		{
+			description: "should look up access tokens first if hinted",
+			hint:        "access_token",
+			setup: func() {
+				accessStrategy.EXPECT().ValidateAccessToken(nil, nil, "token").Return("sig", nil)
+				accessStore.EXPECT().GetAccessTokenSession(nil, "sig", nil).Return(own, nil)
+				accessStore.EXPECT().DeleteAccessTokenSession(nil, "sig").Return(nil)
+			},
+		},
+		{
+			description: "should fail because the token was issued to another client",
+			setup: func() {
+				refreshStrategy.EXPECT().ValidateRefreshToken(nil, nil, "token").Return("sig", nil)
+				refreshStore.EXPECT().GetRefreshTokenSession(nil, "sig", nil).Return(foreign, nil)
+			},
+			expectErr: fosite.ErrUnauthorizedClient,
+		},
+		{
+			description: "should fail because the token could not be deleted",
+			hint:        "access_token",
+			setup: func() {
+				accessStrategy.EXPECT().ValidateAccessToken(nil, nil, "token").Return("sig", nil)
+				accessStore.EXPECT().GetAccessTokenSession(nil, "sig", nil).Return(own, nil)
+				accessStore.EXPECT().DeleteAccessTokenSession(nil, "sig").Return(errors.New(""))
+			},
+			expectErr: fosite.ErrServerError,
+		},
+	} {
+		c.setup()
+		err := h.RevokeToken(nil, "token", c.hint, client)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestRevokeTokenWithoutStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := &TokenRevocationHandler{
+		AccessTokenStrategy:  internal.NewMockAccessTokenStrategy(ctrl),
+		RefreshTokenStrategy: internal.NewMockRefreshTokenStrategy(ctrl),
+	}
+	assert.NotNil(t, h.ValidateConfiguration())
+
+	err := h.RevokeToken(nil, "token", "", &fosite.DefaultClient{ID: "foo"})
+	assert.True(t, errors.Is(fosite.ErrUnsupportedEndpoint, err), "%s", err)
+
+	h.TokenRevocationStorage = tokenRevocationStorage{internal.NewMockAccessTokenStorage(ctrl), internal.NewMockRefreshTokenGrantStorage(ctrl)}
+	assert.Nil(t, h.ValidateConfiguration())
+}
@@ -5,22 +5,31 @@ import (
 	"golang.org/x/net/context"
 )
 
+// CoreStrategy generates and validates the authorize codes, access and refresh tokens of the core handlers. The
+// handlers only depend on the strategy interfaces, so the format of the tokens is chosen by passing either
+// strategy.HMACSHAStrategy for opaque tokens or strategy.RS256JWTStrategy for JWTs.
 type CoreStrategy interface {
 	AccessTokenStrategy
 	RefreshTokenStrategy
 	AuthorizeCodeStrategy
 }
 
+// AccessTokenStrategy generates access tokens and validates them. The signature returned by both methods is the
+// key the token is stored with. The HMAC signature of an opaque token does not reveal the token, but a JWT can be
+// reassembled from its stored claims and signature, unless the key is derived from the signature using
+// strategy.StorageKeys.
 type AccessTokenStrategy interface {
 	GenerateAccessToken(ctx context.Context, requester fosite.Requester) (token string, signature string, err error)
 	ValidateAccessToken(ctx context.Context, requester fosite.Requester, token string) (signature string, err error)
 }
 
+// RefreshTokenStrategy generates refresh tokens and validates them, see AccessTokenStrategy.
 type RefreshTokenStrategy interface {
 	GenerateRefreshToken(ctx context.Context, requester fosite.Requester) (token string, signature string, err error)
 	ValidateRefreshToken(ctx context.Context, requester fosite.Requester, token string) (signature string, err error)
 }
 
+// AuthorizeCodeStrategy generates authorize codes and validates them, see AccessTokenStrategy.
 type AuthorizeCodeStrategy interface {
 	GenerateAuthorizeCode(ctx context.Context, requester fosite.Requester) (token string, signature string, err error)
 	ValidateAuthorizeCode(ctx context.Context, requester fosite.Requester, token string) (signature string, err error)
@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"github.com/go-errors/errors"
 	"github.com/ory-am/fosite"
 	enigma "github.com/ory-am/fosite/token/hmac"
 	"golang.org/x/net/context"
@@ -8,28 +9,53 @@ import (
 
 type HMACSHAStrategy struct {
 	Enigma *enigma.HMACStrategy
+	TokenPrefixes
+	StorageKeys
 }
 
 func (h HMACSHAStrategy) GenerateAccessToken(_ context.Context, _ fosite.Requester) (token string, signature string, err error) {
-	return h.Enigma.Generate()
+	token, signature, err = h.Enigma.Generate()
+	signature, err = h.storageKey(signature, err)
+	return prefixToken(h.AccessTokenPrefix, token), signature, err
 }
 
 func (h HMACSHAStrategy) ValidateAccessToken(_ context.Context, _ fosite.Requester, token string) (signature string, err error) {
-	return h.Enigma.Validate(token)
+	return h.storageKey(h.validate(stripPrefix(h.AccessTokenPrefix, token)))
 }
 
 func (h HMACSHAStrategy) GenerateRefreshToken(_ context.Context, _ fosite.Requester) (token string, signature string, err error) {
-	return h.Enigma.Generate()
+	token, signature, err = h.Enigma.Generate()
+	signature, err = h.storageKey(signature, err)
+	return prefixToken(h.RefreshTokenPrefix, token), signature, err
 }
 
 func (h HMACSHAStrategy) ValidateRefreshToken(_ context.Context, _ fosite.Requester, token string) (signature string, err error) {
-	return h.Enigma.Validate(token)
+	return h.storageKey(h.validate(stripPrefix(h.RefreshTokenPrefix, token)))
 }
 
 func (h HMACSHAStrategy) GenerateAuthorizeCode(_ context.Context, _ fosite.Requester) (token string, signature string, err error) {
-	return h.Enigma.Generate()
+	token, signature, err = h.Enigma.Generate()
+	signature, err = h.storageKey(signature, err)
+	return prefixToken(h.AuthorizeCodePrefix, token), signature, err
 }
 
 func (h HMACSHAStrategy) ValidateAuthorizeCode(_ context.Context, _ fosite.Requester, token string) (signature string, err error) {
+	return h.storageKey(h.validate(stripPrefix(h.AuthorizeCodePrefix, token)))
+}
+
+// validate rejects malformed tokens with fosite.ErrInvalidTokenFormat before computing their signature, which is
+// why callers do not hit the storage for garbage input.
+func (h HMACSHAStrategy) validate(token string) (string, error) {
+	if err := h.Enigma.ValidateFormat(token); err != nil {
+		return "", errors.New(fosite.ErrInvalidTokenFormat)
+	}
 	return h.Enigma.Validate(token)
 }
+
+// ValidateConfiguration implements fosite.ConfigurationValidator.
+func (h HMACSHAStrategy) ValidateConfiguration() error {
+	if h.Enigma == nil {
+		return errors.New("HMAC strategy is missing")
+	}
+	return h.Enigma.ValidateConfiguration()
+}
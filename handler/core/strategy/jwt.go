@@ -1,6 +1,9 @@
 package strategy
 
 import (
+	"strings"
+	"time"
+
 	"github.com/go-errors/errors"
 	"github.com/ory-am/fosite"
 	"github.com/ory-am/fosite/token/jwt"
@@ -19,6 +22,10 @@ type JWTSessionContainer interface {
 type JWTSession struct {
 	JWTClaims *jwt.JWTClaims
 	JWTHeader *jwt.Headers
+
+	// NotBeforeOffset delays the activation of access tokens issued for this session, see fosite.NotBeforeSession.
+	// It is ignored if JWTClaims.NotBefore is set.
+	NotBeforeOffset time.Duration
 }
 
 func (j *JWTSession) GetJWTClaims() *jwt.JWTClaims {
@@ -28,6 +35,11 @@ func (j *JWTSession) GetJWTClaims() *jwt.JWTClaims {
 	return j.JWTClaims
 }
 
+// GetNotBeforeOffset implements fosite.NotBeforeSession.
+func (j *JWTSession) GetNotBeforeOffset() time.Duration {
+	return j.NotBeforeOffset
+}
+
 func (j *JWTSession) GetJWTHeader() *jwt.Headers {
 	if j.JWTHeader == nil {
 		j.JWTHeader = &jwt.Headers{}
@@ -35,56 +47,172 @@ func (j *JWTSession) GetJWTHeader() *jwt.Headers {
 	return j.JWTHeader
 }
 
-// RS256JWTStrategy is a JWT RS256 strategy.
+// idTokenClaims are claims which are only ever contained in ID tokens. A JWT containing one of them is
+// rejected as access token, so that ID tokens can not be replayed against resource servers.
+var idTokenClaims = []string{"nonce", "at_hash", "c_hash"}
+
+// RS256JWTStrategy is a JWT RS256 strategy. Access tokens follow the JWT profile for OAuth 2.0 access tokens
+// defined in https://tools.ietf.org/html/rfc9068
 type RS256JWTStrategy struct {
 	*jwt.RS256JWTStrategy
+	TokenPrefixes
+	StorageKeys
+
+	// Issuer is used as the iss claim of access tokens if neither the session nor the fosite.Configuration of the
+	// request define one.
+	Issuer string
+
+	// AudienceAsArray serializes the aud claim of access tokens as a JSON array even if there is a single
+	// audience. Several audiences are always serialized as an array.
+	AudienceAsArray bool
 }
 
-func (h *RS256JWTStrategy) GenerateAccessToken(_ context.Context, requester fosite.Requester) (token string, signature string, err error) {
-	return h.generate(requester)
+func (h *RS256JWTStrategy) GenerateAccessToken(ctx context.Context, requester fosite.Requester) (token string, signature string, err error) {
+	token, signature, err = h.generateAccessToken(ctx, requester)
+	signature, err = h.storageKey(signature, err)
+	return prefixToken(h.AccessTokenPrefix, token), signature, err
 }
 
-func (h *RS256JWTStrategy) ValidateAccessToken(_ context.Context, _ fosite.Requester, token string) (signature string, err error) {
-	return h.validate(token)
+func (h *RS256JWTStrategy) ValidateAccessToken(ctx context.Context, _ fosite.Requester, token string) (signature string, err error) {
+	return h.storageKey(h.validate(ctx, stripPrefix(h.AccessTokenPrefix, token), jwt.AccessTokenType))
 }
 
-func (h *RS256JWTStrategy) GenerateRefreshToken(_ context.Context, requester fosite.Requester) (token string, signature string, err error) {
-	return h.generate(requester)
+func (h *RS256JWTStrategy) GenerateRefreshToken(ctx context.Context, requester fosite.Requester) (token string, signature string, err error) {
+	token, signature, err = h.generate(ctx, requester)
+	signature, err = h.storageKey(signature, err)
+	return prefixToken(h.RefreshTokenPrefix, token), signature, err
 }
 
-func (h *RS256JWTStrategy) ValidateRefreshToken(_ context.Context, _ fosite.Requester, token string) (signature string, err error) {
-	return h.validate(token)
+func (h *RS256JWTStrategy) ValidateRefreshToken(ctx context.Context, _ fosite.Requester, token string) (signature string, err error) {
+	return h.storageKey(h.validate(ctx, stripPrefix(h.RefreshTokenPrefix, token), ""))
 }
 
-func (h *RS256JWTStrategy) GenerateAuthorizeCode(_ context.Context, requester fosite.Requester) (token string, signature string, err error) {
-	return h.generate(requester)
+func (h *RS256JWTStrategy) GenerateAuthorizeCode(ctx context.Context, requester fosite.Requester) (token string, signature string, err error) {
+	token, signature, err = h.generate(ctx, requester)
+	signature, err = h.storageKey(signature, err)
+	return prefixToken(h.AuthorizeCodePrefix, token), signature, err
 }
 
-func (h *RS256JWTStrategy) ValidateAuthorizeCode(_ context.Context, requester fosite.Requester, token string) (signature string, err error) {
-	return h.validate(token)
+func (h *RS256JWTStrategy) ValidateAuthorizeCode(ctx context.Context, _ fosite.Requester, token string) (signature string, err error) {
+	return h.storageKey(h.validate(ctx, stripPrefix(h.AuthorizeCodePrefix, token), ""))
 }
 
-func (h *RS256JWTStrategy) validate(token string) (string, error) {
-	t, err := h.RS256JWTStrategy.Decode(token)
+// validate validates token and returns its signature. If typ is not empty, the typ header of the token must
+// match typ, which prevents other JWTs signed by the same key, like ID tokens, from being accepted.
+func (h *RS256JWTStrategy) validate(ctx context.Context, token string, typ string) (string, error) {
+	t, err := h.signer(ctx).Decode(token)
 	if err != nil {
 		return "", err
 	}
 
+	if typ != "" && !isType(t.Header["typ"], typ) {
+		return "", errors.Errorf("Token is not of type %s", typ)
+	}
+
+	if typ == jwt.AccessTokenType {
+		for _, claim := range idTokenClaims {
+			if _, ok := t.Claims[claim]; ok {
+				return "", errors.Errorf("Access tokens must not contain the %s claim of ID tokens", claim)
+			}
+		}
+	}
+
 	claims := jwt.JWTClaimsFromMap(t.Claims)
 	if claims.IsNotYetValid() || claims.IsExpired() {
 		return "", errors.New("Token claims did not validate")
 	}
 
+	// Access tokens are validated for the audience a resource server passed to fosite.ValidateToken, if any.
+	if audience := fosite.AudienceFromContext(ctx); typ == jwt.AccessTokenType && audience != "" && !claims.HasAudience(audience) {
+		return "", errors.Errorf("Token was not issued for %s", audience)
+	}
+
 	return h.RS256JWTStrategy.GetSignature(token)
 }
 
-func (h *RS256JWTStrategy) generate(requester fosite.Requester) (string, string, error) {
+// signer returns the strategy signing and validating the tokens of a request, using the signing key of the
+// fosite.Configuration in ctx if there is one.
+func (h *RS256JWTStrategy) signer(ctx context.Context) *jwt.RS256JWTStrategy {
+	if key := fosite.SigningKeyFromContext(ctx, nil); key != nil {
+		return &jwt.RS256JWTStrategy{PrivateKey: key}
+	}
+	return h.RS256JWTStrategy
+}
+
+func (h *RS256JWTStrategy) generate(ctx context.Context, requester fosite.Requester) (string, string, error) {
 	if jwtSession, ok := requester.GetSession().(JWTSessionContainer); ok {
 		if jwtSession.GetJWTClaims() != nil {
-			return h.RS256JWTStrategy.Generate(jwtSession.GetJWTClaims(), jwtSession.GetJWTHeader())
+			return h.signer(ctx).GenerateWithContext(ctx, jwtSession.GetJWTClaims(), jwtSession.GetJWTHeader())
 		}
 		return "", "", errors.New("GetTokenClaims() must not be nil")
 	}
 	return "", "", errors.New("Session must be of type JWTSession")
+}
 
+// generateAccessToken generates an access token containing the claims required by
+// https://tools.ietf.org/html/rfc9068#section-2.2 without modifying the claims of the session.
+func (h *RS256JWTStrategy) generateAccessToken(ctx context.Context, requester fosite.Requester) (string, string, error) {
+	jwtSession, ok := requester.GetSession().(JWTSessionContainer)
+	if !ok {
+		return "", "", errors.New("Session must be of type JWTSession")
+	} else if jwtSession.GetJWTClaims() == nil {
+		return "", "", errors.New("GetTokenClaims() must not be nil")
+	}
+
+	claims := *jwtSession.GetJWTClaims()
+	claims.Extra = jwt.Copy(claims.Extra)
+
+	// Sessions are shared with the ID token, e.g. in the implicit flow. Claims like the nonce of the authorize
+	// request must only ever end up in the ID token.
+	for _, claim := range idTokenClaims {
+		delete(claims.Extra, claim)
+	}
+	if claims.Issuer == "" {
+		claims.Issuer = fosite.IssuerFromContext(ctx, h.Issuer)
+	}
+	if claims.IssuedAt.IsZero() {
+		claims.IssuedAt = time.Now()
+	}
+	if claims.NotBefore.IsZero() {
+		claims.NotBefore = fosite.NotBefore(jwtSession, claims.IssuedAt)
+	}
+
+	if client := requester.GetClient(); client != nil {
+		claims.Add("client_id", client.GetID())
+		if len(claims.GetAudiences()) == 0 {
+			claims.Audience = client.GetID()
+			if audience := client.GetAudience(); len(audience) > 0 {
+				claims.Audience = audience[0]
+				claims.Audiences = append([]string{}, audience...)
+			}
+		}
+	}
+	claims.AudienceAsArray = claims.AudienceAsArray || h.AudienceAsArray
+	claims.Add("scope", strings.Join(requester.GetGrantedScopes(), " "))
+
+	header := &jwt.Headers{}
+	if jwtSession.GetJWTHeader() != nil {
+		header.Extra = jwt.Copy(jwtSession.GetJWTHeader().Extra)
+	}
+	header.Add("typ", jwt.AccessTokenType)
+
+	return h.signer(ctx).GenerateWithContext(ctx, &claims, header)
+}
+
+// isType compares the typ header with an expected media type. The "application/" prefix may be omitted, see
+// https://tools.ietf.org/html/rfc7515#section-4.1.9
+func isType(header interface{}, typ string) bool {
+	actual, _ := header.(string)
+	return strings.EqualFold(strings.TrimPrefix(strings.ToLower(actual), "application/"), typ)
+}
+
+// ValidateConfiguration implements fosite.ConfigurationValidator. Keys of fosite.Configuration are not checked,
+// they are only known once a request is served.
+func (h *RS256JWTStrategy) ValidateConfiguration() error {
+	if h.RS256JWTStrategy == nil {
+		return errors.New("RS256 strategy is missing")
+	} else if err := h.RS256JWTStrategy.ValidateConfiguration(); err != nil {
+		return err
+	}
+	return fosite.ValidateIssuer(h.Issuer)
 }
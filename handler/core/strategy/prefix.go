@@ -0,0 +1,24 @@
+package strategy
+
+import "strings"
+
+// TokenPrefixes are optional, human readable prefixes which are prepended to generated tokens, for example
+// "ory_at_" for access tokens. Prefixes are not part of the token signature and are stripped before a token
+// is validated, which is why tokens issued without a prefix remain valid. An empty prefix leaves the
+// token format unchanged.
+type TokenPrefixes struct {
+	AccessTokenPrefix   string
+	RefreshTokenPrefix  string
+	AuthorizeCodePrefix string
+}
+
+func prefixToken(prefix, token string) string {
+	if token == "" {
+		return ""
+	}
+	return prefix + token
+}
+
+func stripPrefix(prefix, token string) string {
+	return strings.TrimPrefix(token, prefix)
+}
@@ -0,0 +1,28 @@
+package strategy
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// StorageKeys optionally derives the keys tokens are stored under from their signatures. By default, signatures
+// are used as storage keys. Because a JWT can be reassembled from its stored claims and its signature, a leaked
+// storage would then reveal usable tokens. Deriving the key using a one-way function like SHA256StorageKey
+// prevents this. As keys of tokens issued before change, enabling it invalidates all stored tokens.
+type StorageKeys struct {
+	// DeriveStorageKey returns the storage key of a token signature. Signatures are used as keys if nil.
+	DeriveStorageKey func(signature string) string
+}
+
+// SHA256StorageKey returns the unpadded base64url encoded SHA256 hash of signature.
+func SHA256StorageKey(signature string) string {
+	sum := sha256.Sum256([]byte(signature))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (s StorageKeys) storageKey(signature string, err error) (string, error) {
+	if err != nil || s.DeriveStorageKey == nil {
+		return signature, err
+	}
+	return s.DeriveStorageKey(signature), nil
+}
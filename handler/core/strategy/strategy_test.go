@@ -1,15 +1,21 @@
 package strategy
 
 import (
+	"crypto/rsa"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-errors/errors"
 	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/core"
 	"github.com/ory-am/fosite/internal"
 	"github.com/ory-am/fosite/token/hmac"
 	"github.com/ory-am/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	jwtgo "gopkg.in/dgrijalva/jwt-go.v2"
 )
 
 var s = HMACSHAStrategy{
@@ -45,6 +51,12 @@ var r = &fosite.Request{
 	},
 }
 
+func TestStrategiesImplementCoreStrategy(t *testing.T) {
+	assert.Implements(t, (*core.CoreStrategy)(nil), s)
+	assert.Implements(t, (*core.CoreStrategy)(nil), &s)
+	assert.Implements(t, (*core.CoreStrategy)(nil), j)
+}
+
 func TestAccessToken(t *testing.T) {
 	// HMAC
 	token, signature, err := s.GenerateAccessToken(nil, r)
@@ -110,3 +122,396 @@ func TestGenerateAuthorizeCode(t *testing.T) {
 	assert.Nil(t, err, "%s", err)
 	assert.Equal(t, signature, validate)
 }
+
+func TestTokenPrefixes(t *testing.T) {
+	prefixes := TokenPrefixes{
+		AccessTokenPrefix:   "ory_at_",
+		RefreshTokenPrefix:  "ory_rt_",
+		AuthorizeCodePrefix: "ory_ac_",
+	}
+	ps := HMACSHAStrategy{Enigma: s.Enigma, TokenPrefixes: prefixes}
+	pj := &RS256JWTStrategy{RS256JWTStrategy: j.RS256JWTStrategy, TokenPrefixes: prefixes}
+
+	for k, c := range []struct {
+		prefix   string
+		generate func(context.Context, fosite.Requester) (string, string, error)
+		validate func(context.Context, fosite.Requester, string) (string, error)
+		plain    func(context.Context, fosite.Requester, string) (string, error)
+	}{
+		{"ory_at_", ps.GenerateAccessToken, ps.ValidateAccessToken, s.ValidateAccessToken},
+		{"ory_rt_", ps.GenerateRefreshToken, ps.ValidateRefreshToken, s.ValidateRefreshToken},
+		{"ory_ac_", ps.GenerateAuthorizeCode, ps.ValidateAuthorizeCode, s.ValidateAuthorizeCode},
+		{"ory_at_", pj.GenerateAccessToken, pj.ValidateAccessToken, j.ValidateAccessToken},
+		{"ory_rt_", pj.GenerateRefreshToken, pj.ValidateRefreshToken, j.ValidateRefreshToken},
+		{"ory_ac_", pj.GenerateAuthorizeCode, pj.ValidateAuthorizeCode, j.ValidateAuthorizeCode},
+	} {
+		token, signature, err := c.generate(nil, r)
+		assert.Nil(t, err, "%d: %s", k, err)
+		assert.True(t, strings.HasPrefix(token, c.prefix), "%d: %s", k, token)
+		assert.NotContains(t, signature, c.prefix, "%d", k)
+
+		validate, err := c.validate(nil, r, token)
+		assert.Nil(t, err, "%d: %s", k, err)
+		assert.Equal(t, signature, validate, "%d", k)
+
+		// Tokens issued before a prefix was configured remain valid
+		validate, err = c.validate(nil, r, strings.TrimPrefix(token, c.prefix))
+		assert.Nil(t, err, "%d: %s", k, err)
+		assert.Equal(t, signature, validate, "%d", k)
+
+		// Strategies without prefixes do not accept prefixed tokens
+		_, err = c.plain(nil, r, token)
+		assert.NotNil(t, err, "%d", k)
+	}
+}
+
+func TestStorageKeys(t *testing.T) {
+	keys := StorageKeys{DeriveStorageKey: SHA256StorageKey}
+	ks := HMACSHAStrategy{Enigma: s.Enigma, StorageKeys: keys}
+	kj := &RS256JWTStrategy{RS256JWTStrategy: j.RS256JWTStrategy, StorageKeys: keys}
+
+	for k, c := range []struct {
+		generate func(context.Context, fosite.Requester) (string, string, error)
+		validate func(context.Context, fosite.Requester, string) (string, error)
+		plain    func(context.Context, fosite.Requester, string) (string, error)
+	}{
+		{ks.GenerateAccessToken, ks.ValidateAccessToken, s.ValidateAccessToken},
+		{ks.GenerateRefreshToken, ks.ValidateRefreshToken, s.ValidateRefreshToken},
+		{ks.GenerateAuthorizeCode, ks.ValidateAuthorizeCode, s.ValidateAuthorizeCode},
+		{kj.GenerateAccessToken, kj.ValidateAccessToken, j.ValidateAccessToken},
+		{kj.GenerateRefreshToken, kj.ValidateRefreshToken, j.ValidateRefreshToken},
+		{kj.GenerateAuthorizeCode, kj.ValidateAuthorizeCode, j.ValidateAuthorizeCode},
+	} {
+		token, key, err := c.generate(nil, r)
+		require.Nil(t, err, "%d: %s", k, err)
+
+		validated, err := c.validate(nil, r, token)
+		assert.Nil(t, err, "%d: %s", k, err)
+		assert.Equal(t, key, validated, "%d", k)
+
+		// The key is derived from the signature, which therefore can not be read from the storage.
+		signature, err := c.plain(nil, r, token)
+		assert.Nil(t, err, "%d: %s", k, err)
+		assert.NotEqual(t, signature, key, "%d", k)
+		assert.NotContains(t, token, key, "%d", k)
+		assert.Equal(t, SHA256StorageKey(signature), key, "%d", k)
+
+		_, err = c.validate(nil, r, token+"a")
+		assert.NotNil(t, err, "%d", k)
+	}
+}
+
+func TestJWTStrategyRejectsUnsignedTokens(t *testing.T) {
+	token := jwtgo.New(jwtgo.SigningMethodNone)
+	token.Claims = claims.ToMap()
+	unsigned, err := token.SignedString(jwtgo.UnsafeAllowNoneSignatureType)
+	require.Nil(t, err, "%s", err)
+
+	_, err = j.ValidateAccessToken(nil, r, unsigned)
+	assert.NotNil(t, err)
+
+	_, err = j.ValidateRefreshToken(nil, r, unsigned)
+	assert.NotNil(t, err)
+
+	_, err = j.ValidateAuthorizeCode(nil, r, unsigned)
+	assert.NotNil(t, err)
+}
+
+func TestJWTAccessTokenAudience(t *testing.T) {
+	for k, c := range []struct {
+		audience        []string
+		audienceAsArray bool
+		expectAud       interface{}
+	}{
+		{audience: []string{"https://api.example.com"}, expectAud: "https://api.example.com"},
+		{audience: []string{"https://api.example.com"}, audienceAsArray: true, expectAud: []interface{}{"https://api.example.com"}},
+		{audience: []string{"https://api.example.com", "https://photos.example.com"}, expectAud: []interface{}{"https://api.example.com", "https://photos.example.com"}},
+		{expectAud: "foo"},
+	} {
+		ar := &fosite.Request{
+			Client:  &fosite.DefaultClient{ID: "foo", Audience: c.audience},
+			Session: &JWTSession{JWTClaims: &jwt.JWTClaims{ExpiresAt: time.Now().Add(time.Hour)}},
+		}
+		js := &RS256JWTStrategy{RS256JWTStrategy: j.RS256JWTStrategy, AudienceAsArray: c.audienceAsArray}
+
+		token, _, err := js.GenerateAccessToken(nil, ar)
+		require.Nil(t, err, "case %d: %s", k, err)
+		_, err = js.ValidateAccessToken(nil, ar, token)
+		require.Nil(t, err, "case %d: %s", k, err)
+
+		decoded, err := js.RS256JWTStrategy.Decode(token)
+		require.Nil(t, err, "case %d: %s", k, err)
+		assert.Equal(t, c.expectAud, decoded.Claims["aud"], "case %d", k)
+		for _, audience := range c.audience {
+			assert.True(t, jwt.JWTClaimsFromMap(decoded.Claims).HasAudience(audience), "case %d: %s", k, audience)
+
+			_, err = js.ValidateAccessToken(fosite.WithAudience(nil, audience), ar, token)
+			assert.Nil(t, err, "case %d: %s", k, err)
+		}
+
+		// Tokens not issued for the audience a resource server validates them for are rejected, whether aud is a
+		// string or an array.
+		_, err = js.ValidateAccessToken(fosite.WithAudience(nil, "https://other.example.com"), ar, token)
+		assert.NotNil(t, err, "case %d", k)
+	}
+}
+
+func TestJWTAccessTokenProfile(t *testing.T) {
+	ar := &fosite.Request{
+		Client:        &fosite.DefaultClient{ID: "foo", Audience: []string{"https://api.example.com"}},
+		GrantedScopes: fosite.ScopeArguments{"photos", "offline"},
+		Session: &JWTSession{JWTClaims: &jwt.JWTClaims{
+			Subject:   "peter",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}},
+	}
+	js := &RS256JWTStrategy{RS256JWTStrategy: j.RS256JWTStrategy, Issuer: "https://auth.example.com"}
+
+	token, _, err := js.GenerateAccessToken(nil, ar)
+	require.Nil(t, err, "%s", err)
+
+	decoded, err := js.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, jwt.AccessTokenType, decoded.Header["typ"])
+	assert.Equal(t, "https://auth.example.com", decoded.Claims["iss"])
+	assert.Equal(t, "https://api.example.com", decoded.Claims["aud"])
+	assert.Equal(t, "peter", decoded.Claims["sub"])
+	assert.Equal(t, "foo", decoded.Claims["client_id"])
+	assert.Equal(t, "photos offline", decoded.Claims["scope"])
+	for _, claim := range []string{"exp", "iat", "jti"} {
+		assert.NotEmpty(t, decoded.Claims[claim], "%s", claim)
+	}
+
+	// The claims of the session are not modified
+	assert.Empty(t, ar.Session.(*JWTSession).JWTClaims.Issuer)
+	assert.Empty(t, ar.Session.(*JWTSession).JWTClaims.Extra)
+
+	_, err = js.ValidateAccessToken(nil, ar, token)
+	assert.Nil(t, err, "%s", err)
+
+	// Other tokens signed by the same key are not accepted as access tokens
+	refresh, _, err := js.GenerateRefreshToken(nil, ar)
+	require.Nil(t, err, "%s", err)
+	_, err = js.ValidateAccessToken(nil, ar, refresh)
+	assert.NotNil(t, err)
+
+	other, _, err := js.RS256JWTStrategy.Generate(claims, &jwt.Headers{})
+	require.Nil(t, err, "%s", err)
+	_, err = js.ValidateAccessToken(nil, ar, other)
+	assert.NotNil(t, err)
+}
+
+func TestJWTAccessTokenWithUniqueID(t *testing.T) {
+	ar := &fosite.Request{
+		Client:  &fosite.DefaultClient{ID: "foo"},
+		Session: &JWTSession{JWTClaims: &jwt.JWTClaims{Subject: "peter", ExpiresAt: time.Now().Add(time.Hour)}},
+	}
+
+	var ids []interface{}
+	for i := 0; i < 2; i++ {
+		token, _, err := j.GenerateAccessToken(nil, ar)
+		require.Nil(t, err, "%s", err)
+		decoded, err := j.RS256JWTStrategy.Decode(token)
+		require.Nil(t, err, "%s", err)
+		assert.NotEmpty(t, decoded.Claims["jti"])
+		ids = append(ids, decoded.Claims["jti"])
+	}
+	assert.NotEqual(t, ids[0], ids[1], "access tokens issued for the same session must have different IDs")
+	assert.Empty(t, ar.Session.(*JWTSession).JWTClaims.JTI, "the claims of the session are not modified")
+}
+
+func TestJWTAccessTokenClientID(t *testing.T) {
+	client := &fosite.DefaultClient{ID: "foo"}
+	newSession := func() *JWTSession {
+		// The client_id claim identifies the client the token was issued to and can not be set by the session.
+		return &JWTSession{JWTClaims: &jwt.JWTClaims{ExpiresAt: time.Now().Add(time.Hour), Extra: map[string]interface{}{"client_id": "bar"}}}
+	}
+
+	access := fosite.NewAccessRequest(newSession())
+	access.Client = client
+	authorize := fosite.NewAuthorizeRequest()
+	authorize.Client = client
+	authorize.Session = newSession()
+
+	// Requests of the token endpoint, e.g. of the authorization code, client credentials and refresh token grants,
+	// and of the authorize endpoint for the implicit grant.
+	for k, requester := range []fosite.Requester{access, authorize} {
+		token, _, err := j.GenerateAccessToken(nil, requester)
+		require.Nil(t, err, "case %d: %s", k, err)
+		decoded, err := j.RS256JWTStrategy.Decode(token)
+		require.Nil(t, err, "case %d: %s", k, err)
+		assert.Equal(t, "foo", decoded.Claims["client_id"], "case %d", k)
+		assert.Equal(t, "bar", requester.GetSession().(*JWTSession).JWTClaims.Extra["client_id"], "case %d", k)
+	}
+}
+
+func TestJWTAccessTokenNotBefore(t *testing.T) {
+	session := &JWTSession{
+		JWTClaims:       &jwt.JWTClaims{Subject: "peter", ExpiresAt: time.Now().Add(time.Hour)},
+		NotBeforeOffset: time.Hour,
+	}
+	ar := &fosite.Request{Client: &fosite.DefaultClient{ID: "foo"}, Session: session}
+
+	token, _, err := j.GenerateAccessToken(nil, ar)
+	require.Nil(t, err, "%s", err)
+	assert.True(t, session.JWTClaims.NotBefore.IsZero(), "the claims of the session are not modified")
+
+	parsed, err := jwtgo.Parse(token, func(*jwtgo.Token) (interface{}, error) {
+		return &j.RS256JWTStrategy.PrivateKey.PublicKey, nil
+	})
+	require.NotNil(t, err)
+	assert.True(t, err.(*jwtgo.ValidationError).Errors&jwtgo.ValidationErrorNotValidYet != 0, "%s", err)
+	nbf := jwt.ToTime(parsed.Claims["nbf"])
+	assert.WithinDuration(t, time.Now().Add(time.Hour), nbf, time.Minute)
+
+	_, err = j.ValidateAccessToken(nil, ar, token)
+	assert.NotNil(t, err, "tokens must not be accepted before they become valid")
+
+	session.NotBeforeOffset = -time.Hour
+	token, _, err = j.GenerateAccessToken(nil, ar)
+	require.Nil(t, err, "%s", err)
+	_, err = j.ValidateAccessToken(nil, ar, token)
+	assert.Nil(t, err, "%s", err)
+}
+
+func TestJWTStrategyRejectsIDTokens(t *testing.T) {
+	idToken := &jwt.IDTokenClaims{
+		Subject:   "peter",
+		Issuer:    "fosite",
+		Audience:  "foo",
+		Nonce:     "some-nonce",
+		IssuedAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	for k, header := range []*jwt.Headers{
+		{},
+		// The ID token headers are controlled by the session and could be used to forge the type
+		{Extra: map[string]interface{}{"typ": jwt.AccessTokenType}},
+	} {
+		token, _, err := j.RS256JWTStrategy.Generate(idToken, header)
+		require.Nil(t, err, "%d: %s", k, err)
+
+		_, err = j.ValidateAccessToken(nil, r, token)
+		assert.NotNil(t, err, "%d", k)
+	}
+}
+
+func TestHMACRejectsMalformedTokens(t *testing.T) {
+	token, _, err := s.GenerateAccessToken(nil, r)
+	require.Nil(t, err, "%s", err)
+
+	for k, c := range []string{
+		"",
+		"foo.bar",
+		"foo",
+		token[:len(token)-1] + "=",
+		token + ".foo",
+	} {
+		_, err := s.ValidateAccessToken(nil, r, c)
+		assert.True(t, errors.Is(fosite.ErrInvalidTokenFormat, err), "%d: %s", k, err)
+		_, err = s.ValidateRefreshToken(nil, r, c)
+		assert.True(t, errors.Is(fosite.ErrInvalidTokenFormat, err), "%d: %s", k, err)
+		_, err = s.ValidateAuthorizeCode(nil, r, c)
+		assert.True(t, errors.Is(fosite.ErrInvalidTokenFormat, err), "%d: %s", k, err)
+	}
+
+	// Well formed tokens with a wrong signature are still rejected, but not as malformed.
+	forged := token[:strings.Index(token, ".")+1] + strings.Repeat("a", 43)
+	_, err = s.ValidateAccessToken(nil, r, forged)
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(fosite.ErrInvalidTokenFormat, err), "%s", err)
+}
+
+// countingAccessTokenStorage counts storage lookups and returns the stored request.
+type countingAccessTokenStorage struct {
+	core.AccessTokenStorage
+	request fosite.Requester
+	hits    int
+}
+
+func (s *countingAccessTokenStorage) GetAccessTokenSession(_ context.Context, _ string, _ interface{}) (fosite.Requester, error) {
+	s.hits++
+	return s.request, nil
+}
+
+// BenchmarkValidateGarbageAccessTokens reports the storage hits per validated token. Malformed tokens are rejected
+// by the strategy, which is why only the valid case hits the storage.
+func BenchmarkValidateGarbageAccessTokens(b *testing.B) {
+	token, _, _ := s.GenerateAccessToken(nil, r)
+	for _, c := range []struct {
+		name  string
+		token string
+	}{
+		{name: "valid", token: token},
+		{name: "garbage", token: "garbage"},
+		{name: "garbage-base64", token: "ZmF#.YmFy"},
+		{name: "wrong-signature", token: token[:strings.Index(token, ".")+1] + strings.Repeat("a", 43)},
+	} {
+		b.Run("case="+c.name, func(b *testing.B) {
+			store := &countingAccessTokenStorage{request: r}
+			v := &core.CoreValidator{AccessTokenStrategy: s, AccessTokenStorage: store}
+			for i := 0; i < b.N; i++ {
+				v.ValidateToken(nil, fosite.NewAccessRequest(nil), c.token)
+			}
+			b.ReportMetric(float64(store.hits)/float64(b.N), "storage-hits/op")
+		})
+	}
+}
+
+func BenchmarkHMACGenerateAccessToken(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.GenerateAccessToken(nil, r)
+	}
+}
+
+func BenchmarkHMACValidateAccessToken(b *testing.B) {
+	token, _, _ := s.GenerateAccessToken(nil, r)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.ValidateAccessToken(nil, r, token)
+	}
+}
+
+type tenantConfiguration struct {
+	issuer string
+	key    *rsa.PrivateKey
+}
+
+func (c *tenantConfiguration) GetIssuer() string                        { return c.issuer }
+func (c *tenantConfiguration) GetSigningKey() *rsa.PrivateKey           { return c.key }
+func (c *tenantConfiguration) GetLifespan(string, string) time.Duration { return 0 }
+
+func TestJWTStrategyUsesConfigurationFromContext(t *testing.T) {
+	ar := &fosite.Request{
+		Client:  &fosite.DefaultClient{ID: "foo"},
+		Session: &JWTSession{JWTClaims: &jwt.JWTClaims{Subject: "peter", ExpiresAt: time.Now().Add(time.Hour)}},
+	}
+	js := &RS256JWTStrategy{RS256JWTStrategy: j.RS256JWTStrategy, Issuer: "https://auth.example.com"}
+	tenant := &tenantConfiguration{issuer: "https://tenant.example.com", key: internal.MustRSAKey()}
+	ctx := fosite.WithConfiguration(context.Background(), tenant)
+
+	token, signature, err := js.GenerateAccessToken(ctx, ar)
+	require.Nil(t, err, "%s", err)
+
+	parsed, err := jwtgo.Parse(token, func(*jwtgo.Token) (interface{}, error) {
+		return &tenant.key.PublicKey, nil
+	})
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "https://tenant.example.com", parsed.Claims["iss"])
+
+	validate, err := js.ValidateAccessToken(ctx, ar, token)
+	assert.Nil(t, err, "%s", err)
+	assert.Equal(t, signature, validate)
+
+	// Tokens of one configuration are not accepted by another one
+	_, err = js.ValidateAccessToken(nil, ar, token)
+	assert.NotNil(t, err)
+
+	other, _, err := js.GenerateAccessToken(nil, ar)
+	require.Nil(t, err, "%s", err)
+	_, err = js.ValidateAccessToken(ctx, ar, other)
+	assert.NotNil(t, err)
+}
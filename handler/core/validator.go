@@ -3,12 +3,16 @@ package core
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/ory-am/fosite"
 	"golang.org/x/net/context"
 )
 
+// CoreValidator validates access tokens presented to resource servers and implements fosite.TokenIntrospector for
+// access tokens. It requires an AccessTokenStrategy and an AccessTokenStorage. If one of them is nil, tokens are
+// rejected with fosite.ErrUnsupportedEndpoint instead of panicking and ValidateConfiguration reports what is missing.
 type CoreValidator struct {
 	AccessTokenStrategy
 	AccessTokenStorage
@@ -25,6 +29,10 @@ func (c *CoreValidator) ValidateRequest(ctx context.Context, req *http.Request,
 }
 
 func (c *CoreValidator) ValidateToken(ctx context.Context, accessRequest fosite.AccessRequester, token string) error {
+	if c.checkRequirements() != nil {
+		return errors.New(fosite.ErrUnsupportedEndpoint)
+	}
+
 	sig, err := c.AccessTokenStrategy.ValidateAccessToken(ctx, accessRequest, token)
 	if err != nil {
 		return errors.New(fosite.ErrRequestUnauthorized)
@@ -35,6 +43,39 @@ func (c *CoreValidator) ValidateToken(ctx context.Context, accessRequest fosite.
 		return errors.New(fosite.ErrRequestUnauthorized)
 	}
 
+	// Storages may keep access tokens until they are purged, see fosite.Fosite.PurgeExpired.
+	if expiresAt := or.GetExpiresAt(fosite.AccessToken); !expiresAt.IsZero() && expiresAt.Before(time.Now()) {
+		return errors.New(fosite.ErrRequestUnauthorized)
+	}
+
 	accessRequest.Merge(or)
 	return nil
 }
+
+// IntrospectToken implements fosite.TokenIntrospector for access tokens.
+func (c *CoreValidator) IntrospectToken(ctx context.Context, token string, accessRequest fosite.AccessRequester) error {
+	return c.ValidateToken(ctx, accessRequest, token)
+}
+
+// IntrospectedTokenType implements fosite.TokenTypeIntrospector.
+func (c *CoreValidator) IntrospectedTokenType() string {
+	return fosite.AccessToken
+}
+
+// ValidateConfiguration implements fosite.ConfigurationValidator. The AccessTokenStrategy is validated as well.
+func (c *CoreValidator) ValidateConfiguration() error {
+	if err := c.checkRequirements(); err != nil {
+		return err
+	}
+	return fosite.ValidateConfigurations(c.AccessTokenStrategy)
+}
+
+// checkRequirements makes sure that the strategy and storage are set. It is cheap enough to run on every request.
+func (c *CoreValidator) checkRequirements() error {
+	if c.AccessTokenStrategy == nil {
+		return errors.New("CoreValidator requires an AccessTokenStrategy")
+	} else if c.AccessTokenStorage == nil {
+		return errors.New("CoreValidator requires an AccessTokenStorage, the storage does not implement it")
+	}
+	return nil
+}
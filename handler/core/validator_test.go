@@ -3,6 +3,7 @@ package core
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/go-errors/errors"
 	"github.com/golang/mock/gomock"
@@ -57,6 +58,16 @@ func TestValidateRequest(t *testing.T) {
 			},
 			expectErr: fosite.ErrRequestUnauthorized,
 		},
+		{
+			description: "should fail because the access token expired",
+			setup: func() {
+				expired := fosite.NewAccessRequest(nil)
+				expired.SetExpiresAt(fosite.AccessToken, time.Now().Add(-time.Minute))
+				chgen.EXPECT().ValidateAccessToken(nil, areq, "1234").Return("asdf", nil)
+				store.EXPECT().GetAccessTokenSession(nil, "asdf", nil).Return(expired, nil)
+			},
+			expectErr: fosite.ErrRequestUnauthorized,
+		},
 		{
 			description: "should pass",
 			setup: func() {
@@ -71,3 +82,43 @@ func TestValidateRequest(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestIntrospectToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockAccessTokenStorage(ctrl)
+	chgen := internal.NewMockAccessTokenStrategy(ctrl)
+	areq := fosite.NewAccessRequest(nil)
+	defer ctrl.Finish()
+
+	v := &CoreValidator{
+		AccessTokenStrategy: chgen,
+		AccessTokenStorage:  store,
+	}
+
+	chgen.EXPECT().ValidateAccessToken(nil, areq, "1234").Return("", errors.New(""))
+	assert.True(t, errors.Is(fosite.ErrRequestUnauthorized, v.IntrospectToken(nil, "1234", areq)))
+
+	chgen.EXPECT().ValidateAccessToken(nil, areq, "1234").Return("asdf", nil)
+	store.EXPECT().GetAccessTokenSession(nil, "asdf", nil).Return(areq, nil)
+	assert.Nil(t, v.IntrospectToken(nil, "1234", areq))
+
+	expired := fosite.NewAccessRequest(nil)
+	expired.SetExpiresAt(fosite.AccessToken, time.Now().Add(-time.Minute))
+	chgen.EXPECT().ValidateAccessToken(nil, areq, "1234").Return("asdf", nil)
+	store.EXPECT().GetAccessTokenSession(nil, "asdf", nil).Return(expired, nil)
+	assert.True(t, errors.Is(fosite.ErrRequestUnauthorized, v.IntrospectToken(nil, "1234", areq)), "expired access tokens are inactive")
+}
+
+func TestCoreValidatorWithoutStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	v := &CoreValidator{AccessTokenStrategy: internal.NewMockAccessTokenStrategy(ctrl)}
+	assert.NotNil(t, v.ValidateConfiguration())
+
+	err := v.IntrospectToken(nil, "1234", fosite.NewAccessRequest(nil))
+	assert.True(t, errors.Is(fosite.ErrUnsupportedEndpoint, err), "%s", err)
+
+	v.AccessTokenStorage = internal.NewMockAccessTokenStorage(ctrl)
+	assert.Nil(t, v.ValidateConfiguration())
+}
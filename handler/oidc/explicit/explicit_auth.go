@@ -6,6 +6,7 @@ import (
 	"github.com/go-errors/errors"
 	. "github.com/ory-am/fosite"
 	. "github.com/ory-am/fosite/handler/oidc"
+	"github.com/ory-am/fosite/token/jwt"
 	"golang.org/x/net/context"
 )
 
@@ -14,6 +15,10 @@ type OpenIDConnectExplicitHandler struct {
 	OpenIDConnectRequestStorage OpenIDConnectRequestStorage
 
 	*IDTokenHandleHelper
+
+	// RS256JWTStrategy computes the at_hash claim of ID tokens issued at the token endpoint. The claim is omitted
+	// if nil.
+	RS256JWTStrategy *jwt.RS256JWTStrategy
 }
 
 func (c *OpenIDConnectExplicitHandler) HandleAuthorizeEndpointRequest(ctx context.Context, req *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) error {
@@ -21,8 +26,8 @@ func (c *OpenIDConnectExplicitHandler) HandleAuthorizeEndpointRequest(ctx contex
 		return nil
 	}
 
-	if !ar.GetClient().GetResponseTypes().Has("id_token", "code") {
-		return errors.New(ErrInvalidRequest)
+	if err := c.ValidateAuthorizeEndpointRequest(ctx, ar); err != nil {
+		return err
 	}
 
 	if len(resp.GetCode()) == 0 {
@@ -35,3 +40,21 @@ func (c *OpenIDConnectExplicitHandler) HandleAuthorizeEndpointRequest(ctx contex
 
 	return nil
 }
+
+// SupportedResponseTypes implements fosite.ResponseTypeHandler.
+func (c *OpenIDConnectExplicitHandler) SupportedResponseTypes() []Arguments {
+	return []Arguments{{"code"}}
+}
+
+// ValidateAuthorizeEndpointRequest implements fosite.AuthorizeEndpointValidator.
+func (c *OpenIDConnectExplicitHandler) ValidateAuthorizeEndpointRequest(_ context.Context, ar AuthorizeRequester) error {
+	if !(ar.GetScopes().Has("openid") && ar.GetResponseTypes().Exact("code")) {
+		return nil
+	}
+
+	if !ar.GetClient().GetResponseTypes().Has("id_token", "code") {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	return nil
+}
@@ -54,13 +54,13 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 				areq.Client = &fosite.DefaultClient{
 					ResponseTypes: fosite.Arguments{"code", "id_token"},
 				}
-				areq.Scopes = fosite.Arguments{""}
+				areq.Scopes = fosite.ScopeArguments{""}
 			},
 		},
 		{
 			description: "should fail because no code set",
 			setup: func() {
-				areq.Scopes = fosite.Arguments{"openid"}
+				areq.Scopes = fosite.ScopeArguments{"openid"}
 				areq.Form.Set("nonce", "11111111111111111111111111111")
 				aresp.EXPECT().GetCode().Return("")
 			},
@@ -6,36 +6,65 @@ import (
 	"github.com/go-errors/errors"
 	. "github.com/ory-am/fosite"
 	"github.com/ory-am/fosite/handler/oidc"
+	"github.com/ory-am/fosite/handler/oidc/strategy"
 	"golang.org/x/net/context"
 )
 
-func (c *OpenIDConnectExplicitHandler) HandleTokenEndpointRequest(ctx context.Context, r *http.Request, request AccessRequester) error {
-	return ErrUnknownRequest
+// HandleTokenEndpointRequest checks that the client may receive an ID token. The checks are done before the
+// authorization code grant handler issues the access and refresh tokens, so that a failure does not leave tokens
+// behind which were never handed out.
+func (c *OpenIDConnectExplicitHandler) HandleTokenEndpointRequest(ctx context.Context, r *http.Request, requester AccessRequester) error {
+	if _, err := c.getOpenIDConnectSession(ctx, requester); err != nil {
+		return err
+	}
+
+	if !requester.GetClient().GetGrantTypes().Has("authorization_code") {
+		return errors.New(ErrInvalidGrant)
+	}
+
+	if !requester.GetClient().GetResponseTypes().Has("id_token") {
+		return errors.New(ErrInvalidGrant)
+	}
+
+	return nil
 }
 
+// PopulateTokenEndpointResponse adds the ID token to the token response of the code flow. The access token was
+// issued by the authorization code grant handler, which runs first, and is bound to the ID token using the at_hash
+// claim, see http://openid.net/specs/openid-connect-core-1_0.html#CodeIDToken
 func (c *OpenIDConnectExplicitHandler) PopulateTokenEndpointResponse(ctx context.Context, req *http.Request, requester AccessRequester, responder AccessResponder) error {
+	authorize, err := c.getOpenIDConnectSession(ctx, requester)
+	if err != nil {
+		return err
+	}
+
+	if c.RS256JWTStrategy != nil && responder.GetAccessToken() != "" {
+		hash, err := c.RS256JWTStrategy.Hash([]byte(responder.GetAccessToken()))
+		if err != nil {
+			return err
+		}
+		ctx = strategy.WithTokenHashes(ctx, hash[:c.RS256JWTStrategy.GetSigningMethodLength()/2], nil)
+	}
+
+	return c.IssueExplicitIDToken(ctx, req, authorize, responder)
+}
+
+// getOpenIDConnectSession returns the authorize request of the code being exchanged, or ErrUnknownRequest if no
+// ID token was requested.
+func (c *OpenIDConnectExplicitHandler) getOpenIDConnectSession(ctx context.Context, requester AccessRequester) (Requester, error) {
 	if !requester.GetGrantTypes().Exact("authorization_code") {
-		return ErrUnknownRequest
+		return nil, ErrUnknownRequest
 	}
 
 	authorize, err := c.OpenIDConnectRequestStorage.GetOpenIDConnectSession(ctx, requester.GetRequestForm().Get("code"), requester)
 	if err == oidc.ErrNoSessionFound {
-		return ErrUnknownRequest
+		return nil, ErrUnknownRequest
 	} else if err != nil {
-		return errors.New(ErrServerError)
+		return nil, errors.New(ErrServerError)
 	}
 
 	if !authorize.GetScopes().Has("openid") {
-		return ErrUnknownRequest
-	}
-
-	if !requester.GetClient().GetGrantTypes().Has("authorization_code") {
-		return errors.New(ErrInvalidGrant)
+		return nil, ErrUnknownRequest
 	}
-
-	if !requester.GetClient().GetResponseTypes().Has("id_token") {
-		return errors.New(ErrInvalidGrant)
-	}
-
-	return c.IssueExplicitIDToken(ctx, req, authorize, responder)
+	return authorize, nil
 }
@@ -1,6 +1,7 @@
 package explicit
 
 import (
+	"encoding/base64"
 	"net/http"
 	"net/url"
 	"testing"
@@ -13,15 +14,76 @@ import (
 	"github.com/ory-am/fosite/internal"
 	"github.com/ory-am/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandleTokenEndpointRequest(t *testing.T) {
-	h := &OpenIDConnectExplicitHandler{}
-	areq := fosite.NewAccessRequest(nil)
-	areq.Client = &fosite.DefaultClient{
-		ResponseTypes: fosite.Arguments{"id_token"},
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockOpenIDConnectRequestStorage(ctrl)
+	defer ctrl.Finish()
+
+	h := &OpenIDConnectExplicitHandler{OpenIDConnectRequestStorage: store}
+	openid := fosite.NewAuthorizeRequest()
+	openid.Scopes = fosite.ScopeArguments{"openid"}
+	for k, c := range []struct {
+		description string
+		grantTypes  fosite.Arguments
+		client      *fosite.DefaultClient
+		setup       func(areq *fosite.AccessRequest)
+		expectErr   error
+	}{
+		{
+			description: "should not be responsible for other grant types",
+			grantTypes:  fosite.Arguments{"refresh_token"},
+			client:      &fosite.DefaultClient{ResponseTypes: fosite.Arguments{"id_token"}},
+			setup:       func(_ *fosite.AccessRequest) {},
+			expectErr:   fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should not be responsible if no id token was requested",
+			grantTypes:  fosite.Arguments{"authorization_code"},
+			client:      &fosite.DefaultClient{},
+			setup: func(areq *fosite.AccessRequest) {
+				store.EXPECT().GetOpenIDConnectSession(nil, "foobar", areq).Return(nil, oidc.ErrNoSessionFound)
+			},
+			expectErr: fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should fail because the client may not use the authorization code grant",
+			grantTypes:  fosite.Arguments{"authorization_code"},
+			client:      &fosite.DefaultClient{GrantTypes: fosite.Arguments{"implicit"}, ResponseTypes: fosite.Arguments{"id_token"}},
+			setup: func(areq *fosite.AccessRequest) {
+				store.EXPECT().GetOpenIDConnectSession(nil, "foobar", areq).Return(openid, nil)
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should fail because the client may not receive id tokens",
+			grantTypes:  fosite.Arguments{"authorization_code"},
+			client:      &fosite.DefaultClient{GrantTypes: fosite.Arguments{"authorization_code"}},
+			setup: func(areq *fosite.AccessRequest) {
+				store.EXPECT().GetOpenIDConnectSession(nil, "foobar", areq).Return(openid, nil)
+			},
+			expectErr: fosite.ErrInvalidGrant,
+		},
+		{
+			description: "should pass",
+			grantTypes:  fosite.Arguments{"authorization_code"},
+			client:      &fosite.DefaultClient{GrantTypes: fosite.Arguments{"authorization_code"}, ResponseTypes: fosite.Arguments{"id_token"}},
+			setup: func(areq *fosite.AccessRequest) {
+				store.EXPECT().GetOpenIDConnectSession(nil, "foobar", areq).Return(openid, nil)
+			},
+		},
+	} {
+		areq := fosite.NewAccessRequest(nil)
+		areq.GrantTypes = c.grantTypes
+		areq.Client = c.client
+		areq.Form.Set("code", "foobar")
+		c.setup(areq)
+
+		err := h.HandleTokenEndpointRequest(nil, nil, areq)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
 	}
-	assert.True(t, errors.Is(h.HandleTokenEndpointRequest(nil, nil, areq), fosite.ErrUnknownRequest))
 }
 
 func TestPopulateTokenEndpointResponse(t *testing.T) {
@@ -89,7 +151,7 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 			setup: func() {
 				r := fosite.NewAuthorizeRequest()
 				r.Session = areq.Session
-				r.Scopes = fosite.Arguments{"openid"}
+				r.Scopes = fosite.ScopeArguments{"openid"}
 				r.Form.Set("nonce", "1111111111111111")
 				store.EXPECT().GetOpenIDConnectSession(nil, gomock.Any(), areq).AnyTimes().Return(r, nil)
 			},
@@ -101,3 +163,47 @@ func TestPopulateTokenEndpointResponse(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestPopulateTokenEndpointResponseAddsAccessTokenHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockOpenIDConnectRequestStorage(ctrl)
+	defer ctrl.Finish()
+
+	session := &strategy.DefaultSession{
+		Claims:  &jwt.IDTokenClaims{Subject: "peter"},
+		Headers: &jwt.Headers{},
+	}
+	authorize := fosite.NewAuthorizeRequest()
+	authorize.Session = session
+	authorize.Scopes = fosite.ScopeArguments{"openid"}
+	authorize.Form.Set("nonce", "1111111111111111")
+
+	areq := fosite.NewAccessRequest(session)
+	areq.GrantTypes = fosite.Arguments{"authorization_code"}
+	areq.Form.Set("code", "foobar")
+	store.EXPECT().GetOpenIDConnectSession(nil, "foobar", areq).Return(authorize, nil)
+
+	aresp := fosite.NewAccessResponse()
+	aresp.SetAccessToken("some-access-token")
+
+	h := &OpenIDConnectExplicitHandler{
+		OpenIDConnectRequestStorage: store,
+		IDTokenHandleHelper: &oidc.IDTokenHandleHelper{
+			IDTokenStrategy: j,
+		},
+		RS256JWTStrategy: j.RS256JWTStrategy,
+	}
+	require.Nil(t, h.PopulateTokenEndpointResponse(nil, &http.Request{PostForm: url.Values{}}, areq, aresp))
+
+	idToken, ok := aresp.GetExtra("id_token").(string)
+	require.True(t, ok)
+	assert.NotEmpty(t, idToken)
+
+	hash, err := j.RS256JWTStrategy.Hash([]byte("some-access-token"))
+	require.Nil(t, err)
+	assert.Nil(t, session.Claims.AccessTokenHash, "the at_hash claim is not stored in the session")
+
+	token, err := j.RS256JWTStrategy.Decode(idToken)
+	require.Nil(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(hash[:j.RS256JWTStrategy.GetSigningMethodLength()/2]), token.Claims["at_hash"])
+}
@@ -15,45 +15,37 @@ import (
 
 type OpenIDConnectHybridHandler struct {
 	*implicit.AuthorizeImplicitGrantTypeHandler
-	*explicit.AuthorizeExplicitGrantTypeHandler
 	*oidc.IDTokenHandleHelper
 
+	// AuthorizeExplicitGrantTypeHandler is not embedded because its token endpoint methods would be promoted,
+	// which would make this handler a TokenEndpointHandler handling authorize codes a second time.
+	AuthorizeExplicitGrantTypeHandler *explicit.AuthorizeExplicitGrantTypeHandler
+
 	Enigma *jwt.RS256JWTStrategy
 }
 
 func (c *OpenIDConnectHybridHandler) HandleAuthorizeEndpointRequest(ctx context.Context, req *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) error {
-	if len(ar.GetResponseTypes()) < 2 {
+	if !isResponsible(ar) {
 		return nil
 	}
 
-	if !(ar.GetResponseTypes().Matches("token", "id_token", "code") || ar.GetResponseTypes().Matches("token", "code")) {
-		return nil
+	if err := c.ValidateAuthorizeEndpointRequest(ctx, ar); err != nil {
+		return err
 	}
 
-	if !ar.GetClient().GetResponseTypes().Has("token", "code") {
-		return errors.New(ErrInvalidGrant)
-	} else if ar.GetResponseTypes().Matches("id_token") && !ar.GetClient().GetResponseTypes().Has("id_token") {
-		return errors.New(ErrInvalidGrant)
-	}
-
-	sess, ok := ar.GetSession().(strategy.Session)
-	if !ok {
+	if _, ok := ar.GetSession().(strategy.Session); !ok {
 		return errors.New(oidc.ErrInvalidSession)
 	}
 
-	claims := sess.IDTokenClaims()
-
+	var codeHash, accessTokenHash []byte
 	if ar.GetResponseTypes().Has("code") {
-		if !ar.GetClient().GetGrantTypes().Has("authorization_code") {
-			return errors.New(ErrInvalidGrant)
-		}
-
-		code, signature, err := c.AuthorizeCodeStrategy.GenerateAuthorizeCode(ctx, ar)
+		code, signature, err := c.AuthorizeExplicitGrantTypeHandler.AuthorizeCodeStrategy.GenerateAuthorizeCode(ctx, ar)
 		if err != nil {
 			return errors.New(ErrServerError)
 		}
 
-		if err := c.AuthorizeCodeGrantStorage.CreateAuthorizeCodeSession(ctx, signature, ar); err != nil {
+		c.AuthorizeExplicitGrantTypeHandler.SetAuthorizeCodeExpiry(ctx, ar)
+		if err := c.AuthorizeExplicitGrantTypeHandler.AuthorizeCodeGrantStorage.CreateAuthorizeCodeSession(ctx, signature, ar); err != nil {
 			return errors.New(ErrServerError)
 		}
 
@@ -65,14 +57,10 @@ func (c *OpenIDConnectHybridHandler) HandleAuthorizeEndpointRequest(ctx context.
 		if err != nil {
 			return err
 		}
-		claims.CodeHash = hash[:c.Enigma.GetSigningMethodLength()/2]
+		codeHash = hash[:c.Enigma.GetSigningMethodLength()/2]
 	}
 
 	if ar.GetResponseTypes().Has("token") {
-		if !ar.GetClient().GetGrantTypes().Has("implicit") {
-			return errors.New(ErrInvalidGrant)
-		}
-
 		if err := c.IssueImplicitAccessToken(ctx, req, ar, resp); err != nil {
 			return errors.New(err)
 		}
@@ -82,13 +70,15 @@ func (c *OpenIDConnectHybridHandler) HandleAuthorizeEndpointRequest(ctx context.
 		if err != nil {
 			return err
 		}
-		claims.AccessTokenHash = hash[:c.Enigma.GetSigningMethodLength()/2]
+		accessTokenHash = hash[:c.Enigma.GetSigningMethodLength()/2]
 	}
 
 	if !ar.GetScopes().Has("openid") {
 		return nil
 	}
 
+	ctx = strategy.WithTokenHashes(ctx, accessTokenHash, codeHash)
+
 	if err := c.IssueImplicitIDToken(ctx, req, ar, resp); err != nil {
 		return errors.New(err)
 	}
@@ -101,3 +91,39 @@ func (c *OpenIDConnectHybridHandler) HandleAuthorizeEndpointRequest(ctx context.
 	ar.SetResponseTypeHandled("id_token")
 	return nil
 }
+
+// SupportedResponseTypes implements fosite.ResponseTypeHandler.
+func (c *OpenIDConnectHybridHandler) SupportedResponseTypes() []Arguments {
+	return []Arguments{{"token", "code"}, {"token", "id_token", "code"}}
+}
+
+// ValidateAuthorizeEndpointRequest implements fosite.AuthorizeEndpointValidator.
+func (c *OpenIDConnectHybridHandler) ValidateAuthorizeEndpointRequest(_ context.Context, ar AuthorizeRequester) error {
+	if !isResponsible(ar) {
+		return nil
+	}
+
+	// ID tokens are only issued for OpenID Connect requests, which MUST contain the openid scope value.
+	if ar.GetResponseTypes().Has("id_token") && !ar.GetScopes().Has("openid") {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	if !ar.GetClient().GetResponseTypes().Has("token", "code") {
+		return errors.New(ErrInvalidGrant)
+	} else if ar.GetResponseTypes().Has("id_token") && !ar.GetClient().GetResponseTypes().Has("id_token") {
+		return errors.New(ErrInvalidGrant)
+	}
+
+	// Both response types are always requested, see isResponsible.
+	if !ar.GetClient().GetGrantTypes().Has("authorization_code") {
+		return errors.New(ErrInvalidGrant)
+	} else if !ar.GetClient().GetGrantTypes().Has("implicit") {
+		return errors.New(ErrInvalidGrant)
+	}
+
+	return nil
+}
+
+func isResponsible(ar AuthorizeRequester) bool {
+	return ar.GetResponseTypes().Matches("token", "id_token", "code") || ar.GetResponseTypes().Matches("token", "code")
+}
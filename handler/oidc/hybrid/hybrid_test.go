@@ -81,14 +81,23 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 					GrantTypes:    fosite.Arguments{"authorization_code", "implicit"},
 					ResponseTypes: fosite.Arguments{"token", "code", "id_token"},
 				}
-				areq.Scopes = fosite.Arguments{"openid"}
+				areq.Scopes = fosite.ScopeArguments{"openid"}
 			},
 			expectErr: oidc.ErrInvalidSession,
 		},
+		{
+			description: "should fail because an id_token was requested without the openid scope",
+			setup: func() {
+				areq.ResponseTypes = fosite.Arguments{"token", "code", "id_token"}
+				areq.Scopes = fosite.ScopeArguments{"fosite"}
+			},
+			expectErr: fosite.ErrInvalidRequest,
+		},
 		{
 			description: "should fail because client missing response types",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{"token", "code", "id_token"}
+				areq.Scopes = fosite.ScopeArguments{"openid"}
 				areq.Client = &fosite.DefaultClient{
 					GrantTypes:    fosite.Arguments{"implicit"},
 					ResponseTypes: fosite.Arguments{"token", "code", "id_token"},
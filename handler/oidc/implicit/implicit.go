@@ -20,26 +20,18 @@ type OpenIDConnectImplicitHandler struct {
 }
 
 func (c *OpenIDConnectImplicitHandler) HandleAuthorizeEndpointRequest(ctx context.Context, req *http.Request, ar AuthorizeRequester, resp AuthorizeResponder) error {
-	if !(ar.GetScopes().Has("openid") && (ar.GetResponseTypes().Has("token", "id_token") || ar.GetResponseTypes().Exact("id_token"))) {
+	if !isResponsible(ar) {
 		return nil
 	}
 
-	if !ar.GetClient().GetGrantTypes().Has("implicit") {
-		return errors.New(ErrInvalidGrant)
-	}
-
-	if ar.GetResponseTypes().Exact("id_token") && !ar.GetClient().GetResponseTypes().Has("id_token") {
-		return errors.New(ErrInvalidGrant)
-	} else if ar.GetResponseTypes().Matches("token", "id_token") && !ar.GetClient().GetResponseTypes().Has("token", "id_token") {
-		return errors.New(ErrInvalidGrant)
+	if err := c.ValidateAuthorizeEndpointRequest(ctx, ar); err != nil {
+		return err
 	}
 
-	sess, ok := ar.GetSession().(strategy.Session)
-	if !ok {
+	if _, ok := ar.GetSession().(strategy.Session); !ok {
 		return ErrInvalidSession
 	}
 
-	claims := sess.IDTokenClaims()
 	if ar.GetResponseTypes().Has("token") {
 		if err := c.AuthorizeImplicitGrantTypeHandler.IssueImplicitAccessToken(ctx, req, ar, resp); err != nil {
 			return errors.New(err)
@@ -50,7 +42,7 @@ func (c *OpenIDConnectImplicitHandler) HandleAuthorizeEndpointRequest(ctx contex
 		if err != nil {
 			return err
 		}
-		claims.AccessTokenHash = hash[:c.RS256JWTStrategy.GetSigningMethodLength()/2]
+		ctx = strategy.WithTokenHashes(ctx, hash[:c.RS256JWTStrategy.GetSigningMethodLength()/2], nil)
 	}
 
 	if err := c.IssueImplicitIDToken(ctx, req, ar, resp); err != nil {
@@ -60,3 +52,37 @@ func (c *OpenIDConnectImplicitHandler) HandleAuthorizeEndpointRequest(ctx contex
 	ar.SetResponseTypeHandled("id_token")
 	return nil
 }
+
+// SupportedResponseTypes implements fosite.ResponseTypeHandler.
+func (c *OpenIDConnectImplicitHandler) SupportedResponseTypes() []Arguments {
+	return []Arguments{{"id_token"}, {"token", "id_token"}}
+}
+
+// ValidateAuthorizeEndpointRequest implements fosite.AuthorizeEndpointValidator.
+func (c *OpenIDConnectImplicitHandler) ValidateAuthorizeEndpointRequest(_ context.Context, ar AuthorizeRequester) error {
+	if !isResponsible(ar) {
+		return nil
+	}
+
+	// http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+	// OpenID Connect requests MUST contain the openid scope value.
+	if !ar.GetScopes().Has("openid") {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	if !ar.GetClient().GetGrantTypes().Has("implicit") {
+		return errors.New(ErrInvalidGrant)
+	}
+
+	if ar.GetResponseTypes().Exact("id_token") && !ar.GetClient().GetResponseTypes().Has("id_token") {
+		return errors.New(ErrInvalidGrant)
+	} else if ar.GetResponseTypes().Matches("token", "id_token") && !ar.GetClient().GetResponseTypes().Has("token", "id_token") {
+		return errors.New(ErrInvalidGrant)
+	}
+
+	return nil
+}
+
+func isResponsible(ar AuthorizeRequester) bool {
+	return ar.GetResponseTypes().Has("token", "id_token") || ar.GetResponseTypes().Exact("id_token")
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/ory-am/fosite/token/hmac"
 	"github.com/ory-am/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var idStrategy = &strategy.DefaultStrategy{
@@ -61,29 +62,31 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 			setup:       func() {},
 		},
 		{
-			description: "should not do anything because request requirements are not met",
+			description: "should fail because the openid scope is missing",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{"id_token"}
 			},
+			expectErr: fosite.ErrInvalidRequest,
 		},
 		{
-			description: "should not do anything because request requirements are not met",
+			description: "should fail because the openid scope is missing",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{"token", "id_token"}
 			},
+			expectErr: fosite.ErrInvalidRequest,
 		},
 		{
 			description: "should not do anything because request requirements are not met",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{}
-				areq.Scopes = fosite.Arguments{"openid"}
+				areq.Scopes = fosite.ScopeArguments{"openid"}
 			},
 		},
 		{
 			description: "should not do anything because request requirements are not met",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{"token", "id_token"}
-				areq.Scopes = fosite.Arguments{"openid"}
+				areq.Scopes = fosite.ScopeArguments{"openid"}
 				areq.Client = &fosite.DefaultClient{
 					GrantTypes:    fosite.Arguments{},
 					ResponseTypes: fosite.Arguments{},
@@ -95,7 +98,7 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 			description: "should not do anything because request requirements are not met",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{"token", "id_token"}
-				areq.Scopes = fosite.Arguments{"openid"}
+				areq.Scopes = fosite.ScopeArguments{"openid"}
 				areq.Client = &fosite.DefaultClient{
 					GrantTypes:    fosite.Arguments{"implicit"},
 					ResponseTypes: fosite.Arguments{},
@@ -107,7 +110,7 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 			description: "should fail because session not set",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{"id_token"}
-				areq.Scopes = fosite.Arguments{"openid"}
+				areq.Scopes = fosite.ScopeArguments{"openid"}
 				areq.Client = &fosite.DefaultClient{
 					GrantTypes:    fosite.Arguments{"implicit"},
 					ResponseTypes: fosite.Arguments{"token", "id_token"},
@@ -151,7 +154,7 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 			description: "should pass",
 			setup: func() {
 				areq.ResponseTypes = fosite.Arguments{"id_token", "token"}
-				areq.Scopes = fosite.Arguments{"fosite", "openid"}
+				areq.Scopes = fosite.ScopeArguments{"fosite", "openid"}
 			},
 			check: func() {
 				assert.NotEmpty(t, aresp.GetFragment().Get("id_token"))
@@ -168,3 +171,56 @@ func TestHandleAuthorizeEndpointRequest(t *testing.T) {
 		}
 	}
 }
+
+// jwtSession is a session for JWT access tokens and ID tokens.
+type jwtSession struct {
+	*oauthStrat.JWTSession
+	*strategy.DefaultSession
+}
+
+func TestImplicitFlowDoesNotLeakNonceIntoJWTAccessTokens(t *testing.T) {
+	jwtStrategy := &oauthStrat.RS256JWTStrategy{RS256JWTStrategy: idStrategy.RS256JWTStrategy}
+	h := OpenIDConnectImplicitHandler{
+		AuthorizeImplicitGrantTypeHandler: &implicit.AuthorizeImplicitGrantTypeHandler{
+			AccessTokenLifespan: time.Hour,
+			AccessTokenStrategy: jwtStrategy,
+			AccessTokenStorage:  store.NewStore(),
+		},
+		IDTokenHandleHelper: &oidc.IDTokenHandleHelper{
+			IDTokenStrategy: idStrategy,
+		},
+		RS256JWTStrategy: idStrategy.RS256JWTStrategy,
+	}
+
+	// The application copied the nonce into the access token claims by accident.
+	session := &jwtSession{
+		JWTSession: &oauthStrat.JWTSession{JWTClaims: &jwt.JWTClaims{
+			Subject:   "peter",
+			ExpiresAt: time.Now().Add(time.Hour),
+			Extra:     map[string]interface{}{"nonce": "11111111111111111111111"},
+		}},
+		DefaultSession: &strategy.DefaultSession{Claims: &jwt.IDTokenClaims{Subject: "peter"}},
+	}
+
+	areq := fosite.NewAuthorizeRequest()
+	areq.Form.Set("nonce", "11111111111111111111111")
+	areq.ResponseTypes = fosite.Arguments{"token", "id_token"}
+	areq.Scopes = fosite.ScopeArguments{"openid"}
+	areq.Client = &fosite.DefaultClient{
+		GrantTypes:    fosite.Arguments{"implicit"},
+		ResponseTypes: fosite.Arguments{"token", "id_token"},
+	}
+	areq.Session = session
+	aresp := fosite.NewAuthorizeResponse()
+
+	require.Nil(t, h.HandleAuthorizeEndpointRequest(nil, &http.Request{Form: url.Values{}}, areq, aresp))
+
+	accessToken, err := idStrategy.RS256JWTStrategy.Decode(aresp.GetFragment().Get("access_token"))
+	require.Nil(t, err, "%s", err)
+	assert.NotContains(t, accessToken.Claims, "nonce")
+	assert.NotContains(t, accessToken.Claims, "at_hash")
+
+	idToken, err := idStrategy.RS256JWTStrategy.Decode(aresp.GetFragment().Get("id_token"))
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "11111111111111111111111", idToken.Claims["nonce"])
+}
@@ -0,0 +1,68 @@
+package refresh
+
+import (
+	"net/http"
+
+	"github.com/go-errors/errors"
+	. "github.com/ory-am/fosite"
+	. "github.com/ory-am/fosite/handler/oidc"
+	"github.com/ory-am/fosite/handler/oidc/strategy"
+	"github.com/ory-am/fosite/token/jwt"
+	"golang.org/x/net/context"
+)
+
+// OpenIDConnectRefreshHandler issues a new ID token when a refresh token which was granted the openid scope is
+// used, see http://openid.net/specs/openid-connect-core-1_0.html#RefreshTokenResponse
+//
+// The handler does not issue access tokens, use it together with the refresh token grant handler, which restores
+// the session the ID token is issued for.
+type OpenIDConnectRefreshHandler struct {
+	*IDTokenHandleHelper
+
+	// RS256JWTStrategy computes the at_hash claim of refreshed ID tokens. The claim is omitted if nil.
+	RS256JWTStrategy *jwt.RS256JWTStrategy
+}
+
+// HandleTokenEndpointRequest implements fosite.TokenEndpointHandler. The refresh token is validated by the refresh
+// token grant handler.
+func (c *OpenIDConnectRefreshHandler) HandleTokenEndpointRequest(ctx context.Context, r *http.Request, requester AccessRequester) error {
+	if !isResponsible(requester) {
+		return ErrUnknownRequest
+	}
+	return nil
+}
+
+// PopulateTokenEndpointResponse adds a new ID token to the response of the refresh token grant. The ID token keeps
+// the subject, auth_time and nonce of the original ID token, but is issued and expires anew. Claims describing
+// the tokens of the original response, like c_hash, are removed.
+func (c *OpenIDConnectRefreshHandler) PopulateTokenEndpointResponse(ctx context.Context, req *http.Request, requester AccessRequester, responder AccessResponder) error {
+	if !isResponsible(requester) {
+		return ErrUnknownRequest
+	}
+
+	if _, ok := requester.GetSession().(strategy.Session); !ok {
+		return ErrInvalidSession
+	}
+
+	if c.RS256JWTStrategy != nil && responder.GetAccessToken() != "" {
+		hash, err := c.RS256JWTStrategy.Hash([]byte(responder.GetAccessToken()))
+		if err != nil {
+			return err
+		}
+		ctx = strategy.WithTokenHashes(ctx, hash[:c.RS256JWTStrategy.GetSigningMethodLength()/2], nil)
+	}
+
+	if err := c.IssueExplicitIDToken(ctx, req, requester, responder); err != nil {
+		return errors.New(err)
+	}
+	return nil
+}
+
+// isResponsible returns true if an ID token is issued for the refresh request. Clients which may not receive ID
+// tokens at all, e.g. because the openid scope was granted using the resource owner password credentials grant,
+// only receive the refreshed access token.
+func isResponsible(requester AccessRequester) bool {
+	return requester.GetGrantTypes().Exact("refresh_token") &&
+		requester.GetGrantedScopes().Has("openid") &&
+		requester.GetClient().GetResponseTypes().Has("id_token")
+}
@@ -0,0 +1,118 @@
+package refresh
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/oidc"
+	"github.com/ory-am/fosite/handler/oidc/strategy"
+	"github.com/ory-am/fosite/internal"
+	"github.com/ory-am/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var j = &strategy.DefaultStrategy{
+	RS256JWTStrategy: &jwt.RS256JWTStrategy{
+		PrivateKey: internal.MustRSAKey(),
+	},
+}
+
+func TestOpenIDConnectRefreshHandlerIsResponsible(t *testing.T) {
+	h := &OpenIDConnectRefreshHandler{
+		IDTokenHandleHelper: &oidc.IDTokenHandleHelper{IDTokenStrategy: j},
+	}
+	httpreq := &http.Request{PostForm: url.Values{}}
+
+	for k, c := range []struct {
+		description string
+		setup       func(areq *fosite.AccessRequest)
+		expectErr   error
+	}{
+		{
+			description: "should not handle other grant types",
+			setup: func(areq *fosite.AccessRequest) {
+				areq.GrantTypes = fosite.Arguments{"authorization_code"}
+			},
+			expectErr: fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should not handle refresh tokens without the openid scope",
+			setup: func(areq *fosite.AccessRequest) {
+				areq.GrantedScopes = fosite.ScopeArguments{"offline"}
+			},
+			expectErr: fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should not handle clients which may not receive ID tokens",
+			setup: func(areq *fosite.AccessRequest) {
+				areq.Client = &fosite.DefaultClient{ResponseTypes: fosite.Arguments{"code"}}
+			},
+			expectErr: fosite.ErrUnknownRequest,
+		},
+		{
+			description: "should handle refresh tokens which were granted the openid scope",
+			setup:       func(areq *fosite.AccessRequest) {},
+		},
+	} {
+		areq := fosite.NewAccessRequest(&strategy.DefaultSession{})
+		areq.GrantTypes = fosite.Arguments{"refresh_token"}
+		areq.GrantedScopes = fosite.ScopeArguments{"openid", "offline"}
+		areq.Client = &fosite.DefaultClient{ResponseTypes: fosite.Arguments{"code", "id_token"}}
+		c.setup(areq)
+
+		err := h.HandleTokenEndpointRequest(nil, httpreq, areq)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectErr != nil {
+			err = h.PopulateTokenEndpointResponse(nil, httpreq, areq, fosite.NewAccessResponse())
+			assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		}
+	}
+}
+
+func TestOpenIDConnectRefreshHandlerIssuesIDToken(t *testing.T) {
+	authTime := time.Now().Add(-time.Hour).Round(time.Second)
+	session := &strategy.DefaultSession{
+		Claims: &jwt.IDTokenClaims{
+			Subject:   "peter",
+			Nonce:     "some-secure-nonce-state",
+			AuthTime:  authTime,
+			IssuedAt:  authTime,
+			ExpiresAt: authTime.Add(time.Minute),
+			CodeHash:  []byte("some-code-hash"),
+		},
+		Headers: &jwt.Headers{},
+	}
+	areq := fosite.NewAccessRequest(session)
+	areq.GrantTypes = fosite.Arguments{"refresh_token"}
+	areq.GrantedScopes = fosite.ScopeArguments{"openid", "offline"}
+	areq.Client = &fosite.DefaultClient{ID: "foo", ResponseTypes: fosite.Arguments{"code", "id_token"}}
+
+	aresp := fosite.NewAccessResponse()
+	aresp.SetAccessToken("some-access-token")
+
+	h := &OpenIDConnectRefreshHandler{
+		IDTokenHandleHelper: &oidc.IDTokenHandleHelper{IDTokenStrategy: j},
+		RS256JWTStrategy:    j.RS256JWTStrategy,
+	}
+	require.Nil(t, h.PopulateTokenEndpointResponse(nil, &http.Request{PostForm: url.Values{}}, areq, aresp))
+
+	idToken, ok := aresp.GetExtra("id_token").(string)
+	require.True(t, ok)
+	token, err := j.RS256JWTStrategy.Decode(idToken)
+	require.Nil(t, err)
+	require.True(t, token.Valid)
+
+	assert.Equal(t, "peter", token.Claims["sub"])
+	assert.Equal(t, "foo", token.Claims["aud"])
+	assert.Equal(t, "some-secure-nonce-state", token.Claims["nonce"])
+	assert.Equal(t, float64(authTime.Unix()), token.Claims["auth_time"])
+	assert.True(t, token.Claims["iat"].(float64) > float64(authTime.Unix()))
+	assert.True(t, token.Claims["exp"].(float64) > float64(time.Now().Unix()))
+	assert.NotEmpty(t, token.Claims["at_hash"])
+	assert.Empty(t, token.Claims["c_hash"])
+}
@@ -1,6 +1,7 @@
 package strategy
 
 import (
+	"crypto/rsa"
 	"net/http"
 
 	"time"
@@ -22,6 +23,15 @@ type Session interface {
 type DefaultSession struct {
 	Claims  *jwt.IDTokenClaims
 	Headers *jwt.Headers
+
+	// Subject is the internal identifier of the end-user, e.g. the key of the user account in your storage. The sub
+	// claim of ID tokens and UserInfo responses is Claims.Subject, which allows exposing a different identifier,
+	// for example a pairwise one (see PairwiseSubject). Subject is used as sub claim if Claims.Subject is empty.
+	Subject string
+
+	// NotBeforeOffset delays the activation of ID tokens issued for this session, see fosite.NotBeforeSession.
+	// It is ignored if Claims.NotBefore is set.
+	NotBeforeOffset time.Duration
 }
 
 func (s *DefaultSession) IDTokenHeaders() *jwt.Headers {
@@ -38,14 +48,82 @@ func (s *DefaultSession) IDTokenClaims() *jwt.IDTokenClaims {
 	return s.Claims
 }
 
+// GetSubject implements fosite.IntrospectionSession. It returns the internal subject, or the sub claim if no
+// internal subject is set.
+func (s *DefaultSession) GetSubject() string {
+	if s.Subject != "" {
+		return s.Subject
+	}
+	return s.IDTokenClaims().Subject
+}
+
+// GetNotBeforeOffset implements fosite.NotBeforeSession.
+func (s *DefaultSession) GetNotBeforeOffset() time.Duration {
+	return s.NotBeforeOffset
+}
+
+// GetAuthTime implements fosite.IntrospectionSession.
+func (s *DefaultSession) GetAuthTime() time.Time {
+	return s.IDTokenClaims().AuthTime
+}
+
+// GetAuthenticationContextClassReference implements fosite.IntrospectionSession.
+func (s *DefaultSession) GetAuthenticationContextClassReference() string {
+	return s.IDTokenClaims().AuthenticationContextClassReference
+}
+
+type tokenHashesContextKey struct{}
+
+type tokenHashes struct {
+	accessToken []byte
+	code        []byte
+}
+
+// WithTokenHashes returns a copy of ctx carrying the at_hash and c_hash claims of the ID token generated with it.
+// The hashes describe the tokens of a single response and are therefore not part of the session.
+func WithTokenHashes(ctx context.Context, accessTokenHash, codeHash []byte) context.Context {
+	if ctx == nil {
+		ctx = fosite.NewContext()
+	}
+	return context.WithValue(ctx, tokenHashesContextKey{}, tokenHashes{accessToken: accessTokenHash, code: codeHash})
+}
+
+// tokenHashesFromContext returns the at_hash and c_hash claims added to ctx using WithTokenHashes.
+func tokenHashesFromContext(ctx context.Context) (accessTokenHash, codeHash []byte) {
+	if ctx == nil {
+		return nil, nil
+	}
+	hashes, _ := ctx.Value(tokenHashesContextKey{}).(tokenHashes)
+	return hashes.accessToken, hashes.code
+}
+
 type DefaultStrategy struct {
 	*jwt.RS256JWTStrategy
 
+	// ES256JWTStrategy is used for clients requiring ES256 signed ID tokens. ES256 is not supported if nil.
+	ES256JWTStrategy *jwt.ES256JWTStrategy
+
 	Expiry time.Duration
+
+	// Issuer is used as the iss claim of ID tokens if neither the session nor the fosite.Configuration of the
+	// request define one.
 	Issuer string
+
+	// RequireEssentialClaims fails requests for ID tokens which miss a claim the client requested as essential using
+	// the claims parameter, see fosite.EssentialClaimsError. Missing claims are omitted if false.
+	RequireEssentialClaims bool
 }
 
-func (h DefaultStrategy) GenerateIDToken(_ context.Context, _ *http.Request, requester fosite.Requester) (token string, err error) {
+// GetSupportedSigningAlgorithms returns the JWS algorithms this strategy is able to sign ID tokens with.
+func (h DefaultStrategy) GetSupportedSigningAlgorithms() []string {
+	algs := []string{"RS256"}
+	if h.ES256JWTStrategy != nil {
+		algs = append(algs, "ES256")
+	}
+	return algs
+}
+
+func (h DefaultStrategy) GenerateIDToken(ctx context.Context, _ *http.Request, requester fosite.Requester) (token string, err error) {
 	if h.Expiry == 0 {
 		h.Expiry = defaultExpiryTime
 	}
@@ -55,7 +133,17 @@ func (h DefaultStrategy) GenerateIDToken(_ context.Context, _ *http.Request, req
 		return "", errors.New("Session must be of type strategy.Session")
 	}
 
-	claims := sess.IDTokenClaims()
+	// The claims of the session are copied, so that the claims of one ID token, like exp or at_hash, do not end up
+	// in the ID tokens issued for the same session later on.
+	claims := *sess.IDTokenClaims()
+	claims.Extra = jwt.Copy(claims.Extra)
+	claims.AccessTokenHash, claims.CodeHash = tokenHashesFromContext(ctx)
+	if isRefreshRequest(requester) {
+		// ID tokens issued when refreshing expire anew.
+		claims.ExpiresAt = time.Time{}
+	}
+
+	claims.Subject = SubjectClaim(sess)
 	if requester.GetRequestForm().Get("max_age") != "" && (claims.AuthTime.IsZero() || claims.AuthTime.After(time.Now())) {
 		return "", errors.New("Authentication time claim is required when max_age is set and can not be in the future")
 	} else if claims.Subject == "" {
@@ -66,18 +154,122 @@ func (h DefaultStrategy) GenerateIDToken(_ context.Context, _ *http.Request, req
 		return "", errors.New("Expiry claim can not be in the past")
 	}
 
-	nonce := requester.GetRequestForm().Get("nonce")
-	// OPTIONAL. String value used to associate a Client session with an ID Token, and to mitigate replay attacks.
-	// Although optional, this is considered good practice and therefore enforced.
-	if len(nonce) < fosite.MinParameterEntropy {
-		// We're assuming that using less then 8 characters for the state can not be considered "unguessable"
-		return "", errors.New(fosite.ErrInsufficientEntropy)
-	}
+	// ID tokens issued when refreshing keep the nonce of the original ID token, the refresh request has none, see
+	// http://openid.net/specs/openid-connect-core-1_0.html#RefreshTokenResponse
+	if !isRefreshRequest(requester) {
+		nonce := requester.GetRequestForm().Get("nonce")
+		// OPTIONAL. String value used to associate a Client session with an ID Token, and to mitigate replay attacks.
+		// Although optional, this is considered good practice and therefore enforced.
+		if len(nonce) < fosite.MinParameterEntropy {
+			// We're assuming that using less then 8 characters for the state can not be considered "unguessable"
+			return "", errors.New(fosite.ErrInsufficientEntropy)
+		}
 
-	claims.Nonce = nonce
+		claims.Nonce = nonce
+	}
+	if claims.Issuer == "" {
+		claims.Issuer = fosite.IssuerFromContext(ctx, h.Issuer)
+	}
 	claims.Audience = requester.GetClient().GetID()
 	claims.IssuedAt = time.Now()
+	if claims.NotBefore.IsZero() {
+		claims.NotBefore = fosite.NotBefore(sess, claims.IssuedAt)
+	}
+
+	if h.RequireEssentialClaims {
+		if err := validateEssentialClaims(requester, &claims); err != nil {
+			return "", err
+		}
+	}
+
+	client := requester.GetClient()
+	switch alg := client.GetIDTokenSignedResponseAlg(); {
+	case alg == "RS256":
+		token, _, err = h.signer(ctx).GenerateWithContext(ctx, &claims, sess.IDTokenHeaders())
+	case alg == "ES256" && h.ES256JWTStrategy != nil:
+		token, _, err = h.ES256JWTStrategy.GenerateWithContext(ctx, &claims, sess.IDTokenHeaders())
+	default:
+		return "", errors.Errorf("ID token signing algorithm %s is not supported", alg)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return encryptIDToken(client, token)
+}
+
+// validateEssentialClaims returns a fosite.EssentialClaimsError if the ID token misses claims the claims parameter
+// of requester marks essential.
+func validateEssentialClaims(requester fosite.Requester, claims *jwt.IDTokenClaims) error {
+	request, err := fosite.ParseClaimsRequest(requester.GetRequestForm().Get("claims"))
+	if err != nil {
+		return err
+	} else if request == nil {
+		return nil
+	}
+
+	available := claims.ToMap()
+	if claims.AuthTime.IsZero() {
+		delete(available, "auth_time")
+	}
+	if missing := request.IDToken.MissingEssentialClaims(available); len(missing) > 0 {
+		return errors.New(&fosite.EssentialClaimsError{Claims: missing})
+	}
+	return nil
+}
+
+// isRefreshRequest returns true if requester is a token request using the refresh_token grant.
+func isRefreshRequest(requester fosite.Requester) bool {
+	ar, ok := requester.(fosite.AccessRequester)
+	return ok && ar.GetGrantTypes().Exact("refresh_token")
+}
 
-	token, _, err = h.RS256JWTStrategy.Generate(claims, sess.IDTokenHeaders())
-	return token, err
+// signer returns the strategy signing the RS256 ID tokens of a request, using the signing key of the
+// fosite.Configuration in ctx if there is one.
+func (h *DefaultStrategy) signer(ctx context.Context) *jwt.RS256JWTStrategy {
+	if key := fosite.SigningKeyFromContext(ctx, nil); key != nil {
+		return &jwt.RS256JWTStrategy{PrivateKey: key}
+	}
+	return h.RS256JWTStrategy
+}
+
+// encryptIDToken nests the signed ID token in a JWE if the client has registered
+// id_token_encrypted_response_alg, see https://openid.net/specs/openid-connect-core-1_0.html#Encryption
+func encryptIDToken(client fosite.Client, token string) (string, error) {
+	alg := client.GetIDTokenEncryptedResponseAlg()
+	if alg == "" {
+		return token, nil
+	}
+
+	key := client.GetJSONWebKeys().Find("enc", alg)
+	if key == nil {
+		return "", errors.Errorf("Client has no public key for ID token encryption algorithm %s", alg)
+	}
+
+	pub, err := key.PublicKey()
+	if err != nil {
+		return "", err
+	}
+
+	rsaKey, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return "", errors.Errorf("ID token encryption algorithm %s requires a RSA key", alg)
+	}
+
+	return jwt.EncryptRSA([]byte(token), rsaKey, alg, client.GetIDTokenEncryptedResponseEnc(), key.KeyID)
+}
+
+// ValidateConfiguration implements fosite.ConfigurationValidator. Keys of fosite.Configuration are not checked,
+// they are only known once a request is served.
+func (h DefaultStrategy) ValidateConfiguration() error {
+	if h.RS256JWTStrategy == nil {
+		return errors.New("RS256 strategy is missing")
+	} else if err := h.RS256JWTStrategy.ValidateConfiguration(); err != nil {
+		return err
+	} else if h.ES256JWTStrategy != nil {
+		if err := h.ES256JWTStrategy.ValidateConfiguration(); err != nil {
+			return err
+		}
+	}
+	return fosite.ValidateIssuer(h.Issuer)
 }
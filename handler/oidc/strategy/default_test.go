@@ -1,14 +1,18 @@
 package strategy
 
 import (
+	"strings"
 	"testing"
 
 	"time"
 
+	"github.com/go-errors/errors"
 	"github.com/ory-am/fosite"
 	"github.com/ory-am/fosite/internal"
+	"github.com/ory-am/fosite/token/jwk"
 	"github.com/ory-am/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var j = &DefaultStrategy{
@@ -96,6 +100,19 @@ func TestGenerateIDToken(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			setup: func() {
+				req = fosite.NewAccessRequest(&DefaultSession{
+					Claims: &jwt.IDTokenClaims{
+						Subject: "peter",
+						Nonce:   "some-secure-nonce-state",
+					},
+					Headers: &jwt.Headers{},
+				})
+				req.GrantTypes = fosite.Arguments{"refresh_token"}
+			},
+			expectErr: false,
+		},
 	} {
 		c.setup()
 		token, err := j.GenerateIDToken(nil, nil, req)
@@ -105,3 +122,212 @@ func TestGenerateIDToken(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateIDTokenWithNotBeforeOffset(t *testing.T) {
+	session := &DefaultSession{
+		Claims:          &jwt.IDTokenClaims{Subject: "peter"},
+		Headers:         &jwt.Headers{},
+		NotBeforeOffset: time.Minute,
+	}
+	req := fosite.NewAccessRequest(session)
+	req.Form.Set("nonce", "some-secure-nonce-state")
+
+	token, err := j.GenerateIDToken(nil, nil, req)
+	require.Nil(t, err, "%s", err)
+	assert.True(t, session.Claims.NotBefore.IsZero(), "the nbf claim is not stored in the session")
+
+	_, err = j.RS256JWTStrategy.Decode(token)
+	assert.NotNil(t, err, "ID tokens must not be accepted before they become valid")
+}
+
+func TestGenerateIDTokenDoesNotModifyTheSession(t *testing.T) {
+	session := &DefaultSession{
+		Claims:  &jwt.IDTokenClaims{Subject: "peter", Extra: map[string]interface{}{"foo": "bar"}},
+		Headers: &jwt.Headers{},
+		Subject: "internal-peter",
+	}
+	req := fosite.NewAuthorizeRequest()
+	req.Session = session
+	req.Form.Set("nonce", "some-secure-nonce-state")
+
+	first, err := j.GenerateIDToken(WithTokenHashes(nil, []byte("access-token-hash"), []byte("code-hash")), nil, req)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, &jwt.IDTokenClaims{Subject: "peter", Extra: map[string]interface{}{"foo": "bar"}}, session.Claims)
+
+	// The second ID token is issued for another response, it does not describe the tokens of the first one.
+	req.Form.Set("nonce", "another-secure-nonce-state")
+	second, err := j.GenerateIDToken(nil, nil, req)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, &jwt.IDTokenClaims{Subject: "peter", Extra: map[string]interface{}{"foo": "bar"}}, session.Claims)
+
+	token, err := j.RS256JWTStrategy.Decode(first)
+	require.Nil(t, err)
+	assert.NotNil(t, token.Claims["at_hash"])
+	assert.NotNil(t, token.Claims["c_hash"])
+	assert.Equal(t, "some-secure-nonce-state", token.Claims["nonce"])
+
+	token, err = j.RS256JWTStrategy.Decode(second)
+	require.Nil(t, err)
+	assert.Nil(t, token.Claims["at_hash"])
+	assert.Nil(t, token.Claims["c_hash"])
+	assert.Equal(t, "another-secure-nonce-state", token.Claims["nonce"])
+	assert.Equal(t, "peter", token.Claims["sub"])
+	assert.Equal(t, "bar", token.Claims["foo"])
+}
+
+func TestGenerateIDTokenWhenRefreshingExpiresAnew(t *testing.T) {
+	session := &DefaultSession{
+		Claims:  &jwt.IDTokenClaims{Subject: "peter", Nonce: "some-secure-nonce-state", ExpiresAt: time.Now().Add(-time.Hour)},
+		Headers: &jwt.Headers{},
+	}
+	req := fosite.NewAccessRequest(session)
+	req.GrantTypes = fosite.Arguments{"refresh_token"}
+
+	token, err := j.GenerateIDToken(nil, nil, req)
+	require.Nil(t, err, "%s", err)
+
+	decoded, err := j.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "some-secure-nonce-state", decoded.Claims["nonce"])
+}
+
+func TestGenerateIDTokenWithUniqueID(t *testing.T) {
+	req := fosite.NewAccessRequest(&DefaultSession{Claims: &jwt.IDTokenClaims{Subject: "peter"}, Headers: &jwt.Headers{}})
+	req.Form.Set("nonce", "some-secure-nonce-state")
+
+	var ids []interface{}
+	for i := 0; i < 2; i++ {
+		token, err := j.GenerateIDToken(nil, nil, req)
+		require.Nil(t, err, "%s", err)
+		decoded, err := j.RS256JWTStrategy.Decode(token)
+		require.Nil(t, err, "%s", err)
+		assert.NotEmpty(t, decoded.Claims["jti"])
+		ids = append(ids, decoded.Claims["jti"])
+	}
+	assert.NotEqual(t, ids[0], ids[1], "ID tokens issued for the same session must have different IDs")
+}
+
+func TestGenerateIDTokenWithEssentialClaims(t *testing.T) {
+	strict := &DefaultStrategy{RS256JWTStrategy: j.RS256JWTStrategy, RequireEssentialClaims: true}
+	newRequest := func(claims string) *fosite.AccessRequest {
+		req := fosite.NewAccessRequest(&DefaultSession{
+			Claims: &jwt.IDTokenClaims{
+				Subject: "peter",
+				Extra:   map[string]interface{}{"email": "peter@example.com"},
+			},
+			Headers: &jwt.Headers{},
+		})
+		req.Form.Set("nonce", "some-secure-nonce-state")
+		req.Form.Set("claims", claims)
+		return req
+	}
+
+	for k, c := range []struct {
+		strategy      *DefaultStrategy
+		claims        string
+		expectMissing []string
+	}{
+		{strategy: strict, claims: ""},
+		{strategy: strict, claims: `{"id_token": {"email": {"essential": true}, "auth_time": null}}`},
+		{strategy: strict, claims: `{"userinfo": {"phone_number": {"essential": true}}}`},
+		{strategy: strict, claims: `{"id_token": {"auth_time": {"essential": true}, "acr": {"essential": true}}}`, expectMissing: []string{"acr", "auth_time"}},
+		{strategy: j, claims: `{"id_token": {"auth_time": {"essential": true}, "acr": {"essential": true}}}`},
+	} {
+		token, err := c.strategy.GenerateIDToken(nil, nil, newRequest(c.claims))
+		if c.expectMissing == nil {
+			require.Nil(t, err, "%d: %s", k, err)
+			assert.NotEmpty(t, token, "%d", k)
+			continue
+		}
+
+		require.NotNil(t, err, "%d", k)
+		ee, ok := err.(*errors.Error).Err.(*fosite.EssentialClaimsError)
+		require.True(t, ok, "%d: %s", k, err)
+		assert.Equal(t, c.expectMissing, ee.Claims, "%d", k)
+	}
+}
+
+func TestGenerateIDTokenWithClientSigningAlgorithm(t *testing.T) {
+	es := &DefaultStrategy{
+		RS256JWTStrategy: j.RS256JWTStrategy,
+		ES256JWTStrategy: &jwt.ES256JWTStrategy{
+			PrivateKey: internal.MustECDSAKey(),
+		},
+	}
+	assert.Equal(t, []string{"RS256"}, j.GetSupportedSigningAlgorithms())
+	assert.Equal(t, []string{"RS256", "ES256"}, es.GetSupportedSigningAlgorithms())
+
+	for k, c := range []struct {
+		strategy  *DefaultStrategy
+		alg       string
+		expectErr bool
+	}{
+		{strategy: j, alg: "", expectErr: false},
+		{strategy: j, alg: "RS256", expectErr: false},
+		{strategy: j, alg: "ES256", expectErr: true},
+		{strategy: es, alg: "ES256", expectErr: false},
+		{strategy: es, alg: "HS256", expectErr: true},
+		{strategy: es, alg: "none", expectErr: true},
+	} {
+		req := fosite.NewAccessRequest(&DefaultSession{
+			Claims: &jwt.IDTokenClaims{
+				Subject: "peter",
+			},
+			Headers: &jwt.Headers{},
+		})
+		req.Client = &fosite.DefaultClient{IDTokenSignedResponseAlg: c.alg}
+		req.Form.Set("nonce", "some-secure-nonce-state")
+
+		token, err := c.strategy.GenerateIDToken(nil, nil, req)
+		assert.Equal(t, c.expectErr, err != nil, "%d: %s", k, err)
+		if !c.expectErr {
+			assert.NotEmpty(t, token)
+		}
+	}
+}
+
+func TestGenerateEncryptedIDToken(t *testing.T) {
+	key := internal.MustRSAKey()
+	keys := &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{
+		jwk.NewRSAPublicKey("sig-key", "sig", &internal.MustRSAKey().PublicKey),
+		jwk.NewRSAPublicKey("enc-key", "enc", &key.PublicKey),
+	}}
+
+	for k, c := range []struct {
+		client    *fosite.DefaultClient
+		expectErr bool
+		parts     int
+	}{
+		{client: &fosite.DefaultClient{}, parts: 3},
+		{client: &fosite.DefaultClient{IDTokenEncryptedResponseAlg: "RSA-OAEP", JSONWebKeys: keys}, parts: 5},
+		{client: &fosite.DefaultClient{IDTokenEncryptedResponseAlg: "RSA-OAEP-256", IDTokenEncryptedResponseEnc: "A256GCM", JSONWebKeys: keys}, parts: 5},
+		{client: &fosite.DefaultClient{IDTokenEncryptedResponseAlg: "RSA-OAEP"}, expectErr: true},
+		{client: &fosite.DefaultClient{IDTokenEncryptedResponseAlg: "RSA1_5", JSONWebKeys: keys}, expectErr: true},
+		{client: &fosite.DefaultClient{IDTokenEncryptedResponseAlg: "RSA-OAEP", IDTokenEncryptedResponseEnc: "A192GCM", JSONWebKeys: keys}, expectErr: true},
+	} {
+		req := fosite.NewAccessRequest(&DefaultSession{
+			Claims: &jwt.IDTokenClaims{
+				Subject: "peter",
+			},
+			Headers: &jwt.Headers{},
+		})
+		req.Client = c.client
+		req.Form.Set("nonce", "some-secure-nonce-state")
+
+		token, err := j.GenerateIDToken(nil, nil, req)
+		require.Equal(t, c.expectErr, err != nil, "%d: %s", k, err)
+		if c.expectErr {
+			continue
+		}
+
+		require.Len(t, strings.Split(token, "."), c.parts, "%d", k)
+		if c.parts == 5 {
+			decrypted, err := jwt.DecryptRSA(token, key)
+			require.Nil(t, err, "%d: %s", k, err)
+			token = string(decrypted)
+		}
+
+		_, err = j.Decode(token)
+		assert.Nil(t, err, "%d: %s", k, err)
+	}
+}
@@ -0,0 +1,56 @@
+package strategy
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+)
+
+// SubjectClaim returns the sub claim of ID tokens and UserInfo responses issued for sess. This is the subject of
+// the ID token claims or, if empty, the internal subject of sessions implementing fosite.IntrospectionSession.
+func SubjectClaim(sess Session) string {
+	if subject := sess.IDTokenClaims().Subject; subject != "" {
+		return subject
+	} else if is, ok := sess.(fosite.IntrospectionSession); ok {
+		return is.GetSubject()
+	}
+	return ""
+}
+
+// PairwiseSubject returns the pairwise subject identifier of the end-user identified by subject for clients of
+// sectorIdentifier, see http://openid.net/specs/openid-connect-core-1_0.html#PairwiseAlg
+//
+//	sub = base64url(SHA-256(sector_identifier || local_account_id || salt))
+//
+// Pairwise identifiers prevent clients of different sectors from correlating the end-user's activities. Set the
+// result as sub claim and keep the internal identifier in DefaultSession.Subject.
+func PairwiseSubject(sectorIdentifier, subject string, salt []byte) string {
+	h := sha256.New()
+	h.Write([]byte(sectorIdentifier))
+	h.Write([]byte(subject))
+	h.Write(salt)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// SectorIdentifier returns the sector identifier of client, which is the host of its redirect URIs. Returns an error
+// if the redirect URIs use more than one host, because such clients must register a sector_identifier_uri.
+func SectorIdentifier(client fosite.Client) (string, error) {
+	var host string
+	for _, raw := range client.GetRedirectURIs() {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "", errors.New(err)
+		} else if host != "" && u.Host != host {
+			return "", errors.New("Redirect URIs using more than one host require a sector identifier URI")
+		}
+		host = u.Host
+	}
+
+	if host == "" {
+		return "", errors.New("Client has no redirect URIs")
+	}
+	return host, nil
+}
@@ -0,0 +1,61 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubjectClaim(t *testing.T) {
+	sess := &DefaultSession{Subject: "internal-1234"}
+	assert.Equal(t, "internal-1234", SubjectClaim(sess))
+	assert.Equal(t, "internal-1234", sess.GetSubject())
+
+	sess.Claims = &jwt.IDTokenClaims{Subject: "peter"}
+	assert.Equal(t, "peter", SubjectClaim(sess))
+	assert.Equal(t, "internal-1234", sess.GetSubject())
+
+	sess.Subject = ""
+	assert.Equal(t, "peter", sess.GetSubject())
+}
+
+func TestPairwiseSubject(t *testing.T) {
+	a := PairwiseSubject("client-a.com", "internal-1234", []byte("salt"))
+	assert.Equal(t, a, PairwiseSubject("client-a.com", "internal-1234", []byte("salt")))
+	assert.NotEqual(t, a, PairwiseSubject("client-b.com", "internal-1234", []byte("salt")))
+	assert.NotEqual(t, a, PairwiseSubject("client-a.com", "internal-5678", []byte("salt")))
+	assert.NotContains(t, a, "internal-1234")
+}
+
+func TestSectorIdentifier(t *testing.T) {
+	host, err := SectorIdentifier(&fosite.DefaultClient{RedirectURIs: []string{"https://foo.com/cb", "https://foo.com/other"}})
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "foo.com", host)
+
+	_, err = SectorIdentifier(&fosite.DefaultClient{RedirectURIs: []string{"https://foo.com/cb", "https://bar.com/cb"}})
+	assert.NotNil(t, err)
+
+	_, err = SectorIdentifier(&fosite.DefaultClient{})
+	assert.NotNil(t, err)
+}
+
+func TestGenerateIDTokenWithInternalSubject(t *testing.T) {
+	sess := &DefaultSession{
+		Claims:  &jwt.IDTokenClaims{Subject: PairwiseSubject("foo.com", "internal-1234", []byte("salt"))},
+		Headers: &jwt.Headers{},
+		Subject: "internal-1234",
+	}
+	req := fosite.NewAccessRequest(sess)
+	req.Client = &fosite.DefaultClient{}
+	req.Form.Set("nonce", "some-secure-nonce-state")
+
+	token, err := j.GenerateIDToken(nil, nil, req)
+	require.Nil(t, err, "%s", err)
+
+	parsed, err := j.RS256JWTStrategy.Decode(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, sess.Claims.Subject, parsed.Claims["sub"])
+}
@@ -0,0 +1,137 @@
+package userinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/oidc/strategy"
+)
+
+// DefaultScopeClaims maps the scopes defined in http://openid.net/specs/openid-connect-core-1_0.html#ScopeClaims
+// to the claims they release.
+var DefaultScopeClaims = map[string][]string{
+	"profile": {
+		"name", "family_name", "given_name", "middle_name", "nickname", "preferred_username", "profile",
+		"picture", "website", "gender", "birthdate", "zoneinfo", "locale", "updated_at",
+	},
+	"email":   {"email", "email_verified"},
+	"address": {"address"},
+	"phone":   {"phone_number", "phone_number_verified"},
+}
+
+// UserinfoHandler implements the UserInfo endpoint as defined in
+// http://openid.net/specs/openid-connect-core-1_0.html#UserInfo
+//
+// The access token is validated using Provider. Claims are taken from the session the token was issued with,
+// which therefore must implement strategy.Session.
+type UserinfoHandler struct {
+	Provider fosite.OAuth2Provider
+
+	// Audience is the identifier of the UserInfo endpoint, for example the issuer URL. If set, only access tokens
+	// which include Audience in their audience are accepted.
+	Audience string
+
+	// ScopeClaims maps scopes to the claims they release. A claim is only returned if one of the scopes releasing
+	// it was granted, the sub claim is always returned. Defaults to DefaultScopeClaims.
+	ScopeClaims map[string][]string
+
+	// RequireEssentialClaims rejects requests with insufficient_scope if a claim the client requested as essential
+	// for the UserInfo endpoint is not released, see fosite.ClaimsRequest. The claims parameter of the authorize
+	// request is passed on to the tokens issued for it, see fosite.CopyClaimsRequest. Missing claims are omitted if
+	// false.
+	RequireEssentialClaims bool
+
+	// NewSession returns an empty session used for looking up the access token. Defaults to strategy.DefaultSession.
+	NewSession func() interface{}
+}
+
+func (h *UserinfoHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	token := fosite.AccessTokenFromRequest(req)
+	if token == "" {
+		// http://tools.ietf.org/html/rfc6750#section-3.1
+		// If the request lacks any authentication information, the resource server SHOULD NOT include an error code
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var session interface{} = &strategy.DefaultSession{}
+	if h.NewSession != nil {
+		session = h.NewSession()
+	}
+
+	ar, err := h.Provider.ValidateToken(fosite.WithAudience(fosite.NewContext(), h.Audience), token, session, "", "openid")
+	if errors.Is(err, fosite.ErrRequestForbidden) {
+		writeError(rw, http.StatusForbidden, "insufficient_scope", "The access token was not granted the openid scope")
+		return
+	} else if err != nil {
+		writeError(rw, http.StatusUnauthorized, "invalid_token", "The access token is invalid")
+		return
+	}
+
+	if !fosite.HasGrantedAudience(ar, h.Audience) {
+		writeError(rw, http.StatusUnauthorized, "invalid_token", "The access token is not intended for the userinfo endpoint")
+		return
+	}
+
+	sess, ok := ar.GetSession().(strategy.Session)
+	if !ok {
+		http.Error(rw, "Session must be of type strategy.Session", http.StatusInternalServerError)
+		return
+	}
+
+	claims := h.getClaims(sess, ar.GetGrantedScopes())
+	if h.RequireEssentialClaims {
+		// The claims parameter was validated when the token was requested.
+		if request, _ := fosite.ParseClaimsRequest(ar.GetRequestForm().Get("claims")); request != nil {
+			if missing := request.UserInfo.MissingEssentialClaims(claims); len(missing) > 0 {
+				writeError(rw, http.StatusForbidden, "insufficient_scope", (&fosite.EssentialClaimsError{Claims: missing}).Error())
+				return
+			}
+		}
+	}
+
+	js, err := json.Marshal(claims)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Header().Set("Pragma", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(js)
+}
+
+// getClaims returns the end-user claims of the session released by the granted scopes. Claims describing the
+// ID token itself, such as iss or exp, are not part of the UserInfo response.
+func (h *UserinfoHandler) getClaims(sess strategy.Session, granted fosite.Arguments) map[string]interface{} {
+	scopeClaims := h.ScopeClaims
+	if scopeClaims == nil {
+		scopeClaims = DefaultScopeClaims
+	}
+
+	claims := sess.IDTokenClaims()
+	ret := make(map[string]interface{})
+	for _, scope := range granted {
+		for _, claim := range scopeClaims[scope] {
+			if v, ok := claims.Extra[claim]; ok {
+				ret[claim] = v
+			}
+		}
+	}
+
+	// The sub Claim MUST always be returned in the UserInfo Response.
+	ret["sub"] = strategy.SubjectClaim(sess)
+	return ret
+}
+
+// writeError writes an error as defined in https://tools.ietf.org/html/rfc6750#section-3
+func writeError(rw http.ResponseWriter, status int, code, description string) {
+	rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="%s", error_description="%s"`, code, description))
+	rw.WriteHeader(status)
+}
@@ -0,0 +1,218 @@
+package userinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/oidc/strategy"
+	"github.com/ory-am/fosite/internal"
+	"github.com/ory-am/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestUserinfoHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := internal.NewMockAuthorizedRequestValidator(ctrl)
+	defer ctrl.Finish()
+
+	f := fosite.NewFosite(nil)
+	f.AuthorizedRequestValidators.Append(validator)
+	h := &UserinfoHandler{Provider: f, Audience: "https://auth.example.com"}
+
+	grant := func(audience []string, scopes ...string) func(context.Context, *http.Request, fosite.AccessRequester) {
+		return func(_ context.Context, _ *http.Request, ar fosite.AccessRequester) {
+			ar.(*fosite.AccessRequest).GrantedAudience = audience
+			ar.(*fosite.AccessRequest).GrantedScopes = scopes
+			ar.(*fosite.AccessRequest).Session = &strategy.DefaultSession{Claims: &jwt.IDTokenClaims{
+				Subject: "peter",
+				Issuer:  "https://auth.example.com",
+				Extra: map[string]interface{}{
+					"name":           "Peter",
+					"email":          "peter@example.com",
+					"email_verified": true,
+					"internal_id":    "1234",
+				},
+			}}
+		}
+	}
+
+	for k, c := range []struct {
+		description  string
+		header       string
+		setup        func()
+		expectStatus int
+		expectHeader string
+		expectClaims map[string]interface{}
+	}{
+		{
+			description:  "should fail because no token was sent",
+			setup:        func() {},
+			expectStatus: http.StatusUnauthorized,
+			expectHeader: "Bearer",
+		},
+		{
+			description: "should fail because the token is invalid",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(fosite.ErrRequestUnauthorized)
+			},
+			expectStatus: http.StatusUnauthorized,
+			expectHeader: `Bearer error="invalid_token", error_description="The access token is invalid"`,
+		},
+		{
+			description: "should fail because the openid scope was not granted",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://auth.example.com"}, "foo")).Return(nil)
+			},
+			expectStatus: http.StatusForbidden,
+			expectHeader: `Bearer error="insufficient_scope", error_description="The access token was not granted the openid scope"`,
+		},
+		{
+			description: "should fail because the token was issued for another audience",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://api.example.com"}, "openid")).Return(nil)
+			},
+			expectStatus: http.StatusUnauthorized,
+			expectHeader: `Bearer error="invalid_token", error_description="The access token is not intended for the userinfo endpoint"`,
+		},
+		{
+			description: "should pass and only release sub",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://auth.example.com"}, "openid")).Return(nil)
+			},
+			expectStatus: http.StatusOK,
+			expectClaims: map[string]interface{}{"sub": "peter"},
+		},
+		{
+			description: "should pass and release profile claims",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://auth.example.com"}, "openid", "profile")).Return(nil)
+			},
+			expectStatus: http.StatusOK,
+			expectClaims: map[string]interface{}{"sub": "peter", "name": "Peter"},
+		},
+		{
+			description: "should pass and release profile and email claims",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://auth.example.com"}, "openid", "profile", "email")).Return(nil)
+			},
+			expectStatus: http.StatusOK,
+			expectClaims: map[string]interface{}{"sub": "peter", "name": "Peter", "email": "peter@example.com", "email_verified": true},
+		},
+	} {
+		c.setup()
+		req, _ := http.NewRequest("GET", "/userinfo", nil)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		assert.Equal(t, c.expectStatus, rw.Code, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectHeader, rw.Header().Get("WWW-Authenticate"), "(%d) %s", k, c.description)
+		if c.expectClaims != nil {
+			var claims map[string]interface{}
+			assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &claims), "(%d) %s", k, c.description)
+			assert.Equal(t, c.expectClaims, claims, "(%d) %s", k, c.description)
+		}
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestUserinfoHandlerWithEssentialClaims(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := internal.NewMockAuthorizedRequestValidator(ctrl)
+	defer ctrl.Finish()
+
+	f := fosite.NewFosite(nil)
+	f.AuthorizedRequestValidators.Append(validator)
+
+	grant := func(claims string) func(context.Context, *http.Request, fosite.AccessRequester) {
+		return func(_ context.Context, _ *http.Request, ar fosite.AccessRequester) {
+			ar.(*fosite.AccessRequest).GrantedScopes = fosite.ScopeArguments{"openid", "email"}
+			ar.(*fosite.AccessRequest).Form.Set("claims", claims)
+			ar.(*fosite.AccessRequest).Session = &strategy.DefaultSession{Claims: &jwt.IDTokenClaims{
+				Subject: "peter",
+				Extra:   map[string]interface{}{"email": "peter@example.com", "name": "Peter"},
+			}}
+		}
+	}
+
+	for k, c := range []struct {
+		description  string
+		require      bool
+		claims       string
+		expectStatus int
+		expectHeader string
+	}{
+		{
+			description:  "should pass because the essential claims are released",
+			require:      true,
+			claims:       `{"userinfo": {"email": {"essential": true}, "phone_number": null}}`,
+			expectStatus: http.StatusOK,
+		},
+		{
+			description:  "should pass because essential claims are only requested for the ID token",
+			require:      true,
+			claims:       `{"id_token": {"phone_number": {"essential": true}}}`,
+			expectStatus: http.StatusOK,
+		},
+		{
+			description:  "should fail because essential claims are not available or not released",
+			require:      true,
+			claims:       `{"userinfo": {"email": {"essential": true}, "phone_number": {"essential": true}, "name": {"essential": true}}}`,
+			expectStatus: http.StatusForbidden,
+			expectHeader: `Bearer error="insufficient_scope", error_description="Essential claims are not available: name, phone_number"`,
+		},
+		{
+			description:  "should pass and omit missing essential claims",
+			claims:       `{"userinfo": {"phone_number": {"essential": true}}}`,
+			expectStatus: http.StatusOK,
+		},
+	} {
+		h := &UserinfoHandler{Provider: f, RequireEssentialClaims: c.require}
+		validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant(c.claims)).Return(nil)
+
+		req, _ := http.NewRequest("GET", "/userinfo", nil)
+		req.Header.Set("Authorization", "Bearer some-token")
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		assert.Equal(t, c.expectStatus, rw.Code, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectHeader, rw.Header().Get("WWW-Authenticate"), "(%d) %s", k, c.description)
+	}
+}
+
+func TestGetClaimsWithCustomScopeClaims(t *testing.T) {
+	h := &UserinfoHandler{ScopeClaims: map[string][]string{"internal": {"internal_id"}}}
+	sess := &strategy.DefaultSession{Claims: &jwt.IDTokenClaims{
+		Subject: "peter",
+		Extra:   map[string]interface{}{"name": "Peter", "internal_id": "1234"},
+	}}
+
+	assert.Equal(t, map[string]interface{}{"sub": "peter"}, h.getClaims(sess, fosite.Arguments{"openid", "profile"}))
+	assert.Equal(t, map[string]interface{}{"sub": "peter", "internal_id": "1234"}, h.getClaims(sess, fosite.Arguments{"internal"}))
+
+	// The sub claim can not be overwritten through scope claims.
+	h.ScopeClaims = map[string][]string{"foo": {"sub"}}
+	sess.Claims.Extra["sub"] = "not-peter"
+	assert.Equal(t, map[string]interface{}{"sub": "peter"}, h.getClaims(sess, fosite.Arguments{"foo"}))
+}
+
+func TestGetClaimsExposesSubjectClaim(t *testing.T) {
+	h := &UserinfoHandler{}
+	sess := &strategy.DefaultSession{Subject: "internal-1234", Claims: &jwt.IDTokenClaims{Subject: "public-peter"}}
+	assert.Equal(t, map[string]interface{}{"sub": "public-peter"}, h.getClaims(sess, fosite.Arguments{"openid"}))
+
+	sess.Claims.Subject = ""
+	assert.Equal(t, map[string]interface{}{"sub": "internal-1234"}, h.getClaims(sess, fosite.Arguments{"openid"}))
+}
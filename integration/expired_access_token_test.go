@@ -0,0 +1,72 @@
+package integration_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestExpiredAccessTokensAreRejected(t *testing.T) {
+	expiryStore := &store.Store{
+		Clients:        fositeStore.Clients,
+		AuthorizeCodes: map[string]fosite.Requester{},
+		Implicit:       map[string]fosite.Requester{},
+		AccessTokens:   map[string]fosite.Requester{},
+		RefreshTokens:  map[string]fosite.Requester{},
+		IDSessions:     map[string]fosite.Requester{},
+	}
+	f := fosite.NewFosite(expiryStore)
+	ts := mockServer(t, f, nil)
+	defer ts.Close()
+
+	f.TokenEndpointHandlers.Append(&client.ClientCredentialsGrantHandler{
+		HandleHelper: &core.HandleHelper{
+			AccessTokenStrategy: hmacStrategy,
+			AccessTokenStorage:  expiryStore,
+			AccessTokenLifespan: time.Hour,
+		},
+	})
+	validator := &core.CoreValidator{AccessTokenStrategy: hmacStrategy, AccessTokenStorage: expiryStore}
+	f.AuthorizedRequestValidators.Append(validator)
+	f.TokenIntrospectors.Append(validator)
+
+	token, err := newOAuth2AppClient(ts).Token(oauth2.NoContext)
+	require.Nil(t, err)
+
+	validate := func() error {
+		req := &http.Request{Header: http.Header{"Authorization": {"Bearer " + token.AccessToken}}}
+		_, err := f.ValidateRequestAuthorization(nil, req, nil)
+		return err
+	}
+	introspect := func() fosite.IntrospectionResponder {
+		req, err := http.NewRequest("POST", ts.URL+"/introspect", strings.NewReader(url.Values{"token": {token.AccessToken}}.Encode()))
+		require.Nil(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth("my-client", "foobar")
+		response, err := f.NewIntrospectionRequest(nil, req, nil)
+		require.Nil(t, err)
+		return response
+	}
+
+	assert.Nil(t, validate())
+	assert.True(t, introspect().IsActive())
+
+	// Expired access tokens are kept by the store until they are purged.
+	require.Len(t, expiryStore.AccessTokens, 1)
+	for _, requester := range expiryStore.AccessTokens {
+		requester.SetExpiresAt(fosite.AccessToken, time.Now().Add(-time.Minute))
+	}
+
+	assert.NotNil(t, validate(), "expired access tokens do not authorize requests")
+	assert.False(t, introspect().IsActive(), "expired access tokens are inactive")
+}
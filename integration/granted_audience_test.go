@@ -0,0 +1,52 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestAccessTokensKeepTheirGrantedAudience(t *testing.T) {
+	registered := *fositeStore.Clients["my-client"]
+	registered.Audience = []string{"https://api.example.com"}
+	audienceStore := &store.Store{
+		Clients:        map[string]*fosite.DefaultClient{"my-client": &registered},
+		AuthorizeCodes: map[string]fosite.Requester{},
+		Implicit:       map[string]fosite.Requester{},
+		AccessTokens:   map[string]fosite.Requester{},
+		RefreshTokens:  map[string]fosite.Requester{},
+		IDSessions:     map[string]fosite.Requester{},
+	}
+	f := fosite.NewFosite(audienceStore)
+	ts := mockServer(t, f, nil)
+	defer ts.Close()
+
+	f.TokenEndpointHandlers.Append(&client.ClientCredentialsGrantHandler{
+		HandleHelper: &core.HandleHelper{
+			AccessTokenStrategy: hmacStrategy,
+			AccessTokenStorage:  audienceStore,
+			AccessTokenLifespan: time.Hour,
+		},
+	})
+	f.AuthorizedRequestValidators.Append(&core.CoreValidator{AccessTokenStrategy: hmacStrategy, AccessTokenStorage: audienceStore})
+
+	token, err := newOAuth2AppClient(ts).Token(oauth2.NoContext)
+	require.Nil(t, err)
+
+	_, err = f.ValidateToken(nil, token.AccessToken, nil, "https://api.example.com")
+	assert.Nil(t, err, "%s", err)
+
+	// Registering the client for another audience does not extend the audience of tokens issued before.
+	registered.Audience = []string{"https://api.example.com", "https://admin.example.com"}
+	_, err = f.ValidateToken(nil, token.AccessToken, nil, "https://admin.example.com")
+	assert.NotNil(t, err)
+	_, err = f.ValidateToken(nil, token.AccessToken, nil, "https://api.example.com")
+	assert.Nil(t, err, "%s", err)
+}
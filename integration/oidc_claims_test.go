@@ -0,0 +1,116 @@
+package integration_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/explicit"
+	"github.com/ory-am/fosite/handler/core/refresh"
+	"github.com/ory-am/fosite/handler/oidc"
+	oidcexp "github.com/ory-am/fosite/handler/oidc/explicit"
+	oidcrefresh "github.com/ory-am/fosite/handler/oidc/refresh"
+	"github.com/ory-am/fosite/handler/oidc/strategy"
+	"github.com/ory-am/fosite/handler/oidc/userinfo"
+	"github.com/ory-am/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestOpenIDConnectEssentialClaimsAfterCodeExchange(t *testing.T) {
+	session := &strategy.DefaultSession{
+		Claims: &jwt.IDTokenClaims{
+			Subject: "peter",
+		},
+		Headers: &jwt.Headers{},
+	}
+	claimsStore := &store.Store{
+		Clients:        fositeStore.Clients,
+		AuthorizeCodes: map[string]fosite.Requester{},
+		Implicit:       map[string]fosite.Requester{},
+		AccessTokens:   map[string]fosite.Requester{},
+		RefreshTokens:  map[string]fosite.Requester{},
+		IDSessions:     map[string]fosite.Requester{},
+	}
+	f := fosite.NewFosite(claimsStore)
+	ts := mockServer(t, f, session)
+	defer ts.Close()
+
+	oauthClient := newOAuth2Client(ts)
+	oauthClient.Scopes = []string{"fosite", "openid", "offline"}
+	fositeStore.Clients["my-client"].RedirectURIs[0] = ts.URL + "/callback"
+
+	explicitHandler := &explicit.AuthorizeExplicitGrantTypeHandler{
+		AccessTokenStrategy:       hmacStrategy,
+		RefreshTokenStrategy:      hmacStrategy,
+		AuthorizeCodeStrategy:     hmacStrategy,
+		AuthorizeCodeGrantStorage: claimsStore,
+		AuthCodeLifespan:          time.Minute,
+		AccessTokenLifespan:       time.Hour,
+	}
+	idcHandler := &oidcexp.OpenIDConnectExplicitHandler{
+		OpenIDConnectRequestStorage: claimsStore,
+		IDTokenHandleHelper:         &oidc.IDTokenHandleHelper{IDTokenStrategy: idTokenStrategy},
+	}
+	refreshHandler := &refresh.RefreshTokenGrantHandler{
+		AccessTokenStrategy:      hmacStrategy,
+		RefreshTokenStrategy:     hmacStrategy,
+		RefreshTokenGrantStorage: claimsStore,
+		AccessTokenLifespan:      time.Hour,
+	}
+	// ID tokens issued when refreshing must contain the essential claims.
+	idcRefreshHandler := &oidcrefresh.OpenIDConnectRefreshHandler{
+		IDTokenHandleHelper: &oidc.IDTokenHandleHelper{IDTokenStrategy: &strategy.DefaultStrategy{
+			RS256JWTStrategy:       idTokenStrategy.RS256JWTStrategy,
+			RequireEssentialClaims: true,
+		}},
+	}
+	f.AuthorizeEndpointHandlers.Append(explicitHandler)
+	f.AuthorizeEndpointHandlers.Append(idcHandler)
+	f.TokenEndpointHandlers.Append(explicitHandler)
+	f.TokenEndpointHandlers.Append(idcHandler)
+	f.TokenEndpointHandlers.Append(refreshHandler)
+	f.TokenEndpointHandlers.Append(idcRefreshHandler)
+	f.AuthorizedRequestValidators.Append(&core.CoreValidator{
+		AccessTokenStrategy: hmacStrategy,
+		AccessTokenStorage:  claimsStore,
+	})
+
+	claims := `{"userinfo": {"email": {"essential": true}}, "id_token": {"acr": {"essential": true}}}`
+	resp, err := http.Get(oauthClient.AuthCodeURL("12345678901234567890") + "&nonce=1234567890&claims=" + url.QueryEscape(claims))
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	token, err := oauthClient.Exchange(oauth2.NoContext, resp.Request.URL.Query().Get("code"))
+	require.Nil(t, err)
+	require.NotEmpty(t, token.Extra("id_token"))
+
+	// The UserInfo endpoint requires the openid scope to be granted to the access token.
+	require.Len(t, claimsStore.AccessTokens, 1)
+	for _, requester := range claimsStore.AccessTokens {
+		requester.GrantScope("openid")
+	}
+
+	getUserinfo := func(h *userinfo.UserinfoHandler) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/userinfo", nil)
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+		return rw
+	}
+	assert.Equal(t, http.StatusOK, getUserinfo(&userinfo.UserinfoHandler{Provider: f}).Code)
+
+	rw := getUserinfo(&userinfo.UserinfoHandler{Provider: f, RequireEssentialClaims: true})
+	assert.Equal(t, http.StatusForbidden, rw.Code)
+	assert.Equal(t, `Bearer error="insufficient_scope", error_description="Essential claims are not available: email"`, rw.Header().Get("WWW-Authenticate"))
+
+	_, err = oauthClient.TokenSource(oauth2.NoContext, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), fosite.ErrAccessDenied.Error())
+}
@@ -0,0 +1,76 @@
+package integration_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/explicit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestPurgeExpiredAuthorizeCodesAndAccessTokens(t *testing.T) {
+	purgedStore := &store.Store{
+		Clients:        fositeStore.Clients,
+		AuthorizeCodes: map[string]fosite.Requester{},
+		Implicit:       map[string]fosite.Requester{},
+		AccessTokens:   map[string]fosite.Requester{},
+		RefreshTokens:  map[string]fosite.Requester{},
+		IDSessions:     map[string]fosite.Requester{},
+	}
+	f := fosite.NewFosite(purgedStore)
+	ts := mockServer(t, f, nil)
+	defer ts.Close()
+
+	oauthClient := newOAuth2Client(ts)
+	fositeStore.Clients["my-client"].RedirectURIs[0] = ts.URL + "/callback"
+
+	handler := &explicit.AuthorizeExplicitGrantTypeHandler{
+		AccessTokenStrategy:       hmacStrategy,
+		RefreshTokenStrategy:      hmacStrategy,
+		AuthorizeCodeStrategy:     hmacStrategy,
+		AuthorizeCodeGrantStorage: purgedStore,
+		AuthCodeLifespan:          time.Minute,
+		AccessTokenLifespan:       time.Hour,
+	}
+	f.AuthorizeEndpointHandlers.Append(handler)
+	f.TokenEndpointHandlers.Append(handler)
+	f.AuthorizedRequestValidators.Append(&core.CoreValidator{
+		AccessTokenStrategy: hmacStrategy,
+		AccessTokenStorage:  purgedStore,
+	})
+
+	authorize := func() string {
+		resp, err := http.Get(oauthClient.AuthCodeURL("12345678901234567890"))
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		return resp.Request.URL.Query().Get("code")
+	}
+
+	token, err := oauthClient.Exchange(oauth2.NoContext, authorize())
+	require.Nil(t, err)
+	authorize()
+	require.Len(t, purgedStore.AuthorizeCodes, 1)
+	require.Len(t, purgedStore.AccessTokens, 1)
+
+	now := time.Now()
+	require.Nil(t, f.PurgeExpired(nil, now))
+	assert.Len(t, purgedStore.AuthorizeCodes, 1, "codes which did not expire are kept")
+	assert.Len(t, purgedStore.AccessTokens, 1, "access tokens which did not expire are kept")
+
+	require.Nil(t, f.PurgeExpired(nil, now.Add(2*time.Minute)))
+	assert.Len(t, purgedStore.AuthorizeCodes, 0, "expired codes are purged")
+	assert.Len(t, purgedStore.AccessTokens, 1, "access tokens which did not expire are kept")
+
+	require.Nil(t, f.PurgeExpired(nil, now.Add(2*time.Hour)))
+	assert.Len(t, purgedStore.AccessTokens, 0, "expired access tokens are purged")
+
+	resp, err := oauthClient.Client(oauth2.NoContext, token).Get(ts.URL + "/info")
+	require.Nil(t, err)
+	assert.NotEqual(t, http.StatusNoContent, resp.StatusCode, "purged access tokens are not valid")
+}
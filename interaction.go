@@ -0,0 +1,87 @@
+package fosite
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// AuthenticationTimeSession can be implemented by sessions passed to NewAuthorizeResponse to tell fosite when the
+// end-user authenticated, so that max_age is enforced.
+type AuthenticationTimeSession interface {
+	// GetAuthTime returns the time the end-user authenticated, or the zero time if unknown.
+	GetAuthTime() time.Time
+}
+
+// LoginPromptSession can be implemented by sessions passed to NewAuthorizeResponse to tell fosite whether the
+// end-user authenticated in response to prompt=login, so that prompt=login is enforced.
+type LoginPromptSession interface {
+	// HasAuthenticated returns true if the end-user authenticated while serving this request, e.g. on a login page
+	// the application redirected to before calling NewAuthorizeResponse.
+	HasAuthenticated() bool
+}
+
+// AccountSelectionSession can be implemented by sessions passed to NewAuthorizeResponse in multi-account
+// scenarios to tell fosite whether the end-user chose the account to authorize the request with.
+type AccountSelectionSession interface {
+	// HasSelectedAccount returns true if the end-user chose an account while serving this request, or an account
+	// was selected earlier and is still current.
+	HasSelectedAccount() bool
+}
+
+// interactionErrors are the errors telling that the end-user must interact with the authorization server, see
+// http://openid.net/specs/openid-connect-core-1_0.html#AuthError
+var interactionErrors = []error{ErrLoginRequired, ErrConsentRequired, ErrInteractionRequired, ErrAccountSelectionRequired}
+
+// IsInteractionRequired returns true if err is ErrLoginRequired, ErrConsentRequired, ErrInteractionRequired or
+// ErrAccountSelectionRequired. NewAuthorizeResponse returns these errors if the request can not be fulfilled
+// without the end-user. Unless the client sent prompt=none, the application should show its login, consent or
+// account selection page and call NewAuthorizeResponse again afterwards. With prompt=none, the error must be
+// written to the client using WriteAuthorizeError.
+//
+// Applications which find that an interaction is required themselves, e.g. because several accounts are signed
+// in, return these errors as well.
+func IsInteractionRequired(err error) bool {
+	for _, interactionErr := range interactionErrors {
+		if errors.Is(interactionErr, err) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateInteraction returns ErrLoginRequired if the end-user did not authenticate while serving a request with
+// prompt=login, or authenticated longer than max_age seconds ago, and ErrConsentRequired if the end-user did not
+// consent to a request with prompt=consent. ErrAccountSelectionRequired is returned if no account is selected for a
+// request with prompt=select_account, or prompt=none which rules out showing an account chooser. The authentication,
+// authentication time, consent and account selection are only known if the session implements LoginPromptSession,
+// AuthenticationTimeSession, ConsentSession and AccountSelectionSession.
+func validateInteraction(ar AuthorizeRequester, session interface{}) error {
+	if cs, ok := session.(ConsentSession); ok && !cs.HasConsent() && getPrompt(ar.GetRequestForm()).Has("consent") {
+		return errors.New(ErrConsentRequired)
+	}
+
+	if ss, ok := session.(AccountSelectionSession); ok && !ss.HasSelectedAccount() {
+		if prompt := getPrompt(ar.GetRequestForm()); prompt.Has("none") || prompt.Has(PromptSelectAccount) {
+			return errors.New(ErrAccountSelectionRequired)
+		}
+	}
+
+	if ls, ok := session.(LoginPromptSession); ok && !ls.HasAuthenticated() && getPrompt(ar.GetRequestForm()).Has("login") {
+		return errors.New(ErrLoginRequired)
+	}
+
+	as, ok := session.(AuthenticationTimeSession)
+	if !ok || as.GetAuthTime().IsZero() {
+		return nil
+	}
+
+	authTime := as.GetAuthTime()
+	if maxAge, err := strconv.ParseInt(ar.GetRequestForm().Get("max_age"), 10, 64); err == nil && maxAge >= 0 {
+		if time.Since(authTime) > time.Duration(maxAge)*time.Second {
+			return errors.New(ErrLoginRequired)
+		}
+	}
+	return nil
+}
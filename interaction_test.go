@@ -0,0 +1,121 @@
+package fosite
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type authTimeSession time.Time
+
+func (s authTimeSession) GetAuthTime() time.Time {
+	return time.Time(s)
+}
+
+type loginPromptSession bool
+
+func (s loginPromptSession) HasAuthenticated() bool {
+	return bool(s)
+}
+
+type accountSelectionSession bool
+
+func (s accountSelectionSession) HasSelectedAccount() bool {
+	return bool(s)
+}
+
+func TestValidateInteraction(t *testing.T) {
+	requestedAt := time.Now().Add(-time.Minute)
+	for k, c := range []struct {
+		description string
+		form        url.Values
+		session     interface{}
+		expectErr   error
+	}{
+		{
+			description: "should pass without a session telling fosite about the end-user",
+			form:        url.Values{"prompt": {"login consent"}, "max_age": {"0"}},
+			session:     struct{}{},
+		},
+		{
+			description: "should fail because the end-user did not consent although prompt=consent",
+			form:        url.Values{"prompt": {"consent"}},
+			session:     consentSession(false),
+			expectErr:   ErrConsentRequired,
+		},
+		{
+			description: "should pass because the end-user consented",
+			form:        url.Values{"prompt": {"consent"}},
+			session:     consentSession(true),
+		},
+		{
+			description: "should fail because the end-user did not authenticate although prompt=login",
+			form:        url.Values{"prompt": {"login"}},
+			session:     loginPromptSession(false),
+			expectErr:   ErrLoginRequired,
+		},
+		{
+			description: "should pass because the end-user authenticated in response to prompt=login",
+			form:        url.Values{"prompt": {"login"}},
+			session:     loginPromptSession(true),
+		},
+		{
+			description: "should pass because the authentication time does not tell whether the end-user authenticated for this request",
+			form:        url.Values{"prompt": {"login"}},
+			session:     authTimeSession(requestedAt.Add(-time.Hour)),
+		},
+		{
+			description: "should fail because the end-user authenticated longer than max_age ago",
+			form:        url.Values{"max_age": {"60"}},
+			session:     authTimeSession(time.Now().Add(-time.Hour)),
+			expectErr:   ErrLoginRequired,
+		},
+		{
+			description: "should pass because the end-user authenticated within max_age",
+			form:        url.Values{"max_age": {"3600"}},
+			session:     authTimeSession(time.Now().Add(-time.Minute)),
+		},
+		{
+			description: "should fail because no account is selected although prompt=select_account",
+			form:        url.Values{"prompt": {PromptSelectAccount}},
+			session:     accountSelectionSession(false),
+			expectErr:   ErrAccountSelectionRequired,
+		},
+		{
+			description: "should fail because no account is selected and prompt=none rules out an account chooser",
+			form:        url.Values{"prompt": {"none"}},
+			session:     accountSelectionSession(false),
+			expectErr:   ErrAccountSelectionRequired,
+		},
+		{
+			description: "should pass because the end-user selected an account",
+			form:        url.Values{"prompt": {PromptSelectAccount}},
+			session:     accountSelectionSession(true),
+		},
+		{
+			description: "should pass because the application picks the account if the client does not ask for a choice",
+			form:        url.Values{"prompt": {"login"}},
+			session:     accountSelectionSession(false),
+		},
+		{
+			description: "should pass because the authentication time is unknown",
+			form:        url.Values{"prompt": {"login"}, "max_age": {"60"}},
+			session:     authTimeSession(time.Time{}),
+		},
+	} {
+		ar := &AuthorizeRequest{Request: Request{Form: c.form, RequestedAt: requestedAt}}
+		err := validateInteraction(ar, c.session)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s: %s", k, c.description, err)
+	}
+}
+
+func TestIsInteractionRequired(t *testing.T) {
+	for _, err := range []error{ErrLoginRequired, ErrConsentRequired, ErrInteractionRequired, ErrAccountSelectionRequired} {
+		assert.True(t, IsInteractionRequired(errors.New(err)), "%s", err)
+	}
+	assert.False(t, IsInteractionRequired(errors.New(ErrInvalidRequest)))
+	assert.False(t, IsInteractionRequired(nil))
+}
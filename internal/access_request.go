@@ -42,6 +42,16 @@ func (_mr *_MockAccessRequesterRecorder) GetClient() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetClient")
 }
 
+func (_m *MockAccessRequester) GetExpiresAt(_param0 string) time.Time {
+	ret := _m.ctrl.Call(_m, "GetExpiresAt", _param0)
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+func (_mr *_MockAccessRequesterRecorder) GetExpiresAt(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetExpiresAt", arg0)
+}
+
 func (_m *MockAccessRequester) GetGrantTypes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetGrantTypes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -62,6 +72,26 @@ func (_mr *_MockAccessRequesterRecorder) GetGrantedScopes() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedScopes")
 }
 
+func (_m *MockAccessRequester) GetNormalizedRequestForm() url.Values {
+	ret := _m.ctrl.Call(_m, "GetNormalizedRequestForm")
+	ret0, _ := ret[0].(url.Values)
+	return ret0
+}
+
+func (_mr *_MockAccessRequesterRecorder) GetNormalizedRequestForm() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetNormalizedRequestForm")
+}
+
+func (_m *MockAccessRequester) GetOriginalRequest() fosite.Requester {
+	ret := _m.ctrl.Call(_m, "GetOriginalRequest")
+	ret0, _ := ret[0].(fosite.Requester)
+	return ret0
+}
+
+func (_mr *_MockAccessRequesterRecorder) GetOriginalRequest() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetOriginalRequest")
+}
+
 func (_m *MockAccessRequester) GetRequestForm() url.Values {
 	ret := _m.ctrl.Call(_m, "GetRequestForm")
 	ret0, _ := ret[0].(url.Values)
@@ -118,6 +148,22 @@ func (_mr *_MockAccessRequesterRecorder) Merge(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Merge", arg0)
 }
 
+func (_m *MockAccessRequester) SetExpiresAt(_param0 string, _param1 time.Time) {
+	_m.ctrl.Call(_m, "SetExpiresAt", _param0, _param1)
+}
+
+func (_mr *_MockAccessRequesterRecorder) SetExpiresAt(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetExpiresAt", arg0, arg1)
+}
+
+func (_m *MockAccessRequester) SetOriginalRequest(_param0 fosite.Requester) {
+	_m.ctrl.Call(_m, "SetOriginalRequest", _param0)
+}
+
+func (_mr *_MockAccessRequesterRecorder) SetOriginalRequest(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetOriginalRequest", arg0)
+}
+
 func (_m *MockAccessRequester) SetScopes(_param0 fosite.Arguments) {
 	_m.ctrl.Call(_m, "SetScopes", _param0)
 }
@@ -41,6 +41,16 @@ func (_mr *_MockAccessResponderRecorder) GetAccessToken() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetAccessToken")
 }
 
+func (_m *MockAccessResponder) GetExpiresAt() time.Time {
+	ret := _m.ctrl.Call(_m, "GetExpiresAt")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+func (_mr *_MockAccessResponderRecorder) GetExpiresAt() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetExpiresAt")
+}
+
 func (_m *MockAccessResponder) GetExtra(_param0 string) interface{} {
 	ret := _m.ctrl.Call(_m, "GetExtra", _param0)
 	ret0, _ := ret[0].(interface{})
@@ -51,6 +61,16 @@ func (_mr *_MockAccessResponderRecorder) GetExtra(arg0 interface{}) *gomock.Call
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetExtra", arg0)
 }
 
+func (_m *MockAccessResponder) GetIssuedAt() time.Time {
+	ret := _m.ctrl.Call(_m, "GetIssuedAt")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+func (_mr *_MockAccessResponderRecorder) GetIssuedAt() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetIssuedAt")
+}
+
 func (_m *MockAccessResponder) GetTokenType() string {
 	ret := _m.ctrl.Call(_m, "GetTokenType")
 	ret0, _ := ret[0].(string)
@@ -69,6 +89,14 @@ func (_mr *_MockAccessResponderRecorder) SetAccessToken(arg0 interface{}) *gomoc
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetAccessToken", arg0)
 }
 
+func (_m *MockAccessResponder) SetExpiresAt(_param0 time.Time) {
+	_m.ctrl.Call(_m, "SetExpiresAt", _param0)
+}
+
+func (_mr *_MockAccessResponderRecorder) SetExpiresAt(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetExpiresAt", arg0)
+}
+
 func (_m *MockAccessResponder) SetExpiresIn(_param0 time.Duration) {
 	_m.ctrl.Call(_m, "SetExpiresIn", _param0)
 }
@@ -85,6 +113,14 @@ func (_mr *_MockAccessResponderRecorder) SetExtra(arg0, arg1 interface{}) *gomoc
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetExtra", arg0, arg1)
 }
 
+func (_m *MockAccessResponder) SetIssuedAt(_param0 time.Time) {
+	_m.ctrl.Call(_m, "SetIssuedAt", _param0)
+}
+
+func (_mr *_MockAccessResponderRecorder) SetIssuedAt(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetIssuedAt", arg0)
+}
+
 func (_m *MockAccessResponder) SetScopes(_param0 fosite.Arguments) {
 	_m.ctrl.Call(_m, "SetScopes", _param0)
 }
@@ -42,6 +42,16 @@ func (_mr *_MockAuthorizeRequesterRecorder) DidHandleAllResponseTypes() *gomock.
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DidHandleAllResponseTypes")
 }
 
+func (_m *MockAuthorizeRequester) GetClaimsLocales() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetClaimsLocales")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetClaimsLocales() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetClaimsLocales")
+}
+
 func (_m *MockAuthorizeRequester) GetClient() fosite.Client {
 	ret := _m.ctrl.Call(_m, "GetClient")
 	ret0, _ := ret[0].(fosite.Client)
@@ -52,6 +62,26 @@ func (_mr *_MockAuthorizeRequesterRecorder) GetClient() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetClient")
 }
 
+func (_m *MockAuthorizeRequester) GetDisplay() string {
+	ret := _m.ctrl.Call(_m, "GetDisplay")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetDisplay() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetDisplay")
+}
+
+func (_m *MockAuthorizeRequester) GetExpiresAt(_param0 string) time.Time {
+	ret := _m.ctrl.Call(_m, "GetExpiresAt", _param0)
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetExpiresAt(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetExpiresAt", arg0)
+}
+
 func (_m *MockAuthorizeRequester) GetGrantedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetGrantedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -62,6 +92,26 @@ func (_mr *_MockAuthorizeRequesterRecorder) GetGrantedScopes() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedScopes")
 }
 
+func (_m *MockAuthorizeRequester) GetNormalizedRequestForm() url.Values {
+	ret := _m.ctrl.Call(_m, "GetNormalizedRequestForm")
+	ret0, _ := ret[0].(url.Values)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetNormalizedRequestForm() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetNormalizedRequestForm")
+}
+
+func (_m *MockAuthorizeRequester) GetPrompt() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetPrompt")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetPrompt() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetPrompt")
+}
+
 func (_m *MockAuthorizeRequester) GetRedirectURI() *url.URL {
 	ret := _m.ctrl.Call(_m, "GetRedirectURI")
 	ret0, _ := ret[0].(*url.URL)
@@ -92,6 +142,16 @@ func (_mr *_MockAuthorizeRequesterRecorder) GetRequestedAt() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestedAt")
 }
 
+func (_m *MockAuthorizeRequester) GetResponseMode() string {
+	ret := _m.ctrl.Call(_m, "GetResponseMode")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetResponseMode() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetResponseMode")
+}
+
 func (_m *MockAuthorizeRequester) GetResponseTypes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetResponseTypes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -132,6 +192,16 @@ func (_mr *_MockAuthorizeRequesterRecorder) GetState() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetState")
 }
 
+func (_m *MockAuthorizeRequester) GetUILocales() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetUILocales")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) GetUILocales() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetUILocales")
+}
+
 func (_m *MockAuthorizeRequester) GrantScope(_param0 string) {
 	_m.ctrl.Call(_m, "GrantScope", _param0)
 }
@@ -158,6 +228,14 @@ func (_mr *_MockAuthorizeRequesterRecorder) Merge(arg0 interface{}) *gomock.Call
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Merge", arg0)
 }
 
+func (_m *MockAuthorizeRequester) SetExpiresAt(_param0 string, _param1 time.Time) {
+	_m.ctrl.Call(_m, "SetExpiresAt", _param0, _param1)
+}
+
+func (_mr *_MockAuthorizeRequesterRecorder) SetExpiresAt(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetExpiresAt", arg0, arg1)
+}
+
 func (_m *MockAuthorizeRequester) SetResponseTypeHandled(_param0 string) {
 	_m.ctrl.Call(_m, "SetResponseTypeHandled", _param0)
 }
@@ -6,6 +6,7 @@ package internal
 import (
 	gomock "github.com/golang/mock/gomock"
 	fosite "github.com/ory-am/fosite"
+	jwk "github.com/ory-am/fosite/token/jwk"
 )
 
 // Mock of Client interface
@@ -29,6 +30,36 @@ func (_m *MockClient) EXPECT() *_MockClientRecorder {
 	return _m.recorder
 }
 
+func (_m *MockClient) GetAllowedPrompts() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetAllowedPrompts")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetAllowedPrompts() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetAllowedPrompts")
+}
+
+func (_m *MockClient) GetApplicationType() string {
+	ret := _m.ctrl.Call(_m, "GetApplicationType")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetApplicationType() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetApplicationType")
+}
+
+func (_m *MockClient) GetAudience() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetAudience")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetAudience() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetAudience")
+}
+
 func (_m *MockClient) GetGrantTypes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetGrantTypes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -69,6 +100,56 @@ func (_mr *_MockClientRecorder) GetID() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetID")
 }
 
+func (_m *MockClient) GetIDTokenEncryptedResponseAlg() string {
+	ret := _m.ctrl.Call(_m, "GetIDTokenEncryptedResponseAlg")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetIDTokenEncryptedResponseAlg() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetIDTokenEncryptedResponseAlg")
+}
+
+func (_m *MockClient) GetIDTokenEncryptedResponseEnc() string {
+	ret := _m.ctrl.Call(_m, "GetIDTokenEncryptedResponseEnc")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetIDTokenEncryptedResponseEnc() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetIDTokenEncryptedResponseEnc")
+}
+
+func (_m *MockClient) GetIDTokenSignedResponseAlg() string {
+	ret := _m.ctrl.Call(_m, "GetIDTokenSignedResponseAlg")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetIDTokenSignedResponseAlg() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetIDTokenSignedResponseAlg")
+}
+
+func (_m *MockClient) GetJSONWebKeys() *jwk.JSONWebKeySet {
+	ret := _m.ctrl.Call(_m, "GetJSONWebKeys")
+	ret0, _ := ret[0].(*jwk.JSONWebKeySet)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetJSONWebKeys() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetJSONWebKeys")
+}
+
+func (_m *MockClient) GetJSONWebKeysURI() string {
+	ret := _m.ctrl.Call(_m, "GetJSONWebKeysURI")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetJSONWebKeysURI() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetJSONWebKeysURI")
+}
+
 func (_m *MockClient) GetOwner() string {
 	ret := _m.ctrl.Call(_m, "GetOwner")
 	ret0, _ := ret[0].(string)
@@ -79,6 +160,16 @@ func (_mr *_MockClientRecorder) GetOwner() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetOwner")
 }
 
+func (_m *MockClient) GetPostLogoutRedirectURIs() []string {
+	ret := _m.ctrl.Call(_m, "GetPostLogoutRedirectURIs")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetPostLogoutRedirectURIs() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetPostLogoutRedirectURIs")
+}
+
 func (_m *MockClient) GetRedirectURIs() []string {
 	ret := _m.ctrl.Call(_m, "GetRedirectURIs")
 	ret0, _ := ret[0].([]string)
@@ -89,6 +180,56 @@ func (_mr *_MockClientRecorder) GetRedirectURIs() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRedirectURIs")
 }
 
+func (_m *MockClient) GetRequestParameters() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetRequestParameters")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetRequestParameters() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestParameters")
+}
+
+func (_m *MockClient) GetRequestURIs() []string {
+	ret := _m.ctrl.Call(_m, "GetRequestURIs")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetRequestURIs() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequestURIs")
+}
+
+func (_m *MockClient) GetRequirePushedAuthorizationRequests() bool {
+	ret := _m.ctrl.Call(_m, "GetRequirePushedAuthorizationRequests")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetRequirePushedAuthorizationRequests() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequirePushedAuthorizationRequests")
+}
+
+func (_m *MockClient) GetRequiredACRValues() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetRequiredACRValues")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetRequiredACRValues() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetRequiredACRValues")
+}
+
+func (_m *MockClient) GetResponseModes() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetResponseModes")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetResponseModes() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetResponseModes")
+}
+
 func (_m *MockClient) GetResponseTypes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetResponseTypes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -98,3 +239,23 @@ func (_m *MockClient) GetResponseTypes() fosite.Arguments {
 func (_mr *_MockClientRecorder) GetResponseTypes() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetResponseTypes")
 }
+
+func (_m *MockClient) GetScopes() fosite.Arguments {
+	ret := _m.ctrl.Call(_m, "GetScopes")
+	ret0, _ := ret[0].(fosite.Arguments)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) GetScopes() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetScopes")
+}
+
+func (_m *MockClient) IsPublic() bool {
+	ret := _m.ctrl.Call(_m, "IsPublic")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+func (_mr *_MockClientRecorder) IsPublic() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "IsPublic")
+}
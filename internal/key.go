@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 )
@@ -12,3 +14,11 @@ func MustRSAKey() *rsa.PrivateKey {
 	}
 	return key
 }
+
+func MustECDSAKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
@@ -42,6 +42,16 @@ func (_mr *_MockRequesterRecorder) GetClient() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetClient")
 }
 
+func (_m *MockRequester) GetExpiresAt(_param0 string) time.Time {
+	ret := _m.ctrl.Call(_m, "GetExpiresAt", _param0)
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+func (_mr *_MockRequesterRecorder) GetExpiresAt(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetExpiresAt", arg0)
+}
+
 func (_m *MockRequester) GetGrantedScopes() fosite.Arguments {
 	ret := _m.ctrl.Call(_m, "GetGrantedScopes")
 	ret0, _ := ret[0].(fosite.Arguments)
@@ -52,6 +62,16 @@ func (_mr *_MockRequesterRecorder) GetGrantedScopes() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetGrantedScopes")
 }
 
+func (_m *MockRequester) GetNormalizedRequestForm() url.Values {
+	ret := _m.ctrl.Call(_m, "GetNormalizedRequestForm")
+	ret0, _ := ret[0].(url.Values)
+	return ret0
+}
+
+func (_mr *_MockRequesterRecorder) GetNormalizedRequestForm() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "GetNormalizedRequestForm")
+}
+
 func (_m *MockRequester) GetRequestForm() url.Values {
 	ret := _m.ctrl.Call(_m, "GetRequestForm")
 	ret0, _ := ret[0].(url.Values)
@@ -108,6 +128,14 @@ func (_mr *_MockRequesterRecorder) Merge(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Merge", arg0)
 }
 
+func (_m *MockRequester) SetExpiresAt(_param0 string, _param1 time.Time) {
+	_m.ctrl.Call(_m, "SetExpiresAt", _param0, _param1)
+}
+
+func (_mr *_MockRequesterRecorder) SetExpiresAt(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "SetExpiresAt", arg0, arg1)
+}
+
 func (_m *MockRequester) SetScopes(_param0 fosite.Arguments) {
 	_m.ctrl.Call(_m, "SetScopes", _param0)
 }
@@ -0,0 +1,158 @@
+package fosite
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// TokenIntrospector is responsible for looking up a token during introspection.
+type TokenIntrospector interface {
+	// IntrospectToken returns nil if token is active and merges the token's request into accessRequest. Returns
+	// ErrUnknownRequest if the introspector is not responsible for the token.
+	IntrospectToken(ctx context.Context, token string, accessRequest AccessRequester) error
+}
+
+// TokenTypeIntrospector can be implemented by TokenIntrospectors to declare the type of tokens they look up, e.g.
+// "access_token" or "refresh_token". Introspectors of the type given by the token_type_hint parameter are asked
+// first, see https://tools.ietf.org/html/rfc7662#section-2.1
+type TokenTypeIntrospector interface {
+	// IntrospectedTokenType returns the type of tokens the introspector looks up.
+	IntrospectedTokenType() string
+}
+
+// IntrospectionSession can be implemented by sessions to add details about the end-user's authentication to
+// introspection responses. Empty values are omitted.
+type IntrospectionSession interface {
+	// GetSubject returns the subject (sub) of the token.
+	GetSubject() string
+
+	// GetAuthTime returns the time the end-user authenticated (auth_time).
+	GetAuthTime() time.Time
+
+	// GetAuthenticationContextClassReference returns the authentication context class reference (acr).
+	GetAuthenticationContextClassReference() string
+}
+
+// NewIntrospectionRequest implements https://tools.ietf.org/html/rfc7662#section-2.1
+//
+//	The protected resource calls the introspection endpoint using an HTTP POST request with parameters sent as
+//	"application/x-www-form-urlencoded" data. [...] To prevent token scanning attacks, the endpoint MUST also
+//	require some form of authorization to access this endpoint
+//
+// If Fosite.IntrospectionScope is set, the client must be allowed to request it, otherwise ErrAccessDenied is
+// returned. Resource servers may send the optional scope parameter, a space-delimited list of scopes the token must have
+// been granted. Scopes are compared using the configured ScopeStrategy, tokens lacking one of them are inactive.
+//
+// Resource servers may also send the non-standard cnf parameter, a JSON object containing the thumbprints of the key
+// the token was presented with, e.g. {"jkt": "..."} for the key of a DPoP proof or {"x5t#S256": "..."} for a TLS
+// client certificate. Sender-constrained tokens are inactive if they are bound to another key, see
+// ConfirmationSession. Without the parameter, the resource server must verify the cnf member of the response.
+//
+// An error is only returned if the request itself is invalid. Tokens which are unknown, expired or otherwise
+// invalid result in an inactive response as required by https://tools.ietf.org/html/rfc7662#section-2.2
+// ErrUnsupportedEndpoint is returned if no TokenIntrospectors are registered, as every token would be inactive.
+func (f *Fosite) NewIntrospectionRequest(ctx context.Context, r *http.Request, session interface{}) (IntrospectionResponder, error) {
+	if len(f.TokenIntrospectors) == 0 {
+		return nil, errors.New(ErrUnsupportedEndpoint)
+	} else if r.Method != "POST" {
+		return nil, errors.New(ErrInvalidRequest)
+	}
+
+	if err := f.parseForm(r); err != nil {
+		return nil, err
+	}
+
+	// Public clients are not authenticated and could otherwise introspect any token.
+	if client, err := f.authenticateClient(ctx, r); err != nil {
+		return nil, err
+	} else if client.IsPublic() {
+		return nil, errors.New(ErrInvalidClient)
+	} else if f.IntrospectionScope != "" && !f.GetScopeStrategy()(client.GetScopes(), f.IntrospectionScope) {
+		return nil, errors.New(ErrAccessDenied)
+	} else if err := f.validateRequest(ctx, IntrospectionRequestEndpoint, r, client); err != nil {
+		return nil, err
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		return nil, errors.New(ErrInvalidRequest)
+	}
+
+	confirmation, err := parseConfirmation(r.PostForm.Get("cnf"))
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := removeEmpty(strings.Split(r.PostForm.Get("scope"), " "))
+	return f.introspectToken(ctx, token, r.PostForm.Get("token_type_hint"), session, scopes, confirmation), nil
+}
+
+// introspectToken asks the introspectors to look up token, starting with the ones responsible for tokenTypeHint.
+// Because the hint may be wrong, the remaining introspectors are asked if the hinted ones do not know the token.
+// The token is inactive if no introspector knows it, if it is invalid, if it lacks one of scopes or if it is bound
+// to another key than confirmation, which may be nil.
+//
+// Each introspection is emitted as AuditTokenIntrospected event, which fails if the token is inactive.
+func (f *Fosite) introspectToken(ctx context.Context, token, tokenTypeHint string, session interface{}, scopes []string, confirmation map[string]string) IntrospectionResponder {
+	response := f.lookupToken(ctx, token, tokenTypeHint, session, scopes, confirmation)
+	if response.IsActive() {
+		f.emitAuditEvent(ctx, AuditTokenIntrospected, response.GetAccessRequester(), nil)
+	} else {
+		// Inactive tokens are not disclosed, not even to the audit trail.
+		f.GetAuditSink().Emit(ctx, &AuditEvent{Type: AuditTokenIntrospected, Time: time.Now()})
+	}
+	return response
+}
+
+func (f *Fosite) lookupToken(ctx context.Context, token, tokenTypeHint string, session interface{}, scopes []string, confirmation map[string]string) IntrospectionResponder {
+	for _, introspector := range f.TokenIntrospectors.sortByHint(tokenTypeHint) {
+		// Every introspector is given a fresh request, an introspector failing halfway must not leave traces.
+		ar := NewAccessRequest(session)
+		if err := introspector.IntrospectToken(ctx, token, ar); err != nil {
+			continue
+		} else if !f.hasGrantedScopes(ar, scopes) || !isConfirmed(ar, confirmation) {
+			return &IntrospectionResponse{Active: false}
+		}
+		response := &IntrospectionResponse{Active: true, AccessRequester: ar}
+		if ti, ok := introspector.(TokenTypeIntrospector); ok {
+			response.TokenType = ti.IntrospectedTokenType()
+		}
+		return response
+	}
+
+	return &IntrospectionResponse{Active: false}
+}
+
+// sortByHint returns the introspectors of type tokenTypeHint followed by all others, keeping the order in which
+// they were registered.
+func (t TokenIntrospectors) sortByHint(tokenTypeHint string) TokenIntrospectors {
+	if tokenTypeHint == "" {
+		return t
+	}
+
+	hinted := make(TokenIntrospectors, 0, len(t))
+	var others TokenIntrospectors
+	for _, introspector := range t {
+		if ti, ok := introspector.(TokenTypeIntrospector); ok && ti.IntrospectedTokenType() == tokenTypeHint {
+			hinted = append(hinted, introspector)
+		} else {
+			others = append(others, introspector)
+		}
+	}
+	return append(hinted, others...)
+}
+
+// hasGrantedScopes returns true if all scopes have been granted to the token introspected by ar.
+func (f *Fosite) hasGrantedScopes(ar AccessRequester, scopes []string) bool {
+	strategy := f.GetScopeStrategy()
+	for _, scope := range scopes {
+		if !strategy(ar.GetGrantedScopes(), scope) {
+			return false
+		}
+	}
+	return true
+}
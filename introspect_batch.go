@@ -0,0 +1,47 @@
+package fosite
+
+import (
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// IntrospectTokens looks up several tokens at once and returns one response per token, in the order of tokens.
+// Like NewIntrospectionRequest, unknown, expired or otherwise invalid tokens result in inactive responses. Each
+// token is introspected with its own session returned by newSession. If newSession is nil, no session is passed to
+// the storage, which must then return the session the token was issued with.
+//
+// IntrospectTokens is a non-standard extension, RFC 7662 only defines the introspection of a single token. It does
+// not authenticate the caller and is meant for trusted components such as API gateways, which must not expose it
+// to clients. If the storage implements Transactional, all tokens are looked up in a single transaction.
+// ErrUnsupportedEndpoint is returned if no TokenIntrospectors are registered.
+func (f *Fosite) IntrospectTokens(ctx context.Context, tokens []string, newSession func() interface{}) ([]IntrospectionResponder, error) {
+	if len(f.TokenIntrospectors) == 0 {
+		return nil, errors.New(ErrUnsupportedEndpoint)
+	}
+
+	tx, transactional := f.Store.(Transactional)
+	if transactional {
+		var err error
+		if ctx, err = tx.BeginTX(ctx); err != nil {
+			return nil, errors.New(ErrServerError)
+		}
+	}
+
+	responses := make([]IntrospectionResponder, len(tokens))
+	for k, token := range tokens {
+		var session interface{}
+		if newSession != nil {
+			session = newSession()
+		}
+		responses[k] = f.introspectToken(ctx, token, "", session, nil, nil)
+	}
+
+	if transactional {
+		// The lookups do not modify the storage, committing merely ends the transaction.
+		if err := tx.Commit(ctx); err != nil {
+			tx.Rollback(ctx)
+			return nil, errors.New(ErrServerError)
+		}
+	}
+	return responses, nil
+}
@@ -0,0 +1,109 @@
+package fosite_test
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+type txKey struct{}
+
+type transactionalStorage struct {
+	*internal.MockStorage
+	beginErr   error
+	commitErr  error
+	begun      int
+	committed  int
+	rolledBack int
+}
+
+func (s *transactionalStorage) BeginTX(ctx context.Context) (context.Context, error) {
+	s.begun++
+	return context.WithValue(ctx, txKey{}, true), s.beginErr
+}
+
+func (s *transactionalStorage) Commit(ctx context.Context) error {
+	s.committed++
+	return s.commitErr
+}
+
+func (s *transactionalStorage) Rollback(ctx context.Context) error {
+	s.rolledBack++
+	return nil
+}
+
+func TestIntrospectTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	newSession := func() interface{} { return &struct{}{} }
+	f := &Fosite{Store: internal.NewMockStorage(ctrl), TokenIntrospectors: TokenIntrospectors{introspector}}
+
+	introspector.EXPECT().IntrospectToken(nil, "active", gomock.Any()).Do(func(_ context.Context, _ string, ar AccessRequester) {
+		ar.GrantScope("foo")
+	}).Return(nil)
+	introspector.EXPECT().IntrospectToken(nil, "invalid", gomock.Any()).Return(errors.New(ErrRequestUnauthorized))
+	introspector.EXPECT().IntrospectToken(nil, "unknown", gomock.Any()).Return(errors.New(ErrUnknownRequest))
+
+	responses, err := f.IntrospectTokens(nil, []string{"active", "invalid", "unknown"}, newSession)
+	require.Nil(t, err, "%s", err)
+	require.Len(t, responses, 3)
+	assert.True(t, responses[0].IsActive())
+	assert.Equal(t, Arguments{"foo"}, responses[0].GetAccessRequester().GetGrantedScopes())
+	assert.False(t, responses[1].IsActive())
+	assert.False(t, responses[2].IsActive())
+}
+
+func TestIntrospectTokensWithoutSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	f := &Fosite{Store: internal.NewMockStorage(ctrl), TokenIntrospectors: TokenIntrospectors{introspector}}
+	introspector.EXPECT().IntrospectToken(nil, "active", gomock.Any()).Do(func(_ context.Context, _ string, ar AccessRequester) {
+		assert.Nil(t, ar.GetSession())
+	}).Return(nil)
+
+	responses, err := f.IntrospectTokens(nil, []string{"active"}, nil)
+	require.Nil(t, err, "%s", err)
+	require.Len(t, responses, 1)
+	assert.True(t, responses[0].IsActive())
+}
+
+func TestIntrospectTokensUsesTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	newSession := func() interface{} { return nil }
+	store := &transactionalStorage{MockStorage: internal.NewMockStorage(ctrl)}
+	f := &Fosite{Store: store, TokenIntrospectors: TokenIntrospectors{introspector}}
+
+	// Both tokens are looked up within the transaction.
+	introspector.EXPECT().IntrospectToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Do(func(ctx context.Context, _ string, _ AccessRequester) {
+		assert.Equal(t, true, ctx.Value(txKey{}))
+	}).Return(nil)
+
+	responses, err := f.IntrospectTokens(context.Background(), []string{"foo", "bar"}, newSession)
+	require.Nil(t, err, "%s", err)
+	assert.Len(t, responses, 2)
+	assert.Equal(t, 1, store.begun)
+	assert.Equal(t, 1, store.committed)
+
+	store.beginErr = errors.New("")
+	_, err = f.IntrospectTokens(context.Background(), []string{"foo"}, newSession)
+	assert.True(t, errors.Is(ErrServerError, err), "%s", err)
+
+	store.beginErr = nil
+	store.commitErr = errors.New("")
+	introspector.EXPECT().IntrospectToken(gomock.Any(), "foo", gomock.Any()).Return(nil)
+	_, err = f.IntrospectTokens(context.Background(), []string{"foo"}, newSession)
+	assert.True(t, errors.Is(ErrServerError, err), "%s", err)
+}
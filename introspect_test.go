@@ -0,0 +1,369 @@
+package fosite_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestNewIntrospectionRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{ID: "foo", Secret: []byte("foo")}
+	f := &Fosite{Store: store, Hasher: hasher, TokenIntrospectors: TokenIntrospectors{introspector}}
+	authenticate := func() {
+		store.EXPECT().GetClient("foo").Return(client, nil)
+		hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+	}
+
+	for k, c := range []struct {
+		description  string
+		method       string
+		header       http.Header
+		form         url.Values
+		setup        func()
+		expectErr    error
+		expectActive bool
+	}{
+		{
+			description: "should fail because method is not POST",
+			method:      "GET",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup:       func() {},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail because client is not authenticated",
+			method:      "POST",
+			header:      http.Header{},
+			form:        url.Values{"token": {"some-token"}},
+			setup:       func() {},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail because client secret is wrong",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup: func() {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(errors.New(""))
+			},
+			expectErr: ErrInvalidClient,
+		},
+		{
+			description: "should fail because token is missing",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{},
+			setup:       authenticate,
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should be inactive because no introspector knows the token",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup: func() {
+				authenticate()
+				introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(ErrUnknownRequest)
+			},
+		},
+		{
+			description: "should be inactive because the token is invalid",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup: func() {
+				authenticate()
+				introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(ErrRequestUnauthorized)
+			},
+		},
+		{
+			description: "should be active",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup: func() {
+				authenticate()
+				introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Do(func(_ context.Context, _ string, ar AccessRequester) {
+					ar.GrantScope("foo")
+				}).Return(nil)
+			},
+			expectActive: true,
+		},
+		{
+			description: "should be active because the hierarchic scope strategy grants the required scope",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}, "scope": {"api.read foo"}},
+			setup: func() {
+				authenticate()
+				introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Do(func(_ context.Context, _ string, ar AccessRequester) {
+					ar.GrantScope("foo")
+					ar.GrantScope("api")
+				}).Return(nil)
+			},
+			expectActive: true,
+		},
+		{
+			description: "should be inactive because the token lacks the required scope",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}, "scope": {"api.read"}},
+			setup: func() {
+				authenticate()
+				introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Do(func(_ context.Context, _ string, ar AccessRequester) {
+					ar.GrantScope("api.write")
+				}).Return(nil)
+			},
+		},
+	} {
+		c.setup()
+		r := &http.Request{Method: c.method, Header: c.header, PostForm: c.form, Form: c.form}
+		resp, err := f.NewIntrospectionRequest(nil, r, nil)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.Equal(t, c.expectActive, resp.IsActive(), "(%d) %s", k, c.description)
+			assert.Equal(t, c.expectActive, resp.ToMap()["active"], "(%d) %s", k, c.description)
+		}
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+type typedIntrospector struct {
+	TokenIntrospector
+	tokenType string
+}
+
+func (t *typedIntrospector) IntrospectedTokenType() string {
+	return t.tokenType
+}
+
+func TestNewIntrospectionRequestHonorsTokenTypeHint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	access := internal.NewMockTokenIntrospector(ctrl)
+	refresh := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{ID: "foo", Secret: []byte("foo")}
+	f := &Fosite{Store: store, Hasher: hasher, TokenIntrospectors: TokenIntrospectors{
+		&typedIntrospector{TokenIntrospector: access, tokenType: "access_token"},
+		&typedIntrospector{TokenIntrospector: refresh, tokenType: "refresh_token"},
+	}}
+
+	for k, c := range []struct {
+		description  string
+		hint         string
+		setup        func()
+		expectActive bool
+	}{
+		{
+			description: "should ask the introspectors in order without a hint",
+			setup: func() {
+				access.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(nil)
+			},
+			expectActive: true,
+		},
+		{
+			description: "should ask the hinted introspector first",
+			hint:        "refresh_token",
+			setup: func() {
+				refresh.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(nil)
+			},
+			expectActive: true,
+		},
+		{
+			description: "should fall back to the other introspectors if the hint is wrong",
+			hint:        "refresh_token",
+			setup: func() {
+				gomock.InOrder(
+					refresh.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(ErrRequestUnauthorized),
+					access.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(nil),
+				)
+			},
+			expectActive: true,
+		},
+		{
+			description: "should ignore unknown hints",
+			hint:        "foo",
+			setup: func() {
+				gomock.InOrder(
+					access.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(ErrUnknownRequest),
+					refresh.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(ErrRequestUnauthorized),
+				)
+			},
+		},
+	} {
+		store.EXPECT().GetClient("foo").Return(client, nil)
+		hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+		c.setup()
+
+		form := url.Values{"token": {"some-token"}, "token_type_hint": {c.hint}}
+		r := &http.Request{Method: "POST", Header: http.Header{"Authorization": {basicAuth("foo", "bar")}}, PostForm: form, Form: form}
+		resp, err := f.NewIntrospectionRequest(nil, r, nil)
+		assert.Nil(t, err, "(%d) %s\n%s", k, c.description, err)
+		assert.Equal(t, c.expectActive, resp.IsActive(), "(%d) %s", k, c.description)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestNewIntrospectionRequestWithoutIntrospectors(t *testing.T) {
+	r := &http.Request{Method: "POST", Header: http.Header{}, PostForm: url.Values{"token": {"some-token"}}}
+	_, err := (&Fosite{}).NewIntrospectionRequest(nil, r, nil)
+	assert.True(t, errors.Is(ErrUnsupportedEndpoint, err), "%s", err)
+
+	_, err = (&Fosite{}).IntrospectTokens(nil, []string{"some-token"}, func() interface{} { return nil })
+	assert.True(t, errors.Is(ErrUnsupportedEndpoint, err), "%s", err)
+}
+
+func TestNewIntrospectionRequestRejectsPublicClients(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	defer ctrl.Finish()
+
+	store.EXPECT().GetClient("public").Return(&DefaultClient{ID: "public", Public: true}, nil)
+	f := &Fosite{Store: store, TokenIntrospectors: TokenIntrospectors{internal.NewMockTokenIntrospector(ctrl)}}
+
+	r := &http.Request{
+		Method:   "POST",
+		Header:   http.Header{},
+		PostForm: url.Values{"client_id": {"public"}, "token": {"some-token"}},
+	}
+	_, err := f.NewIntrospectionRequest(nil, r, nil)
+	assert.True(t, errors.Is(ErrInvalidClient, err), "%s", err)
+}
+
+func TestNewIntrospectionRequestRequiresIntrospectionScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	f := &Fosite{Store: store, Hasher: hasher, TokenIntrospectors: TokenIntrospectors{introspector}, IntrospectionScope: "api.introspect"}
+	for k, c := range []struct {
+		description string
+		scopes      []string
+		expectErr   error
+	}{
+		{
+			description: "should fail because the client may not request the introspection scope",
+			scopes:      []string{"photos"},
+			expectErr:   ErrAccessDenied,
+		},
+		{
+			description: "should pass because the client may request the introspection scope",
+			scopes:      []string{"api.introspect"},
+		},
+		{
+			description: "should pass because the client may request a parent of the introspection scope",
+			scopes:      []string{"api"},
+		},
+	} {
+		store.EXPECT().GetClient("foo").Return(&DefaultClient{ID: "foo", Secret: []byte("foo"), GrantedScopes: c.scopes}, nil)
+		hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+		if c.expectErr == nil {
+			introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Return(nil)
+		}
+
+		// The scope parameter is omitted, the introspection scope is required regardless.
+		r := &http.Request{
+			Method:   "POST",
+			Header:   http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			PostForm: url.Values{"token": {"some-token"}},
+		}
+		_, err := f.NewIntrospectionRequest(nil, r, nil)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+	}
+}
+
+type confirmationSession map[string]string
+
+func (s confirmationSession) GetConfirmation() map[string]string { return s }
+
+func TestNewIntrospectionRequestVerifiesConfirmation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{ID: "foo", Secret: []byte("foo")}
+	f := &Fosite{Store: store, Hasher: hasher, TokenIntrospectors: TokenIntrospectors{introspector}}
+	bound := confirmationSession{ConfirmationJWKThumbprint: "some-thumbprint"}
+
+	for k, c := range []struct {
+		description  string
+		session      interface{}
+		cnf          string
+		expectErr    error
+		expectActive bool
+	}{
+		{
+			description:  "should leave the verification to the resource server if no proof is presented",
+			session:      bound,
+			expectActive: true,
+		},
+		{
+			description:  "should pass because the presented key matches",
+			session:      bound,
+			cnf:          `{"jkt":"some-thumbprint"}`,
+			expectActive: true,
+		},
+		{
+			description: "should be inactive because the presented key does not match",
+			session:     bound,
+			cnf:         `{"jkt":"other-thumbprint"}`,
+		},
+		{
+			description: "should be inactive because another confirmation method is presented",
+			session:     bound,
+			cnf:         `{"x5t#S256":"some-thumbprint"}`,
+		},
+		{
+			description:  "should pass because the token is not bound",
+			cnf:          `{"jkt":"some-thumbprint"}`,
+			expectActive: true,
+		},
+		{
+			description: "should fail because the proof is malformed",
+			session:     bound,
+			cnf:         `{"jkt":`,
+			expectErr:   ErrInvalidRequest,
+		},
+	} {
+		store.EXPECT().GetClient("foo").Return(client, nil)
+		hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+		if c.expectErr == nil {
+			session := c.session
+			introspector.EXPECT().IntrospectToken(nil, "some-token", gomock.Any()).Do(func(_ context.Context, _ string, ar AccessRequester) {
+				ar.SetSession(session)
+			}).Return(nil)
+		}
+
+		form := url.Values{"token": {"some-token"}, "cnf": {c.cnf}}
+		r := &http.Request{Method: "POST", Header: http.Header{"Authorization": {basicAuth("foo", "bar")}}, PostForm: form, Form: form}
+		resp, err := f.NewIntrospectionRequest(nil, r, nil)
+		if c.expectErr != nil {
+			assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s", k, c.description, err)
+			continue
+		}
+		assert.Nil(t, err, "(%d) %s\n%s", k, c.description, err)
+		assert.Equal(t, c.expectActive, resp.IsActive(), "(%d) %s", k, c.description)
+	}
+}
@@ -0,0 +1,81 @@
+package fosite
+
+import (
+	"strings"
+	"time"
+)
+
+// IntrospectionResponder is the result of an introspection request.
+type IntrospectionResponder interface {
+	// IsActive returns true if the introspected token is active.
+	IsActive() bool
+
+	// GetAccessRequester returns the request the token was issued for or nil if the token is not active.
+	GetAccessRequester() AccessRequester
+
+	// ToMap converts the response to a map as defined in https://tools.ietf.org/html/rfc7662#section-2.2
+	ToMap() map[string]interface{}
+}
+
+// IntrospectionResponse is an implementation of IntrospectionResponder.
+type IntrospectionResponse struct {
+	Active          bool
+	AccessRequester AccessRequester
+
+	// TokenType is the type of the introspected token, e.g. RefreshToken, if the introspector declared it. It is
+	// used to look up the expiry of the token.
+	TokenType string
+}
+
+func (r *IntrospectionResponse) IsActive() bool {
+	return r.Active
+}
+
+func (r *IntrospectionResponse) GetAccessRequester() AccessRequester {
+	return r.AccessRequester
+}
+
+func (r *IntrospectionResponse) ToMap() map[string]interface{} {
+	if !r.Active || r.AccessRequester == nil {
+		// Information about inactive tokens must not be disclosed.
+		return map[string]interface{}{"active": false}
+	}
+
+	ret := map[string]interface{}{
+		"active": true,
+		"scope":  strings.Join(r.AccessRequester.GetGrantedScopes(), " "),
+	}
+
+	if client := r.AccessRequester.GetClient(); client != nil {
+		ret["client_id"] = client.GetID()
+	}
+
+	if requestedAt := r.AccessRequester.GetRequestedAt(); !requestedAt.IsZero() {
+		ret["iat"] = requestedAt.Unix()
+	}
+
+	if expiresAt := r.AccessRequester.GetExpiresAt(r.TokenType); r.TokenType != "" && !expiresAt.IsZero() {
+		ret["exp"] = expiresAt.Unix()
+		// expires_in is not defined by https://tools.ietf.org/html/rfc7662#section-2.2 but saves clients from
+		// comparing exp with their own clock.
+		ret["expires_in"] = ExpiresIn(expiresAt.Sub(time.Now()))
+	}
+
+	if session, ok := r.AccessRequester.GetSession().(IntrospectionSession); ok {
+		if subject := session.GetSubject(); subject != "" {
+			ret["sub"] = subject
+		}
+		if authTime := session.GetAuthTime(); !authTime.IsZero() {
+			ret["auth_time"] = authTime.Unix()
+		}
+		if acr := session.GetAuthenticationContextClassReference(); acr != "" {
+			ret["acr"] = acr
+		}
+	}
+
+	if confirmation := getConfirmation(r.AccessRequester); len(confirmation) > 0 {
+		ret["cnf"] = confirmation
+	}
+
+	return ret
+}
@@ -0,0 +1,65 @@
+package fosite_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/oidc/strategy"
+	"github.com/ory-am/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntrospectionResponseToMap(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"active": false}, (&IntrospectionResponse{}).ToMap())
+
+	ar := NewAccessRequest(nil)
+	ar.GrantScope("foo")
+	ar.GrantScope("bar")
+	ar.Client = &DefaultClient{ID: "client"}
+	assert.Equal(t, map[string]interface{}{"active": false}, (&IntrospectionResponse{AccessRequester: ar}).ToMap())
+	assert.Equal(t, map[string]interface{}{
+		"active":    true,
+		"scope":     "foo bar",
+		"client_id": "client",
+	}, (&IntrospectionResponse{Active: true, AccessRequester: ar}).ToMap())
+
+	authTime := time.Now().Add(-time.Minute)
+	ar.Request.RequestedAt = time.Now()
+	ar.Session = &strategy.DefaultSession{Claims: &jwt.IDTokenClaims{
+		Subject:                             "peter",
+		AuthTime:                            authTime,
+		AuthenticationContextClassReference: "urn:mace:incommon:iap:silver",
+	}}
+	assert.Equal(t, map[string]interface{}{
+		"active":    true,
+		"scope":     "foo bar",
+		"client_id": "client",
+		"iat":       ar.GetRequestedAt().Unix(),
+		"sub":       "peter",
+		"auth_time": authTime.Unix(),
+		"acr":       "urn:mace:incommon:iap:silver",
+	}, (&IntrospectionResponse{Active: true, AccessRequester: ar}).ToMap())
+
+	// Claims which are not present on the session are omitted.
+	ar.Session = &strategy.DefaultSession{Claims: &jwt.IDTokenClaims{Subject: "peter"}}
+	m := (&IntrospectionResponse{Active: true, AccessRequester: ar}).ToMap()
+	assert.NotContains(t, m, "auth_time")
+	assert.NotContains(t, m, "acr")
+
+	// The expiry is only reported if the type of the introspected token is known.
+	ar.SetExpiresAt(RefreshToken, time.Now().Add(time.Hour))
+	assert.NotContains(t, (&IntrospectionResponse{Active: true, AccessRequester: ar}).ToMap(), "exp")
+	m = (&IntrospectionResponse{Active: true, AccessRequester: ar, TokenType: RefreshToken}).ToMap()
+	assert.Equal(t, ar.GetExpiresAt(RefreshToken).Unix(), m["exp"])
+	assert.InDelta(t, 3600, m["expires_in"], 5)
+	assert.NotContains(t, (&IntrospectionResponse{Active: true, AccessRequester: ar, TokenType: AccessToken}).ToMap(), "exp")
+}
+
+func TestIntrospectionResponseToMapContainsConfirmation(t *testing.T) {
+	ar := NewAccessRequest(confirmationSession{ConfirmationJWKThumbprint: "some-thumbprint"})
+	assert.Equal(t, map[string]string{"jkt": "some-thumbprint"}, (&IntrospectionResponse{Active: true, AccessRequester: ar}).ToMap()["cnf"])
+
+	ar = NewAccessRequest(confirmationSession{})
+	assert.NotContains(t, (&IntrospectionResponse{Active: true, AccessRequester: ar}).ToMap(), "cnf")
+}
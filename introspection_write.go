@@ -0,0 +1,45 @@
+package fosite
+
+import (
+	"net/http"
+
+	"github.com/go-errors/errors"
+)
+
+// WriteIntrospectionResponse writes the introspection response as defined in
+// https://tools.ietf.org/html/rfc7662#section-2.2
+func (f *Fosite) WriteIntrospectionResponse(rw http.ResponseWriter, responder IntrospectionResponder) {
+	writeJSON(rw, http.StatusOK, responder.ToMap())
+}
+
+// WriteIntrospectionError writes an error of the introspection endpoint. Unlike WriteAccessError, the body uses
+// the error and error_description members of https://tools.ietf.org/html/rfc6749#section-5.2 and failed client
+// authentication results in 401 Unauthorized with a WWW-Authenticate header. Errors caused by the token itself,
+// e.g. because it is unknown or malformed, result in an inactive response as required by
+// https://tools.ietf.org/html/rfc7662#section-2.2
+func (f *Fosite) WriteIntrospectionError(rw http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+
+	for _, tokenErr := range []error{ErrUnknownRequest, ErrRequestUnauthorized, ErrInvalidTokenFormat, ErrNotFound} {
+		if errors.Is(err, tokenErr) {
+			f.WriteIntrospectionResponse(rw, &IntrospectionResponse{Active: false})
+			return
+		}
+	}
+
+	rfcerr := f.toRFC6749Error(nil, err)
+	body := map[string]string{"error": rfcerr.Name, "error_description": rfcerr.Description}
+	status := rfcerr.StatusCode
+	if _, ok := f.ErrorStatusCodes[rfcerr.Name]; !ok && (errors.Is(err, ErrInvalidClient) || errors.Is(err, ErrUnauthorizedClient)) {
+		status = http.StatusUnauthorized
+	}
+
+	if status == http.StatusUnauthorized {
+		// https://tools.ietf.org/html/rfc6749#section-5.2 requires a challenge matching the authentication scheme
+		// used by the client, Basic is the only scheme using the Authorization header.
+		rw.Header().Set("WWW-Authenticate", `Basic realm="introspection"`)
+	}
+	writeJSON(rw, status, body)
+}
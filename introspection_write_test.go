@@ -0,0 +1,84 @@
+package fosite_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	. "github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteIntrospectionResponse(t *testing.T) {
+	f := &Fosite{}
+	header := http.Header{}
+	ctrl := gomock.NewController(t)
+	rw := NewMockResponseWriter(ctrl)
+	defer ctrl.Finish()
+
+	rw.EXPECT().Header().AnyTimes().Return(header)
+	rw.EXPECT().WriteHeader(http.StatusOK)
+	rw.EXPECT().Write([]byte(`{"active":false}`))
+
+	f.WriteIntrospectionResponse(rw, &IntrospectionResponse{})
+	assert.Equal(t, "application/json;charset=UTF-8", header.Get("Content-Type"))
+	assert.Equal(t, "no-store", header.Get("Cache-Control"))
+	assert.Equal(t, "no-cache", header.Get("Pragma"))
+}
+
+func TestWriteIntrospectionError(t *testing.T) {
+	f := &Fosite{}
+	for k, c := range []struct {
+		description     string
+		err             error
+		expectStatus    int
+		expectBody      map[string]interface{}
+		expectChallenge string
+	}{
+		{
+			description:     "should challenge clients which failed to authenticate",
+			err:             errors.New(ErrInvalidClient),
+			expectStatus:    http.StatusUnauthorized,
+			expectBody:      map[string]interface{}{"error": "invalid_client", "error_description": ErrInvalidClient.Error()},
+			expectChallenge: `Basic realm="introspection"`,
+		},
+		{
+			description:  "should respond with inactive for unknown tokens",
+			err:          errors.New(ErrRequestUnauthorized),
+			expectStatus: http.StatusOK,
+			expectBody:   map[string]interface{}{"active": false},
+		},
+		{
+			description:  "should respond with inactive for malformed tokens",
+			err:          errors.New(ErrInvalidTokenFormat),
+			expectStatus: http.StatusOK,
+			expectBody:   map[string]interface{}{"active": false},
+		},
+		{
+			description:  "should reject unsupported token types",
+			err:          errors.New(ErrUnsupportedTokenType),
+			expectStatus: http.StatusBadRequest,
+			expectBody:   map[string]interface{}{"error": "unsupported_token_type", "error_description": ErrUnsupportedTokenType.Error()},
+		},
+		{
+			description:  "should reject invalid requests",
+			err:          errors.New(ErrInvalidRequest),
+			expectStatus: http.StatusBadRequest,
+			expectBody:   map[string]interface{}{"error": "invalid_request", "error_description": ErrInvalidRequest.Error()},
+		},
+	} {
+		rw := httptest.NewRecorder()
+		f.WriteIntrospectionError(rw, c.err)
+
+		var body map[string]interface{}
+		assert.Nil(t, json.Unmarshal(rw.Body.Bytes(), &body), "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectStatus, rw.Code, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectBody, body, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectChallenge, rw.Header().Get("WWW-Authenticate"), "(%d) %s", k, c.description)
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"), "(%d) %s", k, c.description)
+	}
+}
@@ -0,0 +1,22 @@
+package fosite
+
+import "time"
+
+// ClientWithCustomLifespans can be implemented by clients which override the lifespans configured for the handlers,
+// e.g. to issue short-lived access tokens to a single client.
+type ClientWithCustomLifespans interface {
+	// GetLifespan returns how long tokens of tokenType (AccessToken, RefreshToken, RefreshTokenFamily or
+	// AuthorizeCode) issued using grantType are valid. Returns zero to use the lifespan of the handler.
+	GetLifespan(grantType string, tokenType string) time.Duration
+}
+
+// GetEffectiveLifespan returns how long tokens of tokenType issued to client using grantType are valid. This is the
+// lifespan set by client if it implements ClientWithCustomLifespans, and fallback otherwise.
+func GetEffectiveLifespan(client Client, grantType string, tokenType string, fallback time.Duration) time.Duration {
+	if c, ok := client.(ClientWithCustomLifespans); ok {
+		if lifespan := c.GetLifespan(grantType, tokenType); lifespan > 0 {
+			return lifespan
+		}
+	}
+	return fallback
+}
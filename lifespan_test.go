@@ -0,0 +1,33 @@
+package fosite_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/ory-am/fosite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEffectiveLifespan(t *testing.T) {
+	client := &DefaultClient{
+		Lifespans: map[string]time.Duration{
+			"authorization_code " + AccessToken: time.Minute,
+			"refresh_token " + RefreshToken:     -time.Minute,
+		},
+	}
+
+	for k, c := range []struct {
+		client    Client
+		grantType string
+		tokenType string
+		expect    time.Duration
+	}{
+		{client: &DefaultClient{}, grantType: "authorization_code", tokenType: AccessToken, expect: time.Hour},
+		{client: client, grantType: "authorization_code", tokenType: AccessToken, expect: time.Minute},
+		{client: client, grantType: "authorization_code", tokenType: RefreshToken, expect: time.Hour},
+		{client: client, grantType: "implicit", tokenType: AccessToken, expect: time.Hour},
+		{client: client, grantType: "refresh_token", tokenType: RefreshToken, expect: time.Hour},
+	} {
+		assert.Equal(t, c.expect, GetEffectiveLifespan(c.client, c.grantType, c.tokenType, time.Hour), "case %d", k)
+	}
+}
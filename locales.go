@@ -0,0 +1,61 @@
+package fosite
+
+import (
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// parseLocales parses the ui_locales and claims_locales parameters defined by
+// http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+//
+//	End-User's preferred languages and scripts for the user interface, represented as a space-separated list of
+//	BCP47 [RFC5646] language tag values, ordered by preference.
+//
+// The order of preference is kept. Malformed language tags are rejected with ErrInvalidRequest.
+func parseLocales(raw string) (Arguments, error) {
+	locales := Arguments(removeEmpty(strings.Split(raw, " ")))
+	for _, locale := range locales {
+		if !isLanguageTag(locale) {
+			return nil, errors.New(ErrInvalidRequest)
+		}
+	}
+	return locales, nil
+}
+
+// isLanguageTag checks the syntax of https://tools.ietf.org/html/rfc5646#section-2.1 without looking up subtags
+// in the language subtag registry. The primary language subtag has two to eight letters and is followed
+// by subtags of one to eight letters or digits, e.g. "zh-Hant-TW". Private use ("x-") and grandfathered ("i-")
+// tags are accepted as well.
+func isLanguageTag(tag string) bool {
+	subtags := strings.Split(tag, "-")
+	primary := subtags[0]
+	if !isAlpha(primary) || len(primary) > 8 || (len(primary) < 2 && primary != "x" && primary != "i") {
+		return false
+	}
+
+	for _, subtag := range subtags[1:] {
+		if len(subtag) < 1 || len(subtag) > 8 || !isAlphanumeric(subtag) {
+			return false
+		}
+	}
+	return len(subtags) > 1 || len(primary) > 1
+}
+
+func isAlpha(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,39 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLocales(t *testing.T) {
+	for k, c := range []struct {
+		raw       string
+		expect    Arguments
+		expectErr bool
+	}{
+		{raw: ""},
+		{raw: "en", expect: Arguments{"en"}},
+		{raw: "fr-CA fr en", expect: Arguments{"fr-CA", "fr", "en"}},
+		{raw: " de-DE  zh-Hant-TW ", expect: Arguments{"de-DE", "zh-Hant-TW"}},
+		{raw: "es-419 x-klingon i-enochian", expect: Arguments{"es-419", "x-klingon", "i-enochian"}},
+		{raw: "en_US", expectErr: true},
+		{raw: "en -", expectErr: true},
+		{raw: "e", expectErr: true},
+		{raw: "x", expectErr: true},
+		{raw: "en--US", expectErr: true},
+		{raw: "en-US-", expectErr: true},
+		{raw: "123", expectErr: true},
+		{raw: "en-abcdefghi", expectErr: true},
+		{raw: "<script>", expectErr: true},
+	} {
+		locales, err := parseLocales(c.raw)
+		assert.Equal(t, c.expectErr, err != nil, "%d: %s", k, c.raw)
+		if c.expectErr {
+			assert.True(t, errors.Is(err, ErrInvalidRequest), "%d", k)
+		} else {
+			assert.Equal(t, c.expect, locales, "%d", k)
+		}
+	}
+}
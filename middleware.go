@@ -0,0 +1,98 @@
+package fosite
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+type accessRequesterContextKey struct{}
+
+// AccessRequesterFromContext returns the access request injected by TokenMiddleware.
+func AccessRequesterFromContext(ctx context.Context) (AccessRequester, bool) {
+	ar, ok := ctx.Value(accessRequesterContextKey{}).(AccessRequester)
+	return ar, ok
+}
+
+// SessionFromContext returns the session of the access token validated by TokenMiddleware.
+func SessionFromContext(ctx context.Context) (interface{}, bool) {
+	ar, ok := AccessRequesterFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return ar.GetSession(), true
+}
+
+// AccessTokenFromRequest returns the access token sent using one of the methods defined in
+// https://tools.ietf.org/html/rfc6750#section-2 or an empty string if the request does not contain one.
+func AccessTokenFromRequest(req *http.Request) string {
+	split := strings.SplitN(req.Header.Get("Authorization"), " ", 2)
+	if len(split) == 2 && strings.EqualFold(split[0], "bearer") {
+		return split[1]
+	}
+
+	if req.Method == "POST" && req.ParseForm() == nil {
+		return req.PostForm.Get("access_token")
+	}
+	return ""
+}
+
+// TokenMiddleware returns a middleware protecting resources with bearer tokens as defined in
+// https://tools.ietf.org/html/rfc6750. Requests are passed to the next handler only if their access token is valid,
+// was granted all scopes and, unless audience is empty, was granted audience when it was issued.
+// The validated access request is available to the next handler using AccessRequesterFromContext.
+//
+// newSession returns an empty session used for looking up the access token. If nil, no session is passed to the
+// storage, which must then return the session the token was issued with.
+func TokenMiddleware(provider OAuth2Provider, newSession func() interface{}, audience string, scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			token := AccessTokenFromRequest(req)
+			if token == "" {
+				// http://tools.ietf.org/html/rfc6750#section-3.1
+				// If the request lacks any authentication information, the resource server SHOULD NOT include an
+				// error code or other error information.
+				rw.Header().Set("WWW-Authenticate", "Bearer")
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			var session interface{}
+			if newSession != nil {
+				session = newSession()
+			}
+
+			// The audience is passed in the context instead of to ValidateToken, so that tokens not intended for this
+			// resource are reported as invalid rather than lacking scopes.
+			ar, err := provider.ValidateToken(WithAudience(req.Context(), audience), token, session, "", scopes...)
+			if errors.Is(err, ErrRequestForbidden) {
+				writeBearerError(rw, http.StatusForbidden, "insufficient_scope", "The access token was not granted the required scopes", scopes)
+				return
+			} else if err != nil {
+				writeBearerError(rw, http.StatusUnauthorized, "invalid_token", "The access token is invalid", nil)
+				return
+			}
+
+			if !HasGrantedAudience(ar, audience) {
+				writeBearerError(rw, http.StatusUnauthorized, "invalid_token", "The access token is not intended for this resource", nil)
+				return
+			}
+
+			next.ServeHTTP(rw, req.WithContext(context.WithValue(req.Context(), accessRequesterContextKey{}, ar)))
+		})
+	}
+}
+
+// writeBearerError writes an error as defined in https://tools.ietf.org/html/rfc6750#section-3
+func writeBearerError(rw http.ResponseWriter, status int, code, description string, scopes []string) {
+	value := fmt.Sprintf(`Bearer error="%s", error_description="%s"`, code, description)
+	if len(scopes) > 0 {
+		value += fmt.Sprintf(`, scope="%s"`, strings.Join(scopes, " "))
+	}
+
+	rw.Header().Set("WWW-Authenticate", value)
+	rw.WriteHeader(status)
+}
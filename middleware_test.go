@@ -0,0 +1,148 @@
+package fosite_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	. "github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+func TestAccessTokenFromRequest(t *testing.T) {
+	for k, c := range []struct {
+		req    *http.Request
+		expect string
+	}{
+		{req: &http.Request{Header: http.Header{}}, expect: ""},
+		{req: &http.Request{Header: http.Header{"Authorization": {"Bearer foo"}}}, expect: "foo"},
+		{req: &http.Request{Header: http.Header{"Authorization": {"bearer foo"}}}, expect: "foo"},
+		{req: &http.Request{Header: http.Header{"Authorization": {"Basic foo"}}}, expect: ""},
+		{
+			req: &http.Request{
+				Method: "POST",
+				Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+				Body:   ioutil.NopCloser(strings.NewReader(url.Values{"access_token": {"foo"}}.Encode())),
+			},
+			expect: "foo",
+		},
+	} {
+		assert.Equal(t, c.expect, AccessTokenFromRequest(c.req), "%d", k)
+	}
+}
+
+func TestTokenMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := NewMockAuthorizedRequestValidator(ctrl)
+	defer ctrl.Finish()
+
+	f := NewFosite(nil)
+	f.AuthorizedRequestValidators.Append(validator)
+
+	var session interface{}
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		session, _ = SessionFromContext(req.Context())
+		rw.WriteHeader(http.StatusNoContent)
+	})
+	h := TokenMiddleware(f, func() interface{} { return "session" }, "https://api.example.com", "photos")(next)
+
+	grant := func(audience []string, scopes ...string) func(context.Context, *http.Request, AccessRequester) {
+		return func(_ context.Context, _ *http.Request, ar AccessRequester) {
+			ar.(*AccessRequest).GrantedAudience = audience
+			ar.(*AccessRequest).GrantedScopes = scopes
+		}
+	}
+
+	for k, c := range []struct {
+		description   string
+		header        string
+		setup         func()
+		expectStatus  int
+		expectHeader  string
+		expectSession interface{}
+	}{
+		{
+			description:  "should fail because no token was sent",
+			setup:        func() {},
+			expectStatus: http.StatusUnauthorized,
+			expectHeader: "Bearer",
+		},
+		{
+			description: "should fail because the token is invalid",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(ErrRequestUnauthorized)
+			},
+			expectStatus: http.StatusUnauthorized,
+			expectHeader: `Bearer error="invalid_token", error_description="The access token is invalid"`,
+		},
+		{
+			description: "should fail because a scope is missing",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://api.example.com"}, "foo")).Return(nil)
+			},
+			expectStatus: http.StatusForbidden,
+			expectHeader: `Bearer error="insufficient_scope", error_description="The access token was not granted the required scopes", scope="photos"`,
+		},
+		{
+			description: "should fail because the token was issued for another audience",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://other.example.com"}, "photos")).Return(nil)
+			},
+			expectStatus: http.StatusUnauthorized,
+			expectHeader: `Bearer error="invalid_token", error_description="The access token is not intended for this resource"`,
+		},
+		{
+			description: "should pass and inject the session",
+			header:      "Bearer some-token",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant([]string{"https://api.example.com"}, "photos")).Return(nil)
+			},
+			expectStatus:  http.StatusNoContent,
+			expectSession: "session",
+		},
+	} {
+		session = nil
+		c.setup()
+
+		req, _ := http.NewRequest("GET", "https://api.example.com/photos", nil)
+		if c.header != "" {
+			req.Header.Set("Authorization", c.header)
+		}
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		assert.Equal(t, c.expectStatus, rw.Code, "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectHeader, rw.Header().Get("WWW-Authenticate"), "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectSession, session, "(%d) %s", k, c.description)
+	}
+}
+
+func TestTokenMiddlewareWithoutSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := NewMockAuthorizedRequestValidator(ctrl)
+	defer ctrl.Finish()
+
+	f := NewFosite(nil)
+	f.AuthorizedRequestValidators.Append(validator)
+	validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ context.Context, _ *http.Request, ar AccessRequester) {
+		ar.(*AccessRequest).GrantedScopes = []string{"photos"}
+	}).Return(nil)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	})
+	req, _ := http.NewRequest("GET", "https://api.example.com/photos", nil)
+	req.Header.Set("Authorization", "Bearer some-token")
+	rw := httptest.NewRecorder()
+	TokenMiddleware(f, nil, "", "photos")(next).ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+}
@@ -0,0 +1,20 @@
+package fosite
+
+import "time"
+
+// NotBeforeSession can be implemented by sessions to issue tokens which only become valid some time after they
+// were issued. JWT access tokens and ID tokens issued for such a session carry a nbf claim as defined by
+// https://tools.ietf.org/html/rfc7519#section-4.1.5 and are rejected until then.
+type NotBeforeSession interface {
+	// GetNotBeforeOffset returns how long after being issued tokens become valid.
+	GetNotBeforeOffset() time.Duration
+}
+
+// NotBefore returns the time tokens issued at issuedAt for session become valid. Returns the zero time if session
+// does not implement NotBeforeSession or its offset is not positive, these tokens are valid immediately.
+func NotBefore(session interface{}, issuedAt time.Time) time.Time {
+	if s, ok := session.(NotBeforeSession); ok && s.GetNotBeforeOffset() > 0 {
+		return issuedAt.Add(s.GetNotBeforeOffset())
+	}
+	return time.Time{}
+}
@@ -0,0 +1,23 @@
+package fosite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type notBeforeSession time.Duration
+
+func (s notBeforeSession) GetNotBeforeOffset() time.Duration {
+	return time.Duration(s)
+}
+
+func TestNotBefore(t *testing.T) {
+	now := time.Now()
+	assert.True(t, NotBefore(nil, now).IsZero())
+	assert.True(t, NotBefore(&struct{}{}, now).IsZero())
+	assert.True(t, NotBefore(notBeforeSession(0), now).IsZero())
+	assert.True(t, NotBefore(notBeforeSession(-time.Hour), now).IsZero())
+	assert.Equal(t, now.Add(time.Hour), NotBefore(notBeforeSession(time.Hour), now))
+}
@@ -17,6 +17,12 @@ const MinParameterEntropy = 8
 type OAuth2Provider interface {
 	// NewAuthorizeRequest returns an AuthorizeRequest.
 	//
+	// NewAuthorizeRequest validates everything which does not depend on the session: the client, redirect URI,
+	// state, scopes and whether the registered handlers support the response types for this client (see
+	// ResponseTypeHandler and AuthorizeEndpointValidator). It does not issue anything, so the request can be
+	// inspected, e.g. by a consent screen, before NewAuthorizeResponse is called. NewAuthorizeResponse only fails
+	// for reasons depending on the session, like missing consent to offline access, or on server errors.
+	//
 	// The following specs must be considered in any implementation of this method:
 	// * https://tools.ietf.org/html/rfc6749#section-3.1
 	//	 Extension response types MAY contain a space-delimited (%x20) list of
@@ -108,6 +114,42 @@ type OAuth2Provider interface {
 	// If the token is valid, ValidateRequestAuthorization will return the access request object.
 	ValidateRequestAuthorization(ctx context.Context, req *http.Request, session interface{}, scope ...string) (AccessRequester, error)
 
+	// ValidateToken returns the access request object if token is a valid access token which was granted all scopes
+	// and is intended for audience. Resource servers should pass their own identifier as audience.
+	ValidateToken(ctx context.Context, token string, session interface{}, audience string, scope ...string) (AccessRequester, error)
+
+	// NewIntrospectionRequest authenticates the introspecting client and looks up the token.
+	//
+	// The following specs must be considered in any implementation of this method:
+	// * https://tools.ietf.org/html/rfc7662#section-2.1 (everything)
+	// * https://tools.ietf.org/html/rfc7662#section-2.2 (everything)
+	NewIntrospectionRequest(ctx context.Context, req *http.Request, session interface{}) (IntrospectionResponder, error)
+
+	// WriteIntrospectionResponse writes the introspection response.
+	//
+	// The following specs must be considered in any implementation of this method:
+	// * https://tools.ietf.org/html/rfc7662#section-2.2 (everything)
+	WriteIntrospectionResponse(rw http.ResponseWriter, responder IntrospectionResponder)
+
+	// WriteIntrospectionError writes an error of the introspection endpoint.
+	//
+	// The following specs must be considered in any implementation of this method:
+	// * https://tools.ietf.org/html/rfc6749#section-5.2 (everything)
+	// * https://tools.ietf.org/html/rfc7662#section-2.3 (everything)
+	WriteIntrospectionError(rw http.ResponseWriter, err error)
+
+	// NewRevocationRequest authenticates the client and revokes the token if it was issued to the client.
+	//
+	// The following specs must be considered in any implementation of this method:
+	// * https://tools.ietf.org/html/rfc7009#section-2.1 (everything)
+	NewRevocationRequest(ctx context.Context, req *http.Request) error
+
+	// WriteRevocationResponse writes the revocation response.
+	//
+	// The following specs must be considered in any implementation of this method:
+	// * https://tools.ietf.org/html/rfc7009#section-2.2 (everything)
+	WriteRevocationResponse(rw http.ResponseWriter, err error)
+
 	// GetMandatoryScope returns the mandatory scope. Fosite enforces the usage of at least one scope. Returns a
 	// default value if no scope was set.
 	GetMandatoryScope() string
@@ -139,9 +181,23 @@ type Requester interface {
 	// GetSession sets the request's session pointer.
 	SetSession(session interface{})
 
-	// GetRequestForm returns the request's form input.
+	// GetExpiresAt returns the time the token of tokenType (e.g. RefreshToken) issued for this request expires at.
+	// The zero value is returned if the token does not expire or if its expiry is not tracked.
+	GetExpiresAt(tokenType string) time.Time
+
+	// SetExpiresAt sets the time the token of tokenType issued for this request expires at.
+	SetExpiresAt(tokenType string, expiresAt time.Time)
+
+	// GetRequestForm returns the request's form input. Handlers may modify it, e.g. to remove credentials before
+	// the request is stored.
 	GetRequestForm() url.Values
 
+	// GetNormalizedRequestForm returns a copy of the request's form input which can be read without the risk of
+	// modifying the request. For authorize requests, the form contains the parameters of both the request body
+	// and the URL query. If a parameter is contained in both, only the values of the request body are used. For
+	// access requests, the form contains the parameters of the request body only.
+	GetNormalizedRequestForm() url.Values
+
 	Merge(requester Requester)
 }
 
@@ -150,6 +206,14 @@ type AccessRequester interface {
 	// GetGrantType returns the requests grant type.
 	GetGrantTypes() (grantTypes Arguments)
 
+	// GetOriginalRequest returns the stored request the authorization code or refresh token presented to the token
+	// endpoint was issued for, or nil if the grant is not based on a stored request. Token handlers can use it
+	// to read parameters of the original request, e.g. custom form fields, when issuing tokens.
+	GetOriginalRequest() (original Requester)
+
+	// SetOriginalRequest sets the stored request the presented grant was issued for.
+	SetOriginalRequest(original Requester)
+
 	Requester
 }
 
@@ -175,6 +239,29 @@ type AuthorizeRequester interface {
 	// GetState returns the request's state.
 	GetState() (state string)
 
+	// GetDisplay returns how the authorization server should display the authentication and consent user interface
+	// pages to the end-user (page, popup, touch or wap), see
+	// http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+	// Returns an empty string if the client did not send the display parameter.
+	GetDisplay() (display string)
+
+	// GetResponseMode returns the requested response_mode, see
+	// http://openid.net/specs/oauth-v2-multiple-response-types-1_0.html#ResponseModes
+	// Returns an empty string if the client did not send the response_mode parameter.
+	GetResponseMode() (responseMode string)
+
+	// GetUILocales returns the end-user's preferred languages for the user interface as BCP47 language tags, ordered
+	// by preference, see http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+	GetUILocales() (locales Arguments)
+
+	// GetClaimsLocales returns the end-user's preferred languages for claims being returned as BCP47 language tags,
+	// ordered by preference, see http://openid.net/specs/openid-connect-core-1_0.html#ClaimsLanguagesAndScripts
+	GetClaimsLocales() (locales Arguments)
+
+	// GetPrompt returns the values of the prompt parameter, e.g. "login" or PromptCreate, telling the login and
+	// consent pages what to show the end-user, see http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+	GetPrompt() (prompt Arguments)
+
 	Requester
 }
 
@@ -186,7 +273,20 @@ type AccessResponder interface {
 	// GetExtra returns a key's value.
 	GetExtra(key string) interface{}
 
-	SetExpiresIn(time.Duration)
+	// SetExpiresIn sets expires_in to the lifespan of the access token in whole seconds, see ExpiresIn.
+	SetExpiresIn(lifespan time.Duration)
+
+	// SetIssuedAt sets the time the access token was issued at.
+	SetIssuedAt(issuedAt time.Time)
+
+	// GetIssuedAt returns the time the access token was issued at.
+	GetIssuedAt() time.Time
+
+	// SetExpiresAt sets the time the access token expires at.
+	SetExpiresAt(expiresAt time.Time)
+
+	// GetExpiresAt returns the time the access token expires at.
+	GetExpiresAt() time.Time
 
 	SetScopes(scopes Arguments)
 
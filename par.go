@@ -0,0 +1,73 @@
+package fosite
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// PushedAuthorizationRequestURIPrefix is the prefix of the request_uri values issued by pushed authorization request
+// endpoints, see https://tools.ietf.org/html/rfc9126#section-2.2
+const PushedAuthorizationRequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+// PushedAuthorizationRequest is an authorization request pushed by a client, see
+// https://tools.ietf.org/html/rfc9126#section-2.2
+type PushedAuthorizationRequest struct {
+	// ClientID is the ID of the client which pushed the request.
+	ClientID string
+
+	// Request is the request object which is passed on as the request parameter.
+	Request string
+
+	// ExpiresAt is the time the request_uri issued for the request expires at.
+	ExpiresAt time.Time
+}
+
+// PushedAuthorizationRequestStorage can be implemented by storages to look up the requests pushed by clients.
+// Authorize requests referencing a pushed request are rejected if Fosite.Store does not implement it.
+type PushedAuthorizationRequestStorage interface {
+	// GetPushedAuthorizationRequest returns the request the authorization server issued requestURI for, or an error
+	// if it did not issue requestURI.
+	GetPushedAuthorizationRequest(ctx context.Context, requestURI string) (*PushedAuthorizationRequest, error)
+}
+
+// isPushedAuthorizationRequestURI returns true if requestURI has the form of a pushed authorization request
+// reference. Whether the request was actually pushed is checked by getPushedAuthorizationRequest.
+func isPushedAuthorizationRequestURI(requestURI string) bool {
+	return strings.HasPrefix(requestURI, PushedAuthorizationRequestURIPrefix)
+}
+
+// validatePushedAuthorizationRequest rejects authorize requests which do not reference a pushed authorization
+// request if the client is required to push them, see https://tools.ietf.org/html/rfc9126#section-6
+//
+//	require_pushed_authorization_requests: Boolean parameter indicating whether the only means of initiating an
+//	authorization request the client is allowed to use is PAR.
+//
+// The pushed request itself is looked up by getPushedAuthorizationRequest when the request_uri is resolved.
+func validatePushedAuthorizationRequest(form url.Values, client Client) error {
+	if client.GetRequirePushedAuthorizationRequests() && !isPushedAuthorizationRequestURI(form.Get("request_uri")) {
+		return errors.New(ErrInvalidRequest)
+	}
+	return nil
+}
+
+// getPushedAuthorizationRequest returns the request object pushed by client which requestURI references. It returns
+// ErrInvalidRequestURI if the authorization server did not issue requestURI, issued it to another client or if it
+// expired, so that request_uri values can neither be forged nor redeemed by another client.
+func (f *Fosite) getPushedAuthorizationRequest(ctx context.Context, requestURI string, client Client) (string, error) {
+	storage, ok := f.Store.(PushedAuthorizationRequestStorage)
+	if !ok {
+		return "", errors.New(ErrInvalidRequestURI)
+	}
+
+	pushed, err := storage.GetPushedAuthorizationRequest(ctx, requestURI)
+	if err != nil || pushed == nil {
+		return "", errors.New(ErrInvalidRequestURI)
+	} else if pushed.ClientID != client.GetID() || pushed.ExpiresAt.Before(time.Now()) {
+		return "", errors.New(ErrInvalidRequestURI)
+	}
+	return pushed.Request, nil
+}
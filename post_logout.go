@@ -0,0 +1,24 @@
+package fosite
+
+import (
+	"net/url"
+
+	"github.com/go-errors/errors"
+)
+
+// MatchPostLogoutRedirectURI returns the post_logout_redirect_uri the end-user may be redirected to once the logout
+// initiated by client completed, see http://openid.net/specs/openid-connect-session-1_0.html#RPLogout
+//
+// The URI must be registered by the client and is validated using the same rules as redirect URIs, see
+// MatchRedirectURIWithClientRedirectURIs. Unlike redirect URIs, a registered URI is never picked if rawurl is empty.
+// An end-session handler must complete the logout without redirecting the end-user if an error is returned,
+// otherwise the end-user could be sent to a URL controlled by an attacker.
+func (c *Fosite) MatchPostLogoutRedirectURI(rawurl string, client Client) (*url.URL, error) {
+	if rawurl == "" || client == nil {
+		return nil, errors.New(ErrInvalidRequest)
+	}
+
+	return matchRedirectURI(rawurl, client.GetPostLogoutRedirectURIs(), func(redirectURI *url.URL) bool {
+		return c.isValidRedirectURI(redirectURI, client)
+	})
+}
@@ -0,0 +1,45 @@
+package fosite_test
+
+import (
+	"testing"
+
+	. "github.com/ory-am/fosite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPostLogoutRedirectURI(t *testing.T) {
+	client := &DefaultClient{
+		RedirectURIs:           []string{"https://foo.bar/cb"},
+		PostLogoutRedirectURIs: []string{"https://foo.bar/logout", "http://localhost:1234/logout", "http://foo.bar/logout", "com.example.app:/logout"},
+	}
+
+	for k, c := range []struct {
+		rawurl      string
+		client      Client
+		allowHTTP   bool
+		expectMatch bool
+	}{
+		{rawurl: "https://foo.bar/logout", client: client, expectMatch: true},
+		{rawurl: "http://localhost:1234/logout", client: client, expectMatch: true},
+		{rawurl: "", client: &DefaultClient{PostLogoutRedirectURIs: []string{"https://foo.bar/logout"}}, expectMatch: false},
+		{rawurl: "https://foo.bar/cb", client: client, expectMatch: false},
+		{rawurl: "https://foo.bar/logout/", client: client, expectMatch: false},
+		{rawurl: "https://foo.bar/logout?foo=bar", client: client, expectMatch: false},
+		{rawurl: "https://evil.com/logout", client: client, expectMatch: false},
+		{rawurl: "http://foo.bar/logout", client: client, expectMatch: false},
+		{rawurl: "http://foo.bar/logout", client: client, allowHTTP: true, expectMatch: true},
+		{rawurl: "com.example.app:/logout", client: client, expectMatch: false},
+		{rawurl: "com.example.app:/logout", client: &DefaultClient{PostLogoutRedirectURIs: []string{"com.example.app:/logout"}, ApplicationType: ApplicationTypeNative}, expectMatch: true},
+		{rawurl: "https://foo.bar/logout", client: &DefaultClient{RedirectURIs: []string{"https://foo.bar/logout"}}, expectMatch: false},
+		{rawurl: "https://foo.bar/logout", client: nil, expectMatch: false},
+	} {
+		f := &Fosite{AllowInsecureRedirectURIs: c.allowHTTP}
+		redirectURI, err := f.MatchPostLogoutRedirectURI(c.rawurl, c.client)
+		assert.Equal(t, c.expectMatch, err == nil, "case %d: %s", k, c.rawurl)
+		if c.expectMatch {
+			assert.Equal(t, c.rawurl, redirectURI.String(), "case %d", k)
+		} else {
+			assert.Nil(t, redirectURI, "case %d", k)
+		}
+	}
+}
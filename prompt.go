@@ -0,0 +1,44 @@
+package fosite
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// PromptCreate asks the authorization server to show the end-user a sign up page instead of a login page, see
+// https://openid.net/specs/openid-connect-prompt-create-1_0.html Fosite only validates and passes it on, see
+// AuthorizeRequester.GetPrompt, rendering the registration is up to the application.
+const PromptCreate = "create"
+
+// PromptSelectAccount asks the authorization server to let the end-user choose one of the accounts they are signed
+// in with, see http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest and AccountSelectionSession.
+const PromptSelectAccount = "select_account"
+
+// getPrompt returns the space delimited values of the prompt parameter, see
+// http://openid.net/specs/openid-connect-core-1_0.html#AuthRequest
+func getPrompt(form url.Values) Arguments {
+	return Arguments(removeEmpty(strings.Split(form.Get("prompt"), " ")))
+}
+
+// validatePrompt rejects prompt values the client is not allowed to send, see Client.GetAllowedPrompts, and
+// prompt=create or select_account combined with none, as the end-user can neither sign up nor choose an account
+// without being shown a page.
+func validatePrompt(prompt Arguments, client Client) error {
+	if prompt.Has(PromptCreate, "none") || prompt.Has(PromptSelectAccount, "none") {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	allowed := client.GetAllowedPrompts()
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _, value := range prompt {
+		if !allowed.Has(value) {
+			return errors.New(ErrInvalidRequest)
+		}
+	}
+	return nil
+}
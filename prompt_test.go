@@ -0,0 +1,37 @@
+package fosite
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePrompt(t *testing.T) {
+	for k, c := range []struct {
+		prompt      string
+		allowed     []string
+		expectError bool
+	}{
+		{prompt: "", allowed: []string{"login"}},
+		{prompt: "none"},
+		{prompt: "none consent", allowed: []string{}},
+		{prompt: "login", allowed: []string{"login", "consent"}},
+		{prompt: "login  consent", allowed: []string{"login", "consent"}},
+		{prompt: "none", allowed: []string{"login", "consent"}, expectError: true},
+		{prompt: "login none", allowed: []string{"login", "consent"}, expectError: true},
+		{prompt: "NONE", allowed: []string{"none"}, expectError: true},
+		{prompt: "create"},
+		{prompt: "login create", allowed: []string{"login", "create"}},
+		{prompt: "create", allowed: []string{"login", "consent"}, expectError: true},
+		{prompt: "none create", expectError: true},
+		{prompt: "create none", allowed: []string{"none", "create"}, expectError: true},
+		{prompt: "select_account"},
+		{prompt: "select_account consent"},
+		{prompt: "none select_account", expectError: true},
+	} {
+		prompt := getPrompt(url.Values{"prompt": {c.prompt}})
+		err := validatePrompt(prompt, &DefaultClient{AllowedPrompts: c.allowed})
+		assert.Equal(t, c.expectError, err != nil, "case %d: %s", k, c.prompt)
+	}
+}
@@ -0,0 +1,43 @@
+package fosite
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// PurgeExpired removes expired authorize codes, tokens and sessions from Fosite.Store and stores, for example
+// from a cron job. Stores which do not implement ExpiryAware are skipped and stores passed several times, e.g.
+// because the same storage is used by all handlers, are purged once.
+//
+// All stores are purged even if one of them fails, the first error is returned.
+func (f *Fosite) PurgeExpired(ctx context.Context, before time.Time, stores ...interface{}) error {
+	var purged []ExpiryAware
+	var firstErr error
+	for _, store := range append([]interface{}{f.Store}, stores...) {
+		s, ok := store.(ExpiryAware)
+		if !ok || containsStore(purged, s) {
+			continue
+		}
+		purged = append(purged, s)
+
+		if err := s.PurgeExpired(ctx, before); err != nil && firstErr == nil {
+			firstErr = errors.New(err)
+		}
+	}
+	return firstErr
+}
+
+func containsStore(stores []ExpiryAware, store ExpiryAware) bool {
+	if !reflect.TypeOf(store).Comparable() {
+		return false
+	}
+	for _, s := range stores {
+		if s == store {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package fosite_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type expiryAwareStorage struct {
+	*internal.MockStorage
+	err    error
+	before []time.Time
+}
+
+func (s *expiryAwareStorage) PurgeExpired(ctx context.Context, before time.Time) error {
+	s.before = append(s.before, before)
+	return s.err
+}
+
+func TestPurgeExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	before := time.Now()
+	store := &expiryAwareStorage{MockStorage: internal.NewMockStorage(ctrl)}
+	other := &expiryAwareStorage{}
+	f := &Fosite{Store: store}
+
+	assert.Nil(t, f.PurgeExpired(nil, before, store, other, struct{}{}, other))
+	assert.Equal(t, []time.Time{before}, store.before, "each store is purged once")
+	assert.Equal(t, []time.Time{before}, other.before, "each store is purged once")
+
+	store.err = errors.New("foo")
+	err := f.PurgeExpired(nil, before, other)
+	assert.NotNil(t, err)
+	assert.Equal(t, "foo", err.Error())
+	assert.Len(t, other.before, 2, "the remaining stores are purged after an error")
+
+	f = &Fosite{Store: internal.NewMockStorage(ctrl)}
+	assert.Nil(t, f.PurgeExpired(nil, before))
+}
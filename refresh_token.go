@@ -0,0 +1,33 @@
+package fosite
+
+// NoRefreshTokenParameter is a non-standard authorize request parameter. Clients set it to "true" if they do not
+// want a refresh token, even though offline access is granted.
+const NoRefreshTokenParameter = "no_refresh_token"
+
+// ClientWithRefreshTokenOptOut can be implemented by clients which never want refresh tokens, e.g. single page
+// applications which keep their session by other means.
+type ClientWithRefreshTokenOptOut interface {
+	// GetRefreshTokenOptOut returns true if the client must not be issued refresh tokens.
+	GetRefreshTokenOptOut() bool
+}
+
+// IsRefreshTokenRequested returns true if a refresh token should be issued for the grant authorized by
+// authorizeRequest. Refresh tokens are only issued if all of the following hold, so neither the client nor the
+// request can obtain a refresh token without offline access:
+//
+//  1. The offline or offline_access scope was granted.
+//  2. The client does not opt out, see ClientWithRefreshTokenOptOut.
+//  3. The authorize request does not contain no_refresh_token=true, see NoRefreshTokenParameter.
+func IsRefreshTokenRequested(authorizeRequest Requester) bool {
+	var offline bool
+	for _, scope := range offlineScopes {
+		offline = offline || authorizeRequest.GetGrantedScopes().Has(scope)
+	}
+
+	if !offline {
+		return false
+	} else if c, ok := authorizeRequest.GetClient().(ClientWithRefreshTokenOptOut); ok && c.GetRefreshTokenOptOut() {
+		return false
+	}
+	return authorizeRequest.GetRequestForm().Get(NoRefreshTokenParameter) != "true"
+}
@@ -0,0 +1,71 @@
+package fosite_test
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/ory-am/fosite"
+	"github.com/stretchr/testify/assert"
+)
+
+type optOutClient struct {
+	*DefaultClient
+	optOut bool
+}
+
+func (c *optOutClient) GetRefreshTokenOptOut() bool { return c.optOut }
+
+func TestIsRefreshTokenRequested(t *testing.T) {
+	for k, c := range []struct {
+		description string
+		scopes      Arguments
+		client      Client
+		form        url.Values
+		expect      bool
+	}{
+		{
+			description: "should not issue a refresh token without offline access",
+			scopes:      Arguments{"foo"},
+			client:      &DefaultClient{},
+		},
+		{
+			description: "should issue a refresh token for the offline scope",
+			scopes:      Arguments{"foo", "offline"},
+			client:      &DefaultClient{},
+			expect:      true,
+		},
+		{
+			description: "should issue a refresh token for the offline_access scope",
+			scopes:      Arguments{"offline_access"},
+			client:      &DefaultClient{},
+			expect:      true,
+		},
+		{
+			description: "should not issue a refresh token because the request asks for none",
+			scopes:      Arguments{"offline"},
+			client:      &DefaultClient{},
+			form:        url.Values{NoRefreshTokenParameter: {"true"}},
+		},
+		{
+			description: "should not issue a refresh token because the client opted out",
+			scopes:      Arguments{"offline"},
+			client:      &optOutClient{DefaultClient: &DefaultClient{}, optOut: true},
+		},
+		{
+			description: "should issue a refresh token because the client did not opt out",
+			scopes:      Arguments{"offline"},
+			client:      &optOutClient{DefaultClient: &DefaultClient{}},
+			form:        url.Values{NoRefreshTokenParameter: {"false"}},
+			expect:      true,
+		},
+		{
+			description: "should not issue a refresh token without offline access, even if the request asks for one",
+			scopes:      Arguments{"foo"},
+			client:      &DefaultClient{},
+			form:        url.Values{NoRefreshTokenParameter: {"false"}},
+		},
+	} {
+		ar := &Request{Client: c.client, GrantedScopes: ScopeArguments(c.scopes), Form: c.form}
+		assert.Equal(t, c.expect, IsRefreshTokenRequested(ar), "(%d) %s", k, c.description)
+	}
+}
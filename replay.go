@@ -0,0 +1,63 @@
+package fosite
+
+import (
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// DefaultReplayWindow is the default time identifiers of one-time tokens, e.g. the jti of DPoP proofs and client
+// assertions, are remembered for, see Fosite.ReplayWindow.
+const DefaultReplayWindow = 5 * time.Minute
+
+// ReplayStorage can be implemented by storages to remember the identifiers of one-time tokens and nonces. It is
+// shared by all features which need replay protection.
+type ReplayStorage interface {
+	// MarkSeen remembers id until expiresAt, after which it may be forgotten, and returns true if id was already
+	// remembered. Checking and remembering must be atomic, otherwise two concurrent requests could both use id.
+	MarkSeen(ctx context.Context, id string, expiresAt time.Time) (seen bool, err error)
+}
+
+// GetReplayWindow returns the replay window or DefaultReplayWindow if none is set.
+func (f *Fosite) GetReplayWindow() time.Duration {
+	if f.ReplayWindow == 0 {
+		return DefaultReplayWindow
+	}
+	return f.ReplayWindow
+}
+
+// CheckReplay returns ErrReplayed if id, e.g. the jti claim of a DPoP proof, was already used. Otherwise id is
+// remembered until the time the token expires at or the end of the replay window, whichever is sooner. Tokens
+// older than the window must therefore be rejected by the caller, e.g. using their iat claim. expiresAt is ignored
+// if zero, tokens which already expired are rejected with ErrInvalidRequest.
+//
+// Callers should scope id to avoid collisions between features and issuers, e.g. "dpop:" + jti. Returns
+// ErrMisconfiguration if Fosite.Store does not implement ReplayStorage.
+func (f *Fosite) CheckReplay(ctx context.Context, id string, expiresAt time.Time) error {
+	if id == "" {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	now := time.Now()
+	if !expiresAt.IsZero() && expiresAt.Before(now) {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	storage, ok := f.Store.(ReplayStorage)
+	if !ok {
+		return errors.New(ErrMisconfiguration)
+	}
+
+	until := now.Add(f.GetReplayWindow())
+	if !expiresAt.IsZero() && expiresAt.Before(until) {
+		until = expiresAt
+	}
+
+	if seen, err := storage.MarkSeen(ctx, id, until); err != nil {
+		return errors.New(ErrServerError)
+	} else if seen {
+		return errors.New(ErrReplayed)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package fosite_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/fosite-example/store"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type failingReplayStorage struct {
+	*store.Store
+}
+
+func (failingReplayStorage) MarkSeen(_ context.Context, _ string, _ time.Time) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestGetReplayWindow(t *testing.T) {
+	assert.Equal(t, DefaultReplayWindow, (&Fosite{}).GetReplayWindow())
+	assert.Equal(t, time.Minute, (&Fosite{ReplayWindow: time.Minute}).GetReplayWindow())
+}
+
+func TestCheckReplay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := store.NewStore()
+	f := &Fosite{Store: s, ReplayWindow: time.Minute}
+
+	assert.Nil(t, f.CheckReplay(nil, "dpop:some-jti", time.Time{}))
+	assert.True(t, errors.Is(ErrReplayed, f.CheckReplay(nil, "dpop:some-jti", time.Time{})))
+	assert.Nil(t, f.CheckReplay(nil, "assertion:some-jti", time.Time{}), "identifiers are scoped by the caller")
+	assert.WithinDuration(t, time.Now().Add(time.Minute), s.Seen["dpop:some-jti"], time.Second, "window ends before the token expires")
+
+	expiresAt := time.Now().Add(time.Second * 10)
+	assert.Nil(t, f.CheckReplay(nil, "dpop:short-lived", expiresAt))
+	assert.Equal(t, expiresAt, s.Seen["dpop:short-lived"], "token expires before the window ends")
+
+	s.Seen["dpop:forgotten"] = time.Now().Add(-time.Second)
+	assert.Nil(t, f.CheckReplay(nil, "dpop:forgotten", time.Time{}), "expired identifiers are forgotten")
+
+	assert.True(t, errors.Is(ErrInvalidRequest, f.CheckReplay(nil, "", time.Time{})))
+	assert.True(t, errors.Is(ErrInvalidRequest, f.CheckReplay(nil, "dpop:expired", time.Now().Add(-time.Second))), "expired tokens are rejected")
+
+	err := (&Fosite{Store: internal.NewMockStorage(ctrl)}).CheckReplay(nil, "some-jti", time.Time{})
+	assert.True(t, errors.Is(ErrMisconfiguration, err), "%s", err)
+
+	err = (&Fosite{Store: failingReplayStorage{s}}).CheckReplay(nil, "some-jti", time.Time{})
+	assert.True(t, errors.Is(ErrServerError, err), "%s", err)
+}
+
+func TestCheckReplayIsAtomic(t *testing.T) {
+	f := &Fosite{Store: store.NewStore()}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- f.CheckReplay(nil, "dpop:concurrent", time.Time{})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var passed int
+	for err := range errs {
+		if err == nil {
+			passed++
+		} else {
+			assert.True(t, errors.Is(ErrReplayed, err), "%s", err)
+		}
+	}
+	assert.Equal(t, 1, passed, "only one of several concurrent requests may use an identifier")
+}
@@ -5,20 +5,36 @@ import (
 	"time"
 )
 
+const (
+	// AccessToken, RefreshToken and AuthorizeCode are the token types whose expiry can be tracked using
+	// Requester.SetExpiresAt.
+	AccessToken   = "access_token"
+	RefreshToken  = "refresh_token"
+	AuthorizeCode = "authorize_code"
+
+	// RefreshTokenFamily tracks the absolute expiry of all refresh tokens rotated from the same authorization.
+	RefreshTokenFamily = "refresh_token_family"
+)
+
 // Request is an implementation of Requester
 type Request struct {
-	RequestedAt   time.Time   `json:"requestedAt" gorethink:"requestedAt"`
-	Client        Client      `json:"client" gorethink:"client"`
-	Scopes        Arguments   `json:"scopes" gorethink:"scopes"`
-	GrantedScopes Arguments   `json:"grantedScopes" gorethink:"grantedScopes"`
-	Form          url.Values  `json:"form" gorethink:"form"`
-	Session       interface{} `json:"session" gorethink:"session"`
+	RequestedAt   time.Time      `json:"requestedAt" gorethink:"requestedAt"`
+	Client        Client         `json:"client" gorethink:"client"`
+	Scopes        ScopeArguments `json:"scopes" gorethink:"scopes"`
+	GrantedScopes ScopeArguments `json:"grantedScopes" gorethink:"grantedScopes"`
+	Form          url.Values     `json:"form" gorethink:"form"`
+	Session       interface{}    `json:"session" gorethink:"session"`
+
+	ExpiresAt map[string]time.Time `json:"expiresAt,omitempty" gorethink:"expiresAt"`
+
+	// GrantedAudience is the audience the tokens of the request were issued for, see AudienceRequester.
+	GrantedAudience Arguments `json:"grantedAudience,omitempty" gorethink:"grantedAudience"`
 }
 
 func NewRequest() *Request {
 	return &Request{
 		Client: &DefaultClient{},
-		Scopes: Arguments{},
+		Scopes: ScopeArguments{},
 		Form:   url.Values{},
 	}
 }
@@ -27,6 +43,10 @@ func (a *Request) GetRequestForm() url.Values {
 	return a.Form
 }
 
+func (a *Request) GetNormalizedRequestForm() url.Values {
+	return copyForm(a.Form)
+}
+
 func (a *Request) GetRequestedAt() time.Time {
 	return a.RequestedAt
 }
@@ -36,19 +56,29 @@ func (a *Request) GetClient() Client {
 }
 
 func (a *Request) GetScopes() Arguments {
-	return a.Scopes
+	return Arguments(a.Scopes)
 }
 
 func (a *Request) SetScopes(s Arguments) {
-	a.Scopes = s
+	a.Scopes = ScopeArguments(Arguments{}.Add(s...))
 }
 
 func (a *Request) GetGrantedScopes() Arguments {
-	return a.GrantedScopes
+	return Arguments(a.GrantedScopes)
 }
 
 func (a *Request) GrantScope(scope string) {
-	a.GrantedScopes = append(a.GrantedScopes, scope)
+	a.GrantedScopes = ScopeArguments(a.GetGrantedScopes().Add(scope))
+}
+
+// GetGrantedAudience implements AudienceRequester.
+func (a *Request) GetGrantedAudience() Arguments {
+	return a.GrantedAudience
+}
+
+// GrantAudience implements AudienceRequester.
+func (a *Request) GrantAudience(audience string) {
+	a.GrantedAudience = a.GrantedAudience.Add(audience)
 }
 
 func (a *Request) SetSession(session interface{}) {
@@ -59,16 +89,30 @@ func (a *Request) GetSession() interface{} {
 	return a.Session
 }
 
-func (a *Request) Merge(request Requester) {
-	for _, scope := range request.GetScopes() {
-		a.Scopes = append(a.Scopes, scope)
-	}
-	for _, scope := range request.GetGrantedScopes() {
-		a.GrantedScopes = append(a.GrantedScopes, scope)
+func (a *Request) GetExpiresAt(tokenType string) time.Time {
+	return a.ExpiresAt[tokenType]
+}
+
+func (a *Request) SetExpiresAt(tokenType string, expiresAt time.Time) {
+	if a.ExpiresAt == nil {
+		a.ExpiresAt = map[string]time.Time{}
 	}
+	a.ExpiresAt[tokenType] = expiresAt
+}
+
+func (a *Request) Merge(request Requester) {
+	a.Scopes = ScopeArguments(a.GetScopes().Add(request.GetScopes()...))
+	a.GrantedScopes = ScopeArguments(a.GetGrantedScopes().Add(request.GetGrantedScopes()...))
 	a.RequestedAt = request.GetRequestedAt()
 	a.Client = request.GetClient()
 	a.Session = request.GetSession()
+	CopyGrantedAudience(request, a)
+
+	for _, tokenType := range []string{AccessToken, RefreshToken, AuthorizeCode, RefreshTokenFamily} {
+		if expiresAt := request.GetExpiresAt(tokenType); !expiresAt.IsZero() {
+			a.SetExpiresAt(tokenType, expiresAt)
+		}
+	}
 
 	for k, v := range request.GetRequestForm() {
 		a.Form[k] = v
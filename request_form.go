@@ -0,0 +1,29 @@
+package fosite
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// mergeForm returns the parameters of the request body and the URL query of a parsed request. If a parameter is
+// contained in both, only the values of the request body are used, so that the values of a parameter never
+// originate from different sources. This is the same precedence r.Form.Get uses.
+func mergeForm(r *http.Request) url.Values {
+	form := make(url.Values, len(r.Form))
+	for k, v := range r.Form {
+		if pv, ok := r.PostForm[k]; ok {
+			v = pv
+		}
+		form[k] = v
+	}
+	return copyForm(form)
+}
+
+// copyForm returns a deep copy of form.
+func copyForm(form url.Values) url.Values {
+	c := make(url.Values, len(form))
+	for k, v := range form {
+		c[k] = append([]string{}, v...)
+	}
+	return c
+}
@@ -0,0 +1,28 @@
+package fosite
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeForm(t *testing.T) {
+	r, err := http.NewRequest("POST", "https://auth.example.com/auth?state=query&scope=foo&scope=bar", strings.NewReader(url.Values{"scope": {"baz"}, "nonce": {"body"}}.Encode()))
+	require.Nil(t, err)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	require.Nil(t, r.ParseForm())
+
+	form := mergeForm(r)
+	assert.Equal(t, url.Values{
+		"state": {"query"},
+		"scope": {"baz"},
+		"nonce": {"body"},
+	}, form)
+
+	form["state"][0] = "modified"
+	assert.Equal(t, "query", r.Form.Get("state"))
+}
@@ -0,0 +1,71 @@
+package fosite
+
+import (
+	"net/http"
+
+	"github.com/go-errors/errors"
+)
+
+const (
+	// DefaultMaxRequestBodySize is the default maximum size of a request body in bytes.
+	DefaultMaxRequestBodySize int64 = 1 << 20
+
+	// DefaultMaxRequestParameters is the default maximum number of form and query values a request may contain.
+	DefaultMaxRequestParameters = 128
+
+	// DefaultMaxRequestParameterLength is the default maximum length of a single form or query value. It is
+	// large enough for signed and encrypted request objects and client assertions.
+	DefaultMaxRequestParameterLength = 64 << 10
+)
+
+// GetMaxRequestBodySize returns the maximum request body size. Returns DefaultMaxRequestBodySize if not set.
+func (f *Fosite) GetMaxRequestBodySize() int64 {
+	if f.MaxRequestBodySize <= 0 {
+		return DefaultMaxRequestBodySize
+	}
+	return f.MaxRequestBodySize
+}
+
+// GetMaxRequestParameters returns the maximum number of request values. Returns DefaultMaxRequestParameters if not set.
+func (f *Fosite) GetMaxRequestParameters() int {
+	if f.MaxRequestParameters <= 0 {
+		return DefaultMaxRequestParameters
+	}
+	return f.MaxRequestParameters
+}
+
+// GetMaxRequestParameterLength returns the maximum length of a request value. Returns
+// DefaultMaxRequestParameterLength if not set.
+func (f *Fosite) GetMaxRequestParameterLength() int {
+	if f.MaxRequestParameterLength <= 0 {
+		return DefaultMaxRequestParameterLength
+	}
+	return f.MaxRequestParameterLength
+}
+
+// parseForm parses the request's query and body and enforces the request limits. Returns ErrInvalidRequest
+// if the request can not be parsed or exceeds one of the limits.
+func (f *Fosite) parseForm(r *http.Request) error {
+	if r.Body != nil {
+		r.Body = http.MaxBytesReader(nil, r.Body, f.GetMaxRequestBodySize())
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	var count int
+	for _, values := range r.Form {
+		count += len(values)
+		if count > f.GetMaxRequestParameters() {
+			return errors.New(ErrInvalidRequest)
+		}
+
+		for _, value := range values {
+			if len(value) > f.GetMaxRequestParameterLength() {
+				return errors.New(ErrInvalidRequest)
+			}
+		}
+	}
+	return nil
+}
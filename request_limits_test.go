@@ -0,0 +1,52 @@
+package fosite
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormEnforcesLimits(t *testing.T) {
+	f := &Fosite{
+		MaxRequestBodySize:        64,
+		MaxRequestParameters:      3,
+		MaxRequestParameterLength: 8,
+	}
+
+	many := url.Values{}
+	for i := 0; i < 4; i++ {
+		many.Set(fmt.Sprintf("p%d", i), "foo")
+	}
+
+	for k, c := range []struct {
+		body      string
+		expectErr bool
+	}{
+		{body: "grant_type=foo&scope=bar", expectErr: false},
+		{body: many.Encode(), expectErr: true},
+		{body: "a=1&a=2&a=3&a=4", expectErr: true},
+		{body: "request=" + strings.Repeat("a", 9), expectErr: true},
+		{body: "a=" + strings.Repeat("a", 70), expectErr: true},
+	} {
+		r, _ := http.NewRequest("POST", "/token", strings.NewReader(c.body))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		err := f.parseForm(r)
+		assert.Equal(t, c.expectErr, err != nil, "%d: %s", k, err)
+		if c.expectErr {
+			assert.True(t, errors.Is(err, ErrInvalidRequest), "%d", k)
+		}
+	}
+}
+
+func TestRequestLimitDefaults(t *testing.T) {
+	f := &Fosite{}
+	assert.Equal(t, DefaultMaxRequestBodySize, f.GetMaxRequestBodySize())
+	assert.Equal(t, DefaultMaxRequestParameters, f.GetMaxRequestParameters())
+	assert.Equal(t, DefaultMaxRequestParameterLength, f.GetMaxRequestParameterLength())
+}
@@ -0,0 +1,163 @@
+package fosite
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+	jwt "gopkg.in/dgrijalva/jwt-go.v2"
+)
+
+// requestObjectSigningAlgorithms are the algorithms request objects may be signed with. Unsigned request objects
+// are rejected, their claims could have been changed by anyone forwarding the request.
+var requestObjectSigningAlgorithms = []string{"RS256", "ES256"}
+
+// requestObjectRegisteredClaims are the JWT claims describing the request object itself. They are not merged into
+// the authorize request.
+var requestObjectRegisteredClaims = []string{"iss", "aud", "exp", "iat", "nbf", "jti"}
+
+// resolveRequestObject verifies the request object passed using the request parameter and merges its claims into
+// the parameters of the authorize request, see https://tools.ietf.org/html/rfc9101#section-6.3
+//
+//	The authorization server MUST only use the parameters in the Request Object, even if the same parameter is
+//	provided in the query parameter.
+//
+// The request object must be signed using one of the client's keys and its aud claim must contain the issuer, see
+// Fosite.Issuer. Its claims replace the query parameters, but client_id and response_type are used before the
+// request object is resolved, e.g. to look up the client, and are therefore kept. They must match the claims of the
+// request object. Mismatches are rejected with ErrInvalidRequest, request objects which can not be verified with
+// ErrInvalidRequestObject. Request objects of pushed authorization requests must contain the client_id of client,
+// otherwise ErrInvalidRequestURI is returned.
+func (f *Fosite) resolveRequestObject(ctx context.Context, request *AuthorizeRequest, client Client, pushed bool) error {
+	object := request.Form.Get("request")
+	if object == "" {
+		return nil
+	}
+
+	token, err := jwt.Parse(object, func(t *jwt.Token) (interface{}, error) {
+		return f.findRequestObjectKey(ctx, t, client)
+	})
+	if err != nil || !token.Valid {
+		return errors.New(ErrInvalidRequestObject)
+	}
+	claims := token.Claims
+
+	// Request objects must not reference other request objects, and the iss claim identifies the client which
+	// signed the object.
+	if _, ok := claims["request"]; ok {
+		return errors.New(ErrInvalidRequestObject)
+	} else if _, ok := claims["request_uri"]; ok {
+		return errors.New(ErrInvalidRequestObject)
+	} else if iss, ok := claims["iss"]; ok && iss != client.GetID() {
+		return errors.New(ErrInvalidRequestObject)
+	}
+
+	// The aud claim prevents request objects issued for another authorization server from being replayed here.
+	issuer := IssuerFromContext(ctx, f.Issuer)
+	if issuer == "" {
+		return errors.New(ErrMisconfiguration)
+	} else if !hasAudience(claims["aud"], issuer) {
+		return errors.New(ErrInvalidRequestObject)
+	}
+
+	values := make(map[string]string, len(claims))
+	for key, claim := range claims {
+		if StringInSlice(key, requestObjectRegisteredClaims) {
+			continue
+		}
+
+		value, err := requestObjectClaimToParameter(claim)
+		if err != nil {
+			return errors.New(ErrInvalidRequestObject)
+		}
+		values[key] = value
+	}
+
+	if clientID, ok := values["client_id"]; pushed && clientID != client.GetID() {
+		return errors.New(ErrInvalidRequestURI)
+	} else if ok && clientID != request.Form.Get("client_id") {
+		return errors.New(ErrInvalidRequest)
+	} else if responseType, ok := values["response_type"]; ok && request.Form.Get("response_type") != "" && responseType != request.Form.Get("response_type") {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	form := url.Values{}
+	for _, key := range []string{"client_id", "response_type"} {
+		if value := request.Form.Get(key); value != "" {
+			form.Set(key, value)
+		}
+	}
+	for key, value := range values {
+		form.Set(key, value)
+	}
+	request.Form = form
+	return nil
+}
+
+// hasAudience returns true if the aud claim, which is a string or an array of strings, contains audience.
+func hasAudience(claim interface{}, audience string) bool {
+	switch aud := claim.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, v := range aud {
+			if v == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findRequestObjectKey returns the client's key for verifying the signature of a request object. The key must
+// belong to the family of the signing algorithm, otherwise e.g. an RSA public key could be used as HMAC secret.
+func (f *Fosite) findRequestObjectKey(ctx context.Context, t *jwt.Token, client Client) (interface{}, error) {
+	alg, _ := t.Header["alg"].(string)
+	if !StringInSlice(alg, requestObjectSigningAlgorithms) {
+		return nil, errors.Errorf("Request objects must not be signed using %s", alg)
+	}
+
+	kid, _ := t.Header["kid"].(string)
+	jwk, err := f.findClientKey(ctx, client, kid, alg)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := jwk.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); ok {
+			return key, nil
+		}
+	case *ecdsa.PublicKey:
+		if _, ok := t.Method.(*jwt.SigningMethodECDSA); ok {
+			return key, nil
+		}
+	}
+	return nil, errors.Errorf("Key of type %T can not be used to verify %s signatures", key, alg)
+}
+
+// requestObjectClaimToParameter converts a claim of a request object to the value of a query parameter. Strings
+// and numbers are used as is, JSON objects like the claims parameter are encoded.
+func requestObjectClaimToParameter(claim interface{}) (string, error) {
+	switch v := claim.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case map[string]interface{}:
+		encoded, err := json.Marshal(v)
+		return string(encoded), err
+	}
+	return "", errors.Errorf("Claims of type %T are not supported", claim)
+}
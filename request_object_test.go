@@ -0,0 +1,196 @@
+package fosite
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/token/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	jwt "gopkg.in/dgrijalva/jwt-go.v2"
+)
+
+func mustRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.Nil(t, err, "%s", err)
+	return key
+}
+
+func TestResolveRequestObject(t *testing.T) {
+	key := mustRSAKey(t)
+	client := &DefaultClient{ID: "foo", JSONWebKeys: &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{
+		jwk.NewRSAPublicKey("sig", "sig", &key.PublicKey),
+	}}}
+	sign := func(method jwt.SigningMethod, signingKey interface{}, claims map[string]interface{}) string {
+		token := jwt.New(method)
+		token.Claims = claims
+		signed, err := token.SignedString(signingKey)
+		require.Nil(t, err, "%s", err)
+		return signed
+	}
+	signed := func(claims map[string]interface{}) string {
+		if _, ok := claims["aud"]; !ok {
+			claims["aud"] = "https://auth.example.com"
+		}
+		return sign(jwt.SigningMethodRS256, key, claims)
+	}
+
+	for k, c := range []struct {
+		description string
+		client      Client
+		noIssuer    bool
+		pushed      bool
+		form        url.Values
+		expectErr   error
+		expectForm  url.Values
+	}{
+		{
+			description: "should pass when no request object is used",
+			form:        url.Values{"client_id": {"foo"}, "scope": {"foo"}},
+			expectForm:  url.Values{"client_id": {"foo"}, "scope": {"foo"}},
+		},
+		{
+			description: "should prefer the claims of the request object over the query parameters",
+			form: url.Values{"client_id": {"foo"}, "response_type": {"code"}, "scope": {"foo"}, "request": {signed(map[string]interface{}{
+				"iss":           "foo",
+				"aud":           "https://auth.example.com",
+				"exp":           time.Now().Add(time.Hour).Unix(),
+				"client_id":     "foo",
+				"response_type": "code",
+				"scope":         "openid bar",
+				"max_age":       30,
+				"claims":        map[string]interface{}{"userinfo": map[string]interface{}{"email": nil}},
+			})}},
+			expectForm: url.Values{
+				"client_id":     {"foo"},
+				"response_type": {"code"},
+				"scope":         {"openid bar"},
+				"max_age":       {"30"},
+				"claims":        {`{"userinfo":{"email":null}}`},
+			},
+		},
+		{
+			description: "should ignore query parameters which are not part of the request object",
+			form: url.Values{"client_id": {"foo"}, "response_type": {"code"}, "redirect_uri": {"https://evil.example.com/cb"}, "state": {"some-state"}, "request": {signed(map[string]interface{}{
+				"scope": "openid",
+			})}},
+			expectForm: url.Values{"client_id": {"foo"}, "response_type": {"code"}, "scope": {"openid"}},
+		},
+		{
+			description: "should accept an aud claim containing the issuer",
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"aud": []string{"https://other.example.com", "https://auth.example.com"}, "scope": "openid"})}},
+			expectForm:  url.Values{"client_id": {"foo"}, "scope": {"openid"}},
+		},
+		{
+			description: "should fail when the request object has no aud claim",
+			form:        url.Values{"client_id": {"foo"}, "request": {sign(jwt.SigningMethodRS256, key, map[string]interface{}{"scope": "openid"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the request object is intended for another authorization server",
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"aud": "https://other.example.com"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the issuer is unknown",
+			noIssuer:    true,
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"scope": "openid"})}},
+			expectErr:   ErrMisconfiguration,
+		},
+		{
+			description: "should use the response_type of the request object if there is no query parameter",
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"response_type": "code"})}},
+			expectForm:  url.Values{"client_id": {"foo"}, "response_type": {"code"}},
+		},
+		{
+			description: "should fail when the client_id does not match",
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"client_id": "bar"})}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should accept a pushed authorization request of the client",
+			pushed:      true,
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"client_id": "foo", "scope": "openid"})}},
+			expectForm:  url.Values{"client_id": {"foo"}, "scope": {"openid"}},
+		},
+		{
+			description: "should fail when a pushed authorization request has no client_id",
+			pushed:      true,
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"scope": "openid"})}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+		{
+			description: "should fail when a pushed authorization request was pushed by another client",
+			pushed:      true,
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"client_id": "bar", "scope": "openid"})}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+		{
+			description: "should fail when the response_type does not match",
+			form:        url.Values{"client_id": {"foo"}, "response_type": {"code"}, "request": {signed(map[string]interface{}{"response_type": "token"})}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail when the request object is not signed",
+			form:        url.Values{"client_id": {"foo"}, "request": {sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, map[string]interface{}{"scope": "bar"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the request object is signed using a symmetric algorithm",
+			form:        url.Values{"client_id": {"foo"}, "request": {sign(jwt.SigningMethodHS256, []byte("secret"), map[string]interface{}{"scope": "bar"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the request object is signed by another key",
+			form:        url.Values{"client_id": {"foo"}, "request": {sign(jwt.SigningMethodRS256, mustRSAKey(t), map[string]interface{}{"scope": "bar"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the client has no keys",
+			client:      &DefaultClient{ID: "foo"},
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"scope": "bar"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the request object was issued by another client",
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"iss": "bar"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the request object is expired",
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the request object references another request object",
+			form:        url.Values{"client_id": {"foo"}, "request": {signed(map[string]interface{}{"request_uri": "https://client.example.com/object"})}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+		{
+			description: "should fail when the request object is malformed",
+			form:        url.Values{"client_id": {"foo"}, "request": {"object"}},
+			expectErr:   ErrInvalidRequestObject,
+		},
+	} {
+		if c.client == nil {
+			c.client = client
+		}
+		f := &Fosite{Issuer: "https://auth.example.com"}
+		if c.noIssuer {
+			f.Issuer = ""
+		}
+		request := &AuthorizeRequest{Request: Request{Form: c.form}}
+		err := f.resolveRequestObject(context.Background(), request, c.client, c.pushed)
+		if c.expectErr != nil {
+			assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+			continue
+		}
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+		assert.Equal(t, c.expectForm, request.Form, "(%d) %s", k, c.description)
+	}
+}
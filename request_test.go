@@ -13,7 +13,7 @@ func TestRequest(t *testing.T) {
 	r := &Request{
 		RequestedAt:   time.Now(),
 		Client:        &DefaultClient{},
-		Scopes:        Arguments{},
+		Scopes:        ScopeArguments{},
 		GrantedScopes: []string{},
 		Form:          url.Values{"foo": []string{"bar"}},
 		Session:       1234,
@@ -23,3 +23,43 @@ func TestRequest(t *testing.T) {
 	assert.Equal(t, r.Client, r.GetClient())
 
 }
+
+func TestRequestDoesNotShareScopes(t *testing.T) {
+	shared := make(ScopeArguments, 1, 4)
+	shared[0] = "foo"
+
+	a := &Request{GrantedScopes: shared}
+	b := &Request{GrantedScopes: shared}
+	a.GrantScope("bar")
+	b.GrantScope("baz")
+	assert.Equal(t, Arguments{"foo", "bar"}, a.GetGrantedScopes())
+	assert.Equal(t, Arguments{"foo", "baz"}, b.GetGrantedScopes())
+
+	a.SetScopes(Arguments(shared))
+	a.Scopes[0] = "bar"
+	assert.Equal(t, "foo", shared[0])
+}
+
+func TestGetNormalizedRequestForm(t *testing.T) {
+	r := &Request{Form: url.Values{"foo": {"bar"}}}
+	form := r.GetNormalizedRequestForm()
+	assert.Equal(t, r.Form, form)
+
+	form["foo"][0] = "baz"
+	form.Set("bar", "baz")
+	assert.Equal(t, url.Values{"foo": {"bar"}}, r.GetRequestForm())
+}
+
+func TestRequestExpiresAt(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	r := NewRequest()
+	assert.True(t, r.GetExpiresAt(RefreshToken).IsZero())
+
+	r.SetExpiresAt(RefreshToken, expiresAt)
+	assert.Equal(t, expiresAt, r.GetExpiresAt(RefreshToken))
+	assert.True(t, r.GetExpiresAt(AccessToken).IsZero())
+
+	merged := NewRequest()
+	merged.Merge(r)
+	assert.Equal(t, expiresAt, merged.GetExpiresAt(RefreshToken))
+}
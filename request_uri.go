@@ -0,0 +1,171 @@
+package fosite
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/ssrf"
+	"golang.org/x/net/context"
+)
+
+const (
+	// DefaultRequestURIMaxSize is the default size limit of request objects in bytes.
+	DefaultRequestURIMaxSize = 64 * 1024
+
+	// DefaultRequestURITimeout is the default timeout for fetching a request object.
+	DefaultRequestURITimeout = 5 * time.Second
+)
+
+// RequestURIFetcher fetches request objects passed by reference using the request_uri parameter, see
+// http://openid.net/specs/openid-connect-core-1_0.html#RequestUriParameter
+type RequestURIFetcher interface {
+	// Fetch returns the request object located at requestURI, which was passed by client. Pushed authorization
+	// requests are looked up in the PushedAuthorizationRequestStorage instead.
+	Fetch(ctx context.Context, requestURI string, client Client) ([]byte, error)
+}
+
+// DefaultRequestURIFetcher fetches request objects over HTTP. Because the location is chosen by the client, the
+// fetcher protects against server-side request forgery: only allowed schemes and hosts are requested, connections
+// to private, loopback and link-local addresses are refused - also when following redirects or after DNS
+// resolution - and the size and duration of the download are limited.
+type DefaultRequestURIFetcher struct {
+	// AllowedSchemes are the URL schemes request objects may be fetched with. Defaults to https.
+	AllowedSchemes []string
+
+	// AllowedHosts restricts the hosts request objects may be fetched from. Every public host is allowed if empty.
+	AllowedHosts []string
+
+	// MaxSize limits the size of request objects in bytes. Defaults to DefaultRequestURIMaxSize.
+	MaxSize int64
+
+	// Timeout limits the time for fetching a request object. Defaults to DefaultRequestURITimeout.
+	Timeout time.Duration
+
+	// isAllowedIP decides which addresses may be connected to. Defaults to ssrf.IsPublicIP.
+	isAllowedIP func(ip net.IP) bool
+
+	// httpClient is created once, so that connections are reused between fetches.
+	httpClient     *http.Client
+	httpClientOnce sync.Once
+}
+
+func (f *DefaultRequestURIFetcher) Fetch(ctx context.Context, requestURI string, _ Client) ([]byte, error) {
+	u, err := url.Parse(requestURI)
+	if err != nil {
+		return nil, errors.New(err)
+	} else if err := f.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	// The fragment may be used by clients to distinguish request objects, it is never sent to the server.
+	u.Fragment = ""
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Fetching the request object failed with status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, f.getMaxSize()))
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return body, nil
+}
+
+func (f *DefaultRequestURIFetcher) client() *http.Client {
+	f.httpClientOnce.Do(func() {
+		f.httpClient = ssrf.NewClient(f.getTimeout(), f.isAllowedIP, f.checkURL)
+	})
+	return f.httpClient
+}
+
+func (f *DefaultRequestURIFetcher) checkURL(u *url.URL) error {
+	schemes := f.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	if !StringInSlice(u.Scheme, schemes) {
+		return errors.Errorf("Scheme %s is not allowed", u.Scheme)
+	} else if len(f.AllowedHosts) > 0 && !StringInSlice(u.Hostname(), f.AllowedHosts) {
+		return errors.Errorf("Host %s is not allowed", u.Hostname())
+	}
+	return nil
+}
+
+func (f *DefaultRequestURIFetcher) getMaxSize() int64 {
+	if f.MaxSize == 0 {
+		return DefaultRequestURIMaxSize
+	}
+	return f.MaxSize
+}
+
+func (f *DefaultRequestURIFetcher) getTimeout() time.Duration {
+	if f.Timeout == 0 {
+		return DefaultRequestURITimeout
+	}
+	return f.Timeout
+}
+
+// resolveRequestURI fetches the request object referenced by the request_uri parameter and passes it on as the
+// request parameter, see http://openid.net/specs/openid-connect-core-1_0.html#RequestUriParameter
+func (f *Fosite) resolveRequestURI(ctx context.Context, request *AuthorizeRequest, client Client) error {
+	if err := validatePushedAuthorizationRequest(request.Form, client); err != nil {
+		return err
+	}
+
+	requestURI := request.Form.Get("request_uri")
+	if requestURI == "" {
+		return nil
+	}
+
+	// The request and request_uri parameters MUST NOT be used in the same request.
+	if request.Form.Get("request") != "" {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	// Pushed authorization requests are issued by the authorization server and can not be registered up front. They
+	// are bound to the client which pushed them instead.
+	if isPushedAuthorizationRequestURI(requestURI) {
+		object, err := f.getPushedAuthorizationRequest(ctx, requestURI, client)
+		if err != nil {
+			return err
+		}
+
+		request.Form.Del("request_uri")
+		request.Form.Set("request", object)
+		return nil
+	}
+
+	if f.RequestURIFetcher == nil {
+		return errors.New(ErrRequestURINotSupported)
+	} else if f.RequireRegisteredRequestURIs && !StringInSlice(requestURI, client.GetRequestURIs()) {
+		return errors.New(ErrInvalidRequestURI)
+	}
+
+	object, err := f.RequestURIFetcher.Fetch(ctx, requestURI, client)
+	if err != nil {
+		return errors.New(ErrInvalidRequestURI)
+	}
+
+	request.Form.Del("request_uri")
+	request.Form.Set("request", string(object))
+	return nil
+}
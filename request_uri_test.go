@@ -0,0 +1,267 @@
+package fosite
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/ssrf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func allowAllIPs(net.IP) bool { return true }
+
+func TestDefaultRequestURIFetcher(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/object":
+			rw.Write([]byte("eyJhbGciOiJub25lIn0.e30."))
+		case "/large":
+			rw.Write([]byte(strings.Repeat("a", 128)))
+		case "/slow":
+			time.Sleep(time.Second)
+		case "/redirect":
+			http.Redirect(rw, r, r.URL.Query().Get("to"), http.StatusFound)
+		default:
+			http.NotFound(rw, r)
+		}
+	}))
+	defer ts.Close()
+
+	for k, c := range []struct {
+		description string
+		fetcher     *DefaultRequestURIFetcher
+		uri         string
+		expect      string
+		expectErr   bool
+	}{
+		{
+			description: "should fetch request objects",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/object#foo",
+			expect:      "eyJhbGciOiJub25lIn0.e30.",
+		},
+		{
+			description: "should only allow https by default",
+			fetcher:     &DefaultRequestURIFetcher{isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/object",
+			expectErr:   true,
+		},
+		{
+			description: "should refuse hosts which are not allowed",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, AllowedHosts: []string{"example.com"}, isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/object",
+			expectErr:   true,
+		},
+		{
+			description: "should refuse loopback addresses by default",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}},
+			uri:         ts.URL + "/object",
+			expectErr:   true,
+		},
+		{
+			description: "should refuse responses exceeding the size limit",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, MaxSize: 64, isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/large",
+			expectErr:   true,
+		},
+		{
+			description: "should time out",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, Timeout: 100 * time.Millisecond, isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/slow",
+			expectErr:   true,
+		},
+		{
+			description: "should refuse other status codes than 200",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/missing",
+			expectErr:   true,
+		},
+		{
+			description: "should follow redirects to allowed hosts",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/redirect?to=" + url.QueryEscape(ts.URL+"/object"),
+			expect:      "eyJhbGciOiJub25lIn0.e30.",
+		},
+		{
+			description: "should refuse redirects to hosts which are not allowed",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, AllowedHosts: []string{"127.0.0.1"}, isAllowedIP: allowAllIPs},
+			uri:         ts.URL + "/redirect?to=" + url.QueryEscape("http://example.com/object"),
+			expectErr:   true,
+		},
+		{
+			description: "should refuse redirects to private addresses",
+			fetcher:     &DefaultRequestURIFetcher{AllowedSchemes: []string{"http"}, isAllowedIP: func(ip net.IP) bool { return ip.IsLoopback() || ssrf.IsPublicIP(ip) }},
+			uri:         ts.URL + "/redirect?to=" + url.QueryEscape("http://10.0.0.1/object"),
+			expectErr:   true,
+		},
+	} {
+		object, err := c.fetcher.Fetch(context.Background(), c.uri, &DefaultClient{})
+		if c.expectErr {
+			assert.NotNil(t, err, "(%d) %s", k, c.description)
+			continue
+		}
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+		assert.Equal(t, c.expect, string(object), "(%d) %s", k, c.description)
+	}
+}
+
+func TestDefaultRequestURIFetcherReusesItsHTTPClient(t *testing.T) {
+	f := &DefaultRequestURIFetcher{}
+	require.NotNil(t, f.client())
+	assert.True(t, f.client() == f.client(), "the client and its connection pool are created once")
+}
+
+type pushedRequestStore struct {
+	Storage
+	requests map[string]*PushedAuthorizationRequest
+}
+
+func (s *pushedRequestStore) GetPushedAuthorizationRequest(_ context.Context, requestURI string) (*PushedAuthorizationRequest, error) {
+	if pushed, ok := s.requests[requestURI]; ok {
+		return pushed, nil
+	}
+	return nil, errors.New(ErrNotFound)
+}
+
+type requestURIFetcherFunc func(ctx context.Context, requestURI string, client Client) ([]byte, error)
+
+func (f requestURIFetcherFunc) Fetch(ctx context.Context, requestURI string, client Client) ([]byte, error) {
+	return f(ctx, requestURI, client)
+}
+
+func TestResolveRequestURI(t *testing.T) {
+	fetcher := requestURIFetcherFunc(func(_ context.Context, requestURI string, client Client) ([]byte, error) {
+		if requestURI == "https://client.example.com/object" || isPushedAuthorizationRequestURI(requestURI) {
+			return []byte("object"), nil
+		}
+		return nil, fmt.Errorf("not found")
+	})
+	store := &pushedRequestStore{requests: map[string]*PushedAuthorizationRequest{
+		PushedAuthorizationRequestURIPrefix + "pushed":  {ClientID: "par-client", Request: "pushed", ExpiresAt: time.Now().Add(time.Minute)},
+		PushedAuthorizationRequestURIPrefix + "expired": {ClientID: "par-client", Request: "expired", ExpiresAt: time.Now().Add(-time.Minute)},
+	}}
+	client := &DefaultClient{ID: "foo", RequestURIs: []string{"https://client.example.com/object"}}
+	parClient := &DefaultClient{ID: "par-client", RequestURIs: []string{"https://client.example.com/object"}, RequirePushedAuthorizationRequests: true}
+
+	for k, c := range []struct {
+		description string
+		fosite      *Fosite
+		client      Client
+		form        url.Values
+		expectErr   error
+		expectForm  url.Values
+	}{
+		{
+			description: "should pass when request_uri is not used",
+			fosite:      &Fosite{},
+			form:        url.Values{"foo": {"bar"}},
+			expectForm:  url.Values{"foo": {"bar"}},
+		},
+		{
+			description: "should fail when request_uri is not supported",
+			fosite:      &Fosite{},
+			form:        url.Values{"request_uri": {"https://client.example.com/object"}},
+			expectErr:   ErrRequestURINotSupported,
+		},
+		{
+			description: "should fail when request and request_uri are both used",
+			fosite:      &Fosite{RequestURIFetcher: fetcher},
+			form:        url.Values{"request_uri": {"https://client.example.com/object"}, "request": {"object"}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail when fetching fails",
+			fosite:      &Fosite{RequestURIFetcher: fetcher},
+			form:        url.Values{"request_uri": {"https://client.example.com/unknown"}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+		{
+			description: "should fail when the request_uri is not registered",
+			fosite:      &Fosite{RequestURIFetcher: fetcher, RequireRegisteredRequestURIs: true},
+			form:        url.Values{"request_uri": {"https://client.example.com/object?foo"}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+		{
+			description: "should replace request_uri with the fetched request object",
+			fosite:      &Fosite{RequestURIFetcher: fetcher, RequireRegisteredRequestURIs: true},
+			form:        url.Values{"request_uri": {"https://client.example.com/object"}, "foo": {"bar"}},
+			expectForm:  url.Values{"request": {"object"}, "foo": {"bar"}},
+		},
+		{
+			description: "should fail when a client required to use PAR sends a direct request",
+			fosite:      &Fosite{RequestURIFetcher: fetcher},
+			client:      parClient,
+			form:        url.Values{"foo": {"bar"}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail when a client required to use PAR passes its own request_uri",
+			fosite:      &Fosite{RequestURIFetcher: fetcher},
+			client:      parClient,
+			form:        url.Values{"request_uri": {"https://client.example.com/object"}},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should resolve pushed authorization requests without them being registered",
+			fosite:      &Fosite{Store: store, RequestURIFetcher: fetcher, RequireRegisteredRequestURIs: true},
+			client:      parClient,
+			form:        url.Values{"request_uri": {PushedAuthorizationRequestURIPrefix + "pushed"}},
+			expectForm:  url.Values{"request": {"pushed"}},
+		},
+		{
+			description: "should resolve pushed authorization requests without a request_uri fetcher",
+			fosite:      &Fosite{Store: store},
+			client:      parClient,
+			form:        url.Values{"request_uri": {PushedAuthorizationRequestURIPrefix + "pushed"}},
+			expectForm:  url.Values{"request": {"pushed"}},
+		},
+		{
+			description: "should fail when a pushed authorization request is redeemed by another client",
+			fosite:      &Fosite{Store: store, RequestURIFetcher: fetcher},
+			form:        url.Values{"request_uri": {PushedAuthorizationRequestURIPrefix + "pushed"}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+		{
+			description: "should fail when a pushed authorization request expired",
+			fosite:      &Fosite{Store: store, RequestURIFetcher: fetcher},
+			client:      parClient,
+			form:        url.Values{"request_uri": {PushedAuthorizationRequestURIPrefix + "expired"}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+		{
+			description: "should fail when a forged pushed authorization request reference is not in the store",
+			fosite:      &Fosite{Store: store, RequestURIFetcher: fetcher, RequireRegisteredRequestURIs: true},
+			client:      parClient,
+			form:        url.Values{"request_uri": {PushedAuthorizationRequestURIPrefix + "forged"}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+		{
+			description: "should fail when pushed authorization requests can not be looked up",
+			fosite:      &Fosite{RequestURIFetcher: fetcher},
+			client:      parClient,
+			form:        url.Values{"request_uri": {PushedAuthorizationRequestURIPrefix + "pushed"}},
+			expectErr:   ErrInvalidRequestURI,
+		},
+	} {
+		if c.client == nil {
+			c.client = client
+		}
+		request := &AuthorizeRequest{Request: Request{Form: c.form}}
+		err := c.fosite.resolveRequestURI(context.Background(), request, c.client)
+		if c.expectErr != nil {
+			assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+			continue
+		}
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+		assert.Equal(t, c.expectForm, request.Form, "(%d) %s", k, c.description)
+	}
+}
@@ -0,0 +1,51 @@
+package fosite
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+)
+
+// RequestEndpoint identifies the endpoint a request was sent to, see RequestValidator.
+type RequestEndpoint string
+
+const (
+	// AuthorizeRequestEndpoint requests are validated by NewAuthorizeRequest.
+	AuthorizeRequestEndpoint RequestEndpoint = "authorize"
+
+	// TokenRequestEndpoint requests are validated by NewAccessRequest.
+	TokenRequestEndpoint RequestEndpoint = "token"
+
+	// IntrospectionRequestEndpoint requests are validated by NewIntrospectionRequest.
+	IntrospectionRequestEndpoint RequestEndpoint = "introspection"
+
+	// RevocationRequestEndpoint requests are validated by NewRevocationRequest.
+	RevocationRequestEndpoint RequestEndpoint = "revocation"
+)
+
+// RequestValidator enforces policies which apply to all requests, e.g. blocking clients or requiring TLS. It is
+// called once the client of the request is known, before the request is passed to any handler.
+type RequestValidator interface {
+	// ValidateRequest returns an error if the request must be rejected, which is written to the client like the
+	// errors of the endpoint, e.g. errors.New(ErrAccessDenied). client was authenticated unless endpoint is
+	// AuthorizeRequestEndpoint, in which case it is the client identified by the client_id parameter.
+	ValidateRequest(ctx context.Context, endpoint RequestEndpoint, r *http.Request, client Client) error
+}
+
+// RequestValidators is a list of RequestValidator
+type RequestValidators []RequestValidator
+
+// Add adds an RequestValidator to this list
+func (v *RequestValidators) Append(h RequestValidator) {
+	*v = append(*v, h)
+}
+
+// validateRequest asks all RequestValidators to validate the request and returns the first error.
+func (f *Fosite) validateRequest(ctx context.Context, endpoint RequestEndpoint, r *http.Request, client Client) error {
+	for _, validator := range f.RequestValidators {
+		if err := validator.ValidateRequest(ctx, endpoint, r, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package fosite_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// clientBlocker rejects all requests of the blocked client.
+type clientBlocker struct {
+	blocked   string
+	endpoints []RequestEndpoint
+}
+
+func (v *clientBlocker) ValidateRequest(_ context.Context, endpoint RequestEndpoint, _ *http.Request, client Client) error {
+	v.endpoints = append(v.endpoints, endpoint)
+	if client.GetID() == v.blocked {
+		return errors.New(ErrAccessDenied)
+	}
+	return nil
+}
+
+func TestRequestValidatorsAreAskedBeforeHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	revocation := internal.NewMockRevocationHandler(ctrl)
+	introspector := internal.NewMockTokenIntrospector(ctrl)
+	tokenHandler := internal.NewMockTokenEndpointHandler(ctrl)
+	authorizeHandler := internal.NewMockAuthorizeEndpointHandler(ctrl)
+	defer ctrl.Finish()
+
+	blocker := &clientBlocker{blocked: "foo"}
+	client := &DefaultClient{ID: "foo", Secret: []byte("foo"), RedirectURIs: []string{"https://foo.com/cb"}}
+	f := &Fosite{
+		Store:                     store,
+		Hasher:                    hasher,
+		RequestValidators:         RequestValidators{blocker},
+		RevocationHandlers:        RevocationHandlers{revocation},
+		TokenIntrospectors:        TokenIntrospectors{introspector},
+		TokenEndpointHandlers:     TokenEndpointHandlers{tokenHandler},
+		AuthorizeEndpointHandlers: AuthorizeEndpointHandlers{authorizeHandler},
+	}
+	request := func(form url.Values) *http.Request {
+		return &http.Request{Method: "POST", Header: http.Header{"Authorization": {basicAuth("foo", "bar")}}, PostForm: form, Form: form}
+	}
+	authenticate := func() {
+		store.EXPECT().GetClient("foo").Return(client, nil)
+		hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+	}
+
+	for k, c := range []struct {
+		endpoint RequestEndpoint
+		run      func() error
+	}{
+		{
+			endpoint: AuthorizeRequestEndpoint,
+			run: func() error {
+				store.EXPECT().GetClient("foo").Return(client, nil)
+				form := url.Values{"client_id": {"foo"}, "response_type": {"code"}, "redirect_uri": {"https://foo.com/cb"}, "state": {"strong-state"}}
+				_, err := f.NewAuthorizeRequest(nil, &http.Request{Method: "GET", Form: form})
+				return err
+			},
+		},
+		{
+			endpoint: TokenRequestEndpoint,
+			run: func() error {
+				authenticate()
+				_, err := f.NewAccessRequest(nil, request(url.Values{"grant_type": {"client_credentials"}}), &auditSession{})
+				return err
+			},
+		},
+		{
+			endpoint: IntrospectionRequestEndpoint,
+			run: func() error {
+				authenticate()
+				_, err := f.NewIntrospectionRequest(nil, request(url.Values{"token": {"some-token"}}), nil)
+				return err
+			},
+		},
+		{
+			endpoint: RevocationRequestEndpoint,
+			run: func() error {
+				authenticate()
+				return f.NewRevocationRequest(nil, request(url.Values{"token": {"some-token"}}))
+			},
+		},
+	} {
+		blocker.endpoints = nil
+		err := c.run()
+		assert.True(t, errors.Is(ErrAccessDenied, err), "(%d) %s: %s", k, c.endpoint, err)
+		assert.Equal(t, []RequestEndpoint{c.endpoint}, blocker.endpoints, "(%d) %s", k, c.endpoint)
+	}
+
+	// Requests of other clients are passed to the handlers.
+	blocker.blocked = "bar"
+	authenticate()
+	revocation.EXPECT().RevokeToken(nil, "some-token", "", client).Return(nil)
+	assert.Nil(t, f.NewRevocationRequest(nil, request(url.Values{"token": {"some-token"}})))
+}
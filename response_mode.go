@@ -0,0 +1,94 @@
+package fosite
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/go-errors/errors"
+)
+
+// Response modes defined by http://openid.net/specs/oauth-v2-multiple-response-types-1_0.html#ResponseModes and
+// http://openid.net/specs/oauth-v2-form-post-response-mode-1_0.html
+const (
+	ResponseModeQuery    = "query"
+	ResponseModeFragment = "fragment"
+	ResponseModeFormPost = "form_post"
+)
+
+// defaultResponseModes are the response modes permitted for clients which do not restrict them, see
+// Client.GetResponseModes. They are the only modes WriteAuthorizeResponse is able to write.
+var defaultResponseModes = Arguments{ResponseModeQuery, ResponseModeFragment, ResponseModeFormPost}
+
+// validateResponseMode rejects response modes the client is not allowed to request. The response mode is optional,
+// the default mode of the response type is used if empty.
+//
+// Response types issuing tokens at the authorize endpoint must not use the query mode, see
+// http://openid.net/specs/oauth-v2-multiple-response-types-1_0.html#Combinations
+//
+//	For purposes of this specification, the default Response Mode for the OAuth 2.0 code Response Type is the
+//	query encoding. [...] the use of the query Response Mode with a Response Type that results in tokens being
+//	issued [...] MUST NOT be used.
+func validateResponseMode(mode string, responseTypes Arguments, client Client) error {
+	if mode == "" {
+		return nil
+	} else if !defaultResponseModes.Has(mode) {
+		return errors.New(ErrInvalidRequest)
+	} else if mode == ResponseModeQuery {
+		for _, responseType := range responseTypes {
+			if responseType != "code" && responseType != "none" {
+				return errors.New(ErrInvalidRequest)
+			}
+		}
+	}
+
+	allowed := client.GetResponseModes()
+	if len(allowed) == 0 {
+		allowed = defaultResponseModes
+	}
+
+	if !allowed.Has(mode) {
+		return errors.New(ErrInvalidRequest)
+	}
+	return nil
+}
+
+// formPostTemplate renders the HTML page delivering the parameters of a form_post response, see
+// http://openid.net/specs/oauth-v2-form-post-response-mode-1_0.html#FormPostResponseMode
+var formPostTemplate = template.Must(template.New("form_post").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Submit This Form</title></head>
+<body onload="javascript:document.forms[0].submit()">
+<form method="post" action="{{ .RedirectURI }}">
+{{ range $key, $values := .Parameters }}{{ range $values }}<input type="hidden" name="{{ $key }}" value="{{ . }}"/>
+{{ end }}{{ end }}</form>
+</body>
+</html>
+`))
+
+// writeFormPost writes an HTML page which makes the user-agent post parameters to the redirect URI of the client.
+//
+//	The Authorization Server MUST NOT redirect the User Agent, but rather return an HTML page to the User Agent.
+func writeFormPost(rw http.ResponseWriter, redirectURI *url.URL, parameters url.Values) {
+	h := rw.Header()
+	setNoCacheHeaders(h)
+	h.Set("Content-Type", "text/html;charset=UTF-8")
+	rw.WriteHeader(http.StatusOK)
+	formPostTemplate.Execute(rw, struct {
+		RedirectURI string
+		Parameters  url.Values
+	}{RedirectURI: redirectURI.String(), Parameters: parameters})
+}
+
+// mergeValues returns the values of all sets in a new url.Values.
+func mergeValues(sets ...url.Values) url.Values {
+	merged := url.Values{}
+	for _, set := range sets {
+		for key, values := range set {
+			for _, value := range values {
+				merged.Add(key, value)
+			}
+		}
+	}
+	return merged
+}
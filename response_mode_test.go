@@ -0,0 +1,36 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateResponseMode(t *testing.T) {
+	for k, c := range []struct {
+		mode          string
+		responseTypes Arguments
+		allowed       []string
+		expectError   bool
+	}{
+		{mode: ""},
+		{mode: "", allowed: []string{ResponseModeFormPost}},
+		{mode: ResponseModeQuery},
+		{mode: ResponseModeFragment},
+		{mode: ResponseModeFormPost},
+		{mode: "web_message", expectError: true},
+		{mode: "web_message", allowed: []string{"web_message"}, expectError: true},
+		{mode: ResponseModeFormPost, allowed: []string{ResponseModeFormPost}},
+		{mode: ResponseModeQuery, allowed: []string{ResponseModeFormPost}, expectError: true},
+		{mode: "QUERY", expectError: true},
+		{mode: ResponseModeQuery, responseTypes: Arguments{"code"}},
+		{mode: ResponseModeQuery, responseTypes: Arguments{"none"}},
+		{mode: ResponseModeQuery, responseTypes: Arguments{"token"}, expectError: true},
+		{mode: ResponseModeQuery, responseTypes: Arguments{"code", "id_token"}, expectError: true},
+		{mode: ResponseModeFragment, responseTypes: Arguments{"code"}},
+		{mode: ResponseModeFormPost, responseTypes: Arguments{"token", "id_token"}},
+	} {
+		err := validateResponseMode(c.mode, c.responseTypes, &DefaultClient{ResponseModes: c.allowed})
+		assert.Equal(t, c.expectError, err != nil, "case %d: %s", k, c.mode)
+	}
+}
@@ -0,0 +1,33 @@
+package fosite
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// setNoCacheHeaders marks a response as not cacheable. All responses of the authorize, token and introspection
+// endpoints contain sensitive information, see https://tools.ietf.org/html/rfc6749#section-5.1
+//
+//	The authorization server MUST include the HTTP "Cache-Control" response header field [RFC2616] with a
+//	value of "no-store" in any response containing tokens, credentials, or other sensitive information, as
+//	well as the "Pragma" response header field [RFC2616] with a value of "no-cache".
+func setNoCacheHeaders(h http.Header) {
+	h.Set("Cache-Control", "no-store")
+	h.Set("Pragma", "no-cache")
+}
+
+// writeJSON writes v as a non-cacheable JSON response with the given status code.
+func writeJSON(rw http.ResponseWriter, status int, v interface{}) {
+	h := rw.Header()
+	setNoCacheHeaders(h)
+
+	js, err := json.Marshal(v)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.Set("Content-Type", "application/json;charset=UTF-8")
+	rw.WriteHeader(status)
+	rw.Write(js)
+}
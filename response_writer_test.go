@@ -0,0 +1,26 @@
+package fosite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJSONSetsNoCacheHeaders(t *testing.T) {
+	for k, c := range []struct {
+		v          interface{}
+		expectCode int
+	}{
+		{v: map[string]interface{}{"foo": "bar"}, expectCode: http.StatusOK},
+		// channels can not be serialized, the error fallback must not be cacheable either
+		{v: make(chan int), expectCode: http.StatusInternalServerError},
+	} {
+		rw := httptest.NewRecorder()
+		writeJSON(rw, http.StatusOK, c.v)
+		assert.Equal(t, c.expectCode, rw.Code, "%d", k)
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"), "%d", k)
+		assert.Equal(t, "no-cache", rw.Header().Get("Pragma"), "%d", k)
+	}
+}
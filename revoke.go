@@ -0,0 +1,81 @@
+package fosite
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+)
+
+// RevocationHandler is responsible for revoking a token at the revocation endpoint.
+type RevocationHandler interface {
+	// RevokeToken revokes token if it was issued to client. tokenTypeHint is the optional token_type_hint
+	// parameter and may be used to speed up the lookup. Returns ErrUnknownRequest if the handler is not
+	// responsible for the token and ErrUnauthorizedClient if the token was issued to another client.
+	RevokeToken(ctx context.Context, token string, tokenTypeHint string, client Client) error
+}
+
+// NewRevocationRequest implements https://tools.ietf.org/html/rfc7009#section-2.1
+//
+//	The client requests the revocation of a particular token by making an HTTP POST request to the token
+//	revocation endpoint URL. [...] The authorization server first validates the client credentials (in case of
+//	a confidential client) and then verifies whether the token was issued to the client making the revocation
+//	request.
+//
+// Unknown or invalid tokens do not result in an error, see https://tools.ietf.org/html/rfc7009#section-2.2
+// ErrUnsupportedEndpoint is returned if no RevocationHandlers are registered.
+func (f *Fosite) NewRevocationRequest(ctx context.Context, r *http.Request) error {
+	if len(f.RevocationHandlers) == 0 {
+		return errors.New(ErrUnsupportedEndpoint)
+	} else if r.Method != "POST" {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	if err := f.parseForm(r); err != nil {
+		return err
+	}
+
+	client, err := f.authenticateClient(ctx, r)
+	if err != nil {
+		return err
+	} else if err := f.validateRequest(ctx, RevocationRequestEndpoint, r, client); err != nil {
+		return err
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		return errors.New(ErrInvalidRequest)
+	}
+
+	err = f.revokeToken(ctx, token, r.PostForm.Get("token_type_hint"), client)
+	f.GetAuditSink().Emit(ctx, &AuditEvent{Type: AuditTokenRevoked, Time: time.Now(), ClientID: client.GetID(), Success: err == nil, Err: err})
+	return err
+}
+
+func (f *Fosite) revokeToken(ctx context.Context, token string, tokenTypeHint string, client Client) error {
+	for _, handler := range f.RevocationHandlers {
+		if err := handler.RevokeToken(ctx, token, tokenTypeHint, client); errors.Is(err, ErrUnknownRequest) {
+			// Nothing to do
+		} else if err != nil {
+			return err
+		} else {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// WriteRevocationResponse writes the response of the revocation endpoint. If err is nil, the token was revoked
+// or was invalid, see https://tools.ietf.org/html/rfc7009#section-2.2
+func (f *Fosite) WriteRevocationResponse(rw http.ResponseWriter, err error) {
+	if err != nil {
+		rfcerr := f.toRFC6749Error(nil, err)
+		writeJSON(rw, rfcerr.StatusCode, rfcerr)
+		return
+	}
+
+	setNoCacheHeaders(rw.Header())
+	rw.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,122 @@
+package fosite_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRevocationRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	hasher := internal.NewMockHasher(ctrl)
+	handler := internal.NewMockRevocationHandler(ctrl)
+	defer ctrl.Finish()
+
+	client := &DefaultClient{ID: "foo", Secret: []byte("foo")}
+	f := &Fosite{Store: store, Hasher: hasher, RevocationHandlers: RevocationHandlers{handler}}
+	authenticate := func() {
+		store.EXPECT().GetClient("foo").Return(client, nil)
+		hasher.EXPECT().Compare([]byte("foo"), []byte("bar")).Return(nil)
+	}
+
+	for k, c := range []struct {
+		description string
+		method      string
+		header      http.Header
+		form        url.Values
+		setup       func()
+		expectErr   error
+	}{
+		{
+			description: "should fail because method is not POST",
+			method:      "GET",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup:       func() {},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail because client is not authenticated",
+			method:      "POST",
+			header:      http.Header{},
+			form:        url.Values{"token": {"some-token"}},
+			setup:       func() {},
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should fail because token is missing",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{},
+			setup:       authenticate,
+			expectErr:   ErrInvalidRequest,
+		},
+		{
+			description: "should pass because unknown tokens are ignored",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup: func() {
+				authenticate()
+				handler.EXPECT().RevokeToken(nil, "some-token", "", client).Return(errors.New(ErrUnknownRequest))
+			},
+		},
+		{
+			description: "should fail because the token was issued to another client",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}},
+			setup: func() {
+				authenticate()
+				handler.EXPECT().RevokeToken(nil, "some-token", "", client).Return(errors.New(ErrUnauthorizedClient))
+			},
+			expectErr: ErrUnauthorizedClient,
+		},
+		{
+			description: "should pass and pass the token type hint",
+			method:      "POST",
+			header:      http.Header{"Authorization": {basicAuth("foo", "bar")}},
+			form:        url.Values{"token": {"some-token"}, "token_type_hint": {"access_token"}},
+			setup: func() {
+				authenticate()
+				handler.EXPECT().RevokeToken(nil, "some-token", "access_token", client).Return(nil)
+			},
+		},
+	} {
+		c.setup()
+		r := &http.Request{Method: c.method, Header: c.header, PostForm: c.form, Form: c.form}
+		err := f.NewRevocationRequest(nil, r)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestNewRevocationRequestWithoutHandlers(t *testing.T) {
+	r := &http.Request{Method: "POST", Header: http.Header{}, PostForm: url.Values{"token": {"some-token"}}}
+	err := (&Fosite{}).NewRevocationRequest(nil, r)
+	assert.True(t, errors.Is(ErrUnsupportedEndpoint, err), "%s", err)
+}
+
+func TestWriteRevocationResponse(t *testing.T) {
+	f := &Fosite{}
+	for k, c := range []struct {
+		err        error
+		expectCode int
+	}{
+		{err: nil, expectCode: http.StatusOK},
+		{err: errors.New(ErrUnauthorizedClient), expectCode: http.StatusUnauthorized},
+	} {
+		rw := httptest.NewRecorder()
+		f.WriteRevocationResponse(rw, c.err)
+		assert.Equal(t, c.expectCode, rw.Code, "%d", k)
+		assert.Equal(t, "no-store", rw.Header().Get("Cache-Control"), "%d", k)
+	}
+}
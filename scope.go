@@ -1,8 +1,61 @@
 package fosite
 
+import (
+	"net/url"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// ClientWithScopeSeparators can be implemented by legacy clients which do not delimit the requested scopes with
+// spaces as required by https://tools.ietf.org/html/rfc6749#section-3.3 Scopes of all other clients are strictly
+// space-delimited.
+type ClientWithScopeSeparators interface {
+	// GetScopeSeparators returns the characters the client delimits scopes with in addition to spaces, e.g. ",".
+	GetScopeSeparators() string
+}
+
 func (f *Fosite) GetMandatoryScope() string {
 	if f.MandatoryScope == "" {
 		return DefaultMandatoryScope
 	}
 	return f.MandatoryScope
 }
+
+// GetScopeStrategy returns the scope strategy. Returns HierarchicScopeStrategy if no strategy was set.
+func (f *Fosite) GetScopeStrategy() ScopeStrategy {
+	if f.ScopeStrategy == nil {
+		return HierarchicScopeStrategy
+	}
+	return f.ScopeStrategy
+}
+
+// validateClientScopes returns ErrInvalidScope if the client is not allowed to request one of the scopes.
+func (f *Fosite) validateClientScopes(client Client, scopes Arguments) error {
+	strategy := f.GetScopeStrategy()
+	for _, scope := range scopes {
+		if !strategy(client.GetScopes(), scope) {
+			return errors.New(ErrInvalidScope)
+		}
+	}
+	return nil
+}
+
+// parseScopes splits the scope parameter sent by client. If the client uses other separators than spaces, form is
+// normalized to the space-delimited scopes, so that handlers reading the parameter, or the stored request, see the
+// standard representation.
+func parseScopes(form url.Values, client Client) Arguments {
+	c, ok := client.(ClientWithScopeSeparators)
+	if !ok || c.GetScopeSeparators() == "" {
+		return removeEmpty(strings.Split(form.Get("scope"), " "))
+	}
+
+	separators := " " + c.GetScopeSeparators()
+	scopes := Arguments(removeEmpty(strings.FieldsFunc(form.Get("scope"), func(r rune) bool {
+		return strings.ContainsRune(separators, r)
+	})))
+	if _, ok := form["scope"]; ok {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+	return scopes
+}
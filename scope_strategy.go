@@ -0,0 +1,39 @@
+package fosite
+
+import "strings"
+
+// ScopeStrategy is a strategy for matching scopes. It returns true if needle is fulfilled by one of the
+// scopes in haystack.
+type ScopeStrategy func(haystack []string, needle string) bool
+
+// HierarchicScopeStrategy is a ScopeStrategy where scopes are separated by dots. A scope grants all of its
+// sub scopes, e.g. "picture" grants "picture.read" and "picture.write" but "picture.read" does not grant "picture".
+func HierarchicScopeStrategy(haystack []string, needle string) bool {
+	for _, this := range haystack {
+		// foo == foo -> true
+		if this == needle {
+			return true
+		}
+
+		// picture.read > picture -> false (scope picture includes read, write, ...)
+		if len(this) > len(needle) {
+			continue
+		}
+
+		needles := strings.Split(needle, ".")
+		haystack := strings.Split(this, ".")
+		haystackLen := len(haystack) - 1
+		for k, needle := range needles {
+			if haystackLen < k {
+				return true
+			}
+
+			current := haystack[k]
+			if current != needle {
+				break
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+package fosite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHierarchicScopeStrategy(t *testing.T) {
+	var strategy ScopeStrategy = HierarchicScopeStrategy
+	var scopes = []string{}
+
+	assert.False(t, strategy(scopes, "foo.bar.baz"))
+	assert.False(t, strategy(scopes, "foo.bar"))
+	assert.False(t, strategy(scopes, "foo"))
+
+	scopes = []string{"foo.bar", "bar.baz", "baz.baz.1", "baz.baz.2", "baz.baz.3", "baz.baz.baz"}
+	assert.True(t, strategy(scopes, "foo.bar.baz"))
+	assert.True(t, strategy(scopes, "baz.baz.baz"))
+	assert.True(t, strategy(scopes, "foo.bar"))
+	assert.False(t, strategy(scopes, "foo"))
+
+	assert.True(t, strategy(scopes, "bar.baz"))
+	assert.True(t, strategy(scopes, "bar.baz.zad"))
+	assert.False(t, strategy(scopes, "bar"))
+	assert.False(t, strategy(scopes, "baz"))
+
+	// A scope must not be granted just because it is longer than a registered scope.
+	assert.False(t, strategy(scopes, "zab.zab.zab"))
+	assert.False(t, strategy(scopes, "foo.barbaz"))
+}
@@ -1,6 +1,7 @@
 package fosite
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,3 +14,67 @@ func TestGetRequiredScope(t *testing.T) {
 	f.MandatoryScope = "foo"
 	assert.Equal(t, "foo", f.GetMandatoryScope())
 }
+
+func TestGetScopeStrategy(t *testing.T) {
+	f := Fosite{}
+	assert.True(t, f.GetScopeStrategy()([]string{"foo"}, "foo.bar"))
+
+	f.ScopeStrategy = func(haystack []string, needle string) bool {
+		return false
+	}
+	assert.False(t, f.GetScopeStrategy()([]string{"foo"}, "foo"))
+}
+
+type legacyScopeClient struct {
+	*DefaultClient
+	separators string
+}
+
+func (c *legacyScopeClient) GetScopeSeparators() string { return c.separators }
+
+func TestParseScopes(t *testing.T) {
+	for k, c := range []struct {
+		description string
+		scope       string
+		client      Client
+		expect      Arguments
+		expectForm  string
+	}{
+		{
+			description: "should split scopes by spaces",
+			scope:       "foo  bar",
+			client:      &DefaultClient{},
+			expect:      Arguments{"foo", "bar"},
+			expectForm:  "foo  bar",
+		},
+		{
+			description: "should not split scopes by commas by default",
+			scope:       "foo,bar",
+			client:      &DefaultClient{},
+			expect:      Arguments{"foo,bar"},
+			expectForm:  "foo,bar",
+		},
+		{
+			description: "should not split scopes by commas if the client does not declare separators",
+			scope:       "foo,bar",
+			client:      &legacyScopeClient{DefaultClient: &DefaultClient{}},
+			expect:      Arguments{"foo,bar"},
+			expectForm:  "foo,bar",
+		},
+		{
+			description: "should split scopes by commas and normalize the form",
+			scope:       "foo,bar, baz,,",
+			client:      &legacyScopeClient{DefaultClient: &DefaultClient{}, separators: ","},
+			expect:      Arguments{"foo", "bar", "baz"},
+			expectForm:  "foo bar baz",
+		},
+	} {
+		form := url.Values{"scope": {c.scope}}
+		assert.Equal(t, c.expect, parseScopes(form, c.client), "(%d) %s", k, c.description)
+		assert.Equal(t, c.expectForm, form.Get("scope"), "(%d) %s", k, c.description)
+	}
+
+	form := url.Values{}
+	assert.Empty(t, parseScopes(form, &legacyScopeClient{DefaultClient: &DefaultClient{}, separators: ","}))
+	assert.NotContains(t, form, "scope")
+}
@@ -0,0 +1,90 @@
+// Package ssrf provides HTTP clients for fetching resources at locations chosen by OAuth2 clients, such as
+// request_uri or jwks_uri, without allowing server-side request forgery.
+package ssrf
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/go-errors/errors"
+)
+
+// MaxRedirects is the number of redirects followed by clients returned from NewClient.
+const MaxRedirects = 3
+
+// NewClient returns an HTTP client which refuses to connect to addresses rejected by isAllowedIP - also when
+// following redirects or after DNS resolution - and follows at most MaxRedirects redirects. isAllowedIP defaults
+// to IsPublicIP. If checkURL is not nil, it must accept the URL of every redirect. Every client has its own
+// connection pool, so clients should be created once and reused.
+func NewClient(timeout time.Duration, isAllowedIP func(ip net.IP) bool, checkURL func(u *url.URL) error) *http.Client {
+	if isAllowedIP == nil {
+		isAllowedIP = IsPublicIP
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// Control runs after DNS resolution for every address connected to, which prevents DNS rebinding.
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isAllowedIP(ip) {
+				return fmt.Errorf("Connecting to %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Proxies would connect on our behalf and bypass the address checks.
+			Proxy:       nil,
+			DialContext: dialer.DialContext,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= MaxRedirects {
+				return errors.New("Too many redirects")
+			} else if checkURL != nil {
+				return checkURL(req.URL)
+			}
+			return nil
+		},
+	}
+}
+
+// nonPublicNetworks are the address ranges which are not reachable from the internet, see
+// https://www.iana.org/assignments/iana-ipv4-special-registry and
+// https://www.iana.org/assignments/iana-ipv6-special-registry
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+	"192.0.0.0/24", "192.168.0.0/16", "198.18.0.0/15", "224.0.0.0/4", "240.0.0.0/4",
+	"::/128", "::1/128", "fc00::/7", "fe80::/10", "ff00::/8",
+)
+
+// IsPublicIP returns false if ip belongs to a private, loopback, link-local or otherwise reserved network.
+func IsPublicIP(ip net.IP) bool {
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for k, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[k] = network
+	}
+	return networks
+}
@@ -0,0 +1,88 @@
+package ssrf
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		assert.False(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "93.184.216.34", "2606:4700:4700::1111"} {
+		assert.True(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if to := r.URL.Query().Get("to"); to != "" {
+			http.Redirect(rw, r, to, http.StatusFound)
+			return
+		}
+		rw.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	loopback := func(ip net.IP) bool { return ip.IsLoopback() }
+	redirect := func(to string) string { return ts.URL + "/?to=" + url.QueryEscape(to) }
+
+	for k, c := range []struct {
+		description string
+		client      *http.Client
+		uri         string
+		expectErr   bool
+	}{
+		{
+			description: "should refuse private addresses by default",
+			client:      NewClient(time.Second, nil, nil),
+			uri:         ts.URL,
+			expectErr:   true,
+		},
+		{
+			description: "should connect to allowed addresses",
+			client:      NewClient(time.Second, loopback, nil),
+			uri:         ts.URL,
+		},
+		{
+			description: "should refuse redirects to addresses which are not allowed",
+			client:      NewClient(time.Second, loopback, nil),
+			uri:         redirect("http://10.0.0.1/"),
+			expectErr:   true,
+		},
+		{
+			description: "should refuse redirects rejected by checkURL",
+			client:      NewClient(time.Second, loopback, func(*url.URL) error { return errors.New("not allowed") }),
+			uri:         redirect(ts.URL),
+			expectErr:   true,
+		},
+		{
+			description: "should refuse too many redirects",
+			client:      NewClient(time.Second, loopback, nil),
+			uri:         redirect(redirect(redirect(redirect(ts.URL)))),
+			expectErr:   true,
+		},
+		{
+			description: "should follow redirects",
+			client:      NewClient(time.Second, loopback, nil),
+			uri:         redirect(redirect(ts.URL)),
+		},
+	} {
+		resp, err := c.client.Get(c.uri)
+		if c.expectErr {
+			assert.NotNil(t, err, "(%d) %s", k, c.description)
+			continue
+		}
+		require.Nil(t, err, "(%d) %s: %s", k, c.description, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "(%d) %s", k, c.description)
+	}
+}
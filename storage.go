@@ -1,6 +1,33 @@
 package fosite
 
+import (
+	"time"
+
+	"golang.org/x/net/context"
+)
+
 // Storage defines fosite's minimal storage interface.
 type Storage interface {
 	ClientManager
 }
+
+// Transactional can be implemented by storages to run several operations, e.g. storing the tokens issued by
+// Fosite.NewAccessResponse or the lookups of Fosite.IntrospectTokens, in a single transaction. The transaction is
+// carried by the returned context.
+type Transactional interface {
+	// BeginTX starts a transaction and returns a context carrying it.
+	BeginTX(ctx context.Context) (context.Context, error)
+
+	// Commit commits the transaction carried by ctx.
+	Commit(ctx context.Context) error
+
+	// Rollback aborts the transaction carried by ctx.
+	Rollback(ctx context.Context) error
+}
+
+// ExpiryAware can be implemented by storages to remove authorize codes, tokens and sessions which are no longer
+// usable, see Fosite.PurgeExpired.
+type ExpiryAware interface {
+	// PurgeExpired removes everything which expired before the given time.
+	PurgeExpired(ctx context.Context, before time.Time) error
+}
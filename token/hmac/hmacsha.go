@@ -6,7 +6,6 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
-	"fmt"
 	"strings"
 
 	"github.com/go-errors/errors"
@@ -17,6 +16,10 @@ import (
 type HMACStrategy struct {
 	AuthCodeEntropy int
 	GlobalSecret    []byte
+
+	// Strict rejects global secrets shorter than 32 bytes when generating and validating tokens. Otherwise
+	// secrets of 16 bytes are accepted and only reported by ValidateConfiguration.
+	Strict bool
 }
 
 const (
@@ -32,12 +35,14 @@ var b64 = base64.URLEncoding.WithPadding(base64.NoPadding)
 // Generate generates a token and a matching signature or returns an error.
 // This method implements rfc6819 Section 5.1.4.2.2: Use High Entropy for Secrets.
 func (c *HMACStrategy) Generate() (string, string, error) {
-	if len(c.GlobalSecret) < minimumSecretLength/2 {
-		return "", "", errors.New("Secret is not strong enough")
+	if err := c.checkSecret(); err != nil {
+		return "", "", err
 	}
 
-	if c.AuthCodeEntropy < minimumEntropy {
-		c.AuthCodeEntropy = minimumEntropy
+	// The strategy is shared by concurrent requests and therefore not modified.
+	entropy := c.AuthCodeEntropy
+	if entropy < minimumEntropy {
+		entropy = minimumEntropy
 	}
 
 	// When creating secrets not intended for usage by human users (e.g.,
@@ -47,43 +52,92 @@ func (c *HMACStrategy) Generate() (string, string, error) {
 	// constructed from a cryptographically strong random or pseudo-random
 	// number sequence (see [RFC4086] for best current practice) generated
 	// by the authorization server.
-	key, err := rand.RandomBytes(c.AuthCodeEntropy)
+	key, err := rand.RandomBytes(entropy)
 	if err != nil {
 		return "", "", errors.New(err)
 	}
 
-	if len(key) < c.AuthCodeEntropy {
+	if len(key) < entropy {
 		return "", "", errors.New("Could not read enough random data for key generation")
 	}
 
-	useSecret := append([]byte{}, c.GlobalSecret...)
-	mac := hmac.New(sha256.New, useSecret)
-	_, err = mac.Write(key)
-	if err != nil {
-		return "", "", errors.New(err)
+	// The token is encoded into a single buffer, the signature is a substring of the token.
+	var signature [sha256.Size]byte
+	mac := hmac.New(sha256.New, c.GlobalSecret)
+	mac.Write(key)
+
+	keyLen := b64.EncodedLen(len(key))
+	buf := make([]byte, keyLen+1+b64.EncodedLen(sha256.Size))
+	b64.Encode(buf, key)
+	buf[keyLen] = '.'
+	b64.Encode(buf[keyLen+1:], mac.Sum(signature[:0]))
+
+	token := string(buf)
+	return token, token[keyLen+1:], nil
+}
+
+// ValidateFormat returns an error if token is not structurally valid, that is if it does not consist of a key and
+// a signature of the expected lengths, encoded using unpadded base64url. It is much cheaper than Validate and does
+// not allocate, which makes it suitable for rejecting garbage before doing any further work.
+func (c *HMACStrategy) ValidateFormat(token string) error {
+	dot := strings.IndexByte(token, '.')
+	if dot < 0 {
+		return errors.New("Key and signature must both be set")
+	}
+
+	key, signature := token[:dot], token[dot+1:]
+	if len(key) < b64.EncodedLen(minimumEntropy) || len(signature) != b64.EncodedLen(sha256.Size) {
+		return errors.New("Key or signature have an invalid length")
+	} else if !isBase64URL(key) || !isBase64URL(signature) {
+		return errors.New("Key and signature must be base64url encoded")
+	}
+	return nil
+}
+
+func isBase64URL(in string) bool {
+	for i := 0; i < len(in); i++ {
+		switch b := in[i]; {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkSecret returns an error if the global secret is too short to sign tokens with, see Strict.
+func (c *HMACStrategy) checkSecret() error {
+	length := minimumSecretLength / 2
+	if c.Strict {
+		length = minimumSecretLength
 	}
 
-	signature := mac.Sum([]byte{})
-	encodedSignature := b64.EncodeToString(signature)
-	encodedToken := fmt.Sprintf("%s.%s", b64.EncodeToString(key), encodedSignature)
-	return encodedToken, encodedSignature, nil
+	if len(c.GlobalSecret) < length {
+		return errors.New("Secret is not strong enough")
+	}
+	return nil
 }
 
 // Validate validates a token and returns its signature or an error if the token is not valid.
 func (c *HMACStrategy) Validate(token string) (string, error) {
-	split := strings.Split(token, ".")
-	if len(split) != 2 {
+	if err := c.checkSecret(); err != nil {
+		return "", err
+	}
+
+	dot := strings.IndexByte(token, '.')
+	if dot < 0 || strings.IndexByte(token[dot+1:], '.') >= 0 {
 		return "", errors.New("Key and signature must both be set")
 	}
 
-	key := split[0]
-	signature := split[1]
+	key, signature := token[:dot], token[dot+1:]
 	if key == "" || signature == "" {
 		return "", errors.New("Key and signature must both be set")
 	}
 
-	decodedSignature, err := b64.DecodeString(signature)
-	if err != nil {
+	var decodedSignature [sha256.Size]byte
+	if b64.DecodedLen(len(signature)) != sha256.Size {
+		return "", errors.New("Key and signature do not match")
+	} else if _, err := b64.Decode(decodedSignature[:], []byte(signature)); err != nil {
 		return "", err
 	}
 
@@ -92,14 +146,10 @@ func (c *HMACStrategy) Validate(token string) (string, error) {
 		return "", err
 	}
 
-	useSecret := append([]byte{}, c.GlobalSecret...)
-	mac := hmac.New(sha256.New, useSecret)
-	_, err = mac.Write(decodedKey)
-	if err != nil {
-		return "", errors.New(err)
-	}
-
-	if !hmac.Equal(decodedSignature, mac.Sum([]byte{})) {
+	var expected [sha256.Size]byte
+	mac := hmac.New(sha256.New, c.GlobalSecret)
+	mac.Write(decodedKey)
+	if !hmac.Equal(decodedSignature[:], mac.Sum(expected[:0])) {
 		// Hash is invalid
 		return "", errors.New("Key and signature do not match")
 	}
@@ -1,6 +1,7 @@
 package hmac
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,6 +16,24 @@ func TestGenerateFailsWithShortCredentials(t *testing.T) {
 	require.Empty(t, signature)
 }
 
+func TestStrictRejectsShortSecrets(t *testing.T) {
+	cg := HMACStrategy{GlobalSecret: []byte("12345678901234567890")}
+	token, _, err := cg.Generate()
+	require.Nil(t, err, "%s", err)
+
+	cg.Strict = true
+	_, _, err = cg.Generate()
+	assert.NotNil(t, err)
+	_, err = cg.Validate(token)
+	assert.NotNil(t, err, "tokens signed with a short secret are rejected, too")
+
+	cg.GlobalSecret = []byte("12345678901234567890123456789012")
+	token, _, err = cg.Generate()
+	require.Nil(t, err, "%s", err)
+	_, err = cg.Validate(token)
+	assert.Nil(t, err, "%s", err)
+}
+
 func TestGenerate(t *testing.T) {
 	cg := HMACStrategy{
 		GlobalSecret: []byte("12345678901234567890"),
@@ -35,6 +54,17 @@ func TestGenerate(t *testing.T) {
 	require.NotNil(t, err, "%s", err)
 }
 
+func TestValidateKnownToken(t *testing.T) {
+	cg := HMACStrategy{
+		GlobalSecret: []byte("12345678901234567890"),
+	}
+
+	// The key consists of the bytes 0 to 31, the token format must not change between releases.
+	signature, err := cg.Validate("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8.rhEvq2tFTwxD_SDjVFRMNW_rBhifuBiy_rxCyws57II")
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "rhEvq2tFTwxD_SDjVFRMNW_rBhifuBiy_rxCyws57II", signature)
+}
+
 func TestValidateSignatureRejects(t *testing.T) {
 	var err error
 	cg := HMACStrategy{
@@ -52,3 +82,68 @@ func TestValidateSignatureRejects(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestValidateFormat(t *testing.T) {
+	cg := HMACStrategy{
+		GlobalSecret: []byte("12345678901234567890"),
+	}
+
+	token, _, err := cg.Generate()
+	require.Nil(t, err, "%s", err)
+	assert.Nil(t, cg.ValidateFormat(token))
+
+	for k, c := range []string{
+		"",
+		"foo.bar",
+		token + "a",
+		token[:10] + token[strings.Index(token, "."):],
+		"+" + token[1:],
+		strings.Replace(token, ".", ".=", 1)[:len(token)],
+		token + "." + token,
+	} {
+		assert.NotNil(t, cg.ValidateFormat(c), "%d", k)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	cg := HMACStrategy{
+		GlobalSecret: []byte("12345678901234567890"),
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cg.Generate()
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	cg := HMACStrategy{
+		GlobalSecret: []byte("12345678901234567890"),
+	}
+	token, _, _ := cg.Generate()
+
+	b.Run("case=valid", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cg.Validate(token)
+		}
+	})
+	b.Run("case=malformed-format-check", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cg.ValidateFormat("not-a-token")
+		}
+	})
+	b.Run("case=malformed-full-validation", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cg.Validate("not-a-token")
+		}
+	})
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	assert.NotNil(t, (&HMACStrategy{}).ValidateConfiguration())
+	assert.NotNil(t, (&HMACStrategy{GlobalSecret: []byte("foobarfoobarfoobarfoobar")}).ValidateConfiguration())
+	assert.NotNil(t, (&HMACStrategy{GlobalSecret: []byte("foobarfoobarfoobarfoobar"), Strict: true}).ValidateConfiguration())
+	assert.Nil(t, (&HMACStrategy{GlobalSecret: []byte("foobarfoobarfoobarfoobarfoobarfo")}).ValidateConfiguration())
+}
@@ -0,0 +1,12 @@
+package hmac
+
+import "github.com/go-errors/errors"
+
+// ValidateConfiguration returns an error if the global secret is shorter than 32 bytes. Unless Strict is set,
+// Generate and Validate only reject secrets of less than 16 bytes.
+func (c *HMACStrategy) ValidateConfiguration() error {
+	if len(c.GlobalSecret) < minimumSecretLength {
+		return errors.Errorf("Global secret has %d bytes, at least %d bytes are required", len(c.GlobalSecret), minimumSecretLength)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package jwk
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/ssrf"
+	"golang.org/x/net/context"
+)
+
+const (
+	// DefaultFetchTimeout is the default timeout for fetching a JWK set.
+	DefaultFetchTimeout = 10 * time.Second
+
+	// DefaultCacheTTL is the default duration fetched JWK sets are cached for.
+	DefaultCacheTTL = time.Hour
+
+	// DefaultMinRefreshInterval is the default minimum duration between two refreshes of a JWK set caused by an
+	// unknown key ID.
+	DefaultMinRefreshInterval = time.Minute
+
+	// maxKeySetSize limits the size of fetched JWK sets in bytes.
+	maxKeySetSize = 1 << 20
+)
+
+// Fetcher fetches and caches JWK sets published at a URI, for example a client's jwks_uri, see
+// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+//
+// Key sets are cached per owner and URI until TTL expires. Looking up a key ID which is not in the cached set
+// refreshes it, which allows owners to rotate their keys. The zero value is ready to use and safe for concurrent
+// use.
+type Fetcher struct {
+	// HTTPClient is used for fetching JWK sets. Because URIs are usually chosen by clients, it defaults to a client
+	// using Timeout which refuses to connect to private, loopback and link-local addresses, see ssrf.NewClient.
+	HTTPClient *http.Client
+
+	// Timeout limits the time for fetching a JWK set if HTTPClient is not set. Defaults to DefaultFetchTimeout.
+	Timeout time.Duration
+
+	// TTL is the duration fetched JWK sets are cached for. Defaults to DefaultCacheTTL.
+	TTL time.Duration
+
+	// MinRefreshInterval is the minimum duration between two refreshes caused by an unknown key ID. This prevents
+	// requests with random key IDs from causing a fetch each. Defaults to DefaultMinRefreshInterval.
+	MinRefreshInterval time.Duration
+
+	mu    sync.Mutex
+	cache map[cacheKey]*cachedKeySet
+
+	// defaultClient is created once, so that connections are reused between fetches.
+	defaultClient     *http.Client
+	defaultClientOnce sync.Once
+
+	// isAllowedIP decides which addresses the default client may connect to. Defaults to ssrf.IsPublicIP.
+	isAllowedIP func(ip net.IP) bool
+}
+
+type cacheKey struct {
+	owner string
+	uri   string
+}
+
+type cachedKeySet struct {
+	keys      *JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// KeySet returns the JWK set owned by owner and published at uri, fetching it if it is not cached or expired.
+func (f *Fetcher) KeySet(ctx context.Context, owner, uri string) (*JSONWebKeySet, error) {
+	if cached := f.cached(owner, uri); cached != nil && time.Since(cached.fetchedAt) < f.getTTL() {
+		return cached.keys, nil
+	}
+	return f.fetch(ctx, owner, uri)
+}
+
+// FindByID returns the key identified by kid from the JWK set owned by owner and published at uri. If the key is
+// not in the cached set, the set is fetched again. Returns an error if no such key exists.
+func (f *Fetcher) FindByID(ctx context.Context, owner, uri, kid string) (*JSONWebKey, error) {
+	keys, err := f.KeySet(ctx, owner, uri)
+	if err != nil {
+		return nil, err
+	} else if key := keys.FindByID(kid); key != nil {
+		return key, nil
+	}
+
+	if cached := f.cached(owner, uri); cached != nil && time.Since(cached.fetchedAt) < f.getMinRefreshInterval() {
+		return nil, errors.Errorf("Key %s is unknown", kid)
+	}
+
+	keys, err = f.fetch(ctx, owner, uri)
+	if err != nil {
+		return nil, err
+	} else if key := keys.FindByID(kid); key != nil {
+		return key, nil
+	}
+	return nil, errors.Errorf("Key %s is unknown", kid)
+}
+
+func (f *Fetcher) cached(owner, uri string) *cachedKeySet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cache[cacheKey{owner: owner, uri: uri}]
+}
+
+func (f *Fetcher) fetch(ctx context.Context, owner, uri string) (*JSONWebKeySet, error) {
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	if ctx != nil {
+		req = req.WithContext(ctx)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.getHTTPClient().Do(req)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Fetching the JWK set failed with status code %d", resp.StatusCode)
+	}
+
+	var keys JSONWebKeySet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxKeySetSize)).Decode(&keys); err != nil {
+		return nil, errors.New(err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cache == nil {
+		f.cache = map[cacheKey]*cachedKeySet{}
+	}
+	f.cache[cacheKey{owner: owner, uri: uri}] = &cachedKeySet{keys: &keys, fetchedAt: time.Now()}
+	return &keys, nil
+}
+
+func (f *Fetcher) getHTTPClient() *http.Client {
+	if f.HTTPClient != nil {
+		return f.HTTPClient
+	}
+
+	f.defaultClientOnce.Do(func() {
+		timeout := f.Timeout
+		if timeout == 0 {
+			timeout = DefaultFetchTimeout
+		}
+		f.defaultClient = ssrf.NewClient(timeout, f.isAllowedIP, nil)
+	})
+	return f.defaultClient
+}
+
+func (f *Fetcher) getTTL() time.Duration {
+	if f.TTL == 0 {
+		return DefaultCacheTTL
+	}
+	return f.TTL
+}
+
+func (f *Fetcher) getMinRefreshInterval() time.Duration {
+	if f.MinRefreshInterval == 0 {
+		return DefaultMinRefreshInterval
+	}
+	return f.MinRefreshInterval
+}
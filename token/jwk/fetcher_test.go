@@ -0,0 +1,128 @@
+package jwk
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func allowLoopback(ip net.IP) bool { return ip.IsLoopback() }
+
+func newKeySetServer(keys *JSONWebKeySet, fetches *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(fetches, 1)
+		if r.URL.Path != "/jwks.json" {
+			http.NotFound(rw, r)
+			return
+		}
+		json.NewEncoder(rw).Encode(keys)
+	}))
+}
+
+func TestFetcherCachesKeySets(t *testing.T) {
+	var fetches int32
+	keys := &JSONWebKeySet{Keys: []JSONWebKey{{KeyType: "RSA", KeyID: "foo"}}}
+	ts := newKeySetServer(keys, &fetches)
+	defer ts.Close()
+
+	f := &Fetcher{isAllowedIP: allowLoopback}
+	for i := 0; i < 3; i++ {
+		fetched, err := f.KeySet(context.Background(), "client", ts.URL+"/jwks.json")
+		require.Nil(t, err)
+		assert.Equal(t, keys, fetched)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&fetches))
+
+	// Key sets are cached per owner.
+	_, err := f.KeySet(context.Background(), "other-client", ts.URL+"/jwks.json")
+	require.Nil(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&fetches))
+
+	f.TTL = time.Nanosecond
+	_, err = f.KeySet(context.Background(), "client", ts.URL+"/jwks.json")
+	require.Nil(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&fetches))
+}
+
+func TestFetcherRefreshesOnUnknownKeyID(t *testing.T) {
+	var fetches int32
+	keys := &JSONWebKeySet{Keys: []JSONWebKey{{KeyType: "RSA", KeyID: "foo"}}}
+	ts := newKeySetServer(keys, &fetches)
+	defer ts.Close()
+
+	f := &Fetcher{MinRefreshInterval: time.Nanosecond, isAllowedIP: allowLoopback}
+	key, err := f.FindByID(context.Background(), "client", ts.URL+"/jwks.json", "foo")
+	require.Nil(t, err)
+	assert.Equal(t, "foo", key.KeyID)
+
+	keys.Keys = append(keys.Keys, JSONWebKey{KeyType: "RSA", KeyID: "bar"})
+	key, err = f.FindByID(context.Background(), "client", ts.URL+"/jwks.json", "bar")
+	require.Nil(t, err)
+	assert.Equal(t, "bar", key.KeyID)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&fetches))
+
+	_, err = f.FindByID(context.Background(), "client", ts.URL+"/jwks.json", "baz")
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&fetches))
+
+	// Unknown key IDs do not cause a refresh within MinRefreshInterval.
+	f.MinRefreshInterval = time.Hour
+	_, err = f.FindByID(context.Background(), "client", ts.URL+"/jwks.json", "baz")
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&fetches))
+}
+
+func TestFetcherFailures(t *testing.T) {
+	var fetches int32
+	ts := newKeySetServer(&JSONWebKeySet{}, &fetches)
+	defer ts.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	defer slow.Close()
+
+	invalid := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte("not json"))
+	}))
+	defer invalid.Close()
+
+	f := &Fetcher{Timeout: 100 * time.Millisecond, isAllowedIP: allowLoopback}
+	for k, uri := range []string{
+		ts.URL + "/unknown",
+		slow.URL,
+		invalid.URL,
+		"://foo",
+	} {
+		_, err := f.KeySet(context.Background(), "client", uri)
+		assert.NotNil(t, err, "%d", k)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestFetcherRefusesPrivateAddressesByDefault(t *testing.T) {
+	var fetches int32
+	ts := newKeySetServer(&JSONWebKeySet{}, &fetches)
+	defer ts.Close()
+
+	_, err := (&Fetcher{}).KeySet(context.Background(), "client", ts.URL+"/jwks.json")
+	assert.NotNil(t, err)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&fetches))
+}
+
+func TestFetcherReusesItsHTTPClient(t *testing.T) {
+	f := &Fetcher{}
+	require.NotNil(t, f.getHTTPClient())
+	assert.True(t, f.getHTTPClient() == f.getHTTPClient(), "the default client and its connection pool are created once")
+
+	custom := &http.Client{}
+	assert.True(t, (&Fetcher{HTTPClient: custom}).getHTTPClient() == custom)
+}
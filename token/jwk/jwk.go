@@ -0,0 +1,135 @@
+// Package jwk implements the subset of JSON Web Keys (https://tools.ietf.org/html/rfc7517) required for using
+// keys registered by clients.
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/go-errors/errors"
+)
+
+// JSONWebKey is a public JSON Web Key as defined in https://tools.ietf.org/html/rfc7517#section-4
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+
+	// RSA public key parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Elliptic curve public key parameters
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is a JWK set as defined in https://tools.ietf.org/html/rfc7517#section-5
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+var b64 = base64.URLEncoding.WithPadding(base64.NoPadding)
+
+// NewRSAPublicKey returns the JSON Web Key representation of an RSA public key.
+func NewRSAPublicKey(kid, use string, key *rsa.PublicKey) JSONWebKey {
+	return JSONWebKey{
+		KeyType: "RSA",
+		Use:     use,
+		KeyID:   kid,
+		N:       b64.EncodeToString(key.N.Bytes()),
+		E:       b64.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// Find returns the first key which may be used for use (e.g. "enc" or "sig") and alg. Keys which do not
+// declare a use or an algorithm match any use or algorithm. Returns nil if no key matches.
+func (s *JSONWebKeySet) Find(use, alg string) *JSONWebKey {
+	if s == nil {
+		return nil
+	}
+
+	for k, key := range s.Keys {
+		if key.Use != "" && key.Use != use {
+			continue
+		} else if key.Algorithm != "" && key.Algorithm != alg {
+			continue
+		}
+		return &s.Keys[k]
+	}
+	return nil
+}
+
+// FindByID returns the key identified by kid or nil if no such key exists.
+func (s *JSONWebKeySet) FindByID(kid string) *JSONWebKey {
+	if s == nil {
+		return nil
+	}
+
+	for k, key := range s.Keys {
+		if key.KeyID == kid {
+			return &s.Keys[k]
+		}
+	}
+	return nil
+}
+
+// PublicKey returns the key as *rsa.PublicKey or *ecdsa.PublicKey.
+func (k *JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		} else if e.BitLen() > 31 {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("Unsupported elliptic curve %s", k.Curve)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		} else if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("Point is not on the elliptic curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.Errorf("Unsupported key type %s", k.KeyType)
+}
+
+func decodeInt(in string) (*big.Int, error) {
+	if in == "" {
+		return nil, errors.New("Key parameter must not be empty")
+	}
+
+	raw, err := b64.DecodeString(in)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
@@ -0,0 +1,83 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSAPublicKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.Nil(t, err)
+
+	jwk := NewRSAPublicKey("foo", "enc", &key.PublicKey)
+	out, err := json.Marshal(jwk)
+	require.Nil(t, err)
+
+	var decoded JSONWebKey
+	require.Nil(t, json.Unmarshal(out, &decoded))
+	pub, err := decoded.PublicKey()
+	require.Nil(t, err)
+	assert.Equal(t, &key.PublicKey, pub)
+}
+
+func TestECPublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+
+	jwk := JSONWebKey{
+		KeyType: "EC",
+		Curve:   "P-256",
+		X:       b64.EncodeToString(key.X.Bytes()),
+		Y:       b64.EncodeToString(key.Y.Bytes()),
+	}
+	pub, err := jwk.PublicKey()
+	require.Nil(t, err)
+	assert.Equal(t, &key.PublicKey, pub)
+
+	jwk.Y = jwk.X
+	_, err = jwk.PublicKey()
+	assert.NotNil(t, err)
+}
+
+func TestInvalidPublicKey(t *testing.T) {
+	for k, c := range []JSONWebKey{
+		{},
+		{KeyType: "oct"},
+		{KeyType: "RSA"},
+		{KeyType: "RSA", N: "AQAB"},
+		{KeyType: "RSA", N: "AQAB", E: "#"},
+		{KeyType: "EC", Curve: "P-192"},
+	} {
+		_, err := c.PublicKey()
+		assert.NotNil(t, err, "%d", k)
+		t.Logf("Passed test case %d", k)
+	}
+}
+
+func TestFind(t *testing.T) {
+	set := &JSONWebKeySet{
+		Keys: []JSONWebKey{
+			{KeyID: "1", Use: "sig"},
+			{KeyID: "2", Use: "enc", Algorithm: "RSA-OAEP-256"},
+			{KeyID: "3"},
+		},
+	}
+
+	assert.Equal(t, "1", set.Find("sig", "RS256").KeyID)
+	assert.Equal(t, "2", set.Find("enc", "RSA-OAEP-256").KeyID)
+	assert.Equal(t, "3", set.Find("enc", "RSA-OAEP").KeyID)
+	assert.Equal(t, "2", set.FindByID("2").KeyID)
+	assert.Nil(t, set.FindByID("4"))
+	assert.Nil(t, (&JSONWebKeySet{}).Find("enc", "RSA-OAEP"))
+
+	var empty *JSONWebKeySet
+	assert.Nil(t, empty.Find("enc", "RSA-OAEP"))
+	assert.Nil(t, empty.FindByID("1"))
+}
@@ -22,6 +22,26 @@ func ToString(i interface{}) string {
 	return ""
 }
 
+// ToStringSlice converts a claim which is either a string or an array of strings, like aud, to a slice. Values of
+// other types are ignored.
+func ToStringSlice(i interface{}) []string {
+	switch v := i.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		ret := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				ret = append(ret, s)
+			}
+		}
+		return ret
+	}
+	return nil
+}
+
 func ToTime(i interface{}) time.Time {
 	if i == nil {
 		return time.Time{}
@@ -1,6 +1,10 @@
 package jwt
 
-import "time"
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
 
 type IDTokenClaims struct {
 	Issuer          string
@@ -13,10 +17,29 @@ type IDTokenClaims struct {
 	AccessTokenHash []byte
 	CodeHash        []byte
 	Extra           map[string]interface{}
+
+	// AuthenticationContextClassReference is the acr claim, it is omitted if empty.
+	AuthenticationContextClassReference string
+
+	// AuthenticationMethodsReference is the amr claim listing the methods used to authenticate the end-user, e.g.
+	// "pwd" and "otp". It is omitted if empty.
+	AuthenticationMethodsReference []string
+
+	// NotBefore is the nbf claim, the time before which the ID token must not be accepted. It is omitted if zero.
+	NotBefore time.Time
+
+	// JTI is the jti claim identifying the ID token. A random one is generated for every ID token if empty, so
+	// that ID tokens issued for the same session can be told apart.
+	JTI string
 }
 
 func (c *IDTokenClaims) ToMap() map[string]interface{} {
 	var ret = Copy(c.Extra)
+	ret["jti"] = c.JTI
+	if c.JTI == "" {
+		ret["jti"] = uuid.New()
+	}
+
 	ret["sub"] = c.Subject
 	ret["iss"] = c.Issuer
 	ret["aud"] = c.Audience
@@ -24,6 +47,15 @@ func (c *IDTokenClaims) ToMap() map[string]interface{} {
 	ret["at_hash"] = c.AccessTokenHash
 	ret["c_hash"] = c.CodeHash
 	ret["auth_time"] = c.AuthTime.Unix()
+	if c.AuthenticationContextClassReference != "" {
+		ret["acr"] = c.AuthenticationContextClassReference
+	}
+	if len(c.AuthenticationMethodsReference) > 0 {
+		ret["amr"] = c.AuthenticationMethodsReference
+	}
+	if !c.NotBefore.IsZero() {
+		ret["nbf"] = c.NotBefore.Unix()
+	}
 	ret["iat"] = c.IssuedAt.Unix()
 	ret["exp"] = c.ExpiresAt.Unix()
 	return ret
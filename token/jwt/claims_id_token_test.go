@@ -13,6 +13,7 @@ var idTokenClaims = &IDTokenClaims{
 	IssuedAt:  time.Now().Round(time.Second),
 	Issuer:    "fosite",
 	Audience:  "tests",
+	JTI:       "some-id",
 	ExpiresAt: time.Now().Add(time.Hour).Round(time.Second),
 	Extra: map[string]interface{}{
 		"foo": "bar",
@@ -33,6 +34,7 @@ func TestIDTokenAssert(t *testing.T) {
 
 func TestIDTokenClaimsToMap(t *testing.T) {
 	assert.Equal(t, map[string]interface{}{
+		"jti":       idTokenClaims.JTI,
 		"sub":       idTokenClaims.Subject,
 		"iat":       idTokenClaims.IssuedAt.Unix(),
 		"iss":       idTokenClaims.Issuer,
@@ -46,3 +48,30 @@ func TestIDTokenClaimsToMap(t *testing.T) {
 		"auth_time": idTokenClaims.AuthTime.Unix(),
 	}, idTokenClaims.ToMap())
 }
+
+func TestIDTokenClaimsToMapGeneratesID(t *testing.T) {
+	claims := &IDTokenClaims{}
+	first, second := claims.ToMap()["jti"], claims.ToMap()["jti"]
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+	assert.Empty(t, claims.JTI, "the claims are not modified")
+}
+
+func TestIDTokenClaimsToMapWithACR(t *testing.T) {
+	claims := &IDTokenClaims{AuthenticationContextClassReference: "urn:mace:incommon:iap:silver"}
+	assert.Equal(t, "urn:mace:incommon:iap:silver", claims.ToMap()["acr"])
+	assert.NotContains(t, idTokenClaims.ToMap(), "acr")
+}
+
+func TestIDTokenClaimsToMapWithAMR(t *testing.T) {
+	claims := &IDTokenClaims{AuthenticationMethodsReference: []string{"pwd", "otp"}}
+	assert.Equal(t, []string{"pwd", "otp"}, claims.ToMap()["amr"])
+	assert.NotContains(t, idTokenClaims.ToMap(), "amr")
+}
+
+func TestIDTokenClaimsToMapWithNotBefore(t *testing.T) {
+	nbf := time.Now().Add(time.Hour)
+	claims := &IDTokenClaims{NotBefore: nbf}
+	assert.Equal(t, nbf.Unix(), claims.ToMap()["nbf"])
+	assert.NotContains(t, idTokenClaims.ToMap(), "nbf")
+}
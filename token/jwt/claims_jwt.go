@@ -6,8 +6,10 @@ import (
 	"github.com/pborman/uuid"
 )
 
+// JWTClaimsFromMap converts the claims of a decoded token. The aud claim may be a string or an array, in which case
+// it is stored in Audiences and Audience is its first element.
 func JWTClaimsFromMap(m map[string]interface{}) *JWTClaims {
-	return &JWTClaims{
+	claims := &JWTClaims{
 		Subject:   ToString(m["sub"]),
 		IssuedAt:  ToTime(m["iat"]),
 		Issuer:    ToString(m["iss"]),
@@ -17,13 +19,30 @@ func JWTClaimsFromMap(m map[string]interface{}) *JWTClaims {
 		JTI:       ToString(m["jti"]),
 		Extra:     Filter(m, "sub", "iss", "iat", "nbf", "aud", "exp", "jti"),
 	}
+
+	if _, ok := m["aud"].(string); !ok {
+		if audiences := ToStringSlice(m["aud"]); len(audiences) > 0 {
+			claims.Audience = audiences[0]
+			claims.Audiences = audiences
+		}
+	}
+	return claims
 }
 
 // JWTClaims represent a token's claims.
 type JWTClaims struct {
-	Subject   string
-	Issuer    string
-	Audience  string
+	Subject  string
+	Issuer   string
+	Audience string
+
+	// Audiences is used instead of Audience if the token has several audiences. aud is then a JSON array, see
+	// https://tools.ietf.org/html/rfc7519#section-4.1.3
+	Audiences []string
+
+	// AudienceAsArray serializes aud as a JSON array even if there is a single audience, for resource servers
+	// which do not accept a string.
+	AudienceAsArray bool
+
 	JTI       string
 	IssuedAt  time.Time
 	NotBefore time.Time
@@ -42,12 +61,37 @@ func (c *JWTClaims) ToMap() map[string]interface{} {
 	ret["sub"] = c.Subject
 	ret["iss"] = c.Issuer
 	ret["aud"] = c.Audience
+	if audiences := c.GetAudiences(); len(audiences) > 1 || (len(audiences) == 1 && c.AudienceAsArray) {
+		ret["aud"] = audiences
+	} else if len(audiences) == 1 {
+		ret["aud"] = audiences[0]
+	}
 	ret["iat"] = c.IssuedAt.Unix()
 	ret["nbf"] = c.NotBefore.Unix()
 	ret["exp"] = c.ExpiresAt.Unix()
 	return ret
 }
 
+// GetAudiences returns the audiences of the token, which is Audiences if set or Audience otherwise.
+func (c *JWTClaims) GetAudiences() []string {
+	if len(c.Audiences) > 0 {
+		return c.Audiences
+	} else if c.Audience != "" {
+		return []string{c.Audience}
+	}
+	return nil
+}
+
+// HasAudience returns true if audience is one of the audiences of the token.
+func (c *JWTClaims) HasAudience(audience string) bool {
+	for _, a := range c.GetAudiences() {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
+
 func (c JWTClaims) Get(key string) interface{} {
 	return c.ToMap()[key]
 }
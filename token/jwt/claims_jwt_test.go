@@ -56,3 +56,31 @@ func TestClaimsToMap(t *testing.T) {
 		"baz": jwtClaims.Extra["baz"],
 	}, jwtClaims.ToMap())
 }
+
+func TestClaimsAudience(t *testing.T) {
+	for k, c := range []struct {
+		claims    *JWTClaims
+		expectAud interface{}
+	}{
+		{claims: &JWTClaims{Audience: "foo"}, expectAud: "foo"},
+		{claims: &JWTClaims{Audience: "foo", AudienceAsArray: true}, expectAud: []string{"foo"}},
+		{claims: &JWTClaims{Audiences: []string{"foo"}}, expectAud: "foo"},
+		{claims: &JWTClaims{Audiences: []string{"foo", "bar"}}, expectAud: []string{"foo", "bar"}},
+		{claims: &JWTClaims{Audience: "baz", Audiences: []string{"foo", "bar"}}, expectAud: []string{"foo", "bar"}},
+	} {
+		assert.Equal(t, c.expectAud, c.claims.ToMap()["aud"], "case %d", k)
+	}
+
+	// Both forms are accepted when decoding tokens.
+	single := JWTClaimsFromMap(map[string]interface{}{"aud": "foo"})
+	assert.Equal(t, []string{"foo"}, single.GetAudiences())
+	assert.True(t, single.HasAudience("foo"))
+
+	multiple := JWTClaimsFromMap(map[string]interface{}{"aud": []interface{}{"foo", "bar"}})
+	assert.Equal(t, "foo", multiple.Audience)
+	assert.Equal(t, []string{"foo", "bar"}, multiple.GetAudiences())
+	assert.True(t, multiple.HasAudience("bar"))
+	assert.False(t, multiple.HasAudience("baz"))
+
+	assert.Empty(t, JWTClaimsFromMap(map[string]interface{}{}).GetAudiences())
+}
@@ -1,12 +1,15 @@
 package jwt
 
+// AccessTokenType is the "typ" header of JWT access tokens, see https://tools.ietf.org/html/rfc9068#section-2.1
+const AccessTokenType = "at+jwt"
+
 // HeaderContext is the context for a jwt header.
 type Headers struct {
 	Extra map[string]interface{}
 }
 
 func (h *Headers) ToMap() map[string]interface{} {
-	var filter = map[string]bool{"alg": true, "typ": true}
+	var filter = map[string]bool{"alg": true}
 	var extra = map[string]interface{}{}
 
 	// filter known values from extra.
@@ -13,4 +13,11 @@ func TestHeaderToMap(t *testing.T) {
 	assert.Equal(t, map[string]interface{}{
 		"foo": "bar",
 	}, header.ToMap())
+
+	header.Add("alg", "none")
+	header.Add("typ", AccessTokenType)
+	assert.Equal(t, map[string]interface{}{
+		"foo": "bar",
+		"typ": AccessTokenType,
+	}, header.ToMap())
 }
@@ -0,0 +1,255 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"hash"
+	"strings"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/rand"
+	"gopkg.in/dgrijalva/jwt-go.v2"
+)
+
+// EncryptRSA wraps payload (usually a signed JWT) in a JWE using the compact serialization as defined in
+// https://tools.ietf.org/html/rfc7516#section-3.1
+//
+// Supported key management algorithms (alg) are RSA-OAEP and RSA-OAEP-256, supported content encryption
+// algorithms (enc) are A128CBC-HS256, A128GCM and A256GCM.
+func EncryptRSA(payload []byte, key *rsa.PublicKey, alg, enc, kid string) (string, error) {
+	if key == nil {
+		return "", errors.New("Encryption key must not be nil")
+	}
+
+	oaepHash, err := getOAEPHash(alg)
+	if err != nil {
+		return "", err
+	}
+
+	keyLength, err := getContentKeyLength(enc)
+	if err != nil {
+		return "", err
+	}
+
+	cek, err := rand.RandomBytes(keyLength)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(oaepHash, cryptorand.Reader, key, cek, nil)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	header := map[string]interface{}{"alg": alg, "enc": enc, "cty": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", errors.New(err)
+	}
+
+	// The additional authenticated data is the ASCII representation of the encoded protected header.
+	protected := jwt.EncodeSegment(rawHeader)
+	iv, ciphertext, tag, err := encryptContent(enc, cek, payload, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		protected,
+		jwt.EncodeSegment(encryptedKey),
+		jwt.EncodeSegment(iv),
+		jwt.EncodeSegment(ciphertext),
+		jwt.EncodeSegment(tag),
+	}, "."), nil
+}
+
+// DecryptRSA decrypts a JWE created by EncryptRSA and returns its payload.
+func DecryptRSA(token string, key *rsa.PrivateKey) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, errors.New("Header, encrypted key, initialization vector, ciphertext and tag must all be set")
+	}
+
+	decoded := make([][]byte, len(parts))
+	for k, part := range parts {
+		d, err := jwt.DecodeSegment(part)
+		if err != nil {
+			return nil, errors.New(err)
+		}
+		decoded[k] = d
+	}
+
+	var header struct {
+		Algorithm  string `json:"alg"`
+		Encryption string `json:"enc"`
+	}
+	if err := json.Unmarshal(decoded[0], &header); err != nil {
+		return nil, errors.New(err)
+	}
+
+	oaepHash, err := getOAEPHash(header.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	keyLength, err := getContentKeyLength(header.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := rsa.DecryptOAEP(oaepHash, cryptorand.Reader, key, decoded[1], nil)
+	if err != nil {
+		return nil, errors.New(err)
+	} else if len(cek) != keyLength {
+		return nil, errors.New("Content encryption key has an invalid length")
+	}
+
+	return decryptContent(header.Encryption, cek, decoded[2], decoded[3], decoded[4], []byte(parts[0]))
+}
+
+func getOAEPHash(alg string) (hash.Hash, error) {
+	switch alg {
+	case "RSA-OAEP":
+		return sha1.New(), nil
+	case "RSA-OAEP-256":
+		return sha256.New(), nil
+	}
+	return nil, errors.Errorf("Unsupported key management algorithm %s", alg)
+}
+
+func getContentKeyLength(enc string) (int, error) {
+	switch enc {
+	case "A128GCM":
+		return 16, nil
+	case "A128CBC-HS256", "A256GCM":
+		return 32, nil
+	}
+	return 0, errors.Errorf("Unsupported content encryption algorithm %s", enc)
+}
+
+func encryptContent(enc string, cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	if enc == "A128CBC-HS256" {
+		return encryptCBCHMAC(cek, plaintext, aad)
+	}
+
+	aead, err := newGCM(cek)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if iv, err = rand.RandomBytes(aead.NonceSize()); err != nil {
+		return nil, nil, nil, err
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	split := len(sealed) - aead.Overhead()
+	return iv, sealed[:split], sealed[split:], nil
+}
+
+func decryptContent(enc string, cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if enc == "A128CBC-HS256" {
+		return decryptCBCHMAC(cek, iv, ciphertext, tag, aad)
+	}
+
+	aead, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	} else if len(iv) != aead.NonceSize() {
+		return nil, errors.New("Initialization vector has an invalid length")
+	}
+
+	plaintext, err := aead.Open(nil, iv, append(append([]byte{}, ciphertext...), tag...), aad)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(cek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+	return aead, nil
+}
+
+// encryptCBCHMAC implements AES_128_CBC_HMAC_SHA_256 as defined in https://tools.ietf.org/html/rfc7518#section-5.2
+func encryptCBCHMAC(cek, plaintext, aad []byte) (iv, ciphertext, tag []byte, err error) {
+	macKey, encKey := cek[:16], cek[16:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, errors.New(err)
+	}
+
+	if iv, err = rand.RandomBytes(aes.BlockSize); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// PKCS #7 padding
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext = make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return iv, ciphertext, cbcHMACTag(macKey, aad, iv, ciphertext), nil
+}
+
+func decryptCBCHMAC(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	macKey, encKey := cek[:16], cek[16:]
+	if !hmac.Equal(tag, cbcHMACTag(macKey, aad, iv, ciphertext)) {
+		return nil, errors.New("Authentication tag does not match")
+	} else if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("Ciphertext has an invalid length")
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, errors.New("Invalid padding")
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if subtle.ConstantTimeByteEq(b, byte(padding)) != 1 {
+			return nil, errors.New("Invalid padding")
+		}
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+func cbcHMACTag(macKey, aad, iv, ciphertext []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+	return mac.Sum(nil)[:16]
+}
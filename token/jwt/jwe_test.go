@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dgrijalva/jwt-go.v2"
+)
+
+func TestEncryptRSA(t *testing.T) {
+	key := internal.MustRSAKey()
+	payload := []byte("header.claims.signature")
+
+	for _, alg := range []string{"RSA-OAEP", "RSA-OAEP-256"} {
+		for _, enc := range []string{"A128CBC-HS256", "A128GCM", "A256GCM"} {
+			token, err := EncryptRSA(payload, &key.PublicKey, alg, enc, "some-kid")
+			require.Nil(t, err, "%s %s: %s", alg, enc, err)
+			require.Len(t, strings.Split(token, "."), 5, "%s %s", alg, enc)
+
+			decrypted, err := DecryptRSA(token, key)
+			require.Nil(t, err, "%s %s: %s", alg, enc, err)
+			assert.Equal(t, payload, decrypted, "%s %s", alg, enc)
+
+			// Tampering with the protected header must be detected
+			parts := strings.Split(token, ".")
+			parts[0] = jwt.EncodeSegment([]byte(`{"alg":"` + alg + `","enc":"` + enc + `"}`))
+			_, err = DecryptRSA(strings.Join(parts, "."), key)
+			assert.NotNil(t, err, "%s %s", alg, enc)
+
+			// Tampering with the ciphertext must be detected
+			parts = strings.Split(token, ".")
+			parts[3] = parts[4]
+			_, err = DecryptRSA(strings.Join(parts, "."), key)
+			assert.NotNil(t, err, "%s %s", alg, enc)
+
+			// Decrypting with another key must fail
+			_, err = DecryptRSA(token, internal.MustRSAKey())
+			assert.NotNil(t, err, "%s %s", alg, enc)
+		}
+	}
+}
+
+func TestEncryptRSARejectsUnsupportedAlgorithms(t *testing.T) {
+	key := internal.MustRSAKey()
+
+	_, err := EncryptRSA([]byte("foo"), &key.PublicKey, "RSA1_5", "A128GCM", "")
+	assert.NotNil(t, err)
+
+	_, err = EncryptRSA([]byte("foo"), &key.PublicKey, "RSA-OAEP", "A192GCM", "")
+	assert.NotNil(t, err)
+
+	_, err = EncryptRSA([]byte("foo"), nil, "RSA-OAEP", "A128GCM", "")
+	assert.NotNil(t, err)
+
+	for k, c := range []string{
+		"",
+		"foo.bar",
+		"a.b.c.d.e",
+	} {
+		_, err = DecryptRSA(c, key)
+		assert.NotNil(t, err, "%d", k)
+	}
+}
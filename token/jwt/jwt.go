@@ -3,61 +3,121 @@
 package jwt
 
 import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
 	"fmt"
 	"strings"
-	"crypto/sha256"
-	"crypto/rsa"
 
-	"gopkg.in/dgrijalva/jwt-go.v2"
 	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+	"gopkg.in/dgrijalva/jwt-go.v2"
 )
 
 // Enigma is responsible for generating and validating challenges.
 type RS256JWTStrategy struct {
 	PrivateKey *rsa.PrivateKey
+
+	// Signer signs and validates tokens instead of PrivateKey if set, PrivateKey may then be nil.
+	Signer Signer
 }
 
 // Generate generates a new authorize code or returns an error. set secret
 func (j *RS256JWTStrategy) Generate(claims Mapper, header Mapper) (string, string, error) {
+	return j.GenerateWithContext(context.Background(), claims, header)
+}
+
+// GenerateWithContext is Generate passing ctx to the Signer.
+func (j *RS256JWTStrategy) GenerateWithContext(ctx context.Context, claims Mapper, header Mapper) (string, string, error) {
+	if j.Signer != nil {
+		return generateWithSigner(ctx, jwt.SigningMethodRS256, j.Signer, claims, header)
+	}
+	return generate(jwt.SigningMethodRS256, j.PrivateKey, claims, header)
+}
+
+// Validate : Validates a token and returns its signature or an error if the token is not valid.
+func (j *RS256JWTStrategy) Validate(token string) (string, error) {
+	if _, err := j.Decode(token); err != nil {
+		return "", errors.New(err)
+	}
+
+	return j.GetSignature(token)
+}
+
+func (j *RS256JWTStrategy) Decode(token string) (*jwt.Token, error) {
+	if j.Signer != nil {
+		return decodeWithSigner(token, jwt.SigningMethodRS256, j.Signer)
+	}
+	return decode(token, jwt.SigningMethodRS256, &j.PrivateKey.PublicKey)
+}
+
+func (j *RS256JWTStrategy) GetSignature(token string) (string, error) {
+	return getSignature(token)
+}
+
+func (c *RS256JWTStrategy) Hash(in []byte) ([]byte, error) {
+	// SigningMethodRS256
+	return hashSHA256(in)
+}
+
+func (c *RS256JWTStrategy) GetSigningMethodLength() int {
+	return jwt.SigningMethodRS256.Hash.Size()
+}
+
+func generate(method jwt.SigningMethod, key interface{}, claims Mapper, header Mapper) (string, string, error) {
 	if header == nil || claims == nil {
 		return "", "", errors.New("Either claims or header is nil.")
 	}
 
-	token := jwt.New(jwt.SigningMethodRS256)
-	token.Claims = claims.ToMap()
-	token.Header = assign(token.Header, header.ToMap())
-
+	token := newToken(method, claims, header)
 	var sig, sstr string
 	var err error
 	if sstr, err = token.SigningString(); err != nil {
 		return "", "", errors.New(err)
 	}
 
-	if sig, err = token.Method.Sign(sstr, j.PrivateKey); err != nil {
+	if sig, err = token.Method.Sign(sstr, key); err != nil {
 		return "", "", errors.New(err)
 	}
 
 	return fmt.Sprintf("%s.%s", sstr, sig), sig, nil
 }
 
-// Validate : Validates a token and returns its signature or an error if the token is not valid.
-func (j *RS256JWTStrategy) Validate(token string) (string, error) {
-	if _, err := j.Decode(token); err != nil {
-		return "", errors.New(err)
+// newToken returns an unsigned token containing claims and header.
+func newToken(method jwt.SigningMethod, claims Mapper, header Mapper) *jwt.Token {
+	token := jwt.New(method)
+	token.Claims = claims.ToMap()
+	headers := header.ToMap()
+	token.Header = assign(token.Header, headers)
+
+	// The default type "JWT" may be overridden, e.g. with "at+jwt" for access tokens.
+	if typ, ok := headers["typ"]; ok {
+		token.Header["typ"] = typ
 	}
+	return token
+}
 
-	return j.GetSignature(token)
+// decode parses and verifies token. Only tokens signed with method are accepted, every other algorithm -
+// and especially the unsigned "none" algorithm - is rejected before the signature is looked at.
+func decode(token string, method jwt.SigningMethod, key interface{}) (*jwt.Token, error) {
+	if err := checkKeyType(method, key); err != nil {
+		return nil, err
+	}
+
+	return decodeWithKeyFunc(token, method, func(_ *jwt.Token) (interface{}, error) {
+		return key, nil
+	})
 }
 
-func (j *RS256JWTStrategy) Decode(token string) (*jwt.Token, error) {
+// decodeWithKeyFunc is decode looking up the verification key using keyFunc once the algorithm was checked.
+func decodeWithKeyFunc(token string, method jwt.SigningMethod, keyFunc jwt.Keyfunc) (*jwt.Token, error) {
 	// Parse the token.
 	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+		if alg, _ := t.Header["alg"].(string); alg == jwt.SigningMethodNone.Alg() || alg != method.Alg() {
 			return nil, errors.Errorf("Unexpected signing method: %v", t.Header["alg"])
 		}
-		return &j.PrivateKey.PublicKey, nil
+		return keyFunc(t)
 	})
-
 	if err != nil {
 		return nil, errors.Errorf("Couldn't parse token: %v", err)
 	} else if !parsedToken.Valid {
@@ -67,7 +127,23 @@ func (j *RS256JWTStrategy) Decode(token string) (*jwt.Token, error) {
 	return parsedToken, err
 }
 
-func (j *RS256JWTStrategy) GetSignature(token string) (string, error) {
+// checkKeyType makes sure that a verification key is only ever used with the algorithm family it belongs to.
+// This prevents algorithm confusion, e.g. an RSA public key being used as the secret of a HS256 signature.
+func checkKeyType(method jwt.SigningMethod, key interface{}) error {
+	switch method.(type) {
+	case *jwt.SigningMethodRSA:
+		if _, ok := key.(*rsa.PublicKey); ok {
+			return nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if _, ok := key.(*ecdsa.PublicKey); ok {
+			return nil
+		}
+	}
+	return errors.Errorf("Key of type %T can not be used to verify %s signatures", key, method.Alg())
+}
+
+func getSignature(token string) (string, error) {
 	split := strings.Split(token, ".")
 	if len(split) != 3 {
 		return "", errors.New("Header, body and signature must all be set")
@@ -75,8 +151,7 @@ func (j *RS256JWTStrategy) GetSignature(token string) (string, error) {
 	return split[2], nil
 }
 
-func (c *RS256JWTStrategy) Hash(in []byte) ([]byte, error) {
-	// SigningMethodRS256
+func hashSHA256(in []byte) ([]byte, error) {
 	hash := sha256.New()
 	_, err := hash.Write(in)
 	if err != nil {
@@ -85,10 +160,6 @@ func (c *RS256JWTStrategy) Hash(in []byte) ([]byte, error) {
 	return hash.Sum([]byte{}), nil
 }
 
-func (c *RS256JWTStrategy) GetSigningMethodLength() int {
-	return jwt.SigningMethodRS256.Hash.Size()
-}
-
 func assign(a, b map[string]interface{}) map[string]interface{} {
 	for k, w := range b {
 		if _, ok := a[k]; ok {
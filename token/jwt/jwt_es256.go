@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/go-errors/errors"
+	"golang.org/x/net/context"
+	"gopkg.in/dgrijalva/jwt-go.v2"
+)
+
+// ES256JWTStrategy is responsible for generating and validating JWT challenges using ECDSA P-256 and SHA-256.
+type ES256JWTStrategy struct {
+	PrivateKey *ecdsa.PrivateKey
+
+	// Signer signs and validates tokens instead of PrivateKey if set, PrivateKey may then be nil.
+	Signer Signer
+}
+
+// Generate generates a new token signed with ES256 or returns an error.
+func (j *ES256JWTStrategy) Generate(claims Mapper, header Mapper) (string, string, error) {
+	return j.GenerateWithContext(context.Background(), claims, header)
+}
+
+// GenerateWithContext is Generate passing ctx to the Signer.
+func (j *ES256JWTStrategy) GenerateWithContext(ctx context.Context, claims Mapper, header Mapper) (string, string, error) {
+	if j.Signer != nil {
+		return generateWithSigner(ctx, jwt.SigningMethodES256, j.Signer, claims, header)
+	}
+	return generate(jwt.SigningMethodES256, j.PrivateKey, claims, header)
+}
+
+// Validate validates a token and returns its signature or an error if the token is not valid.
+func (j *ES256JWTStrategy) Validate(token string) (string, error) {
+	if _, err := j.Decode(token); err != nil {
+		return "", errors.New(err)
+	}
+
+	return j.GetSignature(token)
+}
+
+func (j *ES256JWTStrategy) Decode(token string) (*jwt.Token, error) {
+	if j.Signer != nil {
+		return decodeWithSigner(token, jwt.SigningMethodES256, j.Signer)
+	}
+	return decode(token, jwt.SigningMethodES256, &j.PrivateKey.PublicKey)
+}
+
+func (j *ES256JWTStrategy) GetSignature(token string) (string, error) {
+	return getSignature(token)
+}
+
+func (j *ES256JWTStrategy) Hash(in []byte) ([]byte, error) {
+	// SigningMethodES256
+	return hashSHA256(in)
+}
+
+func (j *ES256JWTStrategy) GetSigningMethodLength() int {
+	return jwt.SigningMethodES256.Hash.Size()
+}
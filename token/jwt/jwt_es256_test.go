@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateES256JWT(t *testing.T) {
+	claims := &JWTClaims{
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	j := ES256JWTStrategy{
+		PrivateKey: internal.MustECDSAKey(),
+	}
+
+	token, sig, err := j.Generate(claims, header)
+	require.Nil(t, err, "%s", err)
+	require.NotNil(t, token)
+
+	decoded, err := j.Decode(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "ES256", decoded.Header["alg"])
+
+	sig, err = j.Validate(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, strings.Split(token, ".")[2], sig)
+
+	_, err = j.Validate(token + "." + "0123456789")
+	require.NotNil(t, err, "%s", err)
+
+	// A token signed with another key must not validate
+	j.PrivateKey = internal.MustECDSAKey()
+	_, err = j.Validate(token)
+	require.NotNil(t, err, "%s", err)
+
+	// An RS256 token must not validate
+	rs := RS256JWTStrategy{PrivateKey: internal.MustRSAKey()}
+	token, _, err = rs.Generate(claims, header)
+	require.Nil(t, err, "%s", err)
+	_, err = j.Validate(token)
+	require.NotNil(t, err, "%s", err)
+}
@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"strings"
 	"testing"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/ory-am/fosite/internal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/dgrijalva/jwt-go.v2"
 )
 
 var header = &Headers{
@@ -123,3 +126,74 @@ func TestValidateSignatureRejectsJWT(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestValidateRejectsUnsignedAndUnexpectedAlgorithms(t *testing.T) {
+	rsaKey := internal.MustRSAKey()
+	ecKey := internal.MustECDSAKey()
+	claims := map[string]interface{}{"exp": time.Now().Add(time.Hour).Unix()}
+
+	sign := func(method jwt.SigningMethod, key interface{}) string {
+		token := jwt.New(method)
+		token.Claims = claims
+		signed, err := token.SignedString(key)
+		require.Nil(t, err, "%s", err)
+		return signed
+	}
+
+	unsigned := sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType)
+	for k, c := range []struct {
+		validate func(string) (string, error)
+		tokens   []string
+	}{
+		{
+			validate: (&RS256JWTStrategy{PrivateKey: rsaKey}).Validate,
+			tokens: []string{
+				unsigned,
+				strings.Join(append(strings.Split(sign(jwt.SigningMethodRS256, rsaKey), ".")[:2], ""), "."),
+				sign(jwt.SigningMethodRS512, rsaKey),
+				sign(jwt.SigningMethodES256, ecKey),
+			},
+		},
+		{
+			validate: (&ES256JWTStrategy{PrivateKey: ecKey}).Validate,
+			tokens: []string{
+				unsigned,
+				sign(jwt.SigningMethodHS256, []byte("some-secret")),
+				sign(jwt.SigningMethodRS256, rsaKey),
+			},
+		},
+	} {
+		for n, token := range c.tokens {
+			_, err := c.validate(token)
+			assert.NotNil(t, err, "%d/%d", k, n)
+		}
+	}
+}
+
+func TestValidateRejectsAlgorithmConfusion(t *testing.T) {
+	key := internal.MustRSAKey()
+	j := RS256JWTStrategy{PrivateKey: key}
+
+	// An attacker knowing the public key signs a token with HS256, using the public key as the HMAC secret.
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.Nil(t, err, "%s", err)
+	secret := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+
+	for k, secret := range [][]byte{secret, publicKey} {
+		token := jwt.New(jwt.SigningMethodHS256)
+		token.Claims = map[string]interface{}{"exp": time.Now().Add(time.Hour).Unix()}
+		forged, err := token.SignedString(secret)
+		require.Nil(t, err, "%s", err)
+
+		_, err = j.Validate(forged)
+		assert.NotNil(t, err, "%d", k)
+
+		_, err = j.Decode(forged)
+		assert.NotNil(t, err, "%d", k)
+	}
+
+	assert.NotNil(t, checkKeyType(jwt.SigningMethodHS256, secret))
+	assert.NotNil(t, checkKeyType(jwt.SigningMethodRS256, secret))
+	assert.NotNil(t, checkKeyType(jwt.SigningMethodES256, &key.PublicKey))
+	assert.Nil(t, checkKeyType(jwt.SigningMethodRS256, &key.PublicKey))
+}
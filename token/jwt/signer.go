@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"fmt"
+
+	"github.com/go-errors/errors"
+	"github.com/ory-am/fosite/token/jwk"
+	"golang.org/x/net/context"
+	"gopkg.in/dgrijalva/jwt-go.v2"
+)
+
+// Signer signs tokens without exposing the private key, e.g. because the key is kept in a hardware security
+// module or a key management service. RS256JWTStrategy and ES256JWTStrategy use the Signer instead of their
+// PrivateKey if it is set.
+type Signer interface {
+	// KeyID returns the ID of the key Sign currently signs with. It is added to the kid header before the token is
+	// signed.
+	KeyID(ctx context.Context) (string, error)
+
+	// Sign signs data, the JWS signing input, and returns the signature and the ID of the key it was signed with.
+	// Signatures are encoded as defined in https://tools.ietf.org/html/rfc7518#section-3, e.g. ES256 signatures are
+	// the concatenated r and s values and not ASN.1 encoded.
+	Sign(ctx context.Context, data []byte) (signature []byte, kid string, err error)
+
+	// PublicKeys returns the keys tokens are validated with, which includes previous keys if the key was rotated.
+	PublicKeys() (*jwk.JSONWebKeySet, error)
+}
+
+// generateWithSigner is generate using signer instead of a private key.
+func generateWithSigner(ctx context.Context, method jwt.SigningMethod, signer Signer, claims Mapper, header Mapper) (string, string, error) {
+	if header == nil || claims == nil {
+		return "", "", errors.New("Either claims or header is nil.")
+	}
+
+	kid, err := signer.KeyID(ctx)
+	if err != nil {
+		return "", "", errors.New(err)
+	}
+
+	token := newToken(method, claims, header)
+	token.Header["kid"] = kid
+	sstr, err := token.SigningString()
+	if err != nil {
+		return "", "", errors.New(err)
+	}
+
+	signature, signedWith, err := signer.Sign(ctx, []byte(sstr))
+	if err != nil {
+		return "", "", errors.New(err)
+	} else if signedWith != kid {
+		// The key was rotated after its ID was added to the header, verifiers would look up the wrong key.
+		return "", "", errors.Errorf("Token was signed with key %s instead of %s", signedWith, kid)
+	}
+
+	sig := jwt.EncodeSegment(signature)
+	return fmt.Sprintf("%s.%s", sstr, sig), sig, nil
+}
+
+// decodeWithSigner is decode using the public keys of signer. Tokens are verified with the key given by their kid
+// header, tokens without kid are only accepted if the signer has a single key.
+func decodeWithSigner(token string, method jwt.SigningMethod, signer Signer) (*jwt.Token, error) {
+	keys, err := signer.PublicKeys()
+	if err != nil {
+		return nil, errors.New(err)
+	}
+
+	return decodeWithKeyFunc(token, method, func(t *jwt.Token) (interface{}, error) {
+		var found *jwk.JSONWebKey
+		if kid, _ := t.Header["kid"].(string); kid != "" {
+			found = keys.FindByID(kid)
+		} else if keys != nil && len(keys.Keys) == 1 {
+			found = &keys.Keys[0]
+		}
+		if found == nil {
+			return nil, errors.Errorf("No key found for kid %v", t.Header["kid"])
+		}
+
+		key, err := found.PublicKey()
+		if err != nil {
+			return nil, err
+		} else if err := checkKeyType(method, key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	})
+}
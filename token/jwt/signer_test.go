@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/ory-am/fosite/internal"
+	"github.com/ory-am/fosite/token/jwk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"gopkg.in/dgrijalva/jwt-go.v2"
+)
+
+// keyStoreSigner signs like a key management service would, the strategies never see the private key.
+type keyStoreSigner struct {
+	method  jwt.SigningMethod
+	key     interface{}
+	kid     string
+	keys    *jwk.JSONWebKeySet
+	rotated string
+}
+
+func (s *keyStoreSigner) KeyID(_ context.Context) (string, error) {
+	return s.kid, nil
+}
+
+func (s *keyStoreSigner) Sign(_ context.Context, data []byte) ([]byte, string, error) {
+	sig, err := s.method.Sign(string(data), s.key)
+	if err != nil {
+		return nil, "", err
+	}
+	raw, err := jwt.DecodeSegment(sig)
+	if s.rotated != "" {
+		return raw, s.rotated, err
+	}
+	return raw, s.kid, err
+}
+
+func (s *keyStoreSigner) PublicKeys() (*jwk.JSONWebKeySet, error) {
+	return s.keys, nil
+}
+
+func TestRS256JWTStrategyWithSigner(t *testing.T) {
+	key := internal.MustRSAKey()
+	signer := &keyStoreSigner{method: jwt.SigningMethodRS256, key: key, kid: "current", keys: &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{
+		jwk.NewRSAPublicKey("previous", "sig", &internal.MustRSAKey().PublicKey),
+		jwk.NewRSAPublicKey("current", "sig", &key.PublicKey),
+	}}}
+	j := &RS256JWTStrategy{Signer: signer}
+	claims := &JWTClaims{ExpiresAt: time.Now().Add(time.Hour)}
+
+	token, sig, err := j.GenerateWithContext(context.Background(), claims, header)
+	require.Nil(t, err, "%s", err)
+
+	decoded, err := j.Decode(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, "current", decoded.Header["kid"])
+	validated, err := j.Validate(token)
+	require.Nil(t, err, "%s", err)
+	assert.Equal(t, sig, validated)
+
+	// Tokens signed by the signer can be verified using the public key alone.
+	_, err = (&RS256JWTStrategy{PrivateKey: key}).Decode(token)
+	require.Nil(t, err, "%s", err)
+
+	// Tokens must be signed with the key their kid refers to.
+	other, _, err := (&RS256JWTStrategy{PrivateKey: internal.MustRSAKey()}).Generate(claims, &Headers{Extra: map[string]interface{}{"kid": "current"}})
+	require.Nil(t, err, "%s", err)
+	_, err = j.Validate(other)
+	assert.NotNil(t, err)
+
+	// Tokens without kid are rejected if the signer has several keys.
+	other, _, err = (&RS256JWTStrategy{PrivateKey: key}).Generate(claims, header)
+	require.Nil(t, err, "%s", err)
+	_, err = j.Validate(other)
+	assert.NotNil(t, err)
+
+	// Tokens are not issued if the key was rotated while signing.
+	signer.rotated = "next"
+	_, _, err = j.Generate(claims, header)
+	assert.NotNil(t, err)
+}
+
+func TestES256JWTStrategyWithSigner(t *testing.T) {
+	key := internal.MustECDSAKey()
+	signer := &keyStoreSigner{method: jwt.SigningMethodES256, key: key, kid: "current", keys: &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{
+		ecPublicKey("current", &key.PublicKey),
+	}}}
+	j := &ES256JWTStrategy{Signer: signer}
+
+	token, _, err := j.Generate(&JWTClaims{ExpiresAt: time.Now().Add(time.Hour)}, header)
+	require.Nil(t, err, "%s", err)
+	_, err = j.Validate(token)
+	require.Nil(t, err, "%s", err)
+	_, err = (&ES256JWTStrategy{PrivateKey: key}).Validate(token)
+	require.Nil(t, err, "%s", err)
+
+	// An RSA signer must not be used for ES256.
+	_, err = (&RS256JWTStrategy{Signer: signer}).Validate(token)
+	assert.NotNil(t, err)
+}
+
+func TestValidateSigner(t *testing.T) {
+	weak := &keyStoreSigner{keys: &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{jwk.NewRSAPublicKey("weak", "sig", &internal.MustRSAKey().PublicKey)}}}
+	ec := &keyStoreSigner{keys: &jwk.JSONWebKeySet{Keys: []jwk.JSONWebKey{ecPublicKey("ec", &internal.MustECDSAKey().PublicKey)}}}
+
+	assert.NotNil(t, (&RS256JWTStrategy{Signer: &keyStoreSigner{}}).ValidateConfiguration(), "signers must have public keys")
+	assert.NotNil(t, (&RS256JWTStrategy{Signer: weak}).ValidateConfiguration(), "1024 bit keys are too weak")
+	assert.NotNil(t, (&RS256JWTStrategy{Signer: ec}).ValidateConfiguration())
+	assert.Nil(t, (&ES256JWTStrategy{Signer: ec}).ValidateConfiguration())
+}
+
+func ecPublicKey(kid string, key *ecdsa.PublicKey) jwk.JSONWebKey {
+	return jwk.JSONWebKey{
+		KeyType: "EC",
+		KeyID:   kid,
+		Curve:   "P-256",
+		X:       base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:       base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
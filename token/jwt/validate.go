@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+
+	"github.com/go-errors/errors"
+)
+
+// MinRSAKeySize is the minimum size of RSA signing keys in bits, see https://tools.ietf.org/html/rfc7518#section-3.3
+//
+//	A key of size 2048 bits or larger MUST be used with these algorithms.
+const MinRSAKeySize = 2048
+
+// ValidateConfiguration returns an error if the signing key is missing, invalid or smaller than MinRSAKeySize. If a
+// Signer is used, its public keys are checked instead.
+func (j *RS256JWTStrategy) ValidateConfiguration() error {
+	if j.Signer != nil {
+		return validateSigner("RS256", j.Signer)
+	} else if j.PrivateKey == nil {
+		return errors.New("RS256 signing key is missing")
+	} else if err := j.PrivateKey.Validate(); err != nil {
+		return errors.Errorf("RS256 signing key is invalid: %s", err)
+	} else if size := j.PrivateKey.N.BitLen(); size < MinRSAKeySize {
+		return errors.Errorf("RS256 signing key has %d bits, at least %d bits are required", size, MinRSAKeySize)
+	}
+	return nil
+}
+
+// ValidateConfiguration returns an error if the signing key is missing or does not use the P-256 curve required by
+// ES256. If a Signer is used, its public keys are checked instead.
+func (j *ES256JWTStrategy) ValidateConfiguration() error {
+	if j.Signer != nil {
+		return validateSigner("ES256", j.Signer)
+	} else if j.PrivateKey == nil {
+		return errors.New("ES256 signing key is missing")
+	} else if j.PrivateKey.Curve != elliptic.P256() {
+		return errors.Errorf("ES256 signing key must use the P-256 curve, not %s", j.PrivateKey.Curve.Params().Name)
+	}
+	return nil
+}
+
+// validateSigner returns an error if signer has no public keys or one of them can not be used for alg.
+func validateSigner(alg string, signer Signer) error {
+	keys, err := signer.PublicKeys()
+	if err != nil {
+		return errors.Errorf("%s public keys are not available: %s", alg, err)
+	} else if keys == nil || len(keys.Keys) == 0 {
+		return errors.Errorf("%s signer has no public keys", alg)
+	}
+
+	for _, k := range keys.Keys {
+		key, err := k.PublicKey()
+		if err != nil {
+			return errors.Errorf("%s public key %s is invalid: %s", alg, k.KeyID, err)
+		}
+
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			if alg != "RS256" {
+				return errors.Errorf("%s public key %s must not be an RSA key", alg, k.KeyID)
+			} else if size := key.N.BitLen(); size < MinRSAKeySize {
+				return errors.Errorf("%s public key %s has %d bits, at least %d bits are required", alg, k.KeyID, size, MinRSAKeySize)
+			}
+		case *ecdsa.PublicKey:
+			if alg != "ES256" || key.Curve != elliptic.P256() {
+				return errors.Errorf("%s public key %s must not be an ECDSA key using the %s curve", alg, k.KeyID, key.Curve.Params().Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/ory-am/fosite/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	strong, err := rsa.GenerateKey(rand.Reader, MinRSAKeySize)
+	require.Nil(t, err, "%s", err)
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.Nil(t, err, "%s", err)
+
+	assert.NotNil(t, (&RS256JWTStrategy{}).ValidateConfiguration())
+	assert.NotNil(t, (&RS256JWTStrategy{PrivateKey: internal.MustRSAKey()}).ValidateConfiguration(), "1024 bit keys are too weak")
+	assert.Nil(t, (&RS256JWTStrategy{PrivateKey: strong}).ValidateConfiguration())
+
+	assert.NotNil(t, (&ES256JWTStrategy{}).ValidateConfiguration())
+	assert.NotNil(t, (&ES256JWTStrategy{PrivateKey: p384}).ValidateConfiguration())
+	assert.Nil(t, (&ES256JWTStrategy{PrivateKey: internal.MustECDSAKey()}).ValidateConfiguration())
+}
@@ -0,0 +1,91 @@
+package fosite
+
+import (
+	"net/url"
+
+	"github.com/go-errors/errors"
+)
+
+// ConfigurationValidator can be implemented by handlers and strategies to detect insecure or incomplete
+// configurations, e.g. weak signing keys, before serving requests.
+type ConfigurationValidator interface {
+	// ValidateConfiguration returns an error describing what is wrong with the configuration.
+	ValidateConfiguration() error
+}
+
+// ValidateConfiguration checks Fosite, including its Issuer, and every handler and strategy it holds which
+// implements ConfigurationValidator. Handlers validate the strategies they use. It is meant to be called at startup,
+// so that a misconfigured server fails fast instead of issuing weak tokens or failing on the first request.
+func (f *Fosite) ValidateConfiguration() error {
+	if f.Store == nil {
+		return errors.New("Store must not be nil")
+	} else if f.Hasher == nil {
+		return errors.New("Hasher must not be nil")
+	} else if err := ValidateIssuer(f.Issuer); err != nil {
+		return err
+	}
+
+	var components []interface{}
+	for _, h := range f.AuthorizeEndpointHandlers {
+		components = append(components, h)
+	}
+	for _, h := range f.TokenEndpointHandlers {
+		components = append(components, h)
+	}
+	for _, h := range f.AuthorizedRequestValidators {
+		components = append(components, h)
+	}
+	for _, h := range f.TokenIntrospectors {
+		components = append(components, h)
+	}
+	for _, h := range f.RevocationHandlers {
+		components = append(components, h)
+	}
+	components = append(components, f.DPoPNonceStrategy)
+	return ValidateConfigurations(components...)
+}
+
+// ValidateConfigurations validates every component which implements ConfigurationValidator and returns the first
+// error. Components which do not implement it, including nil, are skipped.
+func ValidateConfigurations(components ...interface{}) error {
+	for _, component := range components {
+		if v, ok := component.(ConfigurationValidator); ok {
+			if err := v.ValidateConfiguration(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateIssuer checks that issuer can be used as the iss claim of tokens, see
+// http://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+//
+//	URL using the https scheme with no query or fragment component that the OP asserts as its Issuer Identifier.
+//
+// Like redirect URIs, issuers on localhost may use http. An empty issuer is valid, it omits the iss claim.
+func ValidateIssuer(issuer string) error {
+	if issuer == "" {
+		return nil
+	}
+
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return errors.Errorf("Issuer %s is not a valid URL: %s", issuer, err)
+	} else if !u.IsAbs() || u.Host == "" {
+		return errors.Errorf("Issuer %s must be an absolute URL", issuer)
+	} else if u.Scheme != "https" && !(u.Scheme == "http" && isLocalhost(u)) {
+		return errors.Errorf("Issuer %s must use https", issuer)
+	} else if u.RawQuery != "" || u.Fragment != "" {
+		return errors.Errorf("Issuer %s must not contain a query or fragment", issuer)
+	}
+	return nil
+}
+
+// ValidateConfiguration implements ConfigurationValidator.
+func (s *HMACDPoPNonceStrategy) ValidateConfiguration() error {
+	if len(s.Secret) < 32 {
+		return errors.New("Secret for signing DPoP nonces must be at least 32 bytes long")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package fosite_test
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/golang/mock/gomock"
+	. "github.com/ory-am/fosite"
+	"github.com/ory-am/fosite/handler/core"
+	"github.com/ory-am/fosite/handler/core/strategy"
+	"github.com/ory-am/fosite/hash"
+	"github.com/ory-am/fosite/internal"
+	"github.com/ory-am/fosite/token/hmac"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIssuer(t *testing.T) {
+	for k, c := range []struct {
+		issuer      string
+		expectError bool
+	}{
+		{issuer: ""},
+		{issuer: "https://auth.example.com"},
+		{issuer: "https://auth.example.com/tenant"},
+		{issuer: "http://localhost:4444"},
+		{issuer: "http://auth.example.com", expectError: true},
+		{issuer: "https://auth.example.com?tenant=foo", expectError: true},
+		{issuer: "https://auth.example.com#foo", expectError: true},
+		{issuer: "auth.example.com", expectError: true},
+		{issuer: "/tenant", expectError: true},
+		{issuer: "https://", expectError: true},
+		{issuer: "https://auth.example.com/%zz", expectError: true},
+	} {
+		err := ValidateIssuer(c.issuer)
+		assert.Equal(t, c.expectError, err != nil, "case %d: %s", k, err)
+	}
+}
+
+type validatingHandler struct {
+	TokenEndpointHandler
+	err error
+}
+
+func (h *validatingHandler) ValidateConfiguration() error {
+	return h.err
+}
+
+func TestFositeValidateConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := internal.NewMockStorage(ctrl)
+	defer ctrl.Finish()
+
+	assert.NotNil(t, (&Fosite{}).ValidateConfiguration(), "store is missing")
+	assert.NotNil(t, (&Fosite{Store: store}).ValidateConfiguration(), "hasher is missing")
+	assert.Nil(t, NewFosite(store).ValidateConfiguration())
+
+	f := &Fosite{Store: store, Hasher: &hash.BCrypt{}, TokenEndpointHandlers: TokenEndpointHandlers{
+		&validatingHandler{},
+		&validatingHandler{err: errors.New("weak")},
+	}}
+	assert.EqualError(t, f.ValidateConfiguration(), "weak")
+
+	f = &Fosite{Store: store, Hasher: &hash.BCrypt{}, DPoPNonceStrategy: &HMACDPoPNonceStrategy{Secret: []byte("foo")}}
+	assert.NotNil(t, f.ValidateConfiguration(), "DPoP nonce secret is too short")
+
+	f = &Fosite{Store: store, Hasher: &hash.BCrypt{}, Issuer: "http://auth.example.com"}
+	assert.NotNil(t, f.ValidateConfiguration(), "issuer must use https")
+
+	validator := func(secret string) *core.CoreValidator {
+		return &core.CoreValidator{
+			AccessTokenStrategy: &strategy.HMACSHAStrategy{Enigma: &hmac.HMACStrategy{GlobalSecret: []byte(secret)}},
+			AccessTokenStorage:  internal.NewMockAccessTokenStorage(ctrl),
+		}
+	}
+	f = &Fosite{Store: store, Hasher: &hash.BCrypt{}, TokenIntrospectors: TokenIntrospectors{validator("short")}}
+	assert.NotNil(t, f.ValidateConfiguration(), "HMAC secret of the access token strategy is too short")
+
+	f = &Fosite{Store: store, Hasher: &hash.BCrypt{}, TokenIntrospectors: TokenIntrospectors{validator("some-super-cool-secret-that-nobody-knows")}}
+	assert.Nil(t, f.ValidateConfiguration())
+}
@@ -0,0 +1,80 @@
+package fosite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// ValidationError is a problem with a parameter of a request.
+type ValidationError struct {
+	// Parameter is the name of the offending parameter, e.g. "redirect_uri".
+	Parameter string
+
+	// Err is the error which would have been returned if the problem was the only one, e.g. ErrInvalidScope.
+	Err error
+}
+
+// ValidationErrors is returned by NewAuthorizeRequest if Fosite.AggregateValidationErrors is set and the request
+// has more than one problem. It is written like the error of the first problem, so that the client receives a
+// single error code, but the Hint of the RFC6749Error lists all problems for developers debugging an integration.
+//
+// errors.Is does not look into ValidationErrors, compare the errors of its elements instead.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	problems := make([]string, len(e))
+	for k, problem := range e {
+		problems[k] = fmt.Sprintf("%s: %s", problem.Parameter, problem.Err.Error())
+	}
+	return strings.Join(problems, "; ")
+}
+
+func (e ValidationErrors) toRFC6749Error() *RFC6749Error {
+	rfcerr := ErrorToRFC6749Error(e[0].Err)
+	problems := make([]string, len(e))
+	for k, problem := range e {
+		problems[k] = fmt.Sprintf("%s (%s): %s", problem.Parameter, ErrorToRFC6749Error(problem.Err).Name, problem.Err.Error())
+	}
+	rfcerr.Hint = fmt.Sprintf("The request has %d problems. %s", len(e), strings.Join(problems, "; "))
+	return rfcerr
+}
+
+// validationProblems collects the problems of a request. Unless aggregate is set, only the first problem is kept
+// and validation must stop.
+type validationProblems struct {
+	aggregate bool
+	problems  ValidationErrors
+}
+
+// add records a problem with parameter. It returns the error to return immediately, or nil if validation continues
+// to find further problems.
+func (v *validationProblems) add(parameter string, err error) error {
+	if !v.aggregate {
+		return err
+	}
+	v.problems = append(v.problems, ValidationError{Parameter: parameter, Err: err})
+	return nil
+}
+
+// err returns nil if no problem was found, the error of the problem if only one was found and ValidationErrors
+// otherwise.
+func (v *validationProblems) err() error {
+	switch len(v.problems) {
+	case 0:
+		return nil
+	case 1:
+		return v.problems[0].Err
+	}
+	return v.problems
+}
+
+// unwrapValidationErrors returns the ValidationErrors carried by err, if any.
+func unwrapValidationErrors(err error) (ValidationErrors, bool) {
+	if ge, ok := err.(*errors.Error); ok {
+		err = ge.Err
+	}
+	ve, ok := err.(ValidationErrors)
+	return ve, ok && len(ve) > 0
+}
@@ -0,0 +1,30 @@
+package fosite
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationErrors(t *testing.T) {
+	ve := ValidationErrors{
+		{Parameter: "state", Err: errors.New(ErrInvalidState)},
+		{Parameter: "scope", Err: errors.New(ErrInvalidScope)},
+	}
+	assert.Equal(t, "state: "+ErrInvalidState.Error()+"; scope: "+ErrInvalidScope.Error(), ve.Error())
+
+	for k, err := range []error{ve, errors.New(ve)} {
+		rfcerr := ErrorToRFC6749Error(err)
+		assert.Equal(t, errInvalidState, rfcerr.Name, "%d", k)
+		assert.Equal(t, ErrInvalidState.Error(), rfcerr.Description, "%d", k)
+		assert.Equal(t, http.StatusBadRequest, rfcerr.StatusCode, "%d", k)
+		assert.Contains(t, rfcerr.Hint, "2 problems", "%d", k)
+		assert.Contains(t, rfcerr.Hint, "scope (invalid_scope)", "%d", k)
+	}
+
+	problems := &validationProblems{}
+	assert.NotNil(t, problems.add("state", ErrInvalidState), "validation stops at the first problem unless aggregating")
+	assert.Nil(t, problems.err())
+}
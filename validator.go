@@ -38,3 +38,93 @@ func (f *Fosite) ValidateRequestAuthorization(ctx context.Context, req *http.Req
 
 	return ar, nil
 }
+
+// ValidateToken validates an access token the same way ValidateRequestAuthorization validates the bearer token of
+// a request. Additionally, the token must have been granted audience unless audience is empty, see
+// HasGrantedAudience. The audience is passed on to the validators using WithAudience, so that tokens carrying their
+// audience, like JWT access tokens, are checked as well.
+func (f *Fosite) ValidateToken(ctx context.Context, token string, session interface{}, audience string, scopes ...string) (AccessRequester, error) {
+	req := &http.Request{Header: http.Header{"Authorization": {"Bearer " + token}}}
+	ar, err := f.ValidateRequestAuthorization(WithAudience(ctx, audience), req, session, scopes...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !HasGrantedAudience(ar, audience) {
+		return nil, errors.New(ErrRequestForbidden)
+	}
+
+	return ar, nil
+}
+
+type audienceContextKey struct{}
+
+// WithAudience returns a copy of ctx carrying the audience an access token is validated for. Strategies whose
+// tokens carry their audience reject tokens which were not issued for it. ctx is returned unchanged if audience is
+// empty.
+func WithAudience(ctx context.Context, audience string) context.Context {
+	if audience == "" {
+		return ctx
+	} else if ctx == nil {
+		ctx = NewContext()
+	}
+	return context.WithValue(ctx, audienceContextKey{}, audience)
+}
+
+// AudienceFromContext returns the audience injected by WithAudience, or an empty string if there is none.
+func AudienceFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	audience, _ := ctx.Value(audienceContextKey{}).(string)
+	return audience
+}
+
+// AudienceRequester is implemented by requests which keep the audience their tokens were issued for. Request
+// implements it. Storages must persist the granted audience, otherwise their tokens are not accepted for any
+// audience.
+type AudienceRequester interface {
+	// GetGrantedAudience returns the audience the tokens of the request were issued for.
+	GetGrantedAudience() Arguments
+
+	// GrantAudience adds audience to the granted audience.
+	GrantAudience(audience string)
+}
+
+// HasGrantedAudience returns true if audience is empty or requester was granted audience when its tokens were
+// issued. Later changes to the audiences of the client do not apply to tokens issued before.
+func HasGrantedAudience(requester Requester, audience string) bool {
+	if audience == "" {
+		return true
+	}
+	ar, ok := requester.(AudienceRequester)
+	return ok && ar.GetGrantedAudience().Has(audience)
+}
+
+// CopyGrantedAudience grants the audience of original, the request a grant was issued for, to the token request
+// requester, so that refreshed or exchanged tokens keep the audience of the grant.
+func CopyGrantedAudience(original, requester Requester) {
+	from, ok := original.(AudienceRequester)
+	if !ok {
+		return
+	}
+	to, ok := requester.(AudienceRequester)
+	if !ok {
+		return
+	}
+	for _, audience := range from.GetGrantedAudience() {
+		to.GrantAudience(audience)
+	}
+}
+
+// grantClientAudience grants the audiences the client is registered for to requester before tokens are issued for
+// it, unless a handler already granted the audience of an earlier grant, see CopyGrantedAudience.
+func grantClientAudience(requester Requester) {
+	ar, ok := requester.(AudienceRequester)
+	if !ok || len(ar.GetGrantedAudience()) > 0 || requester.GetClient() == nil {
+		return
+	}
+	for _, audience := range requester.GetClient().GetAudience() {
+		ar.GrantAudience(audience)
+	}
+}
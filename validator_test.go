@@ -85,3 +85,96 @@ func TestValidateRequestAuthorization(t *testing.T) {
 		t.Logf("Passed test case %d", k)
 	}
 }
+
+func TestValidateToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validator := internal.NewMockAuthorizedRequestValidator(ctrl)
+	defer ctrl.Finish()
+
+	f := NewFosite(store.NewStore())
+	f.AuthorizedRequestValidators = AuthorizedRequestValidators{validator}
+
+	var validatedFor string
+	grant := func(scopes ...string) func(context.Context, *http.Request, AccessRequester) {
+		return func(ctx context.Context, req *http.Request, accessRequest AccessRequester) {
+			assert.Equal(t, "Bearer some-token", req.Header.Get("Authorization"))
+			validatedFor = AudienceFromContext(ctx)
+			accessRequest.(*AccessRequest).Client = &DefaultClient{Audience: []string{"https://api.example.com", "https://other.example.com"}}
+			accessRequest.(*AccessRequest).GrantedAudience = Arguments{"https://api.example.com"}
+			accessRequest.(*AccessRequest).GrantedScopes = scopes
+		}
+	}
+
+	for k, c := range []struct {
+		description string
+		audience    string
+		scopes      []string
+		setup       func()
+		expectErr   error
+	}{
+		{
+			description: "should fail because the token is invalid",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Return(ErrRequestUnauthorized)
+			},
+			expectErr: ErrRequestUnauthorized,
+		},
+		{
+			description: "should fail because the audience does not match",
+			audience:    "https://other.example.com",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant(f.MandatoryScope)).Return(nil)
+			},
+			expectErr: ErrRequestForbidden,
+		},
+		{
+			description: "should fail because a scope is missing",
+			audience:    "https://api.example.com",
+			scopes:      []string{"foo"},
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant("bar")).Return(nil)
+			},
+			expectErr: ErrRequestForbidden,
+		},
+		{
+			description: "should pass",
+			audience:    "https://api.example.com",
+			scopes:      []string{"foo"},
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant("foo")).Return(nil)
+			},
+		},
+		{
+			description: "should pass without audience",
+			setup: func() {
+				validator.EXPECT().ValidateRequest(gomock.Any(), gomock.Any(), gomock.Any()).Do(grant(f.MandatoryScope)).Return(nil)
+			},
+		},
+	} {
+		c.setup()
+		ar, err := f.ValidateToken(nil, "some-token", nil, c.audience, c.scopes...)
+		assert.True(t, errors.Is(c.expectErr, err), "(%d) %s\n%s\n%s", k, c.description, err, c.expectErr)
+		if c.expectErr == nil {
+			assert.NotNil(t, ar, "(%d)", k)
+			assert.Equal(t, c.audience, validatedFor, "(%d) the audience is passed on to the validators", k)
+		}
+	}
+}
+
+func TestHasGrantedAudience(t *testing.T) {
+	ar := NewAccessRequest(nil)
+	assert.True(t, HasGrantedAudience(ar, ""))
+	assert.False(t, HasGrantedAudience(ar, "https://api.example.com"), "requests without granted audience have no audience")
+
+	// The audiences the client is registered for later on do not apply to tokens issued before.
+	ar.Client = &DefaultClient{Audience: []string{"https://api.example.com"}}
+	assert.False(t, HasGrantedAudience(ar, "https://api.example.com"))
+
+	ar.GrantAudience("https://api.example.com")
+	assert.True(t, HasGrantedAudience(ar, "https://api.example.com"))
+	assert.False(t, HasGrantedAudience(ar, "https://other.example.com"))
+
+	refresh := NewAccessRequest(nil)
+	CopyGrantedAudience(ar, refresh)
+	assert.Equal(t, Arguments{"https://api.example.com"}, refresh.GetGrantedAudience())
+}